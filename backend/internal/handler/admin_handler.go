@@ -299,6 +299,72 @@ func (h *AdminHandler) UpdateUserInfo(c *gin.Context) {
 	response.Success(c, user)
 }
 
+// UpdateUserStorageQuota 设置用户存储配额
+// @Summary 设置用户存储配额
+// @Description 为用户单独设置存储配额（MB），0 表示不限制，quota_mb 为空时恢复按角色配置
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Param request body model.UpdateUserStorageQuotaRequest true "存储配额"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/users/{id}/storage-quota [put]
+func (h *AdminHandler) UpdateUserStorageQuota(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		response.Error(c, response.CodeInvalidParams, "用户ID格式错误")
+		return
+	}
+
+	var req model.UpdateUserStorageQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.CodeInvalidParams, "参数错误")
+		return
+	}
+
+	if err := h.adminService.UpdateUserStorageQuota(uint(id), req.QuotaMB); err != nil {
+		response.Error(c, response.CodeServerError, "设置存储配额失败")
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// UpdateUserDownloadLimits 设置用户下载限制
+// @Summary 设置用户下载限制
+// @Description 为用户单独设置每日、每周和每分钟下载次数，0 表示不限制，某项为空时该项恢复按角色配置
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Param request body model.UpdateUserDownloadLimitsRequest true "下载限制"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/users/{id}/download-limits [put]
+func (h *AdminHandler) UpdateUserDownloadLimits(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		response.Error(c, response.CodeInvalidParams, "用户ID格式错误")
+		return
+	}
+
+	var req model.UpdateUserDownloadLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.CodeInvalidParams, "参数错误")
+		return
+	}
+
+	if err := h.adminService.UpdateUserDownloadLimits(uint(id), &req); err != nil {
+		response.Error(c, response.CodeServerError, "设置下载限制失败")
+		return
+	}
+
+	response.Success(c, nil)
+}
+
 // DeleteUser 删除用户
 // @Summary 删除用户
 // @Description 删除用户 (软删除)
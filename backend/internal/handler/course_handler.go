@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// CourseHandler 课程处理器
+type CourseHandler struct {
+	courseService service.CourseService
+}
+
+// NewCourseHandler 创建课程处理器实例
+func NewCourseHandler(courseService service.CourseService) *CourseHandler {
+	return &CourseHandler{
+		courseService: courseService,
+	}
+}
+
+// List 获取课程列表
+// @Summary 获取课程列表
+// @Description 分页获取课程目录，keyword 同时匹配课程代码、名称和别名
+// @Tags 课程
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param keyword query string false "关键词"
+// @Param department query string false "开课院系"
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/courses [get]
+func (h *CourseHandler) List(c *gin.Context) {
+	var req model.CourseListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	courses, total, err := h.courseService.ListCourses(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, courses)
+}
+
+// GetByID 获取课程详情
+// @Summary 获取课程详情
+// @Description 获取课程信息、别名和关联的资料数
+// @Tags 课程
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "课程ID"
+// @Success 200 {object} response.Response{data=model.CourseResponse}
+// @Router /api/v1/courses/{id} [get]
+func (h *CourseHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的课程ID")
+		return
+	}
+
+	course, err := h.courseService.GetCourse(c.Request.Context(), uint(id))
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	response.Success(c, course)
+}
+
+// Create 创建课程
+// @Summary 创建课程
+// @Description 创建课程及其别名，课程名称与名称或别名一致、尚未关联课程的已有资料会自动关联
+// @Tags 课程
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CourseRequest true "课程信息"
+// @Success 200 {object} response.Response{data=model.CourseResponse}
+// @Router /api/v1/admin/courses [post]
+func (h *CourseHandler) Create(c *gin.Context) {
+	var req model.CourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	course, err := h.courseService.CreateCourse(c.Request.Context(), &req)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	response.Success(c, course)
+}
+
+// Update 更新课程
+// @Summary 更新课程
+// @Description 更新课程信息，aliases 整体替换原有别名；新增别名匹配的未关联资料会自动关联
+// @Tags 课程
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "课程ID"
+// @Param request body model.CourseRequest true "课程信息"
+// @Success 200 {object} response.Response{data=model.CourseResponse}
+// @Router /api/v1/admin/courses/{id} [put]
+func (h *CourseHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的课程ID")
+		return
+	}
+
+	var req model.CourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	course, err := h.courseService.UpdateCourse(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	response.Success(c, course)
+}
+
+// Delete 删除课程
+// @Summary 删除课程
+// @Description 删除课程及其别名，关联资料取消关联但保留原课程名称
+// @Tags 课程
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "课程ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/courses/{id} [delete]
+func (h *CourseHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的课程ID")
+		return
+	}
+
+	if err := h.courseService.DeleteCourse(c.Request.Context(), uint(id)); err != nil {
+		handleCourseError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleCourseError 将课程错误转换为响应
+func handleCourseError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCourseNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidCourse), errors.Is(err, service.ErrCourseConflict):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/middleware"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// DownloadBoostHandler 临时下载额度提升处理器
+type DownloadBoostHandler struct {
+	boostService service.DownloadBoostService
+}
+
+// NewDownloadBoostHandler 创建临时下载额度提升处理器实例
+func NewDownloadBoostHandler(boostService service.DownloadBoostService) *DownloadBoostHandler {
+	return &DownloadBoostHandler{
+		boostService: boostService,
+	}
+}
+
+// CreateBoost 创建临时下载额度提升
+// @Summary 创建临时下载额度提升
+// @Description 在指定时间段内为用户、角色或全体用户增加每日/每周下载次数（如考试周），只对有限制的窗口生效
+// @Tags 下载配额
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CreateDownloadQuotaBoostRequest true "临时提升"
+// @Success 200 {object} response.Response{data=model.DownloadQuotaBoost}
+// @Router /api/v1/admin/download-boosts [post]
+func (h *DownloadBoostHandler) CreateBoost(c *gin.Context) {
+	var req model.CreateDownloadQuotaBoostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	adminID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+
+	boost, err := h.boostService.CreateBoost(c.Request.Context(), adminID, &req)
+	if err != nil {
+		handleDownloadBoostError(c, err)
+		return
+	}
+
+	response.Success(c, boost)
+}
+
+// ListBoosts 获取临时下载额度提升列表
+// @Summary 获取临时下载额度提升列表
+// @Description 分页获取临时下载额度提升，可按用户筛选或只看当前生效的
+// @Tags 下载配额
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param user_id query int false "用户ID"
+// @Param active_only query bool false "只返回当前生效的"
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/admin/download-boosts [get]
+func (h *DownloadBoostHandler) ListBoosts(c *gin.Context) {
+	var req model.DownloadQuotaBoostListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	boosts, total, err := h.boostService.ListBoosts(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, boosts)
+}
+
+// DeleteBoost 删除临时下载额度提升
+// @Summary 删除临时下载额度提升
+// @Description 删除后立即失效
+// @Tags 下载配额
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "临时提升ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/download-boosts/{id} [delete]
+func (h *DownloadBoostHandler) DeleteBoost(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的临时提升ID")
+		return
+	}
+
+	if err := h.boostService.DeleteBoost(c.Request.Context(), uint(id)); err != nil {
+		handleDownloadBoostError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleDownloadBoostError 将临时下载额度提升错误转换为响应
+func handleDownloadBoostError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrDownloadQuotaBoostNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidDownloadQuotaBoost):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
@@ -0,0 +1,463 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/middleware"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// FavoriteCollectionHandler 收藏夹处理器
+type FavoriteCollectionHandler struct {
+	collectionService service.FavoriteCollectionService
+}
+
+// NewFavoriteCollectionHandler 创建收藏夹处理器实例
+func NewFavoriteCollectionHandler(collectionService service.FavoriteCollectionService) *FavoriteCollectionHandler {
+	return &FavoriteCollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// ListCollections 获取我的收藏夹
+// @Summary 获取我的收藏夹
+// @Description 按用户设定的顺序返回全部收藏夹，全部收藏请使用 /favorites
+// @Tags 收藏夹
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.CollectionResponse}
+// @Router /api/v1/collections [get]
+func (h *FavoriteCollectionHandler) ListCollections(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	collections, err := h.collectionService.ListCollections(c.Request.Context(), userID)
+	if err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, collections)
+}
+
+// CreateCollection 创建收藏夹
+// @Summary 创建收藏夹
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CollectionRequest true "收藏夹信息"
+// @Success 200 {object} response.Response{data=model.CollectionResponse}
+// @Router /api/v1/collections [post]
+func (h *FavoriteCollectionHandler) CreateCollection(c *gin.Context) {
+	var req model.CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	collection, err := h.collectionService.CreateCollection(c.Request.Context(), userID, &req)
+	if err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, collection)
+}
+
+// UpdateCollection 更新收藏夹
+// @Summary 更新收藏夹
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Param request body model.CollectionRequest true "收藏夹信息"
+// @Success 200 {object} response.Response{data=model.CollectionResponse}
+// @Router /api/v1/collections/{id} [put]
+func (h *FavoriteCollectionHandler) UpdateCollection(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+
+	var req model.CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	collection, err := h.collectionService.UpdateCollection(c.Request.Context(), userID, collectionID, &req)
+	if err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, collection)
+}
+
+// DeleteCollection 删除收藏夹
+// @Summary 删除收藏夹
+// @Description 其中的资料仍保留在全部收藏中
+// @Tags 收藏夹
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/collections/{id} [delete]
+func (h *FavoriteCollectionHandler) DeleteCollection(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.collectionService.DeleteCollection(c.Request.Context(), userID, collectionID); err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ReorderCollections 调整收藏夹顺序
+// @Summary 调整收藏夹顺序
+// @Description ids 为排序后的全部收藏夹ID
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ReorderRequest true "排序后的收藏夹ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/collections/order [put]
+func (h *FavoriteCollectionHandler) ReorderCollections(c *gin.Context) {
+	var req model.ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.collectionService.ReorderCollections(c.Request.Context(), userID, req.IDs); err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ListItems 获取收藏夹中的资料
+// @Summary 获取收藏夹中的资料
+// @Tags 收藏夹
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/collections/{id}/items [get]
+func (h *FavoriteCollectionHandler) ListItems(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+
+	var req model.CollectionItemListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	items, total, err := h.collectionService.ListItems(c.Request.Context(), userID, collectionID, &req)
+	if err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, items)
+}
+
+// AddItem 添加资料到收藏夹
+// @Summary 添加资料到收藏夹
+// @Description 未收藏的资料会同时加入收藏
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Param request body model.AddCollectionItemRequest true "资料及备注"
+// @Success 200 {object} response.Response{data=model.CollectionItemResponse}
+// @Router /api/v1/collections/{id}/items [post]
+func (h *FavoriteCollectionHandler) AddItem(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+
+	var req model.AddCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	item, err := h.collectionService.AddItem(c.Request.Context(), userID, collectionID, &req)
+	if err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// UpdateItem 更新资料备注
+// @Summary 更新收藏夹中资料的备注
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Param materialId path int true "资料ID"
+// @Param request body model.UpdateCollectionItemRequest true "备注"
+// @Success 200 {object} response.Response
+// @Router /api/v1/collections/{id}/items/{materialId} [put]
+func (h *FavoriteCollectionHandler) UpdateItem(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	materialID, err := strconv.ParseUint(c.Param("materialId"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+
+	var req model.UpdateCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.collectionService.UpdateItem(c.Request.Context(), userID, collectionID, uint(materialID), &req); err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// RemoveItem 从收藏夹移除资料
+// @Summary 从收藏夹移除资料
+// @Description 只从该收藏夹移除，不会取消收藏
+// @Tags 收藏夹
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Param materialId path int true "资料ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/collections/{id}/items/{materialId} [delete]
+func (h *FavoriteCollectionHandler) RemoveItem(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	materialID, err := strconv.ParseUint(c.Param("materialId"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.collectionService.RemoveItem(c.Request.Context(), userID, collectionID, uint(materialID)); err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ReorderItems 调整收藏夹中资料的顺序
+// @Summary 调整收藏夹中资料的顺序
+// @Description ids 为排序后的全部资料ID
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Param request body model.ReorderRequest true "排序后的资料ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/collections/{id}/items/order [put]
+func (h *FavoriteCollectionHandler) ReorderItems(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.collectionService.ReorderItems(c.Request.Context(), userID, collectionID, req.IDs); err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ShareCollection 开启公开分享
+// @Summary 开启收藏夹公开分享
+// @Description 返回的 share_token 用于 /shared/collections/{token}，已分享时返回原令牌
+// @Tags 收藏夹
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Success 200 {object} response.Response{data=model.CollectionResponse}
+// @Router /api/v1/collections/{id}/share [post]
+func (h *FavoriteCollectionHandler) ShareCollection(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	collection, err := h.collectionService.ShareCollection(c.Request.Context(), userID, collectionID)
+	if err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, collection)
+}
+
+// UnshareCollection 关闭公开分享
+// @Summary 关闭收藏夹公开分享
+// @Description 关闭后旧的分享链接失效，再次分享会生成新链接
+// @Tags 收藏夹
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/collections/{id}/share [delete]
+func (h *FavoriteCollectionHandler) UnshareCollection(c *gin.Context) {
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.collectionService.UnshareCollection(c.Request.Context(), userID, collectionID); err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ListMaterialCollections 获取资料所在的收藏夹
+// @Summary 获取资料所在的收藏夹
+// @Description 返回当前用户包含该资料的收藏夹ID，用于“添加到收藏夹”弹窗的勾选状态
+// @Tags 收藏夹
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Success 200 {object} response.Response{data=[]int}
+// @Router /api/v1/materials/{id}/collections [get]
+func (h *FavoriteCollectionHandler) ListMaterialCollections(c *gin.Context) {
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	ids, err := h.collectionService.ListCollectionIDsByMaterial(c.Request.Context(), userID, uint(materialID))
+	if err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, ids)
+}
+
+// GetSharedCollection 查看分享的收藏夹
+// @Summary 查看分享的收藏夹
+// @Description 无需登录
+// @Tags 收藏夹
+// @Produce json
+// @Param token path string true "分享令牌"
+// @Success 200 {object} response.Response{data=model.CollectionResponse}
+// @Router /api/v1/shared/collections/{token} [get]
+func (h *FavoriteCollectionHandler) GetSharedCollection(c *gin.Context) {
+	collection, err := h.collectionService.GetSharedCollection(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, collection)
+}
+
+// ListSharedItems 查看分享的收藏夹中的资料
+// @Summary 查看分享的收藏夹中的资料
+// @Description 无需登录，只返回已审核通过的资料
+// @Tags 收藏夹
+// @Produce json
+// @Param token path string true "分享令牌"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/shared/collections/{token}/items [get]
+func (h *FavoriteCollectionHandler) ListSharedItems(c *gin.Context) {
+	var req model.CollectionItemListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	items, total, err := h.collectionService.ListSharedItems(c.Request.Context(), c.Param("token"), &req)
+	if err != nil {
+		handleCollectionError(c, err)
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, items)
+}
+
+// parseCollectionID 解析路径中的收藏夹ID，失败时直接写入错误响应
+func parseCollectionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的收藏夹ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleCollectionError 将收藏夹错误转换为响应
+func handleCollectionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCollectionNotFound),
+		errors.Is(err, service.ErrCollectionItemNotFound),
+		errors.Is(err, service.ErrMaterialNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrAccessDenied):
+		response.Error(c, response.ErrForbidden, err.Error())
+	case errors.Is(err, service.ErrInvalidCollection),
+		errors.Is(err, service.ErrCollectionLimitExceeded),
+		errors.Is(err, service.ErrCollectionItemExists):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
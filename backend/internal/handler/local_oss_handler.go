@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/pkg/oss"
+	"github.com/study-upc/backend/internal/pkg/response"
+)
+
+// LocalOSSHandler 本地文件系统存储的签名上传/下载处理器
+// 仅在 OSS provider 为 filesystem 时注册，返回真实的 HTTP 状态码以兼容直传客户端
+type LocalOSSHandler struct {
+	client      *oss.FilesystemClient
+	maxFileSize int64
+}
+
+// NewLocalOSSHandler 创建本地存储处理器实例
+func NewLocalOSSHandler(client *oss.FilesystemClient, maxFileSize int64) *LocalOSSHandler {
+	return &LocalOSSHandler{
+		client:      client,
+		maxFileSize: maxFileSize,
+	}
+}
+
+// Upload 通过签名 URL 上传文件或分片
+// @Summary 本地存储签名上传
+// @Description 使用预签名 URL 上传文件；携带 uploadId 和 partNumber 时上传分片
+// @Tags 存储
+// @Accept octet-stream
+// @Param key path string true "文件 key"
+// @Param expires query int true "过期时间戳"
+// @Param signature query string true "签名"
+// @Success 200 "上传成功，ETag 响应头为文件 MD5"
+// @Router /oss/objects/{key} [put]
+func (h *LocalOSSHandler) Upload(c *gin.Context) {
+	fileKey, ok := h.verify(c)
+	if !ok {
+		return
+	}
+
+	limit := h.maxFileSize
+	uploadID := c.Query("uploadId")
+	if uploadID != "" {
+		limit = oss.MaxPartSize
+	}
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	defer body.Close()
+
+	var etag string
+	if uploadID != "" {
+		partNumber, err := strconv.Atoi(c.Query("partNumber"))
+		if err != nil {
+			response.FailWithStatus(c, http.StatusBadRequest, response.ErrInvalidParams, oss.ErrInvalidPartNumber.Error())
+			return
+		}
+		etag, err = h.client.PutPart(c.Request.Context(), fileKey, uploadID, partNumber, body)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+	} else {
+		info, err := h.client.PutObject(c.Request.Context(), fileKey, body, c.ContentType())
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		etag = info.ETag
+	}
+
+	c.Header("ETag", fmt.Sprintf("%q", etag))
+	c.Status(http.StatusOK)
+}
+
+// Download 通过签名 URL 下载文件
+// @Summary 本地存储签名下载
+// @Description 使用预签名 URL 下载文件，支持 Range 请求
+// @Tags 存储
+// @Produce octet-stream
+// @Param key path string true "文件 key"
+// @Param expires query int true "过期时间戳"
+// @Param signature query string true "签名"
+// @Success 200 {file} binary
+// @Router /oss/objects/{key} [get]
+func (h *LocalOSSHandler) Download(c *gin.Context) {
+	fileKey, ok := h.verify(c)
+	if !ok {
+		return
+	}
+
+	file, info, err := h.client.OpenObject(c.Request.Context(), fileKey)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	defer file.Close()
+
+	fileName := path.Base(fileKey)
+	c.Header("Content-Type", info.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(fileName)))
+	if info.ETag != "" {
+		c.Header("ETag", fmt.Sprintf("%q", info.ETag))
+	}
+	http.ServeContent(c.Writer, c.Request, fileName, info.LastModified, file)
+}
+
+// verify 校验签名并返回文件 key
+func (h *LocalOSSHandler) verify(c *gin.Context) (string, bool) {
+	fileKey := strings.TrimPrefix(c.Param("key"), "/")
+	if err := h.client.VerifySignature(c.Request.Method, fileKey, c.Request.URL.Query()); err != nil {
+		response.FailWithStatus(c, http.StatusForbidden, response.ErrForbidden, err.Error())
+		return "", false
+	}
+	return fileKey, true
+}
+
+// handleError 将本地存储错误转换为响应
+func (h *LocalOSSHandler) handleError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		response.FailWithStatus(c, http.StatusRequestEntityTooLarge, response.ErrInvalidParams, oss.ErrFileTooLarge.Error())
+	case errors.Is(err, oss.ErrFileNotFound), errors.Is(err, oss.ErrMultipartUploadNotFound):
+		response.FailWithStatus(c, http.StatusNotFound, response.ErrNotFound, err.Error())
+	case errors.Is(err, oss.ErrInvalidFileName), errors.Is(err, oss.ErrInvalidPartNumber):
+		response.FailWithStatus(c, http.StatusBadRequest, response.ErrInvalidParams, err.Error())
+	default:
+		response.FailWithStatus(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+	}
+}
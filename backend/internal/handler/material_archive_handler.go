@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/middleware"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// MaterialArchiveHandler 资料归档处理器
+type MaterialArchiveHandler struct {
+	archiveService service.MaterialArchiveService
+}
+
+// NewMaterialArchiveHandler 创建资料归档处理器实例
+func NewMaterialArchiveHandler(archiveService service.MaterialArchiveService) *MaterialArchiveHandler {
+	return &MaterialArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// ListRules 获取归档规则
+// @Summary 获取归档规则
+// @Description 获取全部资料归档规则
+// @Tags 资料归档
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.MaterialArchiveRule}
+// @Router /api/v1/admin/archive-rules [get]
+func (h *MaterialArchiveHandler) ListRules(c *gin.Context) {
+	rules, err := h.archiveService.ListRules(c.Request.Context())
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// CreateRule 创建归档规则
+// @Summary 创建归档规则
+// @Description 按上传时间（age）、下载活跃度（inactivity）或开课学期（term）归档已通过的资料，notice_days 天前通知上传者
+// @Tags 资料归档
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.MaterialArchiveRuleRequest true "归档规则"
+// @Success 200 {object} response.Response{data=model.MaterialArchiveRule}
+// @Router /api/v1/admin/archive-rules [post]
+func (h *MaterialArchiveHandler) CreateRule(c *gin.Context) {
+	var req model.MaterialArchiveRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	adminID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+
+	rule, err := h.archiveService.CreateRule(c.Request.Context(), adminID, &req)
+	if err != nil {
+		handleMaterialArchiveError(c, err)
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// UpdateRule 更新归档规则
+// @Summary 更新归档规则
+// @Description 修改后已计划归档但不再命中规则的资料会在下次执行时取消
+// @Tags 资料归档
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "规则ID"
+// @Param request body model.MaterialArchiveRuleRequest true "归档规则"
+// @Success 200 {object} response.Response{data=model.MaterialArchiveRule}
+// @Router /api/v1/admin/archive-rules/{id} [put]
+func (h *MaterialArchiveHandler) UpdateRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的规则ID")
+		return
+	}
+
+	var req model.MaterialArchiveRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	rule, err := h.archiveService.UpdateRule(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		handleMaterialArchiveError(c, err)
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// DeleteRule 删除归档规则
+// @Summary 删除归档规则
+// @Description 删除后按该规则计划归档的资料会在下次执行时取消
+// @Tags 资料归档
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "规则ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/archive-rules/{id} [delete]
+func (h *MaterialArchiveHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的规则ID")
+		return
+	}
+
+	if err := h.archiveService.DeleteRule(c.Request.Context(), uint(id)); err != nil {
+		handleMaterialArchiveError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Run 手动执行归档
+// @Summary 执行资料归档
+// @Description 立即执行一次归档任务，dry_run 为 true 时只统计不通知、不归档
+// @Tags 资料归档
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.RunMaterialArchiveRequest false "执行参数"
+// @Success 200 {object} response.Response{data=model.MaterialArchiveReport}
+// @Router /api/v1/admin/archive-rules/run [post]
+func (h *MaterialArchiveHandler) Run(c *gin.Context) {
+	var req model.RunMaterialArchiveRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrInvalidParams, err.Error())
+			return
+		}
+	}
+
+	report, err := h.archiveService.Run(c.Request.Context(), req.DryRun)
+	if err != nil {
+		handleMaterialArchiveError(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// RestoreMaterial 从归档恢复资料
+// @Summary 从归档恢复资料
+// @Description 将已归档的资料恢复为已通过，移动到归档存储的文件会移回原位置；恢复后的资料在宽限期内不会再被自动归档
+// @Tags 资料归档
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Success 200 {object} response.Response{data=model.MaterialResponse}
+// @Router /api/v1/admin/materials/{id}/restore [post]
+func (h *MaterialArchiveHandler) RestoreMaterial(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+
+	material, err := h.archiveService.RestoreMaterial(c.Request.Context(), uint(id))
+	if err != nil {
+		handleMaterialArchiveError(c, err)
+		return
+	}
+
+	response.Success(c, material)
+}
+
+// handleMaterialArchiveError 将资料归档错误转换为响应
+func handleMaterialArchiveError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrMaterialArchiveRuleNotFound), errors.Is(err, service.ErrMaterialNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidMaterialArchiveRule),
+		errors.Is(err, service.ErrMaterialNotArchived),
+		errors.Is(err, service.ErrMaterialArchiveRunning):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/middleware"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// MaterialCommentHandler 资料评论处理器
+type MaterialCommentHandler struct {
+	commentService service.MaterialCommentService
+	reportService  service.ReportService
+}
+
+// NewMaterialCommentHandler 创建资料评论处理器实例
+func NewMaterialCommentHandler(commentService service.MaterialCommentService, reportService service.ReportService) *MaterialCommentHandler {
+	return &MaterialCommentHandler{
+		commentService: commentService,
+		reportService:  reportService,
+	}
+}
+
+// ListComments 获取资料评论
+// @Summary 获取资料评论
+// @Description 分页获取资料的顶层评论（按时间倒序），每条附带最早的 3 条回复
+// @Tags 评论
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/materials/{id}/comments [get]
+func (h *MaterialCommentHandler) ListComments(c *gin.Context) {
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+
+	var req model.CommentListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	comments, total, err := h.commentService.ListComments(c.Request.Context(), uint(materialID), userID, middleware.IsAdmin(c), &req)
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, comments)
+}
+
+// CreateComment 发表评论
+// @Summary 发表评论
+// @Description 发表顶层评论，或通过 parent_id 回复评论
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Param request body model.CreateCommentRequest true "评论内容"
+// @Success 200 {object} response.Response{data=model.CommentResponse}
+// @Router /api/v1/materials/{id}/comments [post]
+func (h *MaterialCommentHandler) CreateComment(c *gin.Context) {
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+
+	var req model.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	comment, err := h.commentService.CreateComment(c.Request.Context(), uint(materialID), userID, &req)
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	response.Success(c, comment)
+}
+
+// ListReplies 获取评论回复
+// @Summary 获取评论回复
+// @Description 分页获取顶层评论的全部回复（按时间正序）
+// @Tags 评论
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评论ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/comments/{id}/replies [get]
+func (h *MaterialCommentHandler) ListReplies(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的评论ID")
+		return
+	}
+
+	var req model.CommentListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	replies, total, err := h.commentService.ListReplies(c.Request.Context(), uint(commentID), userID, middleware.IsAdmin(c), &req)
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, replies)
+}
+
+// UpdateComment 编辑评论
+// @Summary 编辑评论
+// @Description 仅评论作者可以编辑，已隐藏或删除的评论不能编辑
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评论ID"
+// @Param request body model.UpdateCommentRequest true "评论内容"
+// @Success 200 {object} response.Response{data=model.CommentResponse}
+// @Router /api/v1/comments/{id} [put]
+func (h *MaterialCommentHandler) UpdateComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的评论ID")
+		return
+	}
+
+	var req model.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	comment, err := h.commentService.UpdateComment(c.Request.Context(), uint(commentID), userID, &req)
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	response.Success(c, comment)
+}
+
+// DeleteComment 删除评论
+// @Summary 删除评论
+// @Description 评论作者或管理员可以删除，仍有回复的顶层评论保留为已删除占位
+// @Tags 评论
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评论ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/comments/{id} [delete]
+func (h *MaterialCommentHandler) DeleteComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的评论ID")
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.commentService.DeleteComment(c.Request.Context(), uint(commentID), userID, middleware.IsAdmin(c)); err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ReportComment 举报评论
+// @Summary 举报评论
+// @Description 举报通过后评论会被隐藏
+// @Tags 举报
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评论ID"
+// @Param request body model.ReportRequest true "举报信息"
+// @Success 200 {object} response.Response
+// @Router /api/v1/comments/{id}/report [post]
+func (h *MaterialCommentHandler) ReportComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的评论ID")
+		return
+	}
+
+	var req model.ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.reportService.CreateCommentReport(c.Request.Context(), userID, uint(commentID), &req); err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ListAllComments 评论管理列表
+// @Summary 评论管理列表
+// @Description 管理员按资料、用户、状态和关键词筛选评论，返回隐藏评论的内容
+// @Tags 评论
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param material_id query int false "资料ID"
+// @Param user_id query int false "用户ID"
+// @Param status query string false "状态" Enums(visible, hidden, deleted)
+// @Param keyword query string false "关键词"
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/admin/comments [get]
+func (h *MaterialCommentHandler) ListAllComments(c *gin.Context) {
+	var req model.AdminCommentListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	comments, total, err := h.commentService.ListAllComments(c.Request.Context(), &req)
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, comments)
+}
+
+// ModerateComment 隐藏或恢复评论
+// @Summary 隐藏或恢复评论
+// @Description 隐藏评论时通知评论作者
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评论ID"
+// @Param request body model.ModerateCommentRequest true "处理方式"
+// @Success 200 {object} response.Response{data=model.CommentResponse}
+// @Router /api/v1/admin/comments/{id}/moderate [post]
+func (h *MaterialCommentHandler) ModerateComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的评论ID")
+		return
+	}
+
+	var req model.ModerateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+
+	comment, err := h.commentService.ModerateComment(c.Request.Context(), uint(commentID), adminID, &req)
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	response.Success(c, comment)
+}
+
+// handleCommentError 将评论错误转换为响应
+func handleCommentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrMaterialNotFound), errors.Is(err, service.ErrCommentNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrAccessDenied):
+		response.Error(c, response.ErrForbidden, err.Error())
+	case errors.Is(err, service.ErrInvalidComment), errors.Is(err, service.ErrCommentNotEditable):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
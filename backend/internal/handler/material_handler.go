@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/study-upc/backend/internal/model"
@@ -60,8 +64,15 @@ func (h *MaterialHandler) CreateMaterial(c *gin.Context) {
 
 	materialResp, err := h.materialService.CreateMaterial(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
+		if handleUploadedFileError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrInvalidTag) {
+			response.Error(c, response.ErrInvalidParams, err.Error())
+			return
+		}
 		switch err {
-		case service.ErrInvalidMaterialStatus:
+		case service.ErrInvalidMaterialStatus, service.ErrInvalidCourseTerm, service.ErrCourseNotFound:
 			response.Error(c, response.ErrInvalidParams, err.Error())
 		default:
 			response.Error(c, response.ErrInternal, err.Error())
@@ -111,12 +122,16 @@ func (h *MaterialHandler) UpdateMaterial(c *gin.Context) {
 
 	materialResp, err := h.materialService.UpdateMaterial(c.Request.Context(), uint(materialID), userID.(uint), userRole.(string), &req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidTag) {
+			response.Error(c, response.ErrInvalidParams, err.Error())
+			return
+		}
 		switch err {
 		case service.ErrMaterialNotFound:
 			response.Error(c, response.ErrNotFound, err.Error())
 		case service.ErrAccessDenied:
 			response.Error(c, response.ErrForbidden, err.Error())
-		case service.ErrMaterialAlreadyApproved:
+		case service.ErrMaterialAlreadyApproved, service.ErrInvalidCourseTerm, service.ErrCourseNotFound:
 			response.Error(c, response.ErrInvalidParams, err.Error())
 		default:
 			response.Error(c, response.ErrInternal, err.Error())
@@ -172,7 +187,9 @@ func (h *MaterialHandler) GetMaterial(c *gin.Context) {
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(20)
 // @Param category query string false "分类"
-// @Param course_name query string false "课程名称"
+// @Param course_name query string false "课程名称或别名"
+// @Param course_id query int false "课程ID"
+// @Param tags query []string false "标签（命中任一即可）"
 // @Param status query string false "状态"
 // @Param keyword query string false "搜索关键词"
 // @Param sort_by query string false "排序字段" default(created_at)
@@ -250,7 +267,7 @@ func (h *MaterialHandler) ListReviewedMaterials(c *gin.Context) {
 
 // ListPendingMaterials 获取待审核资料列表(管理员专用)
 // @Summary 获取待审核资料列表
-// @Description 管理员获取待审核的资料列表
+// @Description 管理员获取待审核的资料列表，文件内容与已通过资料相同的条目会通过 duplicate_of 标出
 // @Tags 资料
 // @Produce json
 // @Security BearerAuth
@@ -386,6 +403,10 @@ func (h *MaterialHandler) GetUploadSignature(c *gin.Context) {
 
 	signatureResp, err := h.materialService.GetUploadSignature(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
+		if errors.Is(err, service.ErrStorageQuotaExceeded) {
+			response.Error(c, response.ErrForbidden, err.Error())
+			return
+		}
 		response.Error(c, response.ErrInternal, err.Error())
 		return
 	}
@@ -429,12 +450,14 @@ func (h *MaterialHandler) DeleteUploadedFile(c *gin.Context) {
 
 // GetDownloadURL 获取下载链接
 // @Summary 获取下载链接
-// @Description 获取资料下载的预签名URL
+// @Description 获取资料的一次性下载地址（5 分钟内有效，兑换时才计入每日下载次数）。mode=presigned 兑换后重定向到短时有效的 OSS 预签名URL；mode=stream 由后端代理传输，PDF 可选添加用户水印。系统配置 download_mode 为 stream 时始终使用代理下载
 // @Tags 资料
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "资料ID"
-// @Success 200 {object} response.Response{data=map[string]string}
+// @Param mode query string false "下载方式" Enums(presigned, stream)
+// @Param watermark query bool false "代理下载 PDF 时添加用户水印"
+// @Success 200 {object} response.Response{data=model.DownloadLinkResponse}
 // @Router /api/v1/materials/{id}/download [get]
 func (h *MaterialHandler) GetDownloadURL(c *gin.Context) {
 	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -443,6 +466,12 @@ func (h *MaterialHandler) GetDownloadURL(c *gin.Context) {
 		return
 	}
 
+	var req model.DownloadLinkRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
 	// 从上下文获取用户 ID
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -450,22 +479,100 @@ func (h *MaterialHandler) GetDownloadURL(c *gin.Context) {
 		return
 	}
 
-	downloadURL, err := h.materialService.GetDownloadURL(c.Request.Context(), uint(materialID), userID.(uint))
+	link, err := h.materialService.GetDownloadLink(c.Request.Context(), uint(materialID), userID.(uint), c.ClientIP(), &req)
 	if err != nil {
 		switch err {
 		case service.ErrMaterialNotFound:
 			response.Error(c, response.ErrNotFound, err.Error())
 		case service.ErrAccessDenied:
 			response.Error(c, response.ErrForbidden, err.Error())
-		case service.ErrDownloadLimitExceeded:
+		case service.ErrDownloadLimitExceeded, service.ErrWeeklyDownloadLimitExceeded, service.ErrDownloadTooFrequent:
 			response.Error(c, response.ErrForbidden, err.Error())
 		default:
 			response.Error(c, response.ErrInternal, err.Error())
 		}
 		return
 	}
+	setDownloadURL(link)
 
-	response.Success(c, map[string]string{"download_url": downloadURL})
+	response.Success(c, link)
+}
+
+// setDownloadURL 根据下载方式填充下载令牌的兑换地址
+func setDownloadURL(link *model.DownloadLinkResponse) {
+	if link.Mode == model.DownloadModeStream {
+		link.DownloadURL = "/api/v1/downloads/stream/" + link.Token
+		return
+	}
+	link.DownloadURL = "/api/v1/downloads/redirect/" + link.Token
+}
+
+// RedirectDownload 兑换下载令牌
+// @Summary 兑换下载令牌
+// @Description 兑换一次性下载令牌并重定向到短时有效的预签名URL，兑换时计入每日下载次数
+// @Tags 资料
+// @Param token path string true "下载令牌"
+// @Success 302 "重定向到预签名下载URL"
+// @Router /api/v1/downloads/redirect/{token} [get]
+func (h *MaterialHandler) RedirectDownload(c *gin.Context) {
+	downloadURL, err := h.materialService.RedeemDownloadToken(c.Request.Context(), c.Param("token"), c.ClientIP())
+	if err != nil {
+		handleDownloadTokenError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, downloadURL)
+}
+
+// StreamDownload 代理下载
+// @Summary 代理下载
+// @Description 使用一次性令牌下载资料文件，支持 Range 请求。令牌首次使用后绑定客户端 IP，之后仅允许同一 IP 的 Range 请求续传。添加水印的 PDF 不支持 Range 请求，只能完整下载一次
+// @Tags 资料
+// @Produce octet-stream
+// @Param token path string true "下载令牌"
+// @Success 200 {file} binary
+// @Success 206 {file} binary
+// @Router /api/v1/downloads/stream/{token} [get]
+func (h *MaterialHandler) StreamDownload(c *gin.Context) {
+	resume := c.GetHeader("Range") != ""
+	stream, err := h.materialService.OpenDownloadStream(c.Request.Context(), c.Param("token"), c.ClientIP(), resume)
+	if err != nil {
+		handleDownloadTokenError(c, err)
+		return
+	}
+	defer stream.Content.Close()
+
+	c.Header("Content-Type", stream.MimeType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(stream.FileName)))
+	c.Header("Cache-Control", "private, no-store")
+	if stream.ETag != "" {
+		c.Header("ETag", fmt.Sprintf("%q", stream.ETag))
+	}
+	// 添加水印的内容忽略 Range 请求，完整返回
+	if stream.Watermarked {
+		c.Header("Accept-Ranges", "none")
+		c.DataFromReader(http.StatusOK, stream.Size, stream.MimeType, stream.Content, nil)
+		return
+	}
+	http.ServeContent(c.Writer, c.Request, stream.FileName, stream.ModTime, stream.Content)
+}
+
+// handleDownloadTokenError 处理兑换下载令牌的错误
+// 下载地址由浏览器直接访问，返回真实的 HTTP 状态码
+func handleDownloadTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrDownloadTokenInvalid), errors.Is(err, service.ErrMaterialNotFound):
+		response.FailWithStatus(c, http.StatusNotFound, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrDownloadTooFrequent):
+		response.FailWithStatus(c, http.StatusTooManyRequests, response.ErrForbidden, err.Error())
+	case errors.Is(err, service.ErrDownloadTokenUsed), errors.Is(err, service.ErrDownloadTokenIPMismatch),
+		errors.Is(err, service.ErrAccessDenied), errors.Is(err, service.ErrDownloadLimitExceeded),
+		errors.Is(err, service.ErrWeeklyDownloadLimitExceeded):
+		response.FailWithStatus(c, http.StatusForbidden, response.ErrForbidden, err.Error())
+	default:
+		response.FailWithStatus(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+	}
 }
 
 // SearchMaterials 搜索资料
@@ -845,7 +952,7 @@ func (h *MaterialHandler) ListDownloadRecords(c *gin.Context) {
 
 // GetDownloadQuota 获取下载配额
 // @Summary 获取下载配额
-// @Description 获取当前用户今日下载配额与剩余量
+// @Description 获取当前用户的下载配额：顶层字段为今日配额，windows 包含每分钟、每日和每周窗口的限制、已用次数和重置时间，boosts 为当前生效的临时提升
 // @Tags 下载记录
 // @Produce json
 // @Security BearerAuth
@@ -866,3 +973,27 @@ func (h *MaterialHandler) GetDownloadQuota(c *gin.Context) {
 
 	response.Success(c, quota)
 }
+
+// GetUploadQuota 获取存储配额
+// @Summary 获取存储配额
+// @Description 获取当前用户的资料存储配额与已使用空间
+// @Tags 资料
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.UploadQuotaResponse}
+// @Router /api/v1/uploads/quota [get]
+func (h *MaterialHandler) GetUploadQuota(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+
+	quota, err := h.materialService.GetUploadQuota(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.Success(c, quota)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/middleware"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/oss"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// MaterialImportHandler 资料批量导入处理器
+type MaterialImportHandler struct {
+	importService service.MaterialImportService
+}
+
+// NewMaterialImportHandler 创建资料批量导入处理器实例
+func NewMaterialImportHandler(importService service.MaterialImportService) *MaterialImportHandler {
+	return &MaterialImportHandler{
+		importService: importService,
+	}
+}
+
+// CreateImport 创建批量导入任务
+// @Summary 批量导入资料
+// @Description 使用已上传的 ZIP 压缩包批量创建待审核资料。压缩包中需包含 manifest.csv 或 manifest.json，
+// @Description 每行描述一个文件（file、title、course_name、category、description），导入在后台执行，可通过任务详情查看每行结果
+// @Tags 资料
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CreateMaterialImportRequest true "压缩包信息"
+// @Success 200 {object} response.Response{data=model.MaterialImport}
+// @Router /api/v1/materials/imports [post]
+func (h *MaterialImportHandler) CreateImport(c *gin.Context) {
+	var req model.CreateMaterialImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+
+	materialImport, err := h.importService.CreateImport(c.Request.Context(), userID, &req)
+	if err != nil {
+		handleMaterialImportError(c, err)
+		return
+	}
+
+	response.Success(c, materialImport)
+}
+
+// ListImports 获取批量导入任务列表
+// @Summary 获取我的批量导入任务
+// @Description 分页获取当前用户的批量导入任务（不含每行结果）
+// @Tags 资料
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/materials/imports [get]
+func (h *MaterialImportHandler) ListImports(c *gin.Context) {
+	var req model.MaterialImportListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+
+	imports, total, err := h.importService.ListImports(c.Request.Context(), userID, &req)
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, imports)
+}
+
+// GetImport 获取批量导入任务详情
+// @Summary 获取批量导入任务详情
+// @Description 获取导入任务的进度和每行的导入结果
+// @Tags 资料
+// @Produce json
+// @Security BearerAuth
+// @Param importId path int true "导入任务ID"
+// @Success 200 {object} response.Response{data=model.MaterialImport}
+// @Router /api/v1/materials/imports/{importId} [get]
+func (h *MaterialImportHandler) GetImport(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+	userRole, _ := middleware.GetUserRole(c)
+
+	importID, err := strconv.ParseUint(c.Param("importId"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的导入任务ID")
+		return
+	}
+
+	materialImport, err := h.importService.GetImport(c.Request.Context(), uint(importID), userID, userRole)
+	if err != nil {
+		handleMaterialImportError(c, err)
+		return
+	}
+
+	response.Success(c, materialImport)
+}
+
+// handleMaterialImportError 将批量导入错误转换为响应
+func handleMaterialImportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrMaterialImportNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrAccessDenied),
+		errors.Is(err, service.ErrFileKeyForbidden):
+		response.Error(c, response.ErrForbidden, err.Error())
+	case errors.Is(err, service.ErrInvalidImportArchive),
+		errors.Is(err, service.ErrInvalidFileKey),
+		errors.Is(err, service.ErrUploadedFileNotFound),
+		errors.Is(err, oss.ErrFileTooLarge):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
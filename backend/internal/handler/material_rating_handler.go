@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/middleware"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// MaterialRatingHandler 资料评分处理器
+type MaterialRatingHandler struct {
+	ratingService service.MaterialRatingService
+}
+
+// NewMaterialRatingHandler 创建资料评分处理器实例
+func NewMaterialRatingHandler(ratingService service.MaterialRatingService) *MaterialRatingHandler {
+	return &MaterialRatingHandler{
+		ratingService: ratingService,
+	}
+}
+
+// GetRating 获取资料评分
+// @Summary 获取资料评分
+// @Description 获取资料的平均分、评分人数及当前用户的评分
+// @Tags 评分
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Success 200 {object} response.Response{data=model.MaterialRatingResponse}
+// @Router /api/v1/materials/{id}/rating [get]
+func (h *MaterialRatingHandler) GetRating(c *gin.Context) {
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	rating, err := h.ratingService.GetRating(c.Request.Context(), userID, uint(materialID))
+	if err != nil {
+		handleRatingError(c, err)
+		return
+	}
+
+	response.Success(c, rating)
+}
+
+// RateMaterial 给资料评分
+// @Summary 给资料评分
+// @Description 1-5 分，重复评分会覆盖之前的分数，不能给自己上传的资料评分
+// @Tags 评分
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Param request body model.RateMaterialRequest true "评分"
+// @Success 200 {object} response.Response{data=model.MaterialRatingResponse}
+// @Router /api/v1/materials/{id}/rating [put]
+func (h *MaterialRatingHandler) RateMaterial(c *gin.Context) {
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+
+	var req model.RateMaterialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	rating, err := h.ratingService.RateMaterial(c.Request.Context(), userID, uint(materialID), &req)
+	if err != nil {
+		handleRatingError(c, err)
+		return
+	}
+
+	response.Success(c, rating)
+}
+
+// DeleteRating 撤销评分
+// @Summary 撤销评分
+// @Description 删除当前用户对资料的评分
+// @Tags 评分
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Success 200 {object} response.Response{data=model.MaterialRatingResponse}
+// @Router /api/v1/materials/{id}/rating [delete]
+func (h *MaterialRatingHandler) DeleteRating(c *gin.Context) {
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	rating, err := h.ratingService.DeleteRating(c.Request.Context(), userID, uint(materialID))
+	if err != nil {
+		handleRatingError(c, err)
+		return
+	}
+
+	response.Success(c, rating)
+}
+
+// handleRatingError 将评分错误转换为响应
+func handleRatingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrMaterialNotFound), errors.Is(err, service.ErrRatingNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrAccessDenied), errors.Is(err, service.ErrCannotRateOwnMaterial):
+		response.Error(c, response.ErrForbidden, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// FinalizeUpload 确认上传完成
+// @Summary 确认上传完成
+// @Description 校验文件已上传到 OSS 且大小、类型、校验值与声明一致，返回 OSS 中的实际文件信息
+// @Tags 资料
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.FinalizeUploadRequest true "文件信息"
+// @Success 200 {object} response.Response{data=model.FinalizeUploadResponse}
+// @Router /api/v1/materials/finalize-upload [post]
+func (h *MaterialHandler) FinalizeUpload(c *gin.Context) {
+	var req model.FinalizeUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+
+	result, err := h.materialService.FinalizeUpload(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if !handleUploadedFileError(c, err) {
+			response.Error(c, response.ErrInternal, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// handleUploadedFileError 处理上传文件校验错误，返回是否已处理
+func handleUploadedFileError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrFileKeyForbidden),
+		errors.Is(err, service.ErrStorageQuotaExceeded):
+		response.Error(c, response.ErrForbidden, err.Error())
+	case errors.Is(err, service.ErrUploadedFileNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidFileKey),
+		errors.Is(err, service.ErrFileKeyInUse),
+		errors.Is(err, service.ErrUploadedFileMismatch),
+		errors.Is(err, service.ErrFileTypeMismatch):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		return false
+	}
+	return true
+}
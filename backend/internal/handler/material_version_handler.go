@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// UploadVersion 上传资料新版本
+// @Summary 上传资料新版本
+// @Description 上传者或管理员为已有资料上传新文件，学委上传的版本需审核通过后生效
+// @Tags 资料版本
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Param request body model.UploadMaterialVersionRequest true "版本信息"
+// @Success 200 {object} response.Response{data=model.MaterialVersionResponse}
+// @Router /api/v1/materials/{id}/versions [post]
+func (h *MaterialHandler) UploadVersion(c *gin.Context) {
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+
+	var req model.UploadMaterialVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(string)
+
+	versionResp, err := h.materialService.UploadVersion(c.Request.Context(), uint(materialID), userID.(uint), role, &req)
+	if err != nil {
+		if handleUploadedFileError(c, err) {
+			return
+		}
+		switch err {
+		case service.ErrMaterialNotFound:
+			response.Error(c, response.ErrNotFound, err.Error())
+		case service.ErrAccessDenied:
+			response.Error(c, response.ErrForbidden, err.Error())
+		case service.ErrVersionFileKeyExists, service.ErrMaterialArchived:
+			response.Error(c, response.ErrInvalidParams, err.Error())
+		default:
+			response.Error(c, response.ErrInternal, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, versionResp)
+}
+
+// ListVersions 获取资料版本历史
+// @Summary 获取资料版本历史
+// @Description 获取资料的全部版本，普通用户只能看到已审核通过的版本
+// @Tags 资料版本
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Success 200 {object} response.Response{data=[]model.MaterialVersionResponse}
+// @Router /api/v1/materials/{id}/versions [get]
+func (h *MaterialHandler) ListVersions(c *gin.Context) {
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+
+	var currentUserID uint
+	if userID, exists := c.Get("user_id"); exists {
+		currentUserID = userID.(uint)
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(string)
+
+	versions, err := h.materialService.ListVersions(c.Request.Context(), uint(materialID), currentUserID, role)
+	if err != nil {
+		switch err {
+		case service.ErrMaterialNotFound:
+			response.Error(c, response.ErrNotFound, err.Error())
+		case service.ErrAccessDenied:
+			response.Error(c, response.ErrForbidden, err.Error())
+		default:
+			response.Error(c, response.ErrInternal, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, versions)
+}
+
+// GetVersionDownloadURL 获取指定版本的下载链接
+// @Summary 获取指定版本的下载链接
+// @Description 获取资料历史版本的一次性下载地址，兑换时计入每日下载次数。下载方式和水印规则与下载当前版本相同
+// @Tags 资料版本
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Param versionId path int true "版本ID"
+// @Param mode query string false "下载方式: presigned, stream"
+// @Param watermark query bool false "代理下载 PDF 时添加用户水印"
+// @Success 200 {object} response.Response{data=model.DownloadLinkResponse}
+// @Router /api/v1/materials/{id}/versions/{versionId}/download [get]
+func (h *MaterialHandler) GetVersionDownloadURL(c *gin.Context) {
+	materialID, versionID, ok := parseMaterialVersionParams(c)
+	if !ok {
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	role, _ := userRole.(string)
+
+	var req model.DownloadLinkRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	link, err := h.materialService.GetVersionDownloadURL(c.Request.Context(), materialID, versionID, userID.(uint), role, c.ClientIP(), &req)
+	if err != nil {
+		switch err {
+		case service.ErrMaterialNotFound, service.ErrMaterialVersionNotFound:
+			response.Error(c, response.ErrNotFound, err.Error())
+		case service.ErrAccessDenied, service.ErrDownloadLimitExceeded,
+			service.ErrWeeklyDownloadLimitExceeded, service.ErrDownloadTooFrequent:
+			response.Error(c, response.ErrForbidden, err.Error())
+		default:
+			response.Error(c, response.ErrInternal, err.Error())
+		}
+		return
+	}
+
+	setDownloadURL(link)
+
+	response.Success(c, link)
+}
+
+// ListPendingVersions 获取待审核的版本列表
+// @Summary 获取待审核的版本列表
+// @Description 管理员获取待审核的资料版本
+// @Tags 资料版本
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/admin/material-versions/pending [get]
+func (h *MaterialHandler) ListPendingVersions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	versions, total, err := h.materialService.ListPendingVersions(c.Request.Context(), page, pageSize)
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, page, pageSize, versions)
+}
+
+// ReviewVersion 审核资料版本
+// @Summary 审核资料版本
+// @Description 管理员审核资料的新版本，审核通过后该版本成为当前版本
+// @Tags 资料版本
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Param versionId path int true "版本ID"
+// @Param request body model.ReviewMaterialVersionRequest true "审核信息"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/materials/{id}/versions/{versionId}/review [post]
+func (h *MaterialHandler) ReviewVersion(c *gin.Context) {
+	materialID, versionID, ok := parseMaterialVersionParams(c)
+	if !ok {
+		return
+	}
+
+	var req model.ReviewMaterialVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+
+	if err := h.materialService.ReviewVersion(c.Request.Context(), materialID, versionID, userID.(uint), &req); err != nil {
+		switch err {
+		case service.ErrMaterialNotFound, service.ErrMaterialVersionNotFound:
+			response.Error(c, response.ErrNotFound, err.Error())
+		case service.ErrVersionAlreadyReviewed:
+			response.Error(c, response.ErrInvalidParams, err.Error())
+		default:
+			response.Error(c, response.ErrInternal, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// RollbackVersion 回滚资料版本
+// @Summary 回滚资料版本
+// @Description 管理员将资料回滚到指定的历史版本
+// @Tags 资料版本
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Param versionId path int true "版本ID"
+// @Success 200 {object} response.Response{data=model.MaterialResponse}
+// @Router /api/v1/admin/materials/{id}/versions/{versionId}/rollback [post]
+func (h *MaterialHandler) RollbackVersion(c *gin.Context) {
+	materialID, versionID, ok := parseMaterialVersionParams(c)
+	if !ok {
+		return
+	}
+
+	materialResp, err := h.materialService.RollbackVersion(c.Request.Context(), materialID, versionID)
+	if err != nil {
+		switch err {
+		case service.ErrMaterialNotFound, service.ErrMaterialVersionNotFound:
+			response.Error(c, response.ErrNotFound, err.Error())
+		case service.ErrVersionNotApproved, service.ErrVersionIsCurrent:
+			response.Error(c, response.ErrInvalidParams, err.Error())
+		default:
+			response.Error(c, response.ErrInternal, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, materialResp)
+}
+
+// parseMaterialVersionParams 解析路径中的资料ID和版本ID
+func parseMaterialVersionParams(c *gin.Context) (uint, uint, bool) {
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return 0, 0, false
+	}
+
+	versionID, err := strconv.ParseUint(c.Param("versionId"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的版本ID")
+		return 0, 0, false
+	}
+
+	return uint(materialID), uint(versionID), true
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// ProcessingJobHandler 资料后台处理任务处理器
+type ProcessingJobHandler struct {
+	processingService service.MaterialProcessingService
+}
+
+// NewProcessingJobHandler 创建资料后台处理任务处理器实例
+func NewProcessingJobHandler(processingService service.MaterialProcessingService) *ProcessingJobHandler {
+	return &ProcessingJobHandler{
+		processingService: processingService,
+	}
+}
+
+// ListJobs 获取处理任务列表
+// @Summary 获取资料后台处理任务
+// @Description 分页获取缩略图生成和全文提取任务，可按状态、任务类型和资料筛选
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param status query string false "任务状态" Enums(pending, running, succeeded, failed)
+// @Param job_type query string false "任务类型" Enums(preview, extract_text)
+// @Param material_id query int false "资料ID"
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/admin/processing-jobs [get]
+func (h *ProcessingJobHandler) ListJobs(c *gin.Context) {
+	var req model.ProcessingJobListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	jobs, total, err := h.processingService.ListJobs(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, jobs)
+}
+
+// RetryJob 重试已失败的处理任务
+// @Summary 重试资料后台处理任务
+// @Description 将已失败的任务重新加入队列，执行次数清零
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Success 200 {object} response.Response{data=model.ProcessingJob}
+// @Router /api/v1/admin/processing-jobs/{id}/retry [post]
+func (h *ProcessingJobHandler) RetryJob(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的任务ID")
+		return
+	}
+
+	job, err := h.processingService.RetryJob(c.Request.Context(), uint(jobID))
+	if err != nil {
+		handleProcessingJobError(c, err)
+		return
+	}
+
+	response.Success(c, job)
+}
+
+// RegeneratePreview 重新生成资料预览
+// @Summary 重新生成资料预览
+// @Description 为资料的当前文件重新生成首页缩略图和文本摘要
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "资料ID"
+// @Success 200 {object} response.Response{data=model.ProcessingJob}
+// @Router /api/v1/admin/materials/{id}/preview [post]
+func (h *ProcessingJobHandler) RegeneratePreview(c *gin.Context) {
+	materialID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的资料ID")
+		return
+	}
+
+	job, err := h.processingService.RegeneratePreview(c.Request.Context(), uint(materialID))
+	if err != nil {
+		handleProcessingJobError(c, err)
+		return
+	}
+
+	response.Success(c, job)
+}
+
+// handleProcessingJobError 将处理任务错误转换为响应
+func handleProcessingJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrProcessingJobNotFound),
+		errors.Is(err, service.ErrMaterialNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrProcessingJobNotRetryable):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
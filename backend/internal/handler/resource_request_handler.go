@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/middleware"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// ResourceRequestHandler 求资料处理器
+type ResourceRequestHandler struct {
+	requestService service.ResourceRequestService
+}
+
+// NewResourceRequestHandler 创建求资料处理器实例
+func NewResourceRequestHandler(requestService service.ResourceRequestService) *ResourceRequestHandler {
+	return &ResourceRequestHandler{
+		requestService: requestService,
+	}
+}
+
+// ListRequests 获取求资料列表
+// @Summary 获取求资料列表
+// @Description 默认按票数排序，可按状态、类型、课程和关键词筛选
+// @Tags 求资料
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param status query string false "状态" Enums(open, claimed, fulfilled, closed)
+// @Param category query string false "资料类型"
+// @Param course_id query int false "课程ID"
+// @Param course_name query string false "课程名称"
+// @Param keyword query string false "关键词"
+// @Param mine query bool false "只看我发布的"
+// @Param claimed_by_me query bool false "只看我认领的"
+// @Param sort_by query string false "排序字段" Enums(vote_count, created_at)
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/resource-requests [get]
+func (h *ResourceRequestHandler) ListRequests(c *gin.Context) {
+	var req model.ResourceRequestListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	requests, total, err := h.requestService.ListRequests(c.Request.Context(), userID, &req)
+	if err != nil {
+		handleResourceRequestError(c, err)
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, requests)
+}
+
+// GetRequest 获取求资料详情
+// @Summary 获取求资料详情
+// @Tags 求资料
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "求资料ID"
+// @Success 200 {object} response.Response{data=model.ResourceRequestResponse}
+// @Router /api/v1/resource-requests/{id} [get]
+func (h *ResourceRequestHandler) GetRequest(c *gin.Context) {
+	requestID, ok := parseResourceRequestID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.GetRequest(c.Request.Context(), userID, requestID)
+	if err != nil {
+		handleResourceRequestError(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
+
+// CreateRequest 发布求资料
+// @Summary 发布求资料
+// @Tags 求资料
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ResourceRequestRequest true "求资料信息"
+// @Success 200 {object} response.Response{data=model.ResourceRequestResponse}
+// @Router /api/v1/resource-requests [post]
+func (h *ResourceRequestHandler) CreateRequest(c *gin.Context) {
+	var req model.ResourceRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.CreateRequest(c.Request.Context(), userID, &req)
+	if err != nil {
+		handleResourceRequestError(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
+
+// UpdateRequest 编辑求资料
+// @Summary 编辑求资料
+// @Description 仅发布者可在求资料被认领前编辑
+// @Tags 求资料
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "求资料ID"
+// @Param request body model.ResourceRequestRequest true "求资料信息"
+// @Success 200 {object} response.Response{data=model.ResourceRequestResponse}
+// @Router /api/v1/resource-requests/{id} [put]
+func (h *ResourceRequestHandler) UpdateRequest(c *gin.Context) {
+	requestID, ok := parseResourceRequestID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ResourceRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.UpdateRequest(c.Request.Context(), userID, requestID, &req)
+	if err != nil {
+		handleResourceRequestError(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
+
+// CloseRequest 关闭求资料
+// @Summary 关闭求资料
+// @Description 发布者或管理员可关闭尚未完成的求资料
+// @Tags 求资料
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "求资料ID"
+// @Param request body model.CloseResourceRequestRequest false "关闭原因"
+// @Success 200 {object} response.Response
+// @Router /api/v1/resource-requests/{id}/close [post]
+func (h *ResourceRequestHandler) CloseRequest(c *gin.Context) {
+	requestID, ok := parseResourceRequestID(c)
+	if !ok {
+		return
+	}
+
+	var req model.CloseResourceRequestRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrInvalidParams, err.Error())
+			return
+		}
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.requestService.CloseRequest(c.Request.Context(), userID, requestID, middleware.IsAdmin(c), &req); err != nil {
+		handleResourceRequestError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Vote 为求资料投票
+// @Summary 为求资料投票
+// @Tags 求资料
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "求资料ID"
+// @Success 200 {object} response.Response{data=model.ResourceRequestResponse}
+// @Router /api/v1/resource-requests/{id}/vote [post]
+func (h *ResourceRequestHandler) Vote(c *gin.Context) {
+	requestID, ok := parseResourceRequestID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.Vote(c.Request.Context(), userID, requestID)
+	if err != nil {
+		handleResourceRequestError(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
+
+// Unvote 取消投票
+// @Summary 取消投票
+// @Tags 求资料
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "求资料ID"
+// @Success 200 {object} response.Response{data=model.ResourceRequestResponse}
+// @Router /api/v1/resource-requests/{id}/vote [delete]
+func (h *ResourceRequestHandler) Unvote(c *gin.Context) {
+	requestID, ok := parseResourceRequestID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.Unvote(c.Request.Context(), userID, requestID)
+	if err != nil {
+		handleResourceRequestError(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
+
+// ClaimRequest 认领求资料
+// @Summary 认领求资料(学委)
+// @Tags 求资料
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "求资料ID"
+// @Success 200 {object} response.Response{data=model.ResourceRequestResponse}
+// @Router /api/v1/resource-requests/{id}/claim [post]
+func (h *ResourceRequestHandler) ClaimRequest(c *gin.Context) {
+	requestID, ok := parseResourceRequestID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.ClaimRequest(c.Request.Context(), userID, requestID)
+	if err != nil {
+		handleResourceRequestError(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
+
+// ReleaseRequest 取消认领
+// @Summary 取消认领(学委)
+// @Description 认领人或管理员可取消认领，求资料回到等待认领状态
+// @Tags 求资料
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "求资料ID"
+// @Success 200 {object} response.Response{data=model.ResourceRequestResponse}
+// @Router /api/v1/resource-requests/{id}/claim [delete]
+func (h *ResourceRequestHandler) ReleaseRequest(c *gin.Context) {
+	requestID, ok := parseResourceRequestID(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.ReleaseRequest(c.Request.Context(), userID, requestID, middleware.IsAdmin(c))
+	if err != nil {
+		handleResourceRequestError(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
+
+// FulfillRequest 完成求资料
+// @Summary 完成求资料(学委)
+// @Description 关联已审核通过的资料，并通知发布者和投票用户
+// @Tags 求资料
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "求资料ID"
+// @Param request body model.FulfillResourceRequestRequest true "关联的资料"
+// @Success 200 {object} response.Response{data=model.ResourceRequestResponse}
+// @Router /api/v1/resource-requests/{id}/fulfill [post]
+func (h *ResourceRequestHandler) FulfillRequest(c *gin.Context) {
+	requestID, ok := parseResourceRequestID(c)
+	if !ok {
+		return
+	}
+
+	var req model.FulfillResourceRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	request, err := h.requestService.FulfillRequest(c.Request.Context(), userID, requestID, middleware.IsAdmin(c), &req)
+	if err != nil {
+		handleResourceRequestError(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
+
+// parseResourceRequestID 解析路径中的求资料ID
+func parseResourceRequestID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的求资料ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleResourceRequestError 将求资料错误转换为响应
+func handleResourceRequestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrResourceRequestNotFound),
+		errors.Is(err, service.ErrMaterialNotFound),
+		errors.Is(err, service.ErrCourseNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrAccessDenied):
+		response.Error(c, response.ErrForbidden, err.Error())
+	case errors.Is(err, service.ErrInvalidResourceRequest),
+		errors.Is(err, service.ErrResourceRequestNotActive),
+		errors.Is(err, service.ErrResourceRequestClaimed),
+		errors.Is(err, service.ErrResourceRequestNotClaimed),
+		errors.Is(err, service.ErrAlreadyVoted),
+		errors.Is(err, service.ErrVoteNotFound),
+		errors.Is(err, service.ErrCannotVoteOwnRequest):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
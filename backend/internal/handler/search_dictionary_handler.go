@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/middleware"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// SearchDictionaryHandler 搜索分词词典处理器
+type SearchDictionaryHandler struct {
+	dictionaryService service.SearchDictionaryService
+}
+
+// NewSearchDictionaryHandler 创建搜索分词词典处理器实例
+func NewSearchDictionaryHandler(dictionaryService service.SearchDictionaryService) *SearchDictionaryHandler {
+	return &SearchDictionaryHandler{
+		dictionaryService: dictionaryService,
+	}
+}
+
+// ListWords 获取分词词条列表
+// @Summary 获取分词词条列表(管理员)
+// @Description 返回管理员添加的自定义词条，内置词典和课程名称、别名不在此列
+// @Tags 搜索
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param keyword query string false "词条关键词"
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/admin/search/dictionary [get]
+func (h *SearchDictionaryHandler) ListWords(c *gin.Context) {
+	var req model.SearchDictionaryListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	words, total, err := h.dictionaryService.ListWords(c.Request.Context(), &req)
+	if err != nil {
+		handleSearchDictionaryError(c, err)
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, words)
+}
+
+// AddWord 添加分词词条
+// @Summary 添加分词词条(管理员)
+// @Description 添加后在后台重建资料的搜索向量
+// @Tags 搜索
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.SearchDictionaryWordRequest true "词条"
+// @Success 200 {object} response.Response{data=model.SearchDictionaryWord}
+// @Router /api/v1/admin/search/dictionary [post]
+func (h *SearchDictionaryHandler) AddWord(c *gin.Context) {
+	var req model.SearchDictionaryWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+
+	word, err := h.dictionaryService.AddWord(c.Request.Context(), adminID, &req)
+	if err != nil {
+		handleSearchDictionaryError(c, err)
+		return
+	}
+
+	response.Success(c, word)
+}
+
+// DeleteWord 删除分词词条
+// @Summary 删除分词词条(管理员)
+// @Description 删除后在后台重建资料的搜索向量
+// @Tags 搜索
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "词条ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/search/dictionary/{id} [delete]
+func (h *SearchDictionaryHandler) DeleteWord(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的词条ID")
+		return
+	}
+
+	if err := h.dictionaryService.DeleteWord(c.Request.Context(), uint(id)); err != nil {
+		handleSearchDictionaryError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Segment 预览分词结果
+// @Summary 预览分词结果(管理员)
+// @Description 用于检查词条是否生效，tokens 为解析关键词时的切分，index 为构建搜索向量时的切分
+// @Tags 搜索
+// @Produce json
+// @Security BearerAuth
+// @Param text query string true "待分词文本"
+// @Success 200 {object} response.Response{data=model.SegmentPreviewResponse}
+// @Router /api/v1/admin/search/segment [get]
+func (h *SearchDictionaryHandler) Segment(c *gin.Context) {
+	text := c.Query("text")
+	if text == "" {
+		response.Error(c, response.ErrInvalidParams, "待分词文本不能为空")
+		return
+	}
+
+	response.Success(c, h.dictionaryService.Segment(text))
+}
+
+// Reindex 重建搜索索引
+// @Summary 重建搜索索引(管理员)
+// @Description 在后台重建全部资料的搜索向量
+// @Tags 搜索
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.SearchReindexResponse}
+// @Router /api/v1/admin/search/reindex [post]
+func (h *SearchDictionaryHandler) Reindex(c *gin.Context) {
+	response.Success(c, &model.SearchReindexResponse{
+		Started: h.dictionaryService.Reindex(),
+	})
+}
+
+// handleSearchDictionaryError 将分词词典错误转换为响应
+func handleSearchDictionaryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrDictionaryWordNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrDictionaryWordExists),
+		errors.Is(err, service.ErrInvalidDictionaryWord):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/study-upc/backend/internal/middleware"
@@ -14,34 +15,39 @@ import (
 // SearchHandler 搜索处理器
 type SearchHandler struct {
 	searchService         service.SearchService
+	suggestService        service.SearchSuggestService
 	recommendationService service.RecommendationService
 }
 
 // NewSearchHandler 创建搜索处理器实例
 func NewSearchHandler(
 	searchService service.SearchService,
+	suggestService service.SearchSuggestService,
 	recommendationService service.RecommendationService,
 ) *SearchHandler {
 	return &SearchHandler{
 		searchService:         searchService,
+		suggestService:        suggestService,
 		recommendationService: recommendationService,
 	}
 }
 
 // Search 搜索资料
 // @Summary 搜索资料
-// @Description 根据关键词、分类、标签等条件搜索资料
+// @Description 根据关键词、分类、标签等条件搜索资料。关键词支持 "短语"、-排除、OR、括号分组和 course:/category:/tag: 字段前缀
 // @Tags 搜索与推荐
 // @Produce json
 // @Security Bearer
-// @Param keyword query string false "搜索关键词"
+// @Param keyword query string false "搜索关键词，如 高数 OR 线代 -答案"
 // @Param category query string false "分类"
-// @Param course_name query string false "课程名称"
+// @Param course_name query string false "课程名称或别名"
+// @Param course_id query int false "课程ID"
 // @Param tags query []string false "标签"
 // @Param start_date query string false "开始日期"
 // @Param end_date query string false "结束日期"
 // @Param sort_by query string false "排序字段"
 // @Param sort_order query string false "排序方向"
+// @Param fuzzy query bool false "模糊匹配，同时搜索拼写相近的词"
 // @Param page query int false "页码"
 // @Param page_size query int false "每页数量"
 // @Success 200 {object} response.Response{data=model.SearchResponse}
@@ -59,6 +65,10 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	// 执行搜索
 	result, err := h.searchService.Search(c.Request.Context(), userID, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearchQuery) {
+			response.Error(c, response.ErrInvalidParams, err.Error())
+			return
+		}
 		response.Error(c, response.ErrInternal, err.Error())
 		return
 	}
@@ -91,6 +101,35 @@ func (h *SearchHandler) GetHotKeywords(c *gin.Context) {
 	response.Success(c, keywords)
 }
 
+// Suggest 搜索联想
+// @Summary 搜索联想
+// @Description 输入时获取以输入内容开头的联想词（来自资料标题、课程名称、标签和热门搜索词），以及当前用户匹配的最近搜索。输入为空时只返回最近搜索
+// @Tags 搜索与推荐
+// @Produce json
+// @Security Bearer
+// @Param q query string false "已输入的内容"
+// @Param limit query int false "联想词数量，默认 8，最多 20"
+// @Success 200 {object} response.Response{data=model.SearchSuggestResponse}
+// @Router /api/v1/search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	limit := 0
+	if l := c.Query("limit"); l != "" {
+		if parsedLimit, err := strconv.Atoi(l); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	result, err := h.suggestService.Suggest(c.Request.Context(), userID, c.Query("q"), limit)
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
 // GetSearchHistory 获取搜索历史
 // @Summary 搜索历史
 // @Description 获取当前用户的搜索历史
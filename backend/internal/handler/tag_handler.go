@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// TagHandler 标签处理器
+type TagHandler struct {
+	tagService service.TagService
+}
+
+// NewTagHandler 创建标签处理器实例
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// ListPopular 获取热门标签
+// @Summary 获取热门标签
+// @Description 按使用该标签的已通过资料数排序
+// @Tags 标签
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "数量" default(20)
+// @Success 200 {object} response.Response{data=[]model.TagResponse}
+// @Router /api/v1/tags/popular [get]
+func (h *TagHandler) ListPopular(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	tags, err := h.tagService.ListPopularTags(c.Request.Context(), limit)
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.Success(c, tags)
+}
+
+// List 获取标签列表
+// @Summary 获取标签列表
+// @Description 分页获取全部标签（含未被资料使用的），按资料数排序
+// @Tags 标签
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param keyword query string false "关键词"
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/admin/tags [get]
+func (h *TagHandler) List(c *gin.Context) {
+	var req model.TagListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	tags, total, err := h.tagService.ListTags(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, req.Page, req.PageSize, tags)
+}
+
+// Rename 重命名标签
+// @Summary 重命名标签
+// @Description 新名称已被其他标签使用时请改用合并
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "标签ID"
+// @Param request body model.RenameTagRequest true "新名称"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/tags/{id} [put]
+func (h *TagHandler) Rename(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的标签ID")
+		return
+	}
+
+	var req model.RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	if err := h.tagService.RenameTag(c.Request.Context(), uint(id), &req); err != nil {
+		handleTagError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Merge 合并标签
+// @Summary 合并标签
+// @Description 将源标签的资料改为使用目标标签，并删除源标签
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.MergeTagsRequest true "合并参数"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/tags/merge [post]
+func (h *TagHandler) Merge(c *gin.Context) {
+	var req model.MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	if err := h.tagService.MergeTags(c.Request.Context(), &req); err != nil {
+		handleTagError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Delete 删除标签
+// @Summary 删除标签
+// @Description 删除标签及其与资料的关联
+// @Tags 标签
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "标签ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/tags/{id} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的标签ID")
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c.Request.Context(), uint(id)); err != nil {
+		handleTagError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleTagError 将标签错误转换为响应
+func handleTagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTagNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidTag), errors.Is(err, service.ErrTagAlreadyExists):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
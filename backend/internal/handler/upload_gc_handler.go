@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/middleware"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// UploadGCHandler 孤立上传文件清理处理器
+type UploadGCHandler struct {
+	uploadGCService service.UploadGCService
+}
+
+// NewUploadGCHandler 创建孤立上传文件清理处理器实例
+func NewUploadGCHandler(uploadGCService service.UploadGCService) *UploadGCHandler {
+	return &UploadGCHandler{
+		uploadGCService: uploadGCService,
+	}
+}
+
+// Run 手动执行孤立上传文件清理
+// @Summary 执行孤立上传文件清理
+// @Description 删除超过宽限期且未被任何资料引用的上传文件，dry_run 为 true 时只统计不删除
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.RunUploadGCRequest false "清理参数"
+// @Success 200 {object} response.Response{data=model.UploadGCReport}
+// @Router /api/v1/admin/uploads/gc [post]
+func (h *UploadGCHandler) Run(c *gin.Context) {
+	var req model.RunUploadGCRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrInvalidParams, err.Error())
+			return
+		}
+	}
+
+	var triggeredBy *uint
+	if userID, ok := middleware.GetUserID(c); ok {
+		triggeredBy = &userID
+	}
+
+	report, err := h.uploadGCService.Run(c.Request.Context(), req.DryRun, req.GraceHours, triggeredBy)
+	if err != nil {
+		switch err {
+		case service.ErrUploadGCRunning:
+			response.Error(c, response.ErrInvalidParams, err.Error())
+		default:
+			response.Error(c, response.ErrInternal, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// ListRuns 获取清理记录
+// @Summary 获取孤立上传文件清理记录
+// @Description 分页获取历史清理记录及回收空间统计
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=response.PaginateData}
+// @Router /api/v1/admin/uploads/gc/runs [get]
+func (h *UploadGCHandler) ListRuns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	runs, total, err := h.uploadGCService.ListRuns(c.Request.Context(), page, pageSize)
+	if err != nil {
+		response.Error(c, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.SuccessWithPaginate(c, total, page, pageSize, runs)
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/study-upc/backend/internal/middleware"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/oss"
+	"github.com/study-upc/backend/internal/pkg/response"
+	"github.com/study-upc/backend/internal/service"
+)
+
+// UploadSessionHandler 分片上传会话处理器
+type UploadSessionHandler struct {
+	uploadSessionService service.UploadSessionService
+}
+
+// NewUploadSessionHandler 创建分片上传会话处理器实例
+func NewUploadSessionHandler(uploadSessionService service.UploadSessionService) *UploadSessionHandler {
+	return &UploadSessionHandler{
+		uploadSessionService: uploadSessionService,
+	}
+}
+
+// CreateSession 创建分片上传会话
+// @Summary 创建分片上传会话
+// @Description 初始化大文件的分片上传，返回会话信息与分片数量
+// @Tags 资料
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CreateUploadSessionRequest true "文件信息"
+// @Success 200 {object} response.Response{data=model.UploadSessionResponse}
+// @Router /api/v1/materials/upload-sessions [post]
+func (h *UploadSessionHandler) CreateSession(c *gin.Context) {
+	var req model.CreateUploadSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return
+	}
+
+	session, err := h.uploadSessionService.CreateSession(c.Request.Context(), userID, &req)
+	if err != nil {
+		handleUploadSessionError(c, err)
+		return
+	}
+
+	response.Success(c, session)
+}
+
+// GetSession 获取分片上传会话
+// @Summary 获取分片上传会话
+// @Description 获取会话状态与已上传的分片，用于断点续传
+// @Tags 资料
+// @Produce json
+// @Security BearerAuth
+// @Param sessionId path int true "会话ID"
+// @Success 200 {object} response.Response{data=model.UploadSessionResponse}
+// @Router /api/v1/materials/upload-sessions/{sessionId} [get]
+func (h *UploadSessionHandler) GetSession(c *gin.Context) {
+	userID, sessionID, ok := parseUploadSessionParams(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.uploadSessionService.GetSession(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		handleUploadSessionError(c, err)
+		return
+	}
+
+	response.Success(c, session)
+}
+
+// PresignParts 获取分片上传地址
+// @Summary 获取分片上传地址
+// @Description 为指定的分片生成预签名上传URL，单次最多 100 个分片
+// @Tags 资料
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sessionId path int true "会话ID"
+// @Param request body model.PresignUploadPartsRequest true "分片编号"
+// @Success 200 {object} response.Response{data=model.PresignUploadPartsResponse}
+// @Router /api/v1/materials/upload-sessions/{sessionId}/parts [post]
+func (h *UploadSessionHandler) PresignParts(c *gin.Context) {
+	userID, sessionID, ok := parseUploadSessionParams(c)
+	if !ok {
+		return
+	}
+
+	var req model.PresignUploadPartsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrInvalidParams, err.Error())
+		return
+	}
+
+	result, err := h.uploadSessionService.PresignParts(c.Request.Context(), userID, sessionID, &req)
+	if err != nil {
+		handleUploadSessionError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// CompleteSession 完成分片上传
+// @Summary 完成分片上传
+// @Description 校验全部分片已上传并合并为完整文件，之后可使用 file_key 创建资料
+// @Tags 资料
+// @Produce json
+// @Security BearerAuth
+// @Param sessionId path int true "会话ID"
+// @Success 200 {object} response.Response{data=model.UploadSessionResponse}
+// @Router /api/v1/materials/upload-sessions/{sessionId}/complete [post]
+func (h *UploadSessionHandler) CompleteSession(c *gin.Context) {
+	userID, sessionID, ok := parseUploadSessionParams(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.uploadSessionService.CompleteSession(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		handleUploadSessionError(c, err)
+		return
+	}
+
+	response.Success(c, session)
+}
+
+// AbortSession 取消分片上传
+// @Summary 取消分片上传
+// @Description 取消上传并清理已上传的分片
+// @Tags 资料
+// @Produce json
+// @Security BearerAuth
+// @Param sessionId path int true "会话ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/materials/upload-sessions/{sessionId} [delete]
+func (h *UploadSessionHandler) AbortSession(c *gin.Context) {
+	userID, sessionID, ok := parseUploadSessionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uploadSessionService.AbortSession(c.Request.Context(), userID, sessionID); err != nil {
+		handleUploadSessionError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// parseUploadSessionParams 解析当前用户ID和路径中的会话ID
+func parseUploadSessionParams(c *gin.Context) (uint, uint, bool) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Error(c, response.ErrUnauthorized, "未认证")
+		return 0, 0, false
+	}
+
+	sessionID, err := strconv.ParseUint(c.Param("sessionId"), 10, 32)
+	if err != nil {
+		response.Error(c, response.ErrInvalidParams, "无效的会话ID")
+		return 0, 0, false
+	}
+
+	return userID, uint(sessionID), true
+}
+
+// handleUploadSessionError 将上传会话错误转换为响应
+func handleUploadSessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUploadSessionNotFound):
+		response.Error(c, response.ErrNotFound, err.Error())
+	case errors.Is(err, service.ErrStorageQuotaExceeded):
+		response.Error(c, response.ErrForbidden, err.Error())
+	case errors.Is(err, service.ErrUploadSessionClosed),
+		errors.Is(err, service.ErrUploadSessionExpired),
+		errors.Is(err, service.ErrUploadIncomplete),
+		errors.Is(err, service.ErrUploadedFileMismatch),
+		errors.Is(err, service.ErrTooManyUploadSessions),
+		errors.Is(err, oss.ErrInvalidPartSize),
+		errors.Is(err, oss.ErrInvalidPartNumber),
+		errors.Is(err, oss.ErrInvalidFileType),
+		errors.Is(err, oss.ErrFileTooLarge),
+		errors.Is(err, oss.ErrInvalidFileName):
+		response.Error(c, response.ErrInvalidParams, err.Error())
+	default:
+		response.Error(c, response.ErrInternal, err.Error())
+	}
+}
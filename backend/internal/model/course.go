@@ -0,0 +1,97 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Course 课程
+// 资料通过 CourseID 关联课程；课程名称和别名（如“高数”“高等数学A”）都会解析到同一门课程
+type Course struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Code        string        `gorm:"type:varchar(50);index" json:"code,omitempty"`        // 课程代码
+	Name        string        `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`  // 规范名称
+	Department  string        `gorm:"type:varchar(100);index" json:"department,omitempty"` // 开课院系
+	Semester    string        `gorm:"type:varchar(20)" json:"semester,omitempty"`          // 通常开课的学期，如“大一上”“秋季”
+	Description string        `gorm:"type:text" json:"description,omitempty"`              // 课程简介
+	Aliases     []CourseAlias `gorm:"foreignKey:CourseID" json:"-"`                        // 别名
+}
+
+// TableName 指定表名
+func (Course) TableName() string {
+	return "courses"
+}
+
+// CourseAlias 课程别名
+type CourseAlias struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	CourseID uint   `gorm:"not null;index" json:"course_id"`
+	Alias    string `gorm:"type:varchar(100);not null" json:"alias"`
+}
+
+// TableName 指定表名
+func (CourseAlias) TableName() string {
+	return "course_aliases"
+}
+
+// NormalizeCourseName 规范化课程名称：去掉首尾空白并合并连续空白，用于存储和比较
+// 比较时另外忽略大小写（如“C语言”与“c语言”）
+func NormalizeCourseName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// CourseRequest 创建/更新课程请求
+type CourseRequest struct {
+	Code        string   `json:"code" binding:"omitempty,max=50"`
+	Name        string   `json:"name" binding:"required,max=100"`
+	Aliases     []string `json:"aliases" binding:"omitempty,max=50,dive,max=100"`
+	Department  string   `json:"department" binding:"omitempty,max=100"`
+	Semester    string   `json:"semester" binding:"omitempty,max=20"`
+	Description string   `json:"description" binding:"omitempty,max=2000"`
+}
+
+// CourseListRequest 课程列表请求
+type CourseListRequest struct {
+	Page       int    `form:"page,default=1" binding:"min=1"`
+	PageSize   int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Keyword    string `form:"keyword" binding:"omitempty,max=100"` // 匹配课程代码、名称和别名
+	Department string `form:"department" binding:"omitempty,max=100"`
+}
+
+// CourseResponse 课程响应
+type CourseResponse struct {
+	ID            uint     `json:"id"`
+	Code          string   `json:"code,omitempty"`
+	Name          string   `json:"name"`
+	Aliases       []string `json:"aliases"`
+	Department    string   `json:"department,omitempty"`
+	Semester      string   `json:"semester,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	MaterialCount int64    `json:"material_count"` // 关联的资料数
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
+}
+
+// ToCourseResponse 将 Course 转换为 CourseResponse
+func (c *Course) ToCourseResponse() *CourseResponse {
+	aliases := make([]string, 0, len(c.Aliases))
+	for _, alias := range c.Aliases {
+		aliases = append(aliases, alias.Alias)
+	}
+	return &CourseResponse{
+		ID:          c.ID,
+		Code:        c.Code,
+		Name:        c.Name,
+		Aliases:     aliases,
+		Department:  c.Department,
+		Semester:    c.Semester,
+		Description: c.Description,
+		CreatedAt:   c.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   c.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,85 @@
+package model
+
+import "time"
+
+// DownloadWindow 下载次数统计窗口
+type DownloadWindow string
+
+const (
+	DownloadWindowBurst  DownloadWindow = "burst"  // 最近一分钟，限制短时间内的批量下载
+	DownloadWindowDaily  DownloadWindow = "daily"  // 当天
+	DownloadWindowWeekly DownloadWindow = "weekly" // 本周（周一开始）
+)
+
+// 下载限制来源
+const (
+	DownloadLimitSourceUser    = "user"    // 管理员为用户单独设置
+	DownloadLimitSourceRole    = "role"    // 按角色配置
+	DownloadLimitSourceDefault = "default" // 默认配置
+)
+
+// DownloadQuotaBoost 临时下载额度提升（如考试周）
+// 指定 UserID 时只对该用户生效；否则对 Role 角色生效，Role 为空时对所有用户生效
+type DownloadQuotaBoost struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID      *uint     `gorm:"index" json:"user_id,omitempty"`         // 生效用户
+	Role        UserRole  `gorm:"type:varchar(20)" json:"role,omitempty"` // 生效角色
+	ExtraDaily  int       `gorm:"not null;default:0" json:"extra_daily"`  // 每日额外下载次数
+	ExtraWeekly int       `gorm:"not null;default:0" json:"extra_weekly"` // 每周额外下载次数
+	StartsAt    time.Time `gorm:"not null" json:"starts_at"`              // 开始时间
+	EndsAt      time.Time `gorm:"not null;index" json:"ends_at"`          // 结束时间
+	Reason      string    `gorm:"type:varchar(255)" json:"reason"`        // 原因，如“期末考试周”
+	CreatedBy   uint      `gorm:"not null" json:"created_by"`             // 创建的管理员
+}
+
+// TableName 指定表名
+func (DownloadQuotaBoost) TableName() string {
+	return "download_quota_boosts"
+}
+
+// IsActive 在指定时间是否生效
+func (b *DownloadQuotaBoost) IsActive(at time.Time) bool {
+	return !at.Before(b.StartsAt) && at.Before(b.EndsAt)
+}
+
+// CreateDownloadQuotaBoostRequest 创建临时下载额度提升请求
+type CreateDownloadQuotaBoostRequest struct {
+	UserID      *uint      `json:"user_id"`                                                // 生效用户，与 role 二选一
+	Role        UserRole   `json:"role" binding:"omitempty,oneof=student committee admin"` // 生效角色，都为空时对所有用户生效
+	ExtraDaily  int        `json:"extra_daily" binding:"min=0"`
+	ExtraWeekly int        `json:"extra_weekly" binding:"min=0"`
+	StartsAt    *time.Time `json:"starts_at"` // 为空时立即生效
+	EndsAt      time.Time  `json:"ends_at" binding:"required"`
+	Reason      string     `json:"reason" binding:"max=255"`
+}
+
+// DownloadQuotaBoostListRequest 临时下载额度提升列表请求
+type DownloadQuotaBoostListRequest struct {
+	Page       int  `form:"page" binding:"omitempty,min=1"`
+	PageSize   int  `form:"page_size" binding:"omitempty,min=1,max=100"`
+	UserID     uint `form:"user_id"`
+	ActiveOnly bool `form:"active_only"` // 只返回当前生效的
+}
+
+// UpdateUserDownloadLimitsRequest 设置用户下载限制请求
+// 各项为空时恢复按角色配置，0 表示不限制
+type UpdateUserDownloadLimitsRequest struct {
+	DailyLimit  *int `json:"daily_limit" binding:"omitempty,min=0"`
+	WeeklyLimit *int `json:"weekly_limit" binding:"omitempty,min=0"`
+	BurstLimit  *int `json:"burst_limit" binding:"omitempty,min=0"`
+}
+
+// DownloadQuotaWindow 单个统计窗口的下载配额
+type DownloadQuotaWindow struct {
+	Window    DownloadWindow `json:"window"`
+	Limit     int            `json:"limit"`     // 含临时提升的限制次数，0 表示不限制
+	Boost     int            `json:"boost"`     // 其中临时提升的次数
+	Used      int64          `json:"used"`      // 窗口内已下载次数
+	Remaining int64          `json:"remaining"` // 剩余次数，不限制时为 -1
+	Unlimited bool           `json:"unlimited"`
+	Source    string         `json:"source"`   // 限制来源：user / role / default
+	ResetAt   time.Time      `json:"reset_at"` // 窗口重置时间
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxCollectionsPerUser 每个用户最多创建的收藏夹数
+	MaxCollectionsPerUser = 50
+	// MaxItemsPerCollection 每个收藏夹最多收录的资料数
+	MaxItemsPerCollection = 500
+)
+
+// FavoriteCollection 收藏夹
+// 收藏夹建立在收藏之上：加入收藏夹的资料会自动收藏，取消收藏时从所有收藏夹移除。
+// 全部收藏（/favorites）即默认收藏夹，不在本表中保存
+type FavoriteCollection struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	UserID      uint    `gorm:"not null;index" json:"user_id"`
+	User        *User   `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Name        string  `gorm:"type:varchar(30);not null" json:"name"`
+	Description string  `gorm:"type:varchar(500)" json:"description"`
+	Position    int     `gorm:"not null;default:0" json:"position"`    // 在用户收藏夹列表中的位置，越小越靠前
+	ShareToken  *string `gorm:"type:varchar(64);uniqueIndex" json:"-"` // 公开分享令牌，为空表示未分享
+	ItemCount   int     `gorm:"not null;default:0" json:"item_count"`  // 收录的资料数
+}
+
+// TableName 指定表名
+func (FavoriteCollection) TableName() string {
+	return "favorite_collections"
+}
+
+// IsShared 是否已开启公开分享
+func (c *FavoriteCollection) IsShared() bool {
+	return c.ShareToken != nil && *c.ShareToken != ""
+}
+
+// FavoriteCollectionItem 收藏夹中的资料
+type FavoriteCollectionItem struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	CollectionID uint      `gorm:"not null;uniqueIndex:idx_collection_material" json:"collection_id"`
+	MaterialID   uint      `gorm:"not null;uniqueIndex:idx_collection_material;index" json:"material_id"`
+	Material     *Material `gorm:"foreignKey:MaterialID" json:"material,omitempty"`
+	Note         string    `gorm:"type:varchar(500)" json:"note"`      // 用户备注
+	Position     int       `gorm:"not null;default:0" json:"position"` // 在收藏夹中的位置，越小越靠前
+}
+
+// TableName 指定表名
+func (FavoriteCollectionItem) TableName() string {
+	return "favorite_collection_items"
+}
+
+// CollectionRequest 创建或更新收藏夹请求
+type CollectionRequest struct {
+	Name        string `json:"name" binding:"required,max=30"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+// AddCollectionItemRequest 向收藏夹添加资料请求
+type AddCollectionItemRequest struct {
+	MaterialID uint   `json:"material_id" binding:"required"`
+	Note       string `json:"note" binding:"max=500"`
+}
+
+// UpdateCollectionItemRequest 更新收藏夹资料备注请求
+type UpdateCollectionItemRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// ReorderRequest 调整顺序请求，IDs 为排序后的完整ID列表
+type ReorderRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1"`
+}
+
+// CollectionItemListRequest 收藏夹资料列表请求
+type CollectionItemListRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// CollectionResponse 收藏夹响应
+type CollectionResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Position    int       `json:"position"`
+	ItemCount   int       `json:"item_count"`
+	Shared      bool      `json:"shared"`
+	ShareToken  string    `json:"share_token,omitempty"` // 仅收藏夹所有者可见
+	Owner       *UserInfo `json:"owner,omitempty"`       // 仅公开分享时返回
+	CreatedAt   string    `json:"created_at"`
+	UpdatedAt   string    `json:"updated_at"`
+}
+
+// CollectionItemResponse 收藏夹资料响应
+type CollectionItemResponse struct {
+	MaterialID uint              `json:"material_id"`
+	Material   *MaterialResponse `json:"material,omitempty"`
+	Note       string            `json:"note"`
+	Position   int               `json:"position"`
+	CreatedAt  string            `json:"created_at"`
+}
+
+// ToCollectionResponse 将 FavoriteCollection 转换为 CollectionResponse
+// owner 为 true 时返回分享令牌
+func (c *FavoriteCollection) ToCollectionResponse(owner bool) *CollectionResponse {
+	response := &CollectionResponse{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		Position:    c.Position,
+		ItemCount:   c.ItemCount,
+		Shared:      c.IsShared(),
+		CreatedAt:   c.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   c.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if owner && c.IsShared() {
+		response.ShareToken = *c.ShareToken
+	}
+	if !owner && c.User != nil {
+		userInfo := c.User.ToUserInfo()
+		response.Owner = &userInfo
+	}
+	return response
+}
+
+// ToCollectionItemResponse 将 FavoriteCollectionItem 转换为 CollectionItemResponse
+func (i *FavoriteCollectionItem) ToCollectionItemResponse() *CollectionItemResponse {
+	response := &CollectionItemResponse{
+		MaterialID: i.MaterialID,
+		Note:       i.Note,
+		Position:   i.Position,
+		CreatedAt:  i.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if i.Material != nil {
+		response.Material = i.Material.ToMaterialResponse()
+	}
+	return response
+}
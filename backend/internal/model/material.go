@@ -36,8 +36,14 @@ const (
 	StatusApproved  MaterialStatus = "approved"  // 已通过
 	StatusRejected  MaterialStatus = "rejected"  // 已拒绝
 	StatusDeleted   MaterialStatus = "deleted"   // 已删除
+	StatusArchived  MaterialStatus = "archived"  // 已归档（不出现在默认列表和搜索中，仍可查看和下载）
 )
 
+// IsPublic 资料是否对所有用户可见
+func (s MaterialStatus) IsPublic() bool {
+	return s == StatusApproved || s == StatusArchived
+}
+
 // ReportStatus 举报状态
 type ReportStatus string
 
@@ -58,7 +64,10 @@ type Material struct {
 	Description     string                `gorm:"type:text" json:"description"`                                                // 资料描述
 	Category        MaterialCategoryType  `gorm:"type:varchar(50);not null;index:idx_category" json:"category"`                // 分类代码
 	CategoryInfo    *MaterialCategory     `gorm:"foreignKey:Category;references:Code" json:"category_info,omitempty"`          // 分类信息
-	CourseName      string                `gorm:"type:varchar(100);index" json:"course_name"`                                   // 课程名称
+	CourseName      string                `gorm:"type:varchar(100);index" json:"course_name"`                                   // 课程名称（上传者填写）
+	CourseID        *uint                 `gorm:"index" json:"course_id,omitempty"`                                           // 关联的课程ID，按课程名称或别名解析
+	CourseTerm      string                `gorm:"type:varchar(20);index" json:"course_term,omitempty"`                          // 开课学期，如 2024-2025-1
+	Tags            []Tag                 `gorm:"many2many:material_tags" json:"tags,omitempty"`                              // 标签
 	UploaderID      uint                  `gorm:"not null;index:idx_uploader" json:"uploader_id"`                               // 上传者ID
 	Uploader        *User                 `gorm:"foreignKey:UploaderID" json:"uploader,omitempty"`                             // 上传者信息
 	Status          MaterialStatus        `gorm:"type:varchar(20);not null;default:'pending';index:idx_status" json:"status"`  // 状态
@@ -66,14 +75,30 @@ type Material struct {
 	FileSize        int64                 `gorm:"not null" json:"file_size"`                                                    // 文件大小（字节）
 	FileKey         string                `gorm:"type:varchar(500);not null;uniqueIndex" json:"file_key"`                      // OSS 存储键
 	MimeType        string                `gorm:"type:varchar(100);not null" json:"mime_type"`                                 // MIME 类型
+	ContentHash     string                `gorm:"type:varchar(64);index" json:"content_hash,omitempty"`                        // 文件内容 SHA-256
+	DetectedMimeType string               `gorm:"type:varchar(100)" json:"detected_mime_type,omitempty"`                      // 根据文件头检测到的真实类型
+	MimeMismatch    bool                  `gorm:"not null;default:false;index" json:"mime_mismatch"`                          // 检测类型与声明类型是否不一致
 	DownloadCount   int                   `gorm:"not null;default:0" json:"download_count"`                                    // 下载次数
 	FavoriteCount   int                   `gorm:"not null;default:0" json:"favorite_count"`                                    // 收藏次数
 	ViewCount       int                   `gorm:"not null;default:0" json:"view_count"`                                        // 浏览次数
+	RatingAverage   float64               `gorm:"type:numeric(3,2);not null;default:0;index" json:"rating_average"`            // 平均评分（1-5，无评分时为 0）
+	RatingCount     int                   `gorm:"not null;default:0" json:"rating_count"`                                      // 评分人数
+	CommentCount    int                   `gorm:"not null;default:0" json:"comment_count"`                                     // 评论数（不含隐藏和已删除）
 	ReviewerID      *uint                 `gorm:"index" json:"reviewer_id,omitempty"`                                         // 审核人ID
 	Reviewer        *User                 `gorm:"foreignKey:ReviewerID" json:"reviewer,omitempty"`                            // 审核人信息
 	ReviewedAt      *time.Time            `json:"reviewed_at,omitempty"`                                                      // 审核时间
 	RejectionReason string                `gorm:"type:text" json:"rejection_reason,omitempty"`                                // 拒绝原因
+	CurrentVersionID *uint                `gorm:"index" json:"current_version_id,omitempty"`                                  // 当前生效版本ID
+	CurrentVersion  int                   `gorm:"not null;default:1" json:"current_version"`                                  // 当前生效版本号
+	ThumbnailKey    string                `gorm:"type:varchar(500)" json:"-"`                                                 // 首页缩略图 OSS 存储键
+	PreviewExcerpt  string                `gorm:"type:text" json:"preview_excerpt,omitempty"`                                 // 文本摘要
+	PreviewStatus   PreviewStatus         `gorm:"type:varchar(20);not null;default:'pending'" json:"preview_status"`          // 预览生成状态
+	ContentText     string                `gorm:"type:text" json:"-"`                                                         // 从文件中提取的全文（有长度上限），以最低权重计入搜索向量
 	SearchVector    string                `gorm:"type:tsvector;index:idx_search,gin" json:"-"`                                // 全文搜索向量
+	ArchiveRuleID   *uint                 `gorm:"index" json:"archive_rule_id,omitempty"`                                     // 命中的归档规则ID
+	ArchiveScheduledAt *time.Time         `gorm:"index" json:"archive_scheduled_at,omitempty"`                                // 计划归档时间（已提前通知上传者）
+	ArchivedAt      *time.Time            `json:"archived_at,omitempty"`                                                      // 归档时间
+	ArchiveRestoredAt *time.Time          `json:"archive_restored_at,omitempty"`                                              // 最近一次从归档恢复的时间
 }
 
 // TableName 指定表名
@@ -110,6 +135,7 @@ type DownloadRecord struct {
 	User       *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	MaterialID uint      `gorm:"not null;index:idx_material_download" json:"material_id"`
 	Material   *Material `gorm:"foreignKey:MaterialID" json:"material,omitempty"`
+	VersionID  *uint     `gorm:"index" json:"version_id,omitempty"` // 下载的资料版本ID
 }
 
 // TableName 指定表名
@@ -128,6 +154,8 @@ type Report struct {
 	User         *User          `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	MaterialID   uint           `gorm:"not null;index:idx_material_report" json:"material_id"`
 	Material     *Material      `gorm:"foreignKey:MaterialID" json:"material,omitempty"`
+	CommentID    *uint          `gorm:"index" json:"comment_id,omitempty"`                                 // 被举报的评论，为空时举报的是资料本身
+	Comment      *MaterialComment `gorm:"foreignKey:CommentID" json:"comment,omitempty"`
 	Reason       string         `gorm:"type:varchar(50);not null" json:"reason"`                     // 举报原因
 	Description  string         `gorm:"type:text" json:"description"`                                // 详细描述
 	Status       ReportStatus   `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`   // 处理状态
@@ -142,6 +170,11 @@ func (Report) TableName() string {
 	return "reports"
 }
 
+// IsCommentReport 是否为评论举报
+func (r *Report) IsCommentReport() bool {
+	return r.CommentID != nil
+}
+
 // 举报原因常量
 const (
 	ReportReasonInappropriate = "inappropriate" // 内容不当
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"regexp"
+	"time"
+)
+
+// MaterialArchiveRuleType 归档规则类型
+type MaterialArchiveRuleType string
+
+const (
+	ArchiveRuleAge        MaterialArchiveRuleType = "age"        // 按上传时间：超过指定月数
+	ArchiveRuleInactivity MaterialArchiveRuleType = "inactivity" // 按活跃度：指定月数内没有下载
+	ArchiveRuleTerm       MaterialArchiveRuleType = "term"       // 按开课学期：早于指定学期
+)
+
+// courseTermPattern 开课学期格式：起始学年-结束学年-学期序号，如 2024-2025-1
+var courseTermPattern = regexp.MustCompile(`^\d{4}-\d{4}-[123]$`)
+
+// IsValidCourseTerm 开课学期格式是否正确，学期字符串可直接按字典序比较先后
+func IsValidCourseTerm(term string) bool {
+	return courseTermPattern.MatchString(term)
+}
+
+// MaterialArchiveRule 资料归档规则
+// 定时任务找出命中规则的已通过资料，提前通知上传者，到期后将资料状态改为已归档
+type MaterialArchiveRule struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name              string                  `gorm:"type:varchar(100);not null" json:"name"`              // 规则名称
+	Type              MaterialArchiveRuleType `gorm:"type:varchar(20);not null" json:"type"`               // 规则类型
+	Enabled           bool                    `gorm:"not null;default:true" json:"enabled"`                // 是否启用
+	Category          MaterialCategoryType    `gorm:"type:varchar(50)" json:"category,omitempty"`          // 只对该分类生效，为空时对全部分类生效
+	AgeMonths         int                     `gorm:"not null;default:0" json:"age_months,omitempty"`      // age: 上传超过的月数
+	InactiveMonths    int                     `gorm:"not null;default:0" json:"inactive_months,omitempty"` // inactivity: 没有下载的月数
+	BeforeTerm        string                  `gorm:"type:varchar(20)" json:"before_term,omitempty"`       // term: 开课学期早于该学期
+	NoticeDays        int                     `gorm:"not null;default:7" json:"notice_days"`               // 提前通知上传者的天数，0 表示不通知直接归档
+	TransitionStorage bool                    `gorm:"not null;default:false" json:"transition_storage"`    // 归档时是否将文件移动到归档存储前缀
+	CreatedBy         uint                    `gorm:"not null" json:"created_by"`                          // 创建的管理员
+	LastRunAt         *time.Time              `json:"last_run_at,omitempty"`                               // 最近一次执行时间
+}
+
+// TableName 指定表名
+func (MaterialArchiveRule) TableName() string {
+	return "material_archive_rules"
+}
+
+// MaterialArchiveRuleRequest 创建/更新归档规则请求
+type MaterialArchiveRuleRequest struct {
+	Name              string                  `json:"name" binding:"required,max=100"`
+	Type              MaterialArchiveRuleType `json:"type" binding:"required,oneof=age inactivity term"`
+	Enabled           *bool                   `json:"enabled"` // 为空时默认启用
+	Category          MaterialCategoryType    `json:"category" binding:"omitempty,max=50"`
+	AgeMonths         int                     `json:"age_months" binding:"min=0,max=240"`
+	InactiveMonths    int                     `json:"inactive_months" binding:"min=0,max=240"`
+	BeforeTerm        string                  `json:"before_term" binding:"omitempty,max=20"`
+	NoticeDays        *int                    `json:"notice_days" binding:"omitempty,min=0,max=90"` // 为空时默认 7 天
+	TransitionStorage bool                    `json:"transition_storage"`
+}
+
+// RunMaterialArchiveRequest 手动执行归档请求
+type RunMaterialArchiveRequest struct {
+	DryRun bool `json:"dry_run"` // 是否为演练模式（只统计不通知、不归档）
+}
+
+// 归档任务对单个资料执行的操作
+const (
+	ArchiveActionNotified  = "notified"  // 已通知上传者，等待到期归档
+	ArchiveActionArchived  = "archived"  // 已归档
+	ArchiveActionCancelled = "cancelled" // 不再命中规则，取消计划归档
+	ArchiveActionFailed    = "failed"    // 归档失败
+)
+
+// MaterialArchiveItem 归档任务处理的资料
+type MaterialArchiveItem struct {
+	MaterialID  uint       `json:"material_id"`
+	Title       string     `json:"title"`
+	RuleID      uint       `json:"rule_id,omitempty"`
+	Action      string     `json:"action"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// MaterialArchiveReport 归档任务报告
+type MaterialArchiveReport struct {
+	DryRun         bool                   `json:"dry_run"`
+	RuleCount      int                    `json:"rule_count"`      // 执行的规则数
+	NotifiedCount  int                    `json:"notified_count"`  // 新通知的资料数
+	ArchivedCount  int                    `json:"archived_count"`  // 归档的资料数
+	CancelledCount int                    `json:"cancelled_count"` // 取消计划归档的资料数
+	FailedCount    int                    `json:"failed_count"`    // 归档失败的资料数
+	Items          []*MaterialArchiveItem `json:"items"`           // 明细（最多返回前 200 条）
+	StartedAt      time.Time              `json:"started_at"`
+	FinishedAt     time.Time              `json:"finished_at"`
+}
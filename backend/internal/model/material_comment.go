@@ -0,0 +1,136 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CommentStatus 评论状态
+type CommentStatus string
+
+const (
+	CommentVisible CommentStatus = "visible" // 正常显示
+	CommentHidden  CommentStatus = "hidden"  // 被管理员隐藏
+	CommentDeleted CommentStatus = "deleted" // 作者已删除，但仍有回复，保留占位
+)
+
+// MaxCommentLength 评论最大长度（字符数）
+const MaxCommentLength = 1000
+
+// MaterialComment 资料评论
+// 评论分两层：RootID 为空的是顶层评论，回复的 RootID 指向所在的顶层评论，ParentID 指向被回复的评论
+type MaterialComment struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	MaterialID    uint          `gorm:"not null;index" json:"material_id"`
+	UserID        uint          `gorm:"not null;index" json:"user_id"`
+	User          *User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	RootID        *uint         `gorm:"index" json:"root_id,omitempty"`                          // 所在的顶层评论
+	ParentID      *uint         `gorm:"index" json:"parent_id,omitempty"`                        // 被回复的评论
+	ReplyToUserID *uint         `json:"reply_to_user_id,omitempty"`                              // 被回复的用户
+	ReplyToUser   *User         `gorm:"foreignKey:ReplyToUserID" json:"reply_to_user,omitempty"` // 被回复的用户信息
+	Content       string        `gorm:"type:text;not null" json:"content"`                       // 评论内容
+	Status        CommentStatus `gorm:"type:varchar(20);not null;default:'visible';index" json:"status"`
+	ReplyCount    int           `gorm:"not null;default:0" json:"reply_count"` // 顶层评论的回复数（不含已删除）
+	EditedAt      *time.Time    `json:"edited_at,omitempty"`                   // 最近一次编辑时间
+	HiddenBy      *uint         `json:"hidden_by,omitempty"`                   // 隐藏的管理员
+	HiddenAt      *time.Time    `json:"hidden_at,omitempty"`                   // 隐藏时间
+	HiddenReason  string        `gorm:"type:varchar(500)" json:"hidden_reason,omitempty"`
+}
+
+// TableName 指定表名
+func (MaterialComment) TableName() string {
+	return "material_comments"
+}
+
+// CreateCommentRequest 发表评论请求
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required,max=1000"`
+	ParentID *uint  `json:"parent_id"` // 回复的评论，为空时发表顶层评论
+}
+
+// UpdateCommentRequest 编辑评论请求
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,max=1000"`
+}
+
+// CommentListRequest 资料评论列表请求
+type CommentListRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=20" binding:"min=1,max=50"`
+}
+
+// AdminCommentListRequest 评论管理列表请求
+type AdminCommentListRequest struct {
+	Page       int           `form:"page,default=1" binding:"min=1"`
+	PageSize   int           `form:"page_size,default=20" binding:"min=1,max=100"`
+	MaterialID *uint         `form:"material_id"`
+	UserID     *uint         `form:"user_id"`
+	Status     CommentStatus `form:"status" binding:"omitempty,oneof=visible hidden deleted"`
+	Keyword    string        `form:"keyword" binding:"omitempty,max=100"`
+}
+
+// ModerateCommentRequest 管理员处理评论请求
+type ModerateCommentRequest struct {
+	Hidden bool   `json:"hidden"`                             // true 隐藏，false 恢复显示
+	Reason string `json:"reason" binding:"omitempty,max=500"` // 隐藏原因，会通知评论作者
+}
+
+// CommentResponse 评论响应
+type CommentResponse struct {
+	ID           uint               `json:"id"`
+	MaterialID   uint               `json:"material_id"`
+	UserID       uint               `json:"user_id"`
+	User         *UserInfo          `json:"user,omitempty"`
+	RootID       *uint              `json:"root_id,omitempty"`
+	ParentID     *uint              `json:"parent_id,omitempty"`
+	ReplyToUser  *UserInfo          `json:"reply_to_user,omitempty"`
+	Content      string             `json:"content"` // 已隐藏或已删除的评论对普通用户为空
+	Status       CommentStatus      `json:"status"`
+	ReplyCount   int                `json:"reply_count"`
+	Replies      []*CommentResponse `json:"replies,omitempty"` // 顶层评论的回复，按时间正序
+	Edited       bool               `json:"edited"`
+	HiddenReason string             `json:"hidden_reason,omitempty"` // 仅管理员可见
+	CreatedAt    string             `json:"created_at"`
+	UpdatedAt    string             `json:"updated_at"`
+}
+
+// ToCommentResponse 将 MaterialComment 转换为 CommentResponse
+// moderator 为 true 时返回隐藏评论的内容和隐藏原因
+func (c *MaterialComment) ToCommentResponse(moderator bool) *CommentResponse {
+	response := &CommentResponse{
+		ID:         c.ID,
+		MaterialID: c.MaterialID,
+		UserID:     c.UserID,
+		RootID:     c.RootID,
+		ParentID:   c.ParentID,
+		Content:    c.Content,
+		Status:     c.Status,
+		ReplyCount: c.ReplyCount,
+		Edited:     c.EditedAt != nil,
+		CreatedAt:  c.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:  c.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if c.User != nil {
+		userInfo := c.User.ToUserInfo()
+		response.User = &userInfo
+	}
+	if c.ReplyToUser != nil {
+		replyTo := c.ReplyToUser.ToUserInfo()
+		response.ReplyToUser = &replyTo
+	}
+
+	switch {
+	case c.Status == CommentDeleted:
+		response.Content = ""
+	case c.Status == CommentHidden && !moderator:
+		response.Content = ""
+	case moderator:
+		response.HiddenReason = c.HiddenReason
+	}
+	return response
+}
@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// MaterialImportStatus 批量导入任务状态
+type MaterialImportStatus string
+
+const (
+	MaterialImportPending   MaterialImportStatus = "pending"   // 等待执行
+	MaterialImportRunning   MaterialImportStatus = "running"   // 执行中
+	MaterialImportCompleted MaterialImportStatus = "completed" // 已完成（可能有部分条目失败）
+	MaterialImportFailed    MaterialImportStatus = "failed"    // 失败（压缩包或清单无法读取）
+)
+
+// MaterialImportItemStatus 批量导入条目结果
+type MaterialImportItemStatus string
+
+const (
+	MaterialImportItemSucceeded MaterialImportItemStatus = "succeeded" // 已创建资料
+	MaterialImportItemFailed    MaterialImportItemStatus = "failed"    // 校验或创建失败
+)
+
+// MaterialImport 资料批量导入任务
+// 上传一个包含资料文件和清单（manifest.csv / manifest.json）的 ZIP 压缩包，为清单中的每一行创建一个待审核资料
+type MaterialImport struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UploaderID    uint                 `gorm:"not null;index" json:"uploader_id"`                               // 导入用户ID
+	FileKey       string               `gorm:"type:varchar(500);not null" json:"file_key"`                      // 压缩包 OSS 存储键
+	FileName      string               `gorm:"type:varchar(255);not null" json:"file_name"`                     // 压缩包文件名
+	Status        MaterialImportStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // 任务状态
+	TotalRows     int                  `gorm:"not null;default:0" json:"total_rows"`                            // 清单条目数
+	SucceededRows int                  `gorm:"not null;default:0" json:"succeeded_rows"`                        // 成功条目数
+	FailedRows    int                  `gorm:"not null;default:0" json:"failed_rows"`                           // 失败条目数
+	LastError     string               `gorm:"type:text" json:"last_error,omitempty"`                           // 整体失败原因
+	StartedAt     *time.Time           `json:"started_at,omitempty"`                                            // 开始执行时间
+	FinishedAt    *time.Time           `json:"finished_at,omitempty"`                                           // 完成时间
+
+	Items []*MaterialImportItem `gorm:"foreignKey:ImportID" json:"items,omitempty"` // 每行的导入结果
+}
+
+// TableName 指定表名
+func (MaterialImport) TableName() string {
+	return "material_imports"
+}
+
+// MaterialImportItem 批量导入条目结果
+type MaterialImportItem struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	ImportID   uint                     `gorm:"not null;index" json:"import_id"`         // 导入任务ID
+	RowNumber  int                      `gorm:"not null" json:"row_number"`              // 清单中的行号（从 1 开始，不含表头）
+	FilePath   string                   `gorm:"type:varchar(500)" json:"file_path"`      // 文件在压缩包中的路径
+	Title      string                   `gorm:"type:varchar(200)" json:"title"`          // 资料标题
+	Status     MaterialImportItemStatus `gorm:"type:varchar(20);not null" json:"status"` // 导入结果
+	Error      string                   `gorm:"type:text" json:"error,omitempty"`        // 失败原因
+	MaterialID *uint                    `json:"material_id,omitempty"`                   // 创建的资料ID
+}
+
+// TableName 指定表名
+func (MaterialImportItem) TableName() string {
+	return "material_import_items"
+}
+
+// CreateMaterialImportRequest 创建批量导入请求
+type CreateMaterialImportRequest struct {
+	FileKey  string `json:"file_key" binding:"required,max=500"`  // 已上传的 ZIP 压缩包存储键
+	FileName string `json:"file_name" binding:"required,max=255"` // 压缩包文件名
+}
+
+// MaterialImportListRequest 批量导入列表请求
+type MaterialImportListRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
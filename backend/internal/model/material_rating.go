@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// MaterialRating 资料评分，每个用户对每份资料只有一条评分
+type MaterialRating struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	MaterialID uint `gorm:"not null;uniqueIndex:idx_material_ratings_material_user" json:"material_id"`
+	UserID     uint `gorm:"not null;uniqueIndex:idx_material_ratings_material_user;index" json:"user_id"`
+	Score      int  `gorm:"not null" json:"score"` // 1-5 分
+}
+
+// TableName 指定表名
+func (MaterialRating) TableName() string {
+	return "material_ratings"
+}
+
+// RateMaterialRequest 评分请求
+type RateMaterialRequest struct {
+	Score int `json:"score" binding:"required,min=1,max=5"`
+}
+
+// MaterialRatingResponse 评分响应
+type MaterialRatingResponse struct {
+	MaterialID    uint    `json:"material_id"`
+	MyRating      *int    `json:"my_rating"`      // 当前用户的评分，未评分时为空
+	RatingAverage float64 `json:"rating_average"` // 平均分
+	RatingCount   int     `json:"rating_count"`   // 评分人数
+}
@@ -11,11 +11,15 @@ type CreateMaterialRequest struct {
 	Title       string           `json:"title" binding:"required,min=2,max=200"`
 	Description string           `json:"description" binding:"max=2000"`
 	Category    MaterialCategoryType `json:"category" binding:"required"` // 移除 oneof,改为动态验证
-	CourseName  string           `json:"course_name" binding:"required,max=100"`
+	CourseName  string           `json:"course_name" binding:"required_without=CourseID,max=100"` // 指定课程时可为空，默认使用课程规范名称
+	CourseID    *uint            `json:"course_id" binding:"omitempty"`                             // 关联的课程ID，为空时按课程名称或别名自动关联
+	CourseTerm  string           `json:"course_term" binding:"omitempty,max=20"` // 开课学期，如 2024-2025-1
+	Tags        []string         `json:"tags" binding:"omitempty,max=10,dive,max=50"` // 标签
 	FileName    string           `json:"file_name" binding:"required,max=255"`
 	FileSize    int64            `json:"file_size" binding:"required,min=1,max=536870912"` // 最大 512MB
 	MimeType    string           `json:"mime_type" binding:"required"`
 	FileKey     string           `json:"file_key" binding:"required,max=500"` // OSS 存储键
+	Checksum    string           `json:"checksum" binding:"omitempty,len=32,hexadecimal"` // 可选的文件 MD5 校验值
 }
 
 // UpdateMaterialRequest 更新资料请求
@@ -23,7 +27,10 @@ type UpdateMaterialRequest struct {
 	Title       string           `json:"title" binding:"required,min=2,max=200"`
 	Description string           `json:"description" binding:"max=2000"`
 	Category    MaterialCategoryType `json:"category" binding:"required"` // 移除 oneof,改为动态验证
-	CourseName  string           `json:"course_name" binding:"required,max=100"`
+	CourseName  string           `json:"course_name" binding:"required_without=CourseID,max=100"` // 指定课程时可为空，默认使用课程规范名称
+	CourseID    *uint            `json:"course_id" binding:"omitempty"`                             // 关联的课程ID，为空时按课程名称或别名自动关联
+	CourseTerm  string           `json:"course_term" binding:"omitempty,max=20"` // 开课学期，如 2024-2025-1
+	Tags        []string         `json:"tags" binding:"omitempty,max=10,dive,max=50"` // 标签，不传时保留原有标签，传空数组时清空
 }
 
 // MaterialListRequest 资料列表查询请求
@@ -31,10 +38,12 @@ type MaterialListRequest struct {
 	Page         int              `form:"page,default=1" binding:"min=1"`
 	PageSize     int              `form:"page_size,default=20" binding:"min=1,max=100"`
 	Category     MaterialCategoryType `form:"category" binding:"omitempty"` // 移除 oneof,改为动态验证
-	CourseName   string           `form:"course_name" binding:"omitempty,max=100"`
-	Status       MaterialStatus   `form:"status" binding:"omitempty,oneof=pending approved rejected deleted"`
+	CourseName   string           `form:"course_name" binding:"omitempty,max=100"` // 课程名称或别名，能解析到课程时按课程筛选
+	CourseID     *uint            `form:"course_id" binding:"omitempty"`
+	Tags         []string         `form:"tags" binding:"omitempty,max=10,dive,max=50"` // 标签筛选，命中任一标签即可
+	Status       MaterialStatus   `form:"status" binding:"omitempty,oneof=pending approved rejected deleted archived"`
 	Keyword      string           `form:"keyword" binding:"omitempty,max=100"`
-	SortBy       string           `form:"sort_by,default=created_at" binding:"omitempty,oneof=created_at download_count favorite_count view_count title"`
+	SortBy       string           `form:"sort_by,default=created_at" binding:"omitempty,oneof=created_at download_count favorite_count view_count title rating_average"`
 	SortOrder    string           `form:"sort_order,default=desc" binding:"omitempty,oneof=asc desc"`
 	UploaderID   *uint            `form:"uploader_id" binding:"omitempty"` // 可选的上传者ID筛选,用于"我的资料"查询
 	ReviewedOnly bool             `form:"reviewed_only"`                  // 管理员专用:只获取已审核资料(approved + rejected)
@@ -53,22 +62,40 @@ type MaterialResponse struct {
 	Description     string           `json:"description"`
 	Category        MaterialCategoryType `json:"category"`
 	CourseName      string           `json:"course_name"`
+	CourseID        *uint            `json:"course_id,omitempty"`        // 关联的课程ID
+	CourseTerm      string           `json:"course_term,omitempty"`      // 开课学期
+	Tags            []string         `json:"tags"`                       // 标签
 	UploaderID      uint             `json:"uploader_id"`
 	Uploader        *UserInfo        `json:"uploader,omitempty"`
 	Status          MaterialStatus   `json:"status"`
 	FileName        string           `json:"file_name"`
 	FileSize        int64            `json:"file_size"`
 	MimeType        string           `json:"mime_type"`
+	ContentHash     string           `json:"content_hash,omitempty"`   // 文件内容 SHA-256
+	DetectedMimeType string          `json:"detected_mime_type,omitempty"` // 根据文件头检测到的真实类型
+	MimeMismatch    bool             `json:"mime_mismatch"`            // 检测类型与声明类型是否不一致
+	DuplicateOf     *DuplicateMaterialInfo `json:"duplicate_of,omitempty"` // 内容相同的已通过资料（仅待审核列表）
 	DownloadCount   int              `json:"download_count"`
 	FavoriteCount   int              `json:"favorite_count"`
 	ViewCount       int              `json:"view_count"`
+	RatingAverage   float64          `json:"rating_average"`             // 平均评分
+	RatingCount     int              `json:"rating_count"`               // 评分人数
+	CommentCount    int              `json:"comment_count"`              // 评论数
 	ReviewerID      *uint            `json:"reviewer_id,omitempty"`
 	Reviewer        *UserInfo        `json:"reviewer,omitempty"`
 	ReviewedAt      *string          `json:"reviewed_at,omitempty"`
 	RejectionReason string           `json:"rejection_reason,omitempty"`
+	CurrentVersion  int              `json:"current_version"`            // 当前生效版本号
+	CurrentVersionID *uint           `json:"current_version_id,omitempty"` // 当前生效版本ID
+	ThumbnailURL    string           `json:"thumbnail_url,omitempty"`    // 首页缩略图地址（预签名URL）
+	PreviewExcerpt  string           `json:"preview_excerpt,omitempty"`  // 文本摘要
+	PreviewStatus   PreviewStatus    `json:"preview_status"`             // 预览生成状态
 	CreatedAt       string           `json:"created_at"`
 	UpdatedAt       string           `json:"updated_at"`
 	IsFavorited     bool             `json:"is_favorited,omitempty"` // 当前用户是否已收藏
+	MatchedFields   []string         `json:"matched_fields,omitempty"` // 搜索命中的字段（仅搜索结果）
+	ArchiveScheduledAt *string       `json:"archive_scheduled_at,omitempty"` // 计划归档时间
+	ArchivedAt      *string          `json:"archived_at,omitempty"`      // 归档时间
 }
 
 // UploadSignatureRequest 获取上传签名请求
@@ -87,6 +114,32 @@ type UploadSignatureResponse struct {
 	MaxFileSize int64  `json:"max_file_size"`          // 最大文件大小
 }
 
+// FinalizeUploadRequest 确认上传完成请求
+type FinalizeUploadRequest struct {
+	FileKey  string `json:"file_key" binding:"required,max=500"`                // OSS 存储键
+	FileSize int64  `json:"file_size" binding:"required,min=1,max=536870912"`    // 声明的文件大小
+	MimeType string `json:"mime_type" binding:"required"`                        // 声明的 MIME 类型
+	Checksum string `json:"checksum" binding:"omitempty,len=32,hexadecimal"`     // 可选的文件 MD5 校验值
+}
+
+// FinalizeUploadResponse 确认上传完成响应
+type FinalizeUploadResponse struct {
+	FileKey          string                 `json:"file_key"`               // OSS 存储键
+	FileSize         int64                  `json:"file_size"`              // OSS 中的实际文件大小
+	MimeType         string                 `json:"mime_type"`              // OSS 中的实际 MIME 类型
+	DetectedMimeType string                 `json:"detected_mime_type"`     // 根据文件头检测到的真实类型
+	MimeMismatch     bool                   `json:"mime_mismatch"`          // 检测类型与声明类型是否不一致
+	ETag             string                 `json:"etag"`                   // OSS 返回的 ETag
+	ContentHash      string                 `json:"content_hash,omitempty"` // 文件内容 SHA-256
+	DuplicateOf      *DuplicateMaterialInfo `json:"duplicate_of,omitempty"` // 内容相同的已通过资料
+}
+
+// DuplicateMaterialInfo 内容重复的已通过资料
+type DuplicateMaterialInfo struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
 // DeleteUploadedFileRequest 删除已上传文件请求
 type DeleteUploadedFileRequest struct {
 	FileKey string `json:"file_key" binding:"required"` // OSS 存储键
@@ -109,8 +162,11 @@ type ReportResponse struct {
 	ID          uint         `json:"id"`
 	UserID      uint         `json:"user_id"`
 	Reporter    *UserInfo    `json:"reporter,omitempty"` // 举报人信息(前端期望reporter字段)
+	TargetType  string       `json:"target_type"`        // material 或 comment
 	MaterialID  uint         `json:"material_id"`
 	Material    *MaterialResponse `json:"material,omitempty"`
+	CommentID   *uint        `json:"comment_id,omitempty"`
+	Comment     *CommentResponse `json:"comment,omitempty"` // 被举报的评论（含已隐藏内容）
 	Reason      string       `json:"reason"`
 	Description string       `json:"description"`
 	Status      ReportStatus `json:"status"`
@@ -150,11 +206,44 @@ type DownloadRecordResponse struct {
 }
 
 // DownloadQuotaResponse 下载配额响应
+// 顶层字段为当日配额，Windows 包含每个统计窗口的详细信息
 type DownloadQuotaResponse struct {
-	Limit     int   `json:"limit"`
-	Used      int64 `json:"used"`
-	Remaining int64 `json:"remaining"`
-	Unlimited bool  `json:"unlimited"`
+	Limit     int                    `json:"limit"`
+	Used      int64                  `json:"used"`
+	Remaining int64                  `json:"remaining"`
+	Unlimited bool                   `json:"unlimited"`
+	Windows   []DownloadQuotaWindow  `json:"windows"`
+	Boosts    []*DownloadQuotaBoost  `json:"boosts,omitempty"` // 当前生效的临时提升
+}
+
+// 下载方式
+const (
+	DownloadModePresigned = "presigned" // 返回 OSS 预签名 URL，由客户端直接从存储下载
+	DownloadModeStream    = "stream"    // 返回一次性令牌，由后端代理传输文件
+)
+
+// DownloadLinkRequest 获取下载链接请求
+type DownloadLinkRequest struct {
+	Mode      string `form:"mode" binding:"omitempty,oneof=presigned stream"`
+	Watermark bool   `form:"watermark"` // 代理下载 PDF 时是否添加用户水印
+}
+
+// DownloadLinkResponse 获取下载链接响应
+type DownloadLinkResponse struct {
+	DownloadURL string `json:"download_url"`
+	Mode        string `json:"mode"`
+	Token       string `json:"-"`
+	ExpiresIn   int    `json:"expires_in,omitempty"` // 代理下载令牌的有效期（秒）
+	Watermark   bool   `json:"watermark"`
+}
+
+// UploadQuotaResponse 存储配额响应
+type UploadQuotaResponse struct {
+	Limit     int64  `json:"limit"`     // 存储配额（字节）
+	Used      int64  `json:"used"`      // 已使用（字节）
+	Remaining int64  `json:"remaining"` // 剩余（字节），不限制时为 -1
+	Unlimited bool   `json:"unlimited"`
+	Source    string `json:"source"`    // 配额来源: user（单独设置）、role（按角色配置）、default（默认配置）
 }
 
 // ToMaterialResponse 将 Material 转换为 MaterialResponse
@@ -165,16 +254,29 @@ func (m *Material) ToMaterialResponse() *MaterialResponse {
 		Description:     m.Description,
 		Category:        m.Category,
 		CourseName:      m.CourseName,
+		CourseID:        m.CourseID,
+		CourseTerm:      m.CourseTerm,
+		Tags:            m.TagNames(),
 		UploaderID:      m.UploaderID,
 		Status:          m.Status,
 		FileName:        m.FileName,
 		FileSize:        m.FileSize,
 		MimeType:        m.MimeType,
+		ContentHash:     m.ContentHash,
+		DetectedMimeType: m.DetectedMimeType,
+		MimeMismatch:    m.MimeMismatch,
 		DownloadCount:   m.DownloadCount,
 		FavoriteCount:   m.FavoriteCount,
 		ViewCount:       m.ViewCount,
+		RatingAverage:   m.RatingAverage,
+		RatingCount:     m.RatingCount,
+		CommentCount:    m.CommentCount,
 		ReviewerID:      m.ReviewerID,
 		RejectionReason: m.RejectionReason,
+		CurrentVersion:  m.CurrentVersion,
+		CurrentVersionID: m.CurrentVersionID,
+		PreviewExcerpt:  m.PreviewExcerpt,
+		PreviewStatus:   m.PreviewStatus,
 		CreatedAt:       m.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:       m.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
@@ -197,6 +299,16 @@ func (m *Material) ToMaterialResponse() *MaterialResponse {
 		response.ReviewedAt = &reviewedAt
 	}
 
+	// 转换归档时间
+	if m.ArchiveScheduledAt != nil {
+		scheduledAt := m.ArchiveScheduledAt.Format("2006-01-02 15:04:05")
+		response.ArchiveScheduledAt = &scheduledAt
+	}
+	if m.ArchivedAt != nil {
+		archivedAt := m.ArchivedAt.Format("2006-01-02 15:04:05")
+		response.ArchivedAt = &archivedAt
+	}
+
 	return response
 }
 
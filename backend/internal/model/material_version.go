@@ -0,0 +1,166 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaterialVersion 资料版本模型
+// 每个版本拥有独立的文件、元数据快照与审核状态，资料本身(Material)始终指向当前生效的版本
+type MaterialVersion struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	MaterialID       uint                 `gorm:"not null;uniqueIndex:idx_material_version" json:"material_id"`    // 所属资料ID
+	VersionNumber    int                  `gorm:"not null;uniqueIndex:idx_material_version" json:"version_number"` // 版本号（从 1 开始递增）
+	Title            string               `gorm:"type:varchar(200);not null" json:"title"`                         // 标题快照
+	Description      string               `gorm:"type:text" json:"description"`                                    // 描述快照
+	Category         MaterialCategoryType `gorm:"type:varchar(50);not null" json:"category"`                       // 分类快照
+	CourseName       string               `gorm:"type:varchar(100)" json:"course_name"`                            // 课程名称快照
+	FileName         string               `gorm:"type:varchar(255);not null" json:"file_name"`                     // 原始文件名
+	FileSize         int64                `gorm:"not null" json:"file_size"`                                       // 文件大小（字节）
+	FileKey          string               `gorm:"type:varchar(500);not null;uniqueIndex" json:"file_key"`          // OSS 存储键
+	MimeType         string               `gorm:"type:varchar(100);not null" json:"mime_type"`                     // MIME 类型
+	ContentHash      string               `gorm:"type:varchar(64);index" json:"content_hash,omitempty"`            // 文件内容 SHA-256
+	DetectedMimeType string               `gorm:"type:varchar(100)" json:"detected_mime_type,omitempty"`           // 根据文件头检测到的真实类型
+	MimeMismatch     bool                 `gorm:"not null;default:false" json:"mime_mismatch"`                     // 检测类型与声明类型是否不一致
+	ChangeNote       string               `gorm:"type:varchar(500)" json:"change_note"`                            // 版本说明
+	UploaderID       uint                 `gorm:"not null;index" json:"uploader_id"`                               // 版本上传者ID
+	Uploader         *User                `gorm:"foreignKey:UploaderID" json:"uploader,omitempty"`                 // 版本上传者信息
+	Status           MaterialStatus       `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // 审核状态
+	ReviewerID       *uint                `gorm:"index" json:"reviewer_id,omitempty"`                              // 审核人ID
+	Reviewer         *User                `gorm:"foreignKey:ReviewerID" json:"reviewer,omitempty"`                 // 审核人信息
+	ReviewedAt       *time.Time           `json:"reviewed_at,omitempty"`                                           // 审核时间
+	RejectionReason  string               `gorm:"type:text" json:"rejection_reason,omitempty"`                     // 拒绝原因
+}
+
+// TableName 指定表名
+func (MaterialVersion) TableName() string {
+	return "material_versions"
+}
+
+// UploadMaterialVersionRequest 上传新版本请求
+type UploadMaterialVersionRequest struct {
+	FileName    string               `json:"file_name" binding:"required,max=255"`
+	FileSize    int64                `json:"file_size" binding:"required,min=1,max=536870912"` // 最大 512MB
+	MimeType    string               `json:"mime_type" binding:"required"`
+	FileKey     string               `json:"file_key" binding:"required,max=500"`             // OSS 存储键
+	Checksum    string               `json:"checksum" binding:"omitempty,len=32,hexadecimal"` // 可选的文件 MD5 校验值
+	ChangeNote  string               `json:"change_note" binding:"max=500"`
+	Title       string               `json:"title" binding:"omitempty,min=2,max=200"` // 为空时沿用当前版本
+	Description *string              `json:"description" binding:"omitempty,max=2000"`
+	Category    MaterialCategoryType `json:"category"`
+	CourseName  string               `json:"course_name" binding:"omitempty,max=100"`
+}
+
+// ReviewMaterialVersionRequest 审核资料版本请求
+type ReviewMaterialVersionRequest struct {
+	Status          MaterialStatus `json:"status" binding:"required,oneof=approved rejected"`
+	RejectionReason string         `json:"rejection_reason" binding:"omitempty,max=500"`
+}
+
+// MaterialVersionResponse 资料版本响应
+type MaterialVersionResponse struct {
+	ID               uint                 `json:"id"`
+	MaterialID       uint                 `json:"material_id"`
+	VersionNumber    int                  `json:"version_number"`
+	Title            string               `json:"title"`
+	Description      string               `json:"description"`
+	Category         MaterialCategoryType `json:"category"`
+	CourseName       string               `json:"course_name"`
+	FileName         string               `json:"file_name"`
+	FileSize         int64                `json:"file_size"`
+	MimeType         string               `json:"mime_type"`
+	ContentHash      string               `json:"content_hash,omitempty"`
+	DetectedMimeType string               `json:"detected_mime_type,omitempty"`
+	MimeMismatch     bool                 `json:"mime_mismatch"`
+	ChangeNote       string               `json:"change_note,omitempty"`
+	UploaderID       uint                 `json:"uploader_id"`
+	Uploader         *UserInfo            `json:"uploader,omitempty"`
+	Status           MaterialStatus       `json:"status"`
+	ReviewerID       *uint                `json:"reviewer_id,omitempty"`
+	ReviewedAt       *string              `json:"reviewed_at,omitempty"`
+	RejectionReason  string               `json:"rejection_reason,omitempty"`
+	IsCurrent        bool                 `json:"is_current"` // 是否为当前生效版本
+	CreatedAt        string               `json:"created_at"`
+}
+
+// ToMaterialVersionResponse 将 MaterialVersion 转换为 MaterialVersionResponse
+func (v *MaterialVersion) ToMaterialVersionResponse(currentVersionID *uint) *MaterialVersionResponse {
+	response := &MaterialVersionResponse{
+		ID:               v.ID,
+		MaterialID:       v.MaterialID,
+		VersionNumber:    v.VersionNumber,
+		Title:            v.Title,
+		Description:      v.Description,
+		Category:         v.Category,
+		CourseName:       v.CourseName,
+		FileName:         v.FileName,
+		FileSize:         v.FileSize,
+		MimeType:         v.MimeType,
+		ContentHash:      v.ContentHash,
+		DetectedMimeType: v.DetectedMimeType,
+		MimeMismatch:     v.MimeMismatch,
+		ChangeNote:       v.ChangeNote,
+		UploaderID:       v.UploaderID,
+		Status:           v.Status,
+		ReviewerID:       v.ReviewerID,
+		RejectionReason:  v.RejectionReason,
+		IsCurrent:        currentVersionID != nil && *currentVersionID == v.ID,
+		CreatedAt:        v.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	if v.Uploader != nil {
+		uploaderInfo := v.Uploader.ToUserInfo()
+		response.Uploader = &uploaderInfo
+	}
+
+	if v.ReviewedAt != nil {
+		reviewedAt := v.ReviewedAt.Format("2006-01-02 15:04:05")
+		response.ReviewedAt = &reviewedAt
+	}
+
+	return response
+}
+
+// NewMaterialVersionSnapshot 根据资料当前信息生成版本快照
+func NewMaterialVersionSnapshot(m *Material, versionNumber int) *MaterialVersion {
+	return &MaterialVersion{
+		MaterialID:       m.ID,
+		VersionNumber:    versionNumber,
+		Title:            m.Title,
+		Description:      m.Description,
+		Category:         m.Category,
+		CourseName:       m.CourseName,
+		FileName:         m.FileName,
+		FileSize:         m.FileSize,
+		FileKey:          m.FileKey,
+		MimeType:         m.MimeType,
+		ContentHash:      m.ContentHash,
+		DetectedMimeType: m.DetectedMimeType,
+		MimeMismatch:     m.MimeMismatch,
+		UploaderID:       m.UploaderID,
+		Status:           m.Status,
+	}
+}
+
+// ApplyVersion 将版本快照应用到资料上（切换当前版本）
+func (m *Material) ApplyVersion(v *MaterialVersion) {
+	m.Title = v.Title
+	m.Description = v.Description
+	m.Category = v.Category
+	m.CourseName = v.CourseName
+	m.FileName = v.FileName
+	m.FileSize = v.FileSize
+	m.FileKey = v.FileKey
+	m.MimeType = v.MimeType
+	m.ContentHash = v.ContentHash
+	m.DetectedMimeType = v.DetectedMimeType
+	m.MimeMismatch = v.MimeMismatch
+	versionID := v.ID
+	m.CurrentVersionID = &versionID
+	m.CurrentVersion = v.VersionNumber
+}
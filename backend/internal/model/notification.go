@@ -14,6 +14,8 @@ const (
 	NotifyMaterial    NotificationType = "material"     // 资料审核通知
 	NotifyCommittee   NotificationType = "committee"    // 学委申请通知
 	NotifyReport      NotificationType = "report"       // 举报处理通知
+	NotifyComment     NotificationType = "comment"      // 评论与回复通知
+	NotifyRequest     NotificationType = "request"      // 求资料通知
 )
 
 // NotificationStatus 通知状态
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// PreviewStatus 资料预览生成状态
+type PreviewStatus string
+
+const (
+	PreviewStatusPending     PreviewStatus = "pending"     // 等待生成
+	PreviewStatusReady       PreviewStatus = "ready"       // 已生成
+	PreviewStatusFailed      PreviewStatus = "failed"      // 生成失败（重试次数已用完）
+	PreviewStatusUnsupported PreviewStatus = "unsupported" // 文件类型不支持预览
+)
+
+// ProcessingJobType 后台处理任务类型
+type ProcessingJobType string
+
+const (
+	ProcessingJobPreview     ProcessingJobType = "preview"      // 生成缩略图和文本摘要
+	ProcessingJobExtractText ProcessingJobType = "extract_text" // 提取全文用于搜索
+)
+
+// ProcessingJobStatus 后台处理任务状态
+type ProcessingJobStatus string
+
+const (
+	ProcessingJobPending   ProcessingJobStatus = "pending"   // 等待执行（含等待重试）
+	ProcessingJobRunning   ProcessingJobStatus = "running"   // 执行中
+	ProcessingJobSucceeded ProcessingJobStatus = "succeeded" // 已成功
+	ProcessingJobFailed    ProcessingJobStatus = "failed"    // 已失败（重试次数已用完）
+)
+
+// ProcessingJob 资料后台处理任务
+type ProcessingJob struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	MaterialID  uint                `gorm:"not null;index" json:"material_id"`                               // 资料ID
+	FileKey     string              `gorm:"type:varchar(500);not null" json:"file_key"`                      // 处理的文件，资料更换版本后旧任务的结果会被丢弃
+	JobType     ProcessingJobType   `gorm:"type:varchar(20);not null" json:"job_type"`                       // 任务类型
+	Status      ProcessingJobStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // 任务状态
+	Attempts    int                 `gorm:"not null;default:0" json:"attempts"`                              // 已执行次数
+	MaxAttempts int                 `gorm:"not null;default:3" json:"max_attempts"`                          // 最大执行次数
+	LastError   string              `gorm:"type:text" json:"last_error,omitempty"`                           // 最近一次失败原因
+	RunAfter    time.Time           `gorm:"not null;index" json:"run_after"`                                 // 最早执行时间，用于失败后延迟重试
+	StartedAt   *time.Time          `json:"started_at,omitempty"`                                            // 最近一次开始执行时间
+	FinishedAt  *time.Time          `json:"finished_at,omitempty"`                                           // 完成时间
+}
+
+// TableName 指定表名
+func (ProcessingJob) TableName() string {
+	return "processing_jobs"
+}
+
+// ProcessingJobListRequest 处理任务列表请求
+type ProcessingJobListRequest struct {
+	Page       int                 `form:"page" binding:"omitempty,min=1"`
+	PageSize   int                 `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Status     ProcessingJobStatus `form:"status" binding:"omitempty,oneof=pending running succeeded failed"`
+	JobType    ProcessingJobType   `form:"job_type" binding:"omitempty,oneof=preview extract_text"`
+	MaterialID uint                `form:"material_id"`
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ResourceRequestStatus 求资料状态
+type ResourceRequestStatus string
+
+const (
+	ResourceRequestOpen      ResourceRequestStatus = "open"      // 等待认领
+	ResourceRequestClaimed   ResourceRequestStatus = "claimed"   // 已被学委认领
+	ResourceRequestFulfilled ResourceRequestStatus = "fulfilled" // 已关联资料
+	ResourceRequestClosed    ResourceRequestStatus = "closed"    // 已关闭
+)
+
+// IsActive 是否仍在等待资料（可投票、可在搜索中展示）
+func (s ResourceRequestStatus) IsActive() bool {
+	return s == ResourceRequestOpen || s == ResourceRequestClaimed
+}
+
+// ResourceRequest 求资料
+// 学生发布求资料，其他同学投票，学委认领后上传资料并关联以完成求资料
+type ResourceRequest struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	UserID              uint                  `gorm:"not null;index" json:"user_id"`
+	User                *User                 `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Title               string                `gorm:"type:varchar(200);not null" json:"title"`
+	Description         string                `gorm:"type:text" json:"description"`
+	Category            MaterialCategoryType  `gorm:"type:varchar(50);not null;index" json:"category"`
+	CourseName          string                `gorm:"type:varchar(100);not null" json:"course_name"`
+	CourseID            *uint                 `gorm:"index" json:"course_id,omitempty"`
+	Status              ResourceRequestStatus `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	VoteCount           int                   `gorm:"not null;default:0;index" json:"vote_count"`
+	ClaimedBy           *uint                 `gorm:"index" json:"claimed_by,omitempty"` // 认领的学委
+	Claimer             *User                 `gorm:"foreignKey:ClaimedBy" json:"claimer,omitempty"`
+	ClaimedAt           *time.Time            `json:"claimed_at,omitempty"`
+	FulfilledMaterialID *uint                 `gorm:"index" json:"fulfilled_material_id,omitempty"` // 关联的资料
+	FulfilledMaterial   *Material             `gorm:"foreignKey:FulfilledMaterialID" json:"fulfilled_material,omitempty"`
+	FulfilledAt         *time.Time            `json:"fulfilled_at,omitempty"`
+	CloseReason         string                `gorm:"type:varchar(200)" json:"close_reason,omitempty"`
+}
+
+// TableName 指定表名
+func (ResourceRequest) TableName() string {
+	return "resource_requests"
+}
+
+// ResourceRequestVote 求资料投票，每个用户对每条求资料只能投一票
+type ResourceRequestVote struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	RequestID uint `gorm:"not null;uniqueIndex:idx_resource_request_vote" json:"request_id"`
+	UserID    uint `gorm:"not null;uniqueIndex:idx_resource_request_vote;index" json:"user_id"`
+}
+
+// TableName 指定表名
+func (ResourceRequestVote) TableName() string {
+	return "resource_request_votes"
+}
+
+// ResourceRequestRequest 发布或编辑求资料请求
+type ResourceRequestRequest struct {
+	Title       string               `json:"title" binding:"required,min=2,max=200"`
+	Description string               `json:"description" binding:"max=2000"`
+	Category    MaterialCategoryType `json:"category" binding:"required"`
+	CourseName  string               `json:"course_name" binding:"required_without=CourseID,max=100"`
+	CourseID    *uint                `json:"course_id" binding:"omitempty"`
+}
+
+// ResourceRequestListRequest 求资料列表请求
+type ResourceRequestListRequest struct {
+	Page        int                   `form:"page,default=1" binding:"min=1"`
+	PageSize    int                   `form:"page_size,default=20" binding:"min=1,max=100"`
+	Status      ResourceRequestStatus `form:"status" binding:"omitempty,oneof=open claimed fulfilled closed"`
+	Category    MaterialCategoryType  `form:"category"`
+	CourseName  string                `form:"course_name" binding:"omitempty,max=100"`
+	CourseID    *uint                 `form:"course_id"`
+	Keyword     string                `form:"keyword" binding:"omitempty,max=100"`
+	Mine        bool                  `form:"mine"`          // 只看我发布的
+	ClaimedByMe bool                  `form:"claimed_by_me"` // 只看我认领的
+	SortBy      string                `form:"sort_by,default=vote_count" binding:"omitempty,oneof=vote_count created_at"`
+}
+
+// FulfillResourceRequestRequest 完成求资料请求
+type FulfillResourceRequestRequest struct {
+	MaterialID uint `json:"material_id" binding:"required"`
+}
+
+// CloseResourceRequestRequest 关闭求资料请求
+type CloseResourceRequestRequest struct {
+	Reason string `json:"reason" binding:"max=200"`
+}
+
+// ResourceRequestResponse 求资料响应
+type ResourceRequestResponse struct {
+	ID                  uint                  `json:"id"`
+	UserID              uint                  `json:"user_id"`
+	User                *UserInfo             `json:"user,omitempty"`
+	Title               string                `json:"title"`
+	Description         string                `json:"description"`
+	Category            MaterialCategoryType  `json:"category"`
+	CourseName          string                `json:"course_name"`
+	CourseID            *uint                 `json:"course_id,omitempty"`
+	Status              ResourceRequestStatus `json:"status"`
+	VoteCount           int                   `json:"vote_count"`
+	Voted               bool                  `json:"voted"` // 当前用户是否已投票
+	ClaimedBy           *uint                 `json:"claimed_by,omitempty"`
+	Claimer             *UserInfo             `json:"claimer,omitempty"`
+	ClaimedAt           *string               `json:"claimed_at,omitempty"`
+	FulfilledMaterialID *uint                 `json:"fulfilled_material_id,omitempty"`
+	FulfilledMaterial   *MaterialResponse     `json:"fulfilled_material,omitempty"`
+	FulfilledAt         *string               `json:"fulfilled_at,omitempty"`
+	CloseReason         string                `json:"close_reason,omitempty"`
+	CreatedAt           string                `json:"created_at"`
+	UpdatedAt           string                `json:"updated_at"`
+}
+
+// ToResourceRequestResponse 将 ResourceRequest 转换为 ResourceRequestResponse
+func (r *ResourceRequest) ToResourceRequestResponse() *ResourceRequestResponse {
+	response := &ResourceRequestResponse{
+		ID:                  r.ID,
+		UserID:              r.UserID,
+		Title:               r.Title,
+		Description:         r.Description,
+		Category:            r.Category,
+		CourseName:          r.CourseName,
+		CourseID:            r.CourseID,
+		Status:              r.Status,
+		VoteCount:           r.VoteCount,
+		ClaimedBy:           r.ClaimedBy,
+		FulfilledMaterialID: r.FulfilledMaterialID,
+		CloseReason:         r.CloseReason,
+		CreatedAt:           r.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:           r.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if r.User != nil {
+		userInfo := r.User.ToUserInfo()
+		response.User = &userInfo
+	}
+	if r.Claimer != nil {
+		claimer := r.Claimer.ToUserInfo()
+		response.Claimer = &claimer
+	}
+	if r.ClaimedAt != nil {
+		claimedAt := r.ClaimedAt.Format("2006-01-02 15:04:05")
+		response.ClaimedAt = &claimedAt
+	}
+	if r.FulfilledMaterial != nil {
+		response.FulfilledMaterial = r.FulfilledMaterial.ToMaterialResponse()
+	}
+	if r.FulfilledAt != nil {
+		fulfilledAt := r.FulfilledAt.Format("2006-01-02 15:04:05")
+		response.FulfilledAt = &fulfilledAt
+	}
+	return response
+}
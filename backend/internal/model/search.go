@@ -41,15 +41,17 @@ func (HotKeyword) TableName() string {
 
 // SearchRequest 搜索请求参数
 type SearchRequest struct {
-	Keyword    string            `form:"keyword"`                                       // 搜索关键词
+	Keyword    string            `form:"keyword"`                                       // 搜索关键词，支持 "短语"、-排除、OR 和 course:/category:/tag: 字段前缀
 	Category   *MaterialCategory `form:"category"`                                      // 分类筛选
-	CourseName string            `form:"course_name"`                                   // 课程名称
+	CourseName string            `form:"course_name"`                                   // 课程名称或别名
+	CourseID   *uint             `form:"course_id"`                                     // 课程ID
 	Tags       []string          `form:"tags"`                                          // 标签筛选
 	Status     *MaterialStatus   `form:"status"`                                        // 状态筛选
 	StartDate  string            `form:"start_date"`                                    // 开始日期
 	EndDate    string            `form:"end_date"`                                      // 结束日期
-	SortBy     string            `form:"sort_by,default:created_at"`                    // 排序字段: created_at, download_count, favorite_count, view_count, relevance
+	SortBy     string            `form:"sort_by,default:created_at"`                    // 排序字段: created_at, download_count, favorite_count, view_count, rating, relevance
 	SortOrder  string            `form:"sort_order,default:desc"`                       // 排序方向: asc, desc
+	Fuzzy      bool              `form:"fuzzy"`                                         // 模糊匹配，同时搜索拼写相近的词
 	Page       int               `form:"page,default=1"`                                // 页码
 	PageSize   int               `form:"page_size,default=20"`                          // 每页数量
 }
@@ -58,17 +60,43 @@ type SearchRequest struct {
 type SearchResult struct {
 	Material   *Material `json:"material"`
 	Relevance  float64   `json:"relevance"`  // 相关度分数 (0-1)
-	Highlighted string   `json:"highlighted"` // 高亮显示的文本片段
+	Highlighted string   `json:"highlighted"` // 高亮显示的文本片段（取自描述或文件正文）
+	HighlightedTitle string `json:"highlighted_title,omitempty"` // 高亮显示的标题
+	MatchedFields []string `json:"matched_fields,omitempty"` // 命中的字段: title, description, course_name, content
 }
 
+// 搜索命中的字段
+const (
+	MatchedFieldTitle       = "title"       // 标题
+	MatchedFieldDescription = "description" // 描述
+	MatchedFieldCourseName  = "course_name" // 课程名称
+	MatchedFieldContent     = "content"     // 文件正文
+)
+
 // SearchResponse 搜索响应
 type SearchResponse struct {
-	Results     []*SearchResult `json:"results"`
-	Total       int64           `json:"total"`
-	Page        int             `json:"page"`
-	PageSize    int             `json:"page_size"`
-	TotalPages  int             `json:"total_pages"`
-	DidYouMean  []string        `json:"did_you_mean,omitempty"`  // 拼写建议
+	Results    []*SearchResult            `json:"results"`
+	Total      int64                      `json:"total"`
+	Page       int                        `json:"page"`
+	PageSize   int                        `json:"page_size"`
+	TotalPages int                        `json:"total_pages"`
+	DidYouMean []string                   `json:"did_you_mean,omitempty"` // 拼写建议
+	Requests   []*ResourceRequestResponse `json:"requests,omitempty"`     // 匹配的待完成求资料（仅第一页）
+
+	HighlightPreTag  string `json:"highlight_pre_tag,omitempty"`  // 高亮开始标记
+	HighlightPostTag string `json:"highlight_post_tag,omitempty"` // 高亮结束标记
+}
+
+// SearchSuggestion 搜索联想词
+type SearchSuggestion struct {
+	Text string `json:"text"`
+	Type string `json:"type"` // 来源: title, course, tag, keyword
+}
+
+// SearchSuggestResponse 搜索联想响应
+type SearchSuggestResponse struct {
+	Suggestions []*SearchSuggestion `json:"suggestions"`
+	Histories   []string            `json:"histories"` // 当前用户以输入开头的最近搜索
 }
 
 // RecommendationRequest 推荐请求参数
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// SearchDictionaryWord 搜索分词自定义词条
+// 内置词典之外的课程术语由管理员维护，课程名称和别名会自动加入分词词典，无需重复添加
+type SearchDictionaryWord struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Word      string `gorm:"type:varchar(50);not null;uniqueIndex" json:"word"` // 词条（小写）
+	Frequency int    `gorm:"not null;default:1000" json:"frequency"`            // 词频，越大越倾向于整体切分
+	CreatedBy uint   `gorm:"not null" json:"created_by"`                        // 添加的管理员
+}
+
+// TableName 指定表名
+func (SearchDictionaryWord) TableName() string {
+	return "search_dictionary_words"
+}
+
+// SearchDictionaryWordRequest 添加分词词条请求
+type SearchDictionaryWordRequest struct {
+	Word      string `json:"word" binding:"required,max=20"`
+	Frequency int    `json:"frequency" binding:"omitempty,min=1,max=100000"`
+}
+
+// SearchDictionaryListRequest 分词词条列表请求
+type SearchDictionaryListRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Keyword  string `form:"keyword" binding:"omitempty,max=20"`
+}
+
+// SegmentPreviewResponse 分词预览响应
+type SegmentPreviewResponse struct {
+	Text   string   `json:"text"`
+	Tokens []string `json:"tokens"` // 精确模式分词，用于解析搜索关键词
+	Index  []string `json:"index"`  // 搜索模式分词，用于构建搜索向量
+}
+
+// SearchReindexResponse 重建搜索索引响应
+type SearchReindexResponse struct {
+	Started bool `json:"started"` // 为 false 表示已有重建任务在执行
+}
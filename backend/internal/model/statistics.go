@@ -136,6 +136,7 @@ type UserDetailResponse struct {
 	DownloadTotal   int64            `json:"download_total"`
 	UploadTotal     int64            `json:"upload_total"`
 	FavoriteTotal   int64            `json:"favorite_total"`
+	StorageUsed     int64            `json:"storage_used"` // 已上传资料占用的存储空间（字节）
 }
 
 // ActivityLog 活动日志
@@ -166,6 +167,11 @@ type UpdateUserStatusRequest struct {
 	Reason string `json:"reason"`
 }
 
+// UpdateUserStorageQuotaRequest 设置用户存储配额请求
+type UpdateUserStorageQuotaRequest struct {
+	QuotaMB *int64 `json:"quota_mb" binding:"omitempty,min=0"` // 存储配额（MB），0 表示不限制，为空时恢复按角色配置
+}
+
 // PageViewRequest 页面浏览请求
 type PageViewRequest struct {
 	Path    string `json:"path" binding:"required"`    // 页面路径
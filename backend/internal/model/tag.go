@@ -0,0 +1,86 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxTagsPerMaterial 每份资料最多的标签数
+	MaxTagsPerMaterial = 10
+	// MaxTagLength 标签最大长度（字符数）
+	MaxTagLength = 20
+)
+
+// Tag 标签
+type Tag struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name string `gorm:"type:varchar(50);not null;uniqueIndex" json:"name"` // 标签名（规范化后）
+}
+
+// TableName 指定表名
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// MaterialTag 资料与标签的关联
+type MaterialTag struct {
+	MaterialID uint      `gorm:"primaryKey" json:"material_id"`
+	TagID      uint      `gorm:"primaryKey;index" json:"tag_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (MaterialTag) TableName() string {
+	return "material_tags"
+}
+
+// TagNames 返回资料的标签名列表
+func (m *Material) TagNames() []string {
+	names := make([]string, 0, len(m.Tags))
+	for _, tag := range m.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// NormalizeTagName 规范化标签名：去掉开头的 #、首尾空白，合并连续空白并转小写
+func NormalizeTagName(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimLeft(name, "#＃")
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// IsValidTagName 规范化后的标签名是否有效
+func IsValidTagName(name string) bool {
+	return name != "" && utf8.RuneCountInString(name) <= MaxTagLength
+}
+
+// TagResponse 标签响应
+type TagResponse struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	MaterialCount int64  `json:"material_count"` // 使用该标签的已通过资料数
+}
+
+// TagListRequest 标签列表请求（管理员）
+type TagListRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Keyword  string `form:"keyword" binding:"omitempty,max=50"`
+}
+
+// RenameTagRequest 重命名标签请求
+type RenameTagRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// MergeTagsRequest 合并标签请求
+type MergeTagsRequest struct {
+	SourceIDs []uint `json:"source_ids" binding:"required,min=1,max=50"` // 被合并的标签，合并后删除
+	TargetID  uint   `json:"target_id" binding:"required"`               // 保留的标签
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// UploadGCRun 孤立上传文件清理记录
+type UploadGCRun struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	DryRun         bool       `gorm:"not null;default:false" json:"dry_run"`     // 是否为演练模式（只统计不删除）
+	GraceHours     int        `gorm:"not null" json:"grace_hours"`               // 宽限期（小时）
+	TriggeredBy    *uint      `gorm:"index" json:"triggered_by,omitempty"`       // 手动触发的管理员ID，定时任务为空
+	ScannedCount   int        `gorm:"not null;default:0" json:"scanned_count"`   // 扫描的文件数
+	OrphanCount    int        `gorm:"not null;default:0" json:"orphan_count"`    // 孤立文件数
+	OrphanBytes    int64      `gorm:"not null;default:0" json:"orphan_bytes"`    // 孤立文件总大小
+	DeletedCount   int        `gorm:"not null;default:0" json:"deleted_count"`   // 已删除文件数
+	ReclaimedBytes int64      `gorm:"not null;default:0" json:"reclaimed_bytes"` // 已回收空间（字节）
+	FailedCount    int        `gorm:"not null;default:0" json:"failed_count"`    // 删除失败的文件数
+	Error          string     `gorm:"type:text" json:"error,omitempty"`          // 执行错误
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`                     // 完成时间
+}
+
+// TableName 指定表名
+func (UploadGCRun) TableName() string {
+	return "upload_gc_runs"
+}
+
+// UploadGCOrphan 孤立文件信息
+type UploadGCOrphan struct {
+	FileKey      string    `json:"file_key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	Deleted      bool      `json:"deleted"`
+}
+
+// RunUploadGCRequest 手动执行清理请求
+type RunUploadGCRequest struct {
+	DryRun     bool `json:"dry_run"`                                       // 是否为演练模式
+	GraceHours *int `json:"grace_hours" binding:"omitempty,min=1,max=720"` // 宽限期（小时），为空时使用系统配置
+}
+
+// UploadGCReport 清理报告
+type UploadGCReport struct {
+	Run     *UploadGCRun      `json:"run"`
+	Orphans []*UploadGCOrphan `json:"orphans"` // 孤立文件明细（最多返回前 200 条）
+}
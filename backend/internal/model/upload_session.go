@@ -0,0 +1,101 @@
+package model
+
+import "time"
+
+// UploadSessionStatus 分片上传会话状态
+type UploadSessionStatus string
+
+const (
+	UploadSessionUploading UploadSessionStatus = "uploading" // 上传中
+	UploadSessionCompleted UploadSessionStatus = "completed" // 已完成
+	UploadSessionAborted   UploadSessionStatus = "aborted"   // 已取消
+	UploadSessionExpired   UploadSessionStatus = "expired"   // 已过期（自动取消）
+)
+
+// UploadSession 分片上传会话模型
+type UploadSession struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID      uint                `gorm:"not null;index" json:"user_id"`                                     // 上传用户ID
+	FileKey     string              `gorm:"type:varchar(500);not null;uniqueIndex" json:"file_key"`            // OSS 存储键
+	FileName    string              `gorm:"type:varchar(255);not null" json:"file_name"`                       // 原始文件名
+	FileSize    int64               `gorm:"not null" json:"file_size"`                                         // 文件大小（字节）
+	MimeType    string              `gorm:"type:varchar(100);not null" json:"mime_type"`                       // MIME 类型
+	OSSUploadID string              `gorm:"column:oss_upload_id;type:varchar(255);not null" json:"-"`          // OSS 分片上传ID
+	PartSize    int64               `gorm:"not null" json:"part_size"`                                         // 分片大小（字节）
+	PartCount   int                 `gorm:"not null" json:"part_count"`                                        // 分片数量
+	Status      UploadSessionStatus `gorm:"type:varchar(20);not null;default:'uploading';index" json:"status"` // 会话状态
+	ExpiresAt   time.Time           `gorm:"not null;index" json:"expires_at"`                                  // 过期时间
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`                                            // 完成时间
+}
+
+// TableName 指定表名
+func (UploadSession) TableName() string {
+	return "upload_sessions"
+}
+
+// CreateUploadSessionRequest 创建分片上传会话请求
+type CreateUploadSessionRequest struct {
+	FileName string `json:"file_name" binding:"required,max=255"`
+	FileSize int64  `json:"file_size" binding:"required,min=1,max=536870912"` // 最大 512MB
+	MimeType string `json:"mime_type" binding:"required"`
+	PartSize int64  `json:"part_size" binding:"omitempty,min=5242880,max=134217728"` // 分片大小，默认 16MB
+}
+
+// PresignUploadPartsRequest 获取分片上传地址请求
+type PresignUploadPartsRequest struct {
+	PartNumbers []int `json:"part_numbers" binding:"required,min=1,max=100,dive,min=1,max=10000"`
+}
+
+// UploadPartURL 分片上传地址
+type UploadPartURL struct {
+	PartNumber int    `json:"part_number"`
+	UploadURL  string `json:"upload_url"`
+}
+
+// PresignUploadPartsResponse 获取分片上传地址响应
+type PresignUploadPartsResponse struct {
+	Parts     []*UploadPartURL `json:"parts"`
+	ExpiresIn int64            `json:"expires_in"` // 上传地址有效期（秒）
+}
+
+// UploadedPartInfo 已上传分片信息
+type UploadedPartInfo struct {
+	PartNumber int    `json:"part_number"`
+	Size       int64  `json:"size"`
+	ETag       string `json:"etag"`
+}
+
+// UploadSessionResponse 分片上传会话响应
+type UploadSessionResponse struct {
+	ID            uint                `json:"id"`
+	FileKey       string              `json:"file_key"`
+	FileName      string              `json:"file_name"`
+	FileSize      int64               `json:"file_size"`
+	MimeType      string              `json:"mime_type"`
+	PartSize      int64               `json:"part_size"`
+	PartCount     int                 `json:"part_count"`
+	Status        UploadSessionStatus `json:"status"`
+	UploadedParts []*UploadedPartInfo `json:"uploaded_parts"` // 已上传的分片，用于断点续传
+	ExpiresAt     string              `json:"expires_at"`
+	CreatedAt     string              `json:"created_at"`
+}
+
+// ToUploadSessionResponse 将 UploadSession 转换为 UploadSessionResponse
+func (s *UploadSession) ToUploadSessionResponse() *UploadSessionResponse {
+	return &UploadSessionResponse{
+		ID:            s.ID,
+		FileKey:       s.FileKey,
+		FileName:      s.FileName,
+		FileSize:      s.FileSize,
+		MimeType:      s.MimeType,
+		PartSize:      s.PartSize,
+		PartCount:     s.PartCount,
+		Status:        s.Status,
+		UploadedParts: make([]*UploadedPartInfo, 0),
+		ExpiresAt:     s.ExpiresAt.Format("2006-01-02 15:04:05"),
+		CreatedAt:     s.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -44,6 +44,10 @@ type User struct {
 	Class    string     `gorm:"type:varchar(50)" json:"class"`                                 // 班级
 	LastLoginAt *time.Time `json:"last_login_at"`                                             // 最后登录时间
 	EmailVerified bool     `gorm:"default:false" json:"email_verified"`                       // 邮箱是否已验证
+	StorageQuotaMB *int64  `gorm:"column:storage_quota_mb" json:"storage_quota_mb"`            // 管理员为该用户单独设置的存储配额（MB），为空时按角色配置，0 表示不限制
+	DownloadDailyLimit  *int `gorm:"column:download_daily_limit" json:"download_daily_limit"`   // 管理员为该用户单独设置的每日下载次数，为空时按角色配置，0 表示不限制
+	DownloadWeeklyLimit *int `gorm:"column:download_weekly_limit" json:"download_weekly_limit"` // 每周下载次数，规则同上
+	DownloadBurstLimit  *int `gorm:"column:download_burst_limit" json:"download_burst_limit"`   // 每分钟下载次数，规则同上
 }
 
 // TableName 指定表名
@@ -16,6 +16,7 @@ type Config struct {
 	OSS      OSSConfig      `mapstructure:"oss"`
 	SMTP     SMTPConfig     `mapstructure:"smtp"`
 	Log      LogConfig      `mapstructure:"log"`
+	Search   SearchConfig   `mapstructure:"search"`
 }
 
 // ServerConfig 服务器配置
@@ -55,13 +56,15 @@ type JWTConfig struct {
 
 // OSSConfig OSS配置
 type OSSConfig struct {
-	Provider   string `mapstructure:"provider"` // minio, aliyun
+	Provider   string `mapstructure:"provider"` // minio, aliyun, filesystem
 	Endpoint   string `mapstructure:"endpoint"`
 	AccessKey  string `mapstructure:"access_key"`
-	SecretKey  string `mapstructure:"secret_key"`
+	SecretKey  string `mapstructure:"secret_key"` // filesystem 模式下用作 URL 签名密钥
 	BucketName string `mapstructure:"bucket_name"`
 	Region     string `mapstructure:"region"`
 	UseSSL     bool   `mapstructure:"use_ssl"`
+	LocalDir   string `mapstructure:"local_dir"`  // filesystem 模式的存储目录
+	PublicURL  string `mapstructure:"public_url"` // filesystem 模式签名 URL 的访问地址，如 http://localhost:8080
 }
 
 // SMTPConfig SMTP邮件配置
@@ -83,6 +86,13 @@ type LogConfig struct {
 	MaxAge     int    `mapstructure:"max_age"` // days
 }
 
+// SearchConfig 搜索配置
+type SearchConfig struct {
+	HighlightPreTag  string `mapstructure:"highlight_pre_tag"`  // 高亮开始标记，默认 <em>
+	HighlightPostTag string `mapstructure:"highlight_post_tag"` // 高亮结束标记，默认 </em>
+	SnippetLength    int    `mapstructure:"snippet_length"`     // 摘要最大字数，默认 120
+}
+
 var globalConfig *Config
 
 // Load 加载配置文件
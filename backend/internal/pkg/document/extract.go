@@ -0,0 +1,233 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	mimeTypeDocx = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	mimeTypePptx = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+
+	// maxXMLPartSize 单个 OOXML 部件解压后的最大大小，防止压缩炸弹
+	maxXMLPartSize = 32 * 1024 * 1024
+)
+
+// errTextLimitReached 已提取足够的文本
+var errTextLimitReached = errors.New("已达到文本长度上限")
+
+// SupportsTextExtraction 是否支持从该类型的文件中提取文本
+func SupportsTextExtraction(mimeType string) bool {
+	switch documentKind(mimeType) {
+	case "pdf", "docx", "pptx", "markdown", "text":
+		return true
+	}
+	return false
+}
+
+// ExtractText 提取文档的全文，最多返回 maxRunes 个字符
+// 支持 PDF、DOCX、PPTX、纯文本和 Markdown，段落之间以换行分隔
+func ExtractText(mimeType string, data []byte, maxRunes int) (string, error) {
+	if maxRunes <= 0 {
+		return "", nil
+	}
+
+	w := newTextWriter(maxRunes)
+	var err error
+	switch documentKind(mimeType) {
+	case "pdf":
+		err = extractPDFText(data, w)
+	case "docx":
+		err = extractDocxText(data, w)
+	case "pptx":
+		err = extractPptxText(data, w)
+	case "markdown":
+		w.WriteString(StripMarkdown(PlainText(data)))
+	case "text":
+		w.WriteString(PlainText(data))
+	default:
+		return "", ErrUnsupportedType
+	}
+	if err != nil && !errors.Is(err, errTextLimitReached) {
+		return "", err
+	}
+	return w.String(), nil
+}
+
+// extractPDFText 逐页提取 PDF 文本
+func extractPDFText(data []byte, w *textWriter) error {
+	doc, err := ParsePDF(data)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < doc.NumPages(); i++ {
+		text := doc.PageText(i)
+		if doc.limitErr != nil {
+			return doc.limitErr
+		}
+		w.WriteString(text)
+		w.Newline()
+		if w.Full() {
+			return errTextLimitReached
+		}
+	}
+	return nil
+}
+
+// extractDocxText 提取 Word 文档正文（word/document.xml）
+func extractDocxText(data []byte, w *textWriter) error {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("读取 docx 失败: %w", err)
+	}
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			return extractOOXMLText(file, w, "t", "p")
+		}
+	}
+	return fmt.Errorf("读取 docx 失败: 缺少 word/document.xml")
+}
+
+// extractPptxText 按页码顺序提取演示文稿中每页幻灯片的文本
+func extractPptxText(data []byte, w *textWriter) error {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("读取 pptx 失败: %w", err)
+	}
+
+	type slide struct {
+		number int
+		file   *zip.File
+	}
+	slides := make([]slide, 0)
+	for _, file := range archive.File {
+		dir, name := path.Split(file.Name)
+		if dir != "ppt/slides/" || !strings.HasPrefix(name, "slide") || !strings.HasSuffix(name, ".xml") {
+			continue
+		}
+		number, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "slide"), ".xml"))
+		if err != nil {
+			continue
+		}
+		slides = append(slides, slide{number: number, file: file})
+	}
+	sort.Slice(slides, func(i, j int) bool { return slides[i].number < slides[j].number })
+
+	for _, s := range slides {
+		if err := extractOOXMLText(s.file, w, "t", "p"); err != nil {
+			return err
+		}
+		w.Newline()
+	}
+	return nil
+}
+
+// extractOOXMLText 流式解析 OOXML 部件，收集 textElement 元素的文本，在 paragraphElement 结束时换行
+// Word 的 w:t/w:p 与 PowerPoint 的 a:t/a:p 本地名称相同，这里只比较本地名称
+func extractOOXMLText(file *zip.File, w *textWriter, textElement, paragraphElement string) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	decoder := xml.NewDecoder(io.LimitReader(rc, maxXMLPartSize))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("解析 %s 失败: %w", file.Name, err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case textElement:
+				inText = true
+			case "tab":
+				w.WriteString("\t")
+			case "br", "cr":
+				w.Newline()
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case textElement:
+				inText = false
+			case paragraphElement:
+				w.Newline()
+				if w.Full() {
+					return errTextLimitReached
+				}
+			}
+		case xml.CharData:
+			if inText {
+				w.WriteString(string(t))
+			}
+		}
+	}
+}
+
+// textWriter 限制长度的文本缓冲，去除控制字符并合并连续空行
+type textWriter struct {
+	sb       strings.Builder
+	runes    int
+	maxRunes int
+}
+
+// newTextWriter 创建最多保存 maxRunes 个字符的文本缓冲
+func newTextWriter(maxRunes int) *textWriter {
+	return &textWriter{maxRunes: maxRunes}
+}
+
+// WriteString 追加文本，超出上限的部分被丢弃
+func (w *textWriter) WriteString(s string) {
+	for _, r := range s {
+		if w.Full() {
+			return
+		}
+		if r == utf8.RuneError || r == 0 {
+			continue
+		}
+		if r == '\n' {
+			w.Newline()
+			continue
+		}
+		if unicode.IsControl(r) && r != '\t' {
+			continue
+		}
+		w.sb.WriteRune(r)
+		w.runes++
+	}
+}
+
+// Newline 换行，不产生连续的空行
+func (w *textWriter) Newline() {
+	if w.Full() || w.sb.Len() == 0 || strings.HasSuffix(w.sb.String(), "\n") {
+		return
+	}
+	w.sb.WriteByte('\n')
+	w.runes++
+}
+
+// Full 是否已达到长度上限
+func (w *textWriter) Full() bool {
+	return w.runes >= w.maxRunes
+}
+
+// String 返回提取的文本
+func (w *textWriter) String() string {
+	return strings.TrimSpace(w.sb.String())
+}
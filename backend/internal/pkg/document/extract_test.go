@@ -0,0 +1,99 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"unicode/utf8"
+)
+
+// buildOOXML 生成包含指定部件的 OOXML 压缩包
+func buildOOXML(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for name, content := range parts {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("创建 zip 条目失败: %v", err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("生成 zip 失败: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractText(t *testing.T) {
+	docx := buildOOXML(t, map[string]string{
+		"[Content_Types].xml": "<Types/>",
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+			`<w:p><w:r><w:t>傅里叶</w:t></w:r><w:r><w:t xml:space="preserve">变换</w:t></w:r></w:p>` +
+			`<w:p><w:r><w:t>F(ω)</w:t><w:tab/><w:t>= ∫f(t)dt</w:t></w:r></w:p>` +
+			`</w:body></w:document>`,
+	})
+	pptx := buildOOXML(t, map[string]string{
+		"ppt/presentation.xml":             "<p:presentation/>",
+		"ppt/slides/slide10.xml":           `<p:sld xmlns:p="p" xmlns:a="a"><p:txBody><a:p><a:r><a:t>第十页</a:t></a:r></a:p></p:txBody></p:sld>`,
+		"ppt/slides/slide2.xml":            `<p:sld xmlns:p="p" xmlns:a="a"><p:txBody><a:p><a:r><a:t>第二页</a:t></a:r></a:p></p:txBody></p:sld>`,
+		"ppt/slides/_rels/slide2.xml.rels": "<Relationships/>",
+	})
+	pdf := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 5 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		streamObject("", []byte("BT (Eigenvalue) Tj ET")),
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R >>",
+		streamObject("", []byte("BT (Eigenvector) Tj ET")),
+	)
+
+	tests := []struct {
+		name     string
+		mimeType string
+		data     []byte
+		want     string
+	}{
+		{"PDF", "application/pdf", pdf, "Eigenvalue\nEigenvector"},
+		{"DOCX", mimeTypeDocx, docx, "傅里叶变换\nF(ω)\t= ∫f(t)dt"},
+		{"PPTX 按页码排序", mimeTypePptx, pptx, "第二页\n第十页"},
+		{"纯文本", "text/plain", []byte("第一行\r\n\r\n\r\n第二行\x00"), "第一行\n第二行"},
+		{"Markdown", "text/markdown", []byte("## 拉普拉斯变换\n\n`L{f}`"), "拉普拉斯变换\nL{f}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractText(tt.mimeType, tt.data, 1000)
+			if err != nil {
+				t.Fatalf("ExtractText() 失败: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractTextLimit(t *testing.T) {
+	text := bytes.Repeat([]byte("矩阵"), 100)
+	got, err := ExtractText("text/plain", text, 15)
+	if err != nil {
+		t.Fatalf("ExtractText() 失败: %v", err)
+	}
+	if n := utf8.RuneCountInString(got); n != 15 {
+		t.Errorf("ExtractText() 长度 = %d, want 15", n)
+	}
+}
+
+func TestExtractTextErrors(t *testing.T) {
+	if _, err := ExtractText("application/zip", []byte("PK"), 100); err != ErrUnsupportedType {
+		t.Errorf("ExtractText() 错误 = %v, want %v", err, ErrUnsupportedType)
+	}
+	if _, err := ExtractText(mimeTypeDocx, []byte("not a zip"), 100); err == nil {
+		t.Errorf("ExtractText() 应返回错误")
+	}
+	missing := buildOOXML(t, map[string]string{"word/styles.xml": "<w:styles/>"})
+	if _, err := ExtractText(mimeTypeDocx, missing, 100); err == nil {
+		t.Errorf("ExtractText() 缺少正文时应返回错误")
+	}
+}
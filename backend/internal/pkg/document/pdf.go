@@ -0,0 +1,743 @@
+package document
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	// ErrInvalidPDF 无效的 PDF 文件
+	ErrInvalidPDF = errors.New("无效的 PDF 文件")
+	// ErrNoImage 页面中没有可用的图像
+	ErrNoImage = errors.New("页面中没有可解码的图像")
+	// ErrPDFTooLarge 解压后的内容超过限制
+	ErrPDFTooLarge = errors.New("PDF 解压后的内容过大")
+)
+
+var objectHeaderPattern = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+const (
+	// maxPageTreeDepth 页面树的最大深度，防止循环引用
+	maxPageTreeDepth = 32
+	// maxImagePixels 解码图像的最大像素数，防止构造的超大尺寸耗尽内存或使运算溢出
+	maxImagePixels = 40_000_000
+	// maxStreamSize 单个流解压后的最大字节数，可容纳 maxImagePixels 的 RGB 图像
+	maxStreamSize = 128 << 20
+	// maxDecodedSize 一份文档累计解压的最大字节数，防止压缩炸弹耗尽内存
+	maxDecodedSize = 256 << 20
+)
+
+// PDF 解析后的 PDF 文档
+// 仅实现预览和文本提取所需的子集：对象（含对象流）、页面树、内容流文本和页面内嵌图像
+type PDF struct {
+	objects  map[int]interface{}
+	root     pdfRef
+	trailer  pdfDict
+	pages    []pdfDict
+	pageRefs []pdfRef // 页面对象的引用，与 pages 一一对应，直接内嵌的页面为零值
+	decoded  int      // 已累计解压的字节数
+	limitErr error    // 解压超过限制时记录的错误，之后不再解压任何流
+}
+
+// ParsePDF 解析 PDF 文档
+// 不依赖 xref 表，直接扫描文件中的全部对象，对损坏或增量更新的文件也有较好的容错性
+func ParsePDF(data []byte) (*PDF, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\r\n "), []byte("%PDF-")) {
+		return nil, ErrInvalidPDF
+	}
+
+	doc := &PDF{objects: make(map[int]interface{})}
+	doc.scanObjects(data)
+	doc.loadObjectStreams()
+	if doc.limitErr != nil {
+		return nil, doc.limitErr
+	}
+
+	if doc.root.num == 0 {
+		// 缺少 trailer 时查找 Catalog 对象
+		for num, obj := range doc.objects {
+			if dict, ok := obj.(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+				doc.root = pdfRef{num: num}
+				break
+			}
+		}
+	}
+
+	catalog, ok := doc.resolve(doc.root).(pdfDict)
+	if !ok {
+		return nil, ErrInvalidPDF
+	}
+	doc.collectPages(catalog["Pages"], nil, 0, make(map[int]bool))
+	if len(doc.pages) == 0 {
+		return nil, ErrInvalidPDF
+	}
+	return doc, nil
+}
+
+// NumPages 页数
+func (d *PDF) NumPages() int {
+	return len(d.pages)
+}
+
+// scanObjects 扫描文件中所有 "n g obj" 对象，后出现的对象覆盖先出现的（增量更新）
+func (d *PDF) scanObjects(data []byte) {
+	for _, match := range objectHeaderPattern.FindAllSubmatchIndex(data, -1) {
+		num, err := strconv.Atoi(string(data[match[2]:match[3]]))
+		if err != nil {
+			continue
+		}
+
+		lexer := &pdfLexer{data: data, pos: match[1]}
+		obj, err := lexer.readObject()
+		if err != nil {
+			continue
+		}
+
+		dict, isDict := obj.(pdfDict)
+		if isDict {
+			lexer.skipSpace()
+			if bytes.HasPrefix(data[lexer.pos:], []byte("stream")) {
+				obj = d.readStream(data, lexer.pos+len("stream"), dict)
+			}
+			// trailer 信息也可能在交叉引用流中
+			if dict["Type"] == pdfName("XRef") {
+				d.trailer = dict
+				if root, ok := dict["Root"].(pdfRef); ok {
+					d.root = root
+				}
+			}
+		}
+		d.objects[num] = obj
+	}
+
+	// 传统 trailer，取最后一个
+	if idx := bytes.LastIndex(data, []byte("trailer")); idx >= 0 {
+		lexer := &pdfLexer{data: data, pos: idx + len("trailer")}
+		if obj, err := lexer.readObject(); err == nil {
+			if dict, ok := obj.(pdfDict); ok {
+				d.trailer = dict
+				if root, ok := dict["Root"].(pdfRef); ok {
+					d.root = root
+				}
+			}
+		}
+	}
+}
+
+// readStream 读取流数据，优先使用 /Length，不可用时查找 endstream
+func (d *PDF) readStream(data []byte, start int, dict pdfDict) *pdfStream {
+	if start < len(data) && data[start] == '\r' {
+		start++
+	}
+	if start < len(data) && data[start] == '\n' {
+		start++
+	}
+
+	if length, ok := dict["Length"].(float64); ok {
+		end := start + int(length)
+		if end <= len(data) && end >= start {
+			rest := bytes.TrimLeft(data[end:min(end+32, len(data))], "\r\n \t")
+			if bytes.HasPrefix(rest, []byte("endstream")) {
+				return &pdfStream{dict: dict, raw: data[start:end]}
+			}
+		}
+	}
+
+	end := bytes.Index(data[start:], []byte("endstream"))
+	if end < 0 {
+		return &pdfStream{dict: dict, raw: data[start:]}
+	}
+	raw := data[start : start+end]
+	raw = bytes.TrimSuffix(raw, []byte("\n"))
+	raw = bytes.TrimSuffix(raw, []byte("\r"))
+	return &pdfStream{dict: dict, raw: raw}
+}
+
+// loadObjectStreams 展开对象流（PDF 1.5+ 将大部分对象压缩存放在 ObjStm 中）
+func (d *PDF) loadObjectStreams() {
+	streams := make([]*pdfStream, 0)
+	for _, obj := range d.objects {
+		if stream, ok := obj.(*pdfStream); ok && stream.dict["Type"] == pdfName("ObjStm") {
+			streams = append(streams, stream)
+		}
+	}
+
+	for _, stream := range streams {
+		data, filter, err := d.decodeStream(stream)
+		if err != nil || filter != "" {
+			continue
+		}
+		n, _ := d.resolve(stream.dict["N"]).(float64)
+		first, _ := d.resolve(stream.dict["First"]).(float64)
+		if int(first) > len(data) {
+			continue
+		}
+
+		header := &pdfLexer{data: data[:int(first)]}
+		for i := 0; i < int(n); i++ {
+			numObj, err1 := header.readObject()
+			offsetObj, err2 := header.readObject()
+			if err1 != nil || err2 != nil {
+				break
+			}
+			num, ok1 := numObj.(float64)
+			offset, ok2 := offsetObj.(float64)
+			if !ok1 || !ok2 || int(first+offset) >= len(data) {
+				continue
+			}
+			if _, exists := d.objects[int(num)]; exists {
+				continue
+			}
+			lexer := &pdfLexer{data: data, pos: int(first + offset)}
+			if obj, err := lexer.readObject(); err == nil {
+				d.objects[int(num)] = obj
+			}
+		}
+	}
+}
+
+// decodeStream 解码流数据，单个流和整份文档累计解压的字节数都受限制
+// 超过限制时返回 ErrPDFTooLarge 并记录在 limitErr 中；PageText 等不返回错误的方法此时内容不完整，
+// 包内的入口函数在处理完成后检查 limitErr，放弃整个文档而不是使用不完整的内容
+func (d *PDF) decodeStream(stream *pdfStream) ([]byte, string, error) {
+	if d.limitErr != nil {
+		return nil, "", d.limitErr
+	}
+	limit := maxDecodedSize - d.decoded
+	if limit > maxStreamSize {
+		limit = maxStreamSize
+	}
+	data, filter, err := decodeStream(stream, d.resolve, limit)
+	if errors.Is(err, ErrPDFTooLarge) {
+		d.limitErr = err
+	}
+	d.decoded += len(data)
+	return data, filter, err
+}
+
+// resolve 解析间接引用
+func (d *PDF) resolve(obj interface{}) interface{} {
+	for i := 0; i < 8; i++ {
+		ref, ok := obj.(pdfRef)
+		if !ok {
+			return obj
+		}
+		obj = d.objects[ref.num]
+	}
+	return nil
+}
+
+// resolveDict 解析为字典（流对象返回其字典）
+func (d *PDF) resolveDict(obj interface{}) pdfDict {
+	switch v := d.resolve(obj).(type) {
+	case pdfDict:
+		return v
+	case *pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+// inheritablePageKeys 页面可从父节点继承的属性
+var inheritablePageKeys = []string{"Resources", "MediaBox", "CropBox", "Rotate"}
+
+// collectPages 深度优先遍历页面树，继承父节点的 Resources、MediaBox 等属性
+func (d *PDF) collectPages(node interface{}, inherited pdfDict, depth int, visited map[int]bool) {
+	if depth > maxPageTreeDepth {
+		return
+	}
+	ref, isRef := node.(pdfRef)
+	if isRef {
+		if visited[ref.num] {
+			return
+		}
+		visited[ref.num] = true
+	}
+
+	dict := d.resolveDict(node)
+	if dict == nil {
+		return
+	}
+	attrs := pdfDict{}
+	for k, v := range inherited {
+		attrs[k] = v
+	}
+	for _, key := range inheritablePageKeys {
+		if value, ok := dict[key]; ok {
+			attrs[key] = value
+		}
+	}
+	if resources := d.resolveDict(dict["Resources"]); resources != nil {
+		attrs["Resources"] = resources
+	}
+
+	if kids, ok := d.resolve(dict["Kids"]).(pdfArray); ok {
+		for _, kid := range kids {
+			d.collectPages(kid, attrs, depth+1, visited)
+		}
+		return
+	}
+
+	page := pdfDict{}
+	for k, v := range dict {
+		page[k] = v
+	}
+	for k, v := range attrs {
+		page[k] = v
+	}
+	d.pages = append(d.pages, page)
+	if !isRef {
+		ref = pdfRef{}
+	}
+	d.pageRefs = append(d.pageRefs, ref)
+}
+
+// pageContent 获取页面内容流（多个内容流按顺序拼接）
+func (d *PDF) pageContent(page pdfDict) []byte {
+	var streams []interface{}
+	switch contents := d.resolve(page["Contents"]).(type) {
+	case *pdfStream:
+		streams = append(streams, contents)
+	case pdfArray:
+		streams = contents
+	}
+
+	var buf bytes.Buffer
+	for _, item := range streams {
+		stream, ok := d.resolve(item).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, filter, err := d.decodeStream(stream)
+		if err != nil || filter != "" {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// PageText 提取指定页（从 0 开始）的文本
+func (d *PDF) PageText(index int) string {
+	if index < 0 || index >= len(d.pages) {
+		return ""
+	}
+	page := d.pages[index]
+	fonts := d.pageFonts(page)
+	return extractContentText(d.pageContent(page), fonts)
+}
+
+// Text 提取前 maxPages 页的文本，maxPages <= 0 时提取全部
+func (d *PDF) Text(maxPages int) string {
+	if maxPages <= 0 || maxPages > len(d.pages) {
+		maxPages = len(d.pages)
+	}
+	var sb strings.Builder
+	for i := 0; i < maxPages; i++ {
+		text := d.PageText(i)
+		if text == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+// pageFonts 解析页面使用的字体编码
+func (d *PDF) pageFonts(page pdfDict) map[string]*pdfFont {
+	fonts := make(map[string]*pdfFont)
+	resources := d.resolveDict(page["Resources"])
+	if resources == nil {
+		return fonts
+	}
+	fontDict := d.resolveDict(resources["Font"])
+	for name, ref := range fontDict {
+		font := d.resolveDict(ref)
+		if font == nil {
+			continue
+		}
+		fonts[name] = d.loadFont(font)
+	}
+	return fonts
+}
+
+// loadFont 读取字体的 ToUnicode 映射
+func (d *PDF) loadFont(font pdfDict) *pdfFont {
+	f := &pdfFont{codeLen: 1}
+	if font["Subtype"] == pdfName("Type0") {
+		f.codeLen = 2
+	}
+	if stream, ok := d.resolve(font["ToUnicode"]).(*pdfStream); ok {
+		if data, filter, err := d.decodeStream(stream); err == nil && filter == "" {
+			f.parseCMap(data)
+		}
+	}
+	return f
+}
+
+// PageImage 获取指定页中面积最大的图像
+// 支持 JPEG（DCTDecode）和 8 位 DeviceRGB/DeviceGray 的无损压缩图像
+func (d *PDF) PageImage(index int) (image.Image, error) {
+	if index < 0 || index >= len(d.pages) {
+		return nil, ErrNoImage
+	}
+	resources := d.resolveDict(d.pages[index]["Resources"])
+	if resources == nil {
+		return nil, ErrNoImage
+	}
+
+	var best image.Image
+	bestArea := 0
+	for _, ref := range d.resolveDict(resources["XObject"]) {
+		stream, ok := d.resolve(ref).(*pdfStream)
+		if !ok || stream.dict["Subtype"] != pdfName("Image") {
+			continue
+		}
+		width, _ := d.resolve(stream.dict["Width"]).(float64)
+		height, _ := d.resolve(stream.dict["Height"]).(float64)
+		if !validImageSize(width, height) {
+			continue
+		}
+		area := int(width) * int(height)
+		if area <= bestArea {
+			continue
+		}
+		img, err := d.decodeImage(stream, int(width), int(height))
+		if err != nil {
+			continue
+		}
+		best, bestArea = img, area
+	}
+
+	if d.limitErr != nil {
+		return nil, d.limitErr
+	}
+	if best == nil {
+		return nil, ErrNoImage
+	}
+	return best, nil
+}
+
+// decodeImage 解码图像 XObject
+func (d *PDF) decodeImage(stream *pdfStream, width, height int) (image.Image, error) {
+	data, filter, err := d.decodeStream(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	switch filter {
+	case "DCTDecode", "DCT":
+		// JPEG 的实际尺寸以文件头为准，解码前先检查
+		config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if !validImageSize(float64(config.Width), float64(config.Height)) {
+			return nil, ErrNoImage
+		}
+		return jpeg.Decode(bytes.NewReader(data))
+	case "":
+	default:
+		return nil, ErrNoImage
+	}
+
+	bits, _ := d.resolve(stream.dict["BitsPerComponent"]).(float64)
+	if bits != 8 || !validImageSize(float64(width), float64(height)) {
+		return nil, ErrNoImage
+	}
+
+	// 尺寸已限制在 maxImagePixels 以内，像素数乘以通道数不会溢出；按除法比较避免依赖这一前提
+	pixels := width * height
+	colorSpace, _ := d.resolve(stream.dict["ColorSpace"]).(pdfName)
+	switch colorSpace {
+	case "DeviceRGB":
+		if len(data)/3 < pixels {
+			return nil, ErrNoImage
+		}
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		for i := 0; i < pixels; i++ {
+			img.Pix[i*4] = data[i*3]
+			img.Pix[i*4+1] = data[i*3+1]
+			img.Pix[i*4+2] = data[i*3+2]
+			img.Pix[i*4+3] = 0xff
+		}
+		return img, nil
+	case "DeviceGray":
+		if len(data) < pixels {
+			return nil, ErrNoImage
+		}
+		img := image.NewGray(image.Rect(0, 0, width, height))
+		copy(img.Pix, data[:pixels])
+		return img, nil
+	}
+	return nil, ErrNoImage
+}
+
+// validImageSize 图像宽高是否为正且像素数不超过 maxImagePixels
+// 以浮点数比较，避免超大的宽高在转换为 int 或相乘时溢出
+func validImageSize(width, height float64) bool {
+	return width >= 1 && height >= 1 && width*height <= maxImagePixels
+}
+
+// pdfFont 字体编码信息
+type pdfFont struct {
+	codeLen int
+	cmap    map[string]string
+}
+
+// parseCMap 解析 ToUnicode CMap 中的 codespacerange、bfchar 和 bfrange
+func (f *pdfFont) parseCMap(data []byte) {
+	f.cmap = make(map[string]string)
+	lexer := &pdfLexer{data: data}
+	var operands []interface{}
+
+	for !lexer.eof() {
+		obj, err := lexer.readObject()
+		if err != nil {
+			return
+		}
+		op, isOp := obj.(pdfOperator)
+		if !isOp {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if lo, ok := operands[0].(pdfString); ok && len(lo) > 0 {
+					f.codeLen = len(lo)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					f.cmap[string(src)] = decodeUTF16BE(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) == 0 {
+					continue
+				}
+				f.addRange(lo, hi, operands[i+2])
+			}
+		}
+		if strings.HasPrefix(string(op), "end") || strings.HasPrefix(string(op), "begin") {
+			operands = operands[:0]
+		}
+	}
+}
+
+// addRange 添加 bfrange 映射，目标为起始码或逐个码的数组
+func (f *pdfFont) addRange(lo, hi pdfString, dst interface{}) {
+	start := bytesToInt(lo)
+	end := bytesToInt(hi)
+	if end < start || end-start > 0xffff {
+		return
+	}
+
+	for code := start; code <= end; code++ {
+		key := string(intToBytes(code, len(lo)))
+		switch v := dst.(type) {
+		case pdfString:
+			if len(v) == 0 {
+				continue
+			}
+			target := append([]byte{}, v...)
+			// 最后一个字节递增
+			offset := code - start
+			last := int(target[len(target)-1]) + offset
+			target[len(target)-1] = byte(last & 0xff)
+			if last > 0xff && len(target) >= 2 {
+				target[len(target)-2] += byte(last >> 8)
+			}
+			f.cmap[key] = decodeUTF16BE(target)
+		case pdfArray:
+			if idx := code - start; idx < len(v) {
+				if s, ok := v[idx].(pdfString); ok {
+					f.cmap[key] = decodeUTF16BE(s)
+				}
+			}
+		}
+	}
+}
+
+// decode 将字符串按字体编码转为文本
+func (f *pdfFont) decode(s pdfString) string {
+	if f == nil || f.cmap == nil || len(f.cmap) == 0 {
+		if f != nil && f.codeLen == 2 {
+			// 无 ToUnicode 的 CID 字体无法还原文本
+			return ""
+		}
+		return decodeLatin1(s)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		n := f.codeLen
+		if i+n > len(s) {
+			n = len(s) - i
+		}
+		if text, ok := f.cmap[string(s[i:i+n])]; ok {
+			sb.WriteString(text)
+		} else if n == 1 && s[i] >= 0x20 && s[i] < 0x7f {
+			sb.WriteByte(s[i])
+		}
+		i += n
+	}
+	return sb.String()
+}
+
+// decodeLatin1 按 Latin-1 解码单字节字符串，忽略控制字符
+func decodeLatin1(s []byte) string {
+	var sb strings.Builder
+	for _, b := range s {
+		if b >= 0x20 && b != 0x7f {
+			sb.WriteRune(rune(b))
+		}
+	}
+	return sb.String()
+}
+
+// decodeUTF16BE 解码 UTF-16BE 字符串
+func decodeUTF16BE(s []byte) string {
+	if len(s)%2 == 1 {
+		s = append(s, 0)
+	}
+	units := make([]uint16, 0, len(s)/2)
+	for i := 0; i+1 < len(s); i += 2 {
+		units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// bytesToInt 大端字节转整数
+func bytesToInt(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+// intToBytes 整数转指定长度的大端字节
+func intToBytes(v, n int) []byte {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v & 0xff)
+		v >>= 8
+	}
+	return b
+}
+
+// extractContentText 从内容流中提取文本
+func extractContentText(content []byte, fonts map[string]*pdfFont) string {
+	lexer := &pdfLexer{data: content}
+	var sb strings.Builder
+	var operands []interface{}
+	var font *pdfFont
+
+	newline := func() {
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	space := func() {
+		s := sb.String()
+		if sb.Len() > 0 && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+			sb.WriteString(" ")
+		}
+	}
+
+	for !lexer.eof() {
+		obj, err := lexer.readObject()
+		if err != nil {
+			break
+		}
+		op, isOp := obj.(pdfOperator)
+		if !isOp {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "Tf":
+			if len(operands) >= 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					font = fonts[string(name)]
+				}
+			}
+		case "Tj":
+			if s, ok := lastString(operands); ok {
+				sb.WriteString(font.decode(s))
+			}
+		case "'", "\"":
+			newline()
+			if s, ok := lastString(operands); ok {
+				sb.WriteString(font.decode(s))
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				if arr, ok := operands[len(operands)-1].(pdfArray); ok {
+					for _, item := range arr {
+						switch v := item.(type) {
+						case pdfString:
+							sb.WriteString(font.decode(v))
+						case float64:
+							// 较大的负偏移通常表示单词间距
+							if v < -180 {
+								space()
+							}
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, ok := operands[1].(float64); ok && ty != 0 {
+					newline()
+				} else {
+					space()
+				}
+			}
+		case "T*", "ET":
+			newline()
+		case "Tm":
+			space()
+		case "BI":
+			// 跳过内联图像数据
+			if idx := bytes.Index(content[lexer.pos:], []byte("EI")); idx >= 0 {
+				lexer.pos += idx + 2
+			} else {
+				lexer.pos = len(content)
+			}
+		}
+		operands = operands[:0]
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// lastString 获取最后一个字符串操作数
+func lastString(operands []interface{}) (pdfString, bool) {
+	if len(operands) == 0 {
+		return nil, false
+	}
+	s, ok := operands[len(operands)-1].(pdfString)
+	return s, ok
+}
@@ -0,0 +1,385 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// PDF 对象类型
+type (
+	pdfName     string
+	pdfString   []byte
+	pdfArray    []interface{}
+	pdfDict     map[string]interface{}
+	pdfOperator string
+	pdfRef      struct{ num, gen int }
+	pdfStream   struct {
+		dict pdfDict
+		raw  []byte
+	}
+)
+
+var errUnexpectedEOF = errors.New("PDF 内容意外结束")
+
+// pdfLexer PDF 词法/语法解析器，同时用于解析对象和内容流
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+// isPDFSpace 是否为 PDF 空白字符
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+// isPDFDelimiter 是否为 PDF 分隔符
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// skipSpace 跳过空白和注释
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFSpace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		break
+	}
+}
+
+// eof 是否已读完
+func (l *pdfLexer) eof() bool {
+	l.skipSpace()
+	return l.pos >= len(l.data)
+}
+
+// readObject 读取一个对象；遇到内容流中的操作符时返回 pdfOperator
+func (l *pdfLexer) readObject() (interface{}, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, errUnexpectedEOF
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		return l.readDict()
+	case c == '<':
+		return l.readHexString()
+	case c == '(':
+		return l.readLiteralString()
+	case c == '[':
+		return l.readArray()
+	case c == '/':
+		return l.readName(), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.readNumberOrRef()
+	case c == ']' || c == '>' || c == ')' || c == '}' || c == '{':
+		// 不成对的分隔符，作为操作符返回由调用方处理
+		l.pos++
+		return pdfOperator(string(c)), nil
+	}
+
+	token := l.readToken()
+	switch token {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return pdfOperator(token), nil
+}
+
+// readToken 读取到下一个空白或分隔符为止
+func (l *pdfLexer) readToken() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		// 防止死循环
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+// readName 读取名称对象，处理 #xx 转义
+func (l *pdfLexer) readName() pdfName {
+	l.pos++ // 跳过 '/'
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	raw := l.data[start:l.pos]
+	if bytes.IndexByte(raw, '#') < 0 {
+		return pdfName(raw)
+	}
+
+	var buf []byte
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if b, err := hex.DecodeString(string(raw[i+1 : i+3])); err == nil {
+				buf = append(buf, b[0])
+				i += 2
+				continue
+			}
+		}
+		buf = append(buf, raw[i])
+	}
+	return pdfName(buf)
+}
+
+// readNumberOrRef 读取数字，"n g R" 形式时返回间接引用
+func (l *pdfLexer) readNumberOrRef() (interface{}, error) {
+	token := l.readToken()
+	num, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return pdfOperator(token), nil
+	}
+
+	// 尝试匹配间接引用
+	if isInteger(token) {
+		save := l.pos
+		l.skipSpace()
+		genStart := l.pos
+		for l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+			l.pos++
+		}
+		if l.pos > genStart {
+			gen, _ := strconv.Atoi(string(l.data[genStart:l.pos]))
+			l.skipSpace()
+			if l.pos < len(l.data) && l.data[l.pos] == 'R' &&
+				(l.pos+1 >= len(l.data) || isPDFSpace(l.data[l.pos+1]) || isPDFDelimiter(l.data[l.pos+1])) {
+				l.pos++
+				return pdfRef{num: int(num), gen: gen}, nil
+			}
+		}
+		l.pos = save
+	}
+	return num, nil
+}
+
+// isInteger 是否为非负整数
+func isInteger(token string) bool {
+	if token == "" {
+		return false
+	}
+	for i := 0; i < len(token); i++ {
+		if token[i] < '0' || token[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// readDict 读取字典
+func (l *pdfLexer) readDict() (interface{}, error) {
+	l.pos += 2
+	dict := pdfDict{}
+	for {
+		l.skipSpace()
+		if l.pos+1 >= len(l.data) {
+			return nil, errUnexpectedEOF
+		}
+		if l.data[l.pos] == '>' && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return dict, nil
+		}
+		if l.data[l.pos] != '/' {
+			// 非法键，跳过一个对象后继续
+			if _, err := l.readObject(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		key := l.readName()
+		value, err := l.readObject()
+		if err != nil {
+			return nil, err
+		}
+		dict[string(key)] = value
+	}
+}
+
+// readArray 读取数组
+func (l *pdfLexer) readArray() (interface{}, error) {
+	l.pos++
+	arr := pdfArray{}
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return nil, errUnexpectedEOF
+		}
+		if l.data[l.pos] == ']' {
+			l.pos++
+			return arr, nil
+		}
+		obj, err := l.readObject()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, obj)
+	}
+}
+
+// readHexString 读取十六进制字符串
+func (l *pdfLexer) readHexString() (interface{}, error) {
+	l.pos++
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		c := l.data[l.pos]
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	if l.pos >= len(l.data) {
+		return nil, errUnexpectedEOF
+	}
+	l.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	if _, err := hex.Decode(out, digits); err != nil {
+		return nil, err
+	}
+	return pdfString(out), nil
+}
+
+// readLiteralString 读取括号字符串，处理嵌套括号和转义
+func (l *pdfLexer) readLiteralString() (interface{}, error) {
+	l.pos++
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			out = append(out, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfString(out), nil
+			}
+			out = append(out, c)
+		case '\\':
+			if l.pos >= len(l.data) {
+				return nil, errUnexpectedEOF
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				// 续行
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					val := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						val = val*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(val))
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return nil, errUnexpectedEOF
+}
+
+// decodeStream 按 Filter 解码流数据；遇到 DCTDecode 等图像编码时停止并返回已解码的数据
+// 解码结果超过 limit 字节时返回 ErrPDFTooLarge
+func decodeStream(stream *pdfStream, resolve func(interface{}) interface{}, limit int) ([]byte, string, error) {
+	data := stream.raw
+	var filters []string
+	switch f := resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []string{string(f)}
+	case pdfArray:
+		for _, item := range f {
+			if name, ok := resolve(item).(pdfName); ok {
+				filters = append(filters, string(name))
+			}
+		}
+	}
+
+	for _, filter := range filters {
+		switch filter {
+		case "FlateDecode", "Fl":
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, filter, fmt.Errorf("解压流失败: %w", err)
+			}
+			// 部分 PDF 的压缩流缺少校验和，忽略结尾错误，使用已读取的内容
+			decoded, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+			if err != nil && len(decoded) == 0 {
+				return nil, filter, fmt.Errorf("解压流失败: %w", err)
+			}
+			data = decoded
+		case "ASCIIHexDecode", "AHx":
+			lexer := &pdfLexer{data: append(append([]byte{'<'}, bytes.TrimSuffix(bytes.TrimSpace(data), []byte(">"))...), '>')}
+			decoded, err := lexer.readHexString()
+			if err != nil {
+				return nil, filter, err
+			}
+			data = decoded.(pdfString)
+		case "ASCII85Decode", "A85":
+			trimmed := bytes.TrimSpace(data)
+			trimmed = bytes.TrimPrefix(trimmed, []byte("<~"))
+			trimmed = bytes.TrimSuffix(trimmed, []byte("~>"))
+			decoded := make([]byte, len(trimmed)*4/5+4)
+			n, _, err := ascii85.Decode(decoded, trimmed, true)
+			if err != nil {
+				return nil, filter, fmt.Errorf("解码流失败: %w", err)
+			}
+			data = decoded[:n]
+		default:
+			// 图像编码（DCTDecode、JPXDecode 等）或不支持的编码，返回当前数据
+			return data, filter, nil
+		}
+		if len(data) > limit {
+			return nil, filter, ErrPDFTooLarge
+		}
+	}
+	return data, "", nil
+}
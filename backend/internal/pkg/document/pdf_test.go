@@ -0,0 +1,277 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// buildPDF 按顺序生成包含指定对象的 PDF，对象编号从 1 开始
+func buildPDF(objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	buf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+// streamObject 生成流对象
+func streamObject(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+// deflate 压缩数据
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestParsePDFText(t *testing.T) {
+	content := deflate([]byte("BT /F1 12 Tf 72 720 Td (Calculus Notes) Tj 0 -14 Td [(Chapter) -300 (One)] TJ ET"))
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		streamObject("/Filter /FlateDecode", content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	)
+
+	doc, err := ParsePDF(data)
+	if err != nil {
+		t.Fatalf("ParsePDF() 失败: %v", err)
+	}
+	if doc.NumPages() != 1 {
+		t.Fatalf("NumPages() = %d, want 1", doc.NumPages())
+	}
+	if got, want := doc.PageText(0), "Calculus Notes\nChapter One"; got != want {
+		t.Errorf("PageText() = %q, want %q", got, want)
+	}
+}
+
+func TestParsePDFToUnicode(t *testing.T) {
+	cmap := []byte("begincmap\n1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"2 beginbfchar <0001> <9AD8> <0002> <6570> endbfchar\n" +
+		"1 beginbfrange <0003> <0004> <5B66 > endbfrange\nendcmap")
+	content := []byte("BT /F1 12 Tf <00010002000300040004> Tj ET")
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		streamObject("", content),
+		"<< /Type /Font /Subtype /Type0 /ToUnicode 6 0 R >>",
+		streamObject("", cmap),
+	)
+
+	doc, err := ParsePDF(data)
+	if err != nil {
+		t.Fatalf("ParsePDF() 失败: %v", err)
+	}
+	if got, want := doc.PageText(0), "高数学孧孧"; got != want {
+		t.Errorf("PageText() = %q, want %q", got, want)
+	}
+}
+
+func TestParsePDFObjectStream(t *testing.T) {
+	objects := "<< /Type /Catalog /Pages 2 0 R >> << /Type /Pages /Kids [3 0 R] /Count 1 >>"
+	header := "1 0 2 34 "
+	objStm := deflate([]byte(header + objects))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.5\n")
+	fmt.Fprintf(&buf, "3 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>\nendobj\n")
+	fmt.Fprintf(&buf, "4 0 obj\n%s\nendobj\n", streamObject("", []byte("BT (Packed) Tj ET")))
+	fmt.Fprintf(&buf, "5 0 obj\n%s\nendobj\n", streamObject(fmt.Sprintf("/Type /ObjStm /N 2 /First %d /Filter /FlateDecode", len(header)), objStm))
+	fmt.Fprintf(&buf, "6 0 obj\n%s\nendobj\n", streamObject("/Type /XRef /Root 1 0 R", nil))
+	buf.WriteString("%%EOF\n")
+
+	doc, err := ParsePDF(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParsePDF() 失败: %v", err)
+	}
+	if got := doc.PageText(0); got != "Packed" {
+		t.Errorf("PageText() = %q, want %q", got, "Packed")
+	}
+}
+
+func TestParsePDFImage(t *testing.T) {
+	pixels := bytes.Repeat([]byte{0x20, 0x40, 0x60}, 4*2)
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im1 4 0 R >> >> >>",
+		streamObject("/Type /XObject /Subtype /Image /Width 4 /Height 2 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode", deflate(pixels)),
+	)
+
+	doc, err := ParsePDF(data)
+	if err != nil {
+		t.Fatalf("ParsePDF() 失败: %v", err)
+	}
+	img, err := doc.PageImage(0)
+	if err != nil {
+		t.Fatalf("PageImage() 失败: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 4 || b.Dy() != 2 {
+		t.Errorf("PageImage() 尺寸 = %dx%d, want 4x2", b.Dx(), b.Dy())
+	}
+	if r, g, b, _ := img.At(0, 0).RGBA(); r>>8 != 0x20 || g>>8 != 0x40 || b>>8 != 0x60 {
+		t.Errorf("PageImage() 像素 = (%x, %x, %x), want (20, 40, 60)", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestParsePDFImageMalformed(t *testing.T) {
+	// 宽度 2^62+1024 与高度 4 相乘溢出后恰为 4096，数据长度足以通过未检查溢出的长度校验
+	pixels := bytes.Repeat([]byte{0x20, 0x40, 0x60}, 4096)
+	tests := []struct {
+		name string
+		dict string
+	}{
+		{"宽度溢出", "/Width 4611686018427388928 /Height 4"},
+		{"负数尺寸", "/Width -4 /Height 2"},
+		{"零尺寸", "/Width 0 /Height 2"},
+		{"像素过多", "/Width 100000 /Height 100000"},
+		{"数据不足", "/Width 4000 /Height 20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildPDF(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im1 4 0 R >> >> >>",
+				streamObject("/Type /XObject /Subtype /Image "+tt.dict+" /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode", deflate(pixels)),
+			)
+			doc, err := ParsePDF(data)
+			if err != nil {
+				t.Fatalf("ParsePDF() 失败: %v", err)
+			}
+			if _, err := doc.PageImage(0); err != ErrNoImage {
+				t.Errorf("PageImage() 错误 = %v, want %v", err, ErrNoImage)
+			}
+		})
+	}
+}
+
+func TestParsePDFDecodeLimit(t *testing.T) {
+	// 单个流解压后超过限制时报错，而不是截断
+	stream := &pdfStream{
+		dict: pdfDict{"Filter": pdfName("FlateDecode")},
+		raw:  deflate(make([]byte, 4096)),
+	}
+	resolve := func(obj interface{}) interface{} { return obj }
+	if _, _, err := decodeStream(stream, resolve, 1024); err != ErrPDFTooLarge {
+		t.Errorf("decodeStream() 错误 = %v, want %v", err, ErrPDFTooLarge)
+	}
+	if data, _, err := decodeStream(stream, resolve, 4096); err != nil || len(data) != 4096 {
+		t.Errorf("decodeStream() = %d 字节, %v, want 4096 字节", len(data), err)
+	}
+
+	// 整份文档累计解压超过限制后不再解压任何流
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		streamObject("/Filter /FlateDecode", deflate([]byte("BT (Linear Algebra) Tj ET"))),
+	)
+	doc, err := ParsePDF(data)
+	if err != nil {
+		t.Fatalf("ParsePDF() 失败: %v", err)
+	}
+	doc.decoded = maxDecodedSize - 8
+	if text := doc.PageText(0); text != "" || doc.limitErr != ErrPDFTooLarge {
+		t.Errorf("PageText() = %q, limitErr = %v, want 空文本和 %v", text, doc.limitErr, ErrPDFTooLarge)
+	}
+	if _, _, err := doc.decodeStream(stream); err != ErrPDFTooLarge {
+		t.Errorf("decodeStream() 错误 = %v, want %v", err, ErrPDFTooLarge)
+	}
+}
+
+func TestParsePDFInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"非 PDF", []byte("hello world")},
+		{"没有页面", buildPDF("<< /Type /Catalog /Pages 2 0 R >>", "<< /Type /Pages /Kids [] /Count 0 >>")},
+		{"循环页面树", buildPDF("<< /Type /Catalog /Pages 2 0 R >>", "<< /Type /Pages /Kids [2 0 R] /Count 1 >>")},
+		{"截断", []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePDF(tt.data); err == nil {
+				t.Errorf("ParsePDF() 应返回错误")
+			}
+		})
+	}
+}
+
+func TestGeneratePreview(t *testing.T) {
+	pdf := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		streamObject("", []byte("BT (Linear Algebra) Tj ET")),
+	)
+
+	tests := []struct {
+		name     string
+		mimeType string
+		data     []byte
+		excerpt  string
+	}{
+		{"PDF", "application/pdf", pdf, "Linear Algebra"},
+		{"纯文本", "text/plain; charset=utf-8", []byte("\xef\xbb\xbf高等数学\r\n\r\n第一章\x00 函数"), "高等数学 第一章 函数"},
+		{"Markdown", "text/markdown", []byte("# 复习提纲\n\n- **极限**与[连续](http://a.b)\n```go\nx := 1\n```"), "复习提纲 极限与连续 x := 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preview, err := GeneratePreview(tt.mimeType, tt.data)
+			if err != nil {
+				t.Fatalf("GeneratePreview() 失败: %v", err)
+			}
+			if preview.Excerpt != tt.excerpt {
+				t.Errorf("Excerpt = %q, want %q", preview.Excerpt, tt.excerpt)
+			}
+			if !bytes.HasPrefix(preview.Thumbnail, []byte("\x89PNG")) {
+				t.Errorf("Thumbnail 不是 PNG 图片")
+			}
+		})
+	}
+
+	// 文件头声明超大尺寸的图片在解码前拒绝
+	huge := image.NewGray(image.Rect(0, 0, 1, 1))
+	var buf bytes.Buffer
+	png.Encode(&buf, huge)
+	header := buf.Bytes()
+	binary.BigEndian.PutUint32(header[16:20], 100000)
+	binary.BigEndian.PutUint32(header[20:24], 100000)
+	binary.BigEndian.PutUint32(header[29:33], crc32.ChecksumIEEE(header[12:29]))
+	if _, err := GeneratePreview("image/png", header); err != ErrImageTooLarge {
+		t.Errorf("GeneratePreview() 错误 = %v, want %v", err, ErrImageTooLarge)
+	}
+
+	if _, err := GeneratePreview("application/zip", []byte("PK")); err != ErrUnsupportedType {
+		t.Errorf("GeneratePreview() 错误 = %v, want %v", err, ErrUnsupportedType)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	if got := Excerpt("  a \n\n b\tc  ", 10); got != "a b c" {
+		t.Errorf("Excerpt() = %q, want %q", got, "a b c")
+	}
+	long := strings.Repeat("数", 20)
+	if got := Excerpt(long, 5); got != "数数数数数…" {
+		t.Errorf("Excerpt() = %q, want %q", got, "数数数数数…")
+	}
+}
@@ -0,0 +1,137 @@
+package document
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+)
+
+var (
+	// ErrUnsupportedType 不支持生成预览的文件类型
+	ErrUnsupportedType = errors.New("不支持生成预览的文件类型")
+	// ErrImageTooLarge 图片尺寸无效或超过限制
+	ErrImageTooLarge = errors.New("图片尺寸无效或过大")
+)
+
+// excerptPages PDF 摘要最多读取的页数
+const excerptPages = 3
+
+// Preview 文档预览结果
+type Preview struct {
+	Thumbnail []byte // 首页缩略图（PNG）
+	Excerpt   string // 文本摘要，图片等无文本内容时为空
+}
+
+// SupportsPreview 是否支持为该类型生成预览
+func SupportsPreview(mimeType string) bool {
+	switch documentKind(mimeType) {
+	case "pdf", "markdown", "text", "image":
+		return true
+	}
+	return false
+}
+
+// GeneratePreview 根据文件类型生成首页缩略图和文本摘要
+// 支持 PDF、纯文本、Markdown 和常见图片格式，全部使用纯 Go 实现
+func GeneratePreview(mimeType string, data []byte) (*Preview, error) {
+	switch documentKind(mimeType) {
+	case "pdf":
+		return pdfPreview(data)
+	case "markdown":
+		return textPreview(StripMarkdown(PlainText(data)))
+	case "text":
+		return textPreview(PlainText(data))
+	case "image":
+		// 先读取文件头中的尺寸，构造的超大尺寸会在解码时耗尽内存
+		config, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("解码图片失败: %w", err)
+		}
+		if !validImageSize(float64(config.Width), float64(config.Height)) {
+			return nil, ErrImageTooLarge
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("解码图片失败: %w", err)
+		}
+		thumbnail, err := ImageThumbnail(img)
+		if err != nil {
+			return nil, fmt.Errorf("生成缩略图失败: %w", err)
+		}
+		return &Preview{Thumbnail: thumbnail}, nil
+	}
+	return nil, ErrUnsupportedType
+}
+
+// documentKind 将 MIME 类型归类
+func documentKind(mimeType string) string {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:i])
+	}
+
+	switch {
+	case mimeType == "application/pdf":
+		return "pdf"
+	case mimeType == mimeTypeDocx:
+		return "docx"
+	case mimeType == mimeTypePptx:
+		return "pptx"
+	case strings.Contains(mimeType, "markdown"):
+		return "markdown"
+	case mimeType == "text/plain":
+		return "text"
+	case mimeType == "image/png", mimeType == "image/jpeg", mimeType == "image/jpg", mimeType == "image/gif":
+		return "image"
+	}
+	return ""
+}
+
+// pdfPreview 生成 PDF 预览：首页含图像时缩放图像（扫描件），否则绘制首页文字草图
+func pdfPreview(data []byte) (*Preview, error) {
+	doc, err := ParsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var thumbnail []byte
+	if img, err := doc.PageImage(0); err == nil {
+		thumbnail, err = ImageThumbnail(img)
+		if err != nil {
+			return nil, fmt.Errorf("生成缩略图失败: %w", err)
+		}
+	} else if errors.Is(err, ErrPDFTooLarge) {
+		return nil, err
+	} else {
+		thumbnail, err = TextThumbnail(doc.PageText(0))
+		if err != nil {
+			return nil, fmt.Errorf("生成缩略图失败: %w", err)
+		}
+	}
+
+	excerpt := doc.Text(excerptPages)
+	if doc.limitErr != nil {
+		return nil, doc.limitErr
+	}
+	return &Preview{
+		Thumbnail: thumbnail,
+		Excerpt:   Excerpt(excerpt, DefaultExcerptLength),
+	}, nil
+}
+
+// textPreview 生成文本预览
+func textPreview(text string) (*Preview, error) {
+	thumbnail, err := TextThumbnail(text)
+	if err != nil {
+		return nil, fmt.Errorf("生成缩略图失败: %w", err)
+	}
+	return &Preview{
+		Thumbnail: thumbnail,
+		Excerpt:   Excerpt(text, DefaultExcerptLength),
+	}, nil
+}
@@ -0,0 +1,85 @@
+package document
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultExcerptLength 默认摘要长度（字符数）
+const DefaultExcerptLength = 500
+
+var (
+	markdownCodeFence = regexp.MustCompile("(?m)^\\s*(```|~~~).*$")
+	markdownImage     = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLink      = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownHTMLTag   = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	markdownHeading   = regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s*`)
+	markdownQuote     = regexp.MustCompile(`(?m)^\s*>+\s?`)
+	markdownList      = regexp.MustCompile(`(?m)^\s*([-*+]|\d+\.)\s+`)
+	markdownRule      = regexp.MustCompile(`(?m)^\s*([-*_]\s*){3,}$`)
+	markdownTableRule = regexp.MustCompile(`(?m)^\s*\|?(\s*:?-+:?\s*\|)+\s*:?-*:?\s*$`)
+	markdownEmphasis  = regexp.MustCompile("(\\*\\*|__|\\*|_|~~|`)")
+)
+
+// PlainText 将字节内容转换为合法的 UTF-8 文本，去除非法字节和控制字符
+func PlainText(data []byte) string {
+	data = trimBOM(data)
+	var sb strings.Builder
+	sb.Grow(len(data))
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		if r == utf8.RuneError && size <= 1 {
+			continue
+		}
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			if r == '\r' || r == '\f' {
+				sb.WriteByte('\n')
+			}
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// StripMarkdown 去除 Markdown 标记，保留可读文本
+func StripMarkdown(text string) string {
+	text = markdownCodeFence.ReplaceAllString(text, "")
+	text = markdownImage.ReplaceAllString(text, "$1")
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = markdownHTMLTag.ReplaceAllString(text, "")
+	text = markdownTableRule.ReplaceAllString(text, "")
+	text = markdownRule.ReplaceAllString(text, "")
+	text = markdownHeading.ReplaceAllString(text, "")
+	text = markdownQuote.ReplaceAllString(text, "")
+	text = markdownList.ReplaceAllString(text, "")
+	text = markdownEmphasis.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(text, "|", " ")
+	return text
+}
+
+// Excerpt 折叠空白并截取前 maxRunes 个字符，截断时追加省略号
+func Excerpt(text string, maxRunes int) string {
+	if maxRunes <= 0 {
+		maxRunes = DefaultExcerptLength
+	}
+
+	collapsed := strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(collapsed) <= maxRunes {
+		return collapsed
+	}
+
+	runes := []rune(collapsed)
+	return strings.TrimSpace(string(runes[:maxRunes])) + "…"
+}
+
+// trimBOM 去除 UTF-8 BOM
+func trimBOM(data []byte) []byte {
+	if len(data) >= 3 && data[0] == 0xef && data[1] == 0xbb && data[2] == 0xbf {
+		return data[3:]
+	}
+	return data
+}
@@ -0,0 +1,181 @@
+package document
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 缩略图尺寸，按 A4 纸张比例
+const (
+	ThumbnailWidth  = 300
+	ThumbnailHeight = 424
+)
+
+// ThumbnailContentType 缩略图的 MIME 类型
+const ThumbnailContentType = "image/png"
+
+var (
+	sketchBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	sketchBorder     = color.RGBA{0xd9, 0xd9, 0xd9, 0xff}
+	sketchHeading    = color.RGBA{0x8c, 0x8c, 0x8c, 0xff}
+	sketchLine       = color.RGBA{0xbf, 0xbf, 0xbf, 0xff}
+)
+
+// sketch 文字页面草图的排版参数
+const (
+	sketchMargin     = 24
+	sketchLineHeight = 12
+	sketchBarHeight  = 5
+	// sketchCharsPerLine 每行按该字符宽度折行，中文等宽字符计为 2
+	sketchCharsPerLine = 48
+)
+
+// ImageThumbnail 将图像等比缩放到缩略图尺寸，居中放在白色背景上并编码为 PNG
+func ImageThumbnail(src image.Image) ([]byte, error) {
+	dst := image.NewRGBA(image.Rect(0, 0, ThumbnailWidth, ThumbnailHeight))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(sketchBackground), image.Point{}, draw.Src)
+
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW > 0 && srcH > 0 {
+		// 等比缩放，不放大
+		w, h := srcW, srcH
+		if w > ThumbnailWidth || h > ThumbnailHeight {
+			if w*ThumbnailHeight > h*ThumbnailWidth {
+				w, h = ThumbnailWidth, max(1, srcH*ThumbnailWidth/srcW)
+			} else {
+				w, h = max(1, srcW*ThumbnailHeight/srcH), ThumbnailHeight
+			}
+		}
+		offset := image.Pt((ThumbnailWidth-w)/2, (ThumbnailHeight-h)/2)
+		scaleBox(dst, image.Rectangle{Min: offset, Max: offset.Add(image.Pt(w, h))}, src)
+	}
+
+	return encodePNG(dst)
+}
+
+// scaleBox 使用区域平均（box filter）缩放图像
+func scaleBox(dst *image.RGBA, rect image.Rectangle, src image.Image) {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	w, h := rect.Dx(), rect.Dy()
+
+	for y := 0; y < h; y++ {
+		y0 := bounds.Min.Y + y*srcH/h
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/h)
+		for x := 0; x < w; x++ {
+			x0 := bounds.Min.X + x*srcW/w
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/w)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			// 与白色背景混合（颜色值已预乘 alpha）
+			alpha := a / n
+			dst.SetRGBA(rect.Min.X+x, rect.Min.Y+y, color.RGBA{
+				R: uint8((r/n + (0xffff - alpha)) >> 8),
+				G: uint8((g/n + (0xffff - alpha)) >> 8),
+				B: uint8((b/n + (0xffff - alpha)) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+}
+
+// TextThumbnail 根据文本内容绘制页面草图：每行文字以灰色条块表示，保留段落和行长的版式轮廓
+// 纯 Go 实现无需字体文件，适用于没有内嵌图像的文字页面
+func TextThumbnail(text string) ([]byte, error) {
+	dst := image.NewRGBA(image.Rect(0, 0, ThumbnailWidth, ThumbnailHeight))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(sketchBackground), image.Point{}, draw.Src)
+	drawBorder(dst, sketchBorder)
+
+	contentWidth := ThumbnailWidth - sketchMargin*2
+	y := sketchMargin
+	first := true
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			y += sketchLineHeight / 2
+			continue
+		}
+
+		lineColor := sketchLine
+		if first {
+			// 首个段落视为标题，颜色加深
+			lineColor = sketchHeading
+			first = false
+		}
+
+		for _, width := range wrapWidths(paragraph, sketchCharsPerLine) {
+			if y+sketchBarHeight > ThumbnailHeight-sketchMargin {
+				return encodePNG(dst)
+			}
+			barWidth := max(8, contentWidth*width/sketchCharsPerLine)
+			bar := image.Rect(sketchMargin, y, sketchMargin+barWidth, y+sketchBarHeight)
+			draw.Draw(dst, bar, image.NewUniform(lineColor), image.Point{}, draw.Src)
+			y += sketchLineHeight
+		}
+		y += sketchLineHeight / 3
+	}
+
+	return encodePNG(dst)
+}
+
+// wrapWidths 按显示宽度折行，返回每行的宽度
+func wrapWidths(paragraph string, limit int) []int {
+	var widths []int
+	current := 0
+	for _, r := range paragraph {
+		w := 1
+		if r >= utf8.RuneSelf && !unicode.IsSpace(r) && (unicode.Is(unicode.Han, r) || unicode.IsPunct(r) || r > 0x2e80) {
+			w = 2
+		}
+		if current+w > limit {
+			widths = append(widths, current)
+			current = 0
+		}
+		current += w
+	}
+	if current > 0 {
+		widths = append(widths, current)
+	}
+	return widths
+}
+
+// drawBorder 绘制 1 像素边框
+func drawBorder(dst *image.RGBA, c color.RGBA) {
+	b := dst.Bounds()
+	for x := b.Min.X; x < b.Max.X; x++ {
+		dst.SetRGBA(x, b.Min.Y, c)
+		dst.SetRGBA(x, b.Max.Y-1, c)
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		dst.SetRGBA(b.Min.X, y, c)
+		dst.SetRGBA(b.Max.X-1, y, c)
+	}
+}
+
+// encodePNG 编码为 PNG
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,413 @@
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	// ErrEncryptedPDF 加密的 PDF 无法添加水印
+	ErrEncryptedPDF = errors.New("加密的 PDF 不支持添加水印")
+)
+
+var startXrefPattern = regexp.MustCompile(`startxref\s+(\d+)`)
+
+const (
+	// watermarkFooterSize 页脚水印字号
+	watermarkFooterSize = 8
+	// watermarkMaxDiagonalSize 对角线水印的最大字号
+	watermarkMaxDiagonalSize = 42
+	// watermarkOpacity 水印不透明度
+	watermarkOpacity = 0.25
+)
+
+// WatermarkPDF 在 PDF 每一页的页脚和对角线位置叠加文字水印
+// 采用增量更新：原文件内容保持不变，只在末尾追加改写后的页面对象、水印内容流和新的交叉引用，
+// 因此不需要理解或重新压缩原有内容。水印使用标准 Helvetica 字体，非 ASCII 字符会被替换为 '?'
+func WatermarkPDF(data []byte, text string) ([]byte, error) {
+	doc, err := ParsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+	if doc.trailer["Encrypt"] != nil {
+		return nil, ErrEncryptedPDF
+	}
+	prevXref, xrefStream, ok := findStartXref(data)
+	if !ok || doc.root.num == 0 {
+		return nil, ErrInvalidPDF
+	}
+
+	w := newPDFWriter(data, doc.maxObjectNumber()+1)
+	fontRef := w.add(pdfDict{
+		"Type":     pdfName("Font"),
+		"Subtype":  pdfName("Type1"),
+		"BaseFont": pdfName("Helvetica"),
+		"Encoding": pdfName("WinAnsiEncoding"),
+	})
+	stateRef := w.add(pdfDict{
+		"Type": pdfName("ExtGState"),
+		"ca":   float64(watermarkOpacity),
+		"CA":   float64(watermarkOpacity),
+	})
+	// 原内容流之前插入 q，水印流开头使用 Q 恢复，避免原内容遗留的图形状态（如坐标变换）影响水印
+	saveRef := w.addStream([]byte("q\n"))
+	text = sanitizeWatermarkText(text)
+
+	for i, page := range doc.pages {
+		ref := doc.pageRefs[i]
+		if ref.num == 0 {
+			continue
+		}
+
+		resources := pdfDict{}
+		for k, v := range doc.resolveDict(page["Resources"]) {
+			resources[k] = v
+		}
+		fontName := addResource(doc, resources, "Font", "WmFont", fontRef)
+		stateName := addResource(doc, resources, "ExtGState", "WmState", stateRef)
+		contentRef := w.addStream(watermarkContent(doc.pageBox(page), text, fontName, stateName))
+
+		contents := pdfArray{saveRef}
+		switch existing := doc.resolve(page["Contents"]).(type) {
+		case pdfArray:
+			contents = append(contents, existing...)
+		case *pdfStream:
+			contents = append(contents, page["Contents"])
+		}
+		contents = append(contents, contentRef)
+
+		updated := pdfDict{}
+		for k, v := range page {
+			updated[k] = v
+		}
+		updated["Resources"] = resources
+		updated["Contents"] = contents
+		w.set(ref, updated)
+	}
+
+	trailer := pdfDict{"Root": doc.root}
+	for _, key := range []string{"Info", "ID"} {
+		if value, ok := doc.trailer[key]; ok {
+			trailer[key] = value
+		}
+	}
+	return w.finish(trailer, prevXref, xrefStream), nil
+}
+
+// findStartXref 查找最后一个 startxref 指向的交叉引用位置，并判断其是否为交叉引用流
+func findStartXref(data []byte) (int, bool, bool) {
+	matches := startXrefPattern.FindAllSubmatch(data, -1)
+	if len(matches) == 0 {
+		return 0, false, false
+	}
+	offset, err := strconv.Atoi(string(matches[len(matches)-1][1]))
+	if err != nil || offset <= 0 || offset >= len(data) {
+		return 0, false, false
+	}
+	return offset, !bytes.HasPrefix(data[offset:], []byte("xref")), true
+}
+
+// maxObjectNumber 已使用的最大对象编号
+func (d *PDF) maxObjectNumber() int {
+	max := 0
+	for num := range d.objects {
+		if num > max {
+			max = num
+		}
+	}
+	if size, ok := d.trailer["Size"].(float64); ok && int(size)-1 > max {
+		max = int(size) - 1
+	}
+	return max
+}
+
+// pageBox 页面可见区域，依次使用 CropBox、MediaBox，缺失时按 Letter 尺寸处理
+func (d *PDF) pageBox(page pdfDict) [4]float64 {
+	for _, key := range []string{"CropBox", "MediaBox"} {
+		box, ok := d.resolve(page[key]).(pdfArray)
+		if !ok || len(box) != 4 {
+			continue
+		}
+		var rect [4]float64
+		valid := true
+		for i, v := range box {
+			n, ok := d.resolve(v).(float64)
+			if !ok {
+				valid = false
+				break
+			}
+			rect[i] = n
+		}
+		if valid && rect[2] != rect[0] && rect[3] != rect[1] {
+			return [4]float64{
+				math.Min(rect[0], rect[2]), math.Min(rect[1], rect[3]),
+				math.Max(rect[0], rect[2]), math.Max(rect[1], rect[3]),
+			}
+		}
+	}
+	return [4]float64{0, 0, 612, 792}
+}
+
+// addResource 将对象加入页面资源的指定分类中，返回不与现有资源冲突的名称
+func addResource(doc *PDF, resources pdfDict, category, name string, ref pdfRef) string {
+	entries := pdfDict{}
+	for k, v := range doc.resolveDict(resources[category]) {
+		entries[k] = v
+	}
+	unique := name
+	for i := 1; entries[unique] != nil; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	entries[unique] = ref
+	resources[category] = entries
+	return unique
+}
+
+// watermarkContent 生成水印内容流：页脚一行小字，页面中央沿对角线一行大字
+func watermarkContent(box [4]float64, text, fontName, stateName string) []byte {
+	width, height := box[2]-box[0], box[3]-box[1]
+	literal := escapeLiteralString(text)
+
+	// Helvetica 平均字宽约为字号的 0.55 倍，据此让对角线水印占对角线长度的六成左右
+	diagonal := math.Hypot(width, height)
+	size := math.Min(watermarkMaxDiagonalSize, diagonal*0.6/(float64(len(text))*0.55))
+	size = math.Max(size, watermarkFooterSize)
+	angle := math.Atan2(height, width)
+	cos, sin := math.Cos(angle), math.Sin(angle)
+
+	var buf bytes.Buffer
+	buf.WriteString("Q\nq\n")
+	fmt.Fprintf(&buf, "/%s gs\n", stateName)
+	fmt.Fprintf(&buf, "BT /%s %d Tf 0.2 g 1 0 0 1 %s %s Tm (%s) Tj ET\n",
+		fontName, watermarkFooterSize, formatPDFNumber(box[0]+18), formatPDFNumber(box[1]+12), literal)
+	fmt.Fprintf(&buf, "BT /%s %s Tf 0.5 g %s %s %s %s %s %s Tm %s 0 Td (%s) Tj ET\n",
+		fontName, formatPDFNumber(size),
+		formatPDFNumber(cos), formatPDFNumber(sin), formatPDFNumber(-sin), formatPDFNumber(cos),
+		formatPDFNumber(box[0]+width/2), formatPDFNumber(box[1]+height/2),
+		formatPDFNumber(-float64(len(text))*size*0.55/2), literal)
+	buf.WriteString("Q\n")
+	return buf.Bytes()
+}
+
+// sanitizeWatermarkText 将水印文本限制为可打印 ASCII 字符
+func sanitizeWatermarkText(text string) string {
+	buf := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 0x20 || r > 0x7e {
+			r = '?'
+		}
+		buf = append(buf, byte(r))
+	}
+	return string(buf)
+}
+
+// escapeLiteralString 转义字面量字符串中的特殊字符
+func escapeLiteralString(s string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', ')', '\\':
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(s[i])
+	}
+	return buf.String()
+}
+
+// formatPDFNumber 格式化数字，整数不带小数部分
+func formatPDFNumber(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
+
+// pdfWriter 以增量更新的方式向 PDF 末尾追加对象
+type pdfWriter struct {
+	buf     bytes.Buffer
+	next    int
+	offsets map[int]int
+	gens    map[int]int
+}
+
+// newPDFWriter 创建增量更新写入器，新对象从 next 开始编号
+func newPDFWriter(data []byte, next int) *pdfWriter {
+	w := &pdfWriter{next: next, offsets: make(map[int]int), gens: make(map[int]int)}
+	w.buf.Grow(len(data) + 4096)
+	w.buf.Write(data)
+	if len(data) > 0 && data[len(data)-1] != '\n' && data[len(data)-1] != '\r' {
+		w.buf.WriteByte('\n')
+	}
+	return w
+}
+
+// add 追加一个新对象
+func (w *pdfWriter) add(obj interface{}) pdfRef {
+	ref := pdfRef{num: w.next}
+	w.next++
+	w.set(ref, obj)
+	return ref
+}
+
+// addStream 追加一个未压缩的流对象
+func (w *pdfWriter) addStream(data []byte) pdfRef {
+	ref := pdfRef{num: w.next}
+	w.next++
+	w.begin(ref)
+	fmt.Fprintf(&w.buf, "<< /Length %d >>\nstream\n", len(data))
+	w.buf.Write(data)
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return ref
+}
+
+// set 写入对象的新版本，覆盖原文件中相同编号的对象
+func (w *pdfWriter) set(ref pdfRef, obj interface{}) {
+	w.begin(ref)
+	writePDFObject(&w.buf, obj)
+	w.buf.WriteString("\nendobj\n")
+}
+
+// begin 记录对象偏移并写入对象头
+func (w *pdfWriter) begin(ref pdfRef) {
+	w.offsets[ref.num] = w.buf.Len()
+	w.gens[ref.num] = ref.gen
+	fmt.Fprintf(&w.buf, "%d %d obj\n", ref.num, ref.gen)
+}
+
+// finish 写入交叉引用和 trailer
+// 原文件使用交叉引用流时同样写入交叉引用流，否则写入传统的 xref 表
+func (w *pdfWriter) finish(trailer pdfDict, prevXref int, xrefStream bool) []byte {
+	trailer["Prev"] = float64(prevXref)
+
+	if xrefStream {
+		ref := pdfRef{num: w.next}
+		w.next++
+		w.offsets[ref.num] = w.buf.Len()
+		w.gens[ref.num] = 0
+
+		nums, index := w.sections()
+		var data bytes.Buffer
+		for _, num := range nums {
+			entry := make([]byte, 7)
+			entry[0] = 1
+			binary.BigEndian.PutUint32(entry[1:5], uint32(w.offsets[num]))
+			binary.BigEndian.PutUint16(entry[5:7], uint16(w.gens[num]))
+			data.Write(entry)
+		}
+
+		trailer["Type"] = pdfName("XRef")
+		trailer["Size"] = float64(w.next)
+		trailer["Index"] = index
+		trailer["W"] = pdfArray{float64(1), float64(4), float64(2)}
+		trailer["Length"] = float64(data.Len())
+		fmt.Fprintf(&w.buf, "%d 0 obj\n", ref.num)
+		writePDFObject(&w.buf, trailer)
+		w.buf.WriteString("\nstream\n")
+		w.buf.Write(data.Bytes())
+		w.buf.WriteString("\nendstream\nendobj\n")
+		fmt.Fprintf(&w.buf, "startxref\n%d\n%%%%EOF\n", w.offsets[ref.num])
+		return w.buf.Bytes()
+	}
+
+	xrefOffset := w.buf.Len()
+	nums, index := w.sections()
+	w.buf.WriteString("xref\n")
+	for i, pos := 0, 0; i < len(index); i += 2 {
+		start, count := int(index[i].(float64)), int(index[i+1].(float64))
+		fmt.Fprintf(&w.buf, "%d %d\n", start, count)
+		for _, num := range nums[pos : pos+count] {
+			fmt.Fprintf(&w.buf, "%010d %05d n\r\n", w.offsets[num], w.gens[num])
+		}
+		pos += count
+	}
+	trailer["Size"] = float64(w.next)
+	w.buf.WriteString("trailer\n")
+	writePDFObject(&w.buf, trailer)
+	fmt.Fprintf(&w.buf, "\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+	return w.buf.Bytes()
+}
+
+// sections 按编号排序已写入的对象，并拆分为连续的区段（起始编号, 数量）
+func (w *pdfWriter) sections() ([]int, pdfArray) {
+	nums := make([]int, 0, len(w.offsets))
+	for num := range w.offsets {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	index := pdfArray{}
+	for i := 0; i < len(nums); {
+		j := i + 1
+		for j < len(nums) && nums[j] == nums[j-1]+1 {
+			j++
+		}
+		index = append(index, float64(nums[i]), float64(j-i))
+		i = j
+	}
+	return nums, index
+}
+
+// writePDFObject 序列化 PDF 对象，流对象只能作为间接对象出现，这里按 null 处理
+func writePDFObject(buf *bytes.Buffer, obj interface{}) {
+	switch v := obj.(type) {
+	case nil, *pdfStream:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case float64:
+		buf.WriteString(formatPDFNumber(v))
+	case pdfName:
+		buf.WriteString(encodePDFName(v))
+	case pdfString:
+		buf.WriteString("<" + hex.EncodeToString(v) + ">")
+	case pdfRef:
+		fmt.Fprintf(buf, "%d %d R", v.num, v.gen)
+	case pdfArray:
+		buf.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteString(" ")
+			}
+			writePDFObject(buf, item)
+		}
+		buf.WriteString("]")
+	case pdfDict:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteString("<<")
+		for _, k := range keys {
+			buf.WriteString(" " + encodePDFName(pdfName(k)) + " ")
+			writePDFObject(buf, v[k])
+		}
+		buf.WriteString(" >>")
+	case pdfOperator:
+		buf.WriteString(string(v))
+	default:
+		buf.WriteString("null")
+	}
+}
+
+// encodePDFName 编码名称对象，分隔符、空白和非 ASCII 字符使用 #xx 转义
+func encodePDFName(name pdfName) string {
+	var buf bytes.Buffer
+	buf.WriteByte('/')
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c < 0x21 || c > 0x7e || c == '#' || isPDFDelimiter(c) {
+			fmt.Fprintf(&buf, "#%02X", c)
+			continue
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
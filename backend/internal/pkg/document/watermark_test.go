@@ -0,0 +1,100 @@
+package document
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// buildPDFWithXref 生成带传统 xref 表和 startxref 的 PDF，对象编号从 1 开始
+func buildPDFWithXref(trailer string, objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f\r\n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n\r\n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R %s >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, trailer, xref)
+	return buf.Bytes()
+}
+
+func TestWatermarkPDF(t *testing.T) {
+	data := buildPDFWithXref("",
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 6 0 R] /Count 2 /MediaBox [0 0 595 842] /Resources << /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		streamObject("", []byte("BT /F1 12 Tf 72 720 Td (Chapter One) Tj ET")),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Times-Roman >>",
+		"<< /Type /Page /Parent 2 0 R /Contents [4 0 R 7 0 R] /Resources << /Font << /F1 5 0 R /WmFont 5 0 R >> >> >>",
+		streamObject("", []byte("BT /F1 12 Tf 72 700 Td (Chapter Two) Tj ET")),
+	)
+
+	stamped, err := WatermarkPDF(data, "alice (ID 42) 2026-10-16 张三")
+	if err != nil {
+		t.Fatalf("WatermarkPDF() 失败: %v", err)
+	}
+	if !bytes.HasPrefix(stamped, data) {
+		t.Fatal("WatermarkPDF() 修改了原文件内容")
+	}
+
+	doc, err := ParsePDF(stamped)
+	if err != nil {
+		t.Fatalf("ParsePDF() 失败: %v", err)
+	}
+	wantText := "alice (ID 42) 2026-10-16 ??"
+	for i, want := range []string{"Chapter One", "Chapter One\nChapter Two"} {
+		text := doc.PageText(i)
+		if !strings.Contains(text, want) || strings.Count(text, wantText) != 2 {
+			t.Errorf("PageText(%d) = %q", i, text)
+		}
+	}
+
+	// 第二页已有同名资源，水印字体需要换名
+	if fonts := doc.resolveDict(doc.resolveDict(doc.pages[1]["Resources"])["Font"]); fonts["WmFont1"] == nil || fonts["F1"] == nil {
+		t.Errorf("第二页字体资源 = %v", fonts)
+	}
+
+	// 新的 xref 中每个偏移都应指向对应的对象
+	tail := stamped[len(data):]
+	entries := regexp.MustCompile(`(?m)^(\d{10}) (\d{5}) n`).FindAllSubmatch(tail, -1)
+	if len(entries) == 0 {
+		t.Fatal("未写入交叉引用表")
+	}
+	if !bytes.Contains(tail, []byte(fmt.Sprintf("/Prev %d", bytes.Index(data, []byte("xref\n"))))) {
+		t.Errorf("trailer 缺少指向原交叉引用的 /Prev")
+	}
+	for _, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if !objectHeaderPattern.Match(stamped[offset:min(offset+16, len(stamped))]) {
+			t.Errorf("偏移 %d 处不是对象: %q", offset, stamped[offset:min(offset+16, len(stamped))])
+		}
+	}
+}
+
+func TestWatermarkPDFErrors(t *testing.T) {
+	pages := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R >>",
+	}
+
+	if _, err := WatermarkPDF(buildPDFWithXref("/Encrypt << /Filter /Standard >>", pages...), "x"); !errors.Is(err, ErrEncryptedPDF) {
+		t.Errorf("WatermarkPDF() 错误 = %v, want %v", err, ErrEncryptedPDF)
+	}
+	if _, err := WatermarkPDF(buildPDF(pages...), "x"); !errors.Is(err, ErrInvalidPDF) {
+		t.Errorf("WatermarkPDF() 错误 = %v, want %v", err, ErrInvalidPDF)
+	}
+	if _, err := WatermarkPDF([]byte("not a pdf"), "x"); !errors.Is(err, ErrInvalidPDF) {
+		t.Errorf("WatermarkPDF() 错误 = %v, want %v", err, ErrInvalidPDF)
+	}
+}
@@ -0,0 +1,202 @@
+// Package highlight 在搜索结果中标记命中的关键词并截取摘要。
+//
+// PostgreSQL 的 ts_headline 按 simple 解析器切词，连续的中文会被当作一个词而无法高亮，
+// 因此这里在 Go 中按子串（忽略大小写）匹配关键词。
+package highlight
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultPreTag 默认高亮开始标记
+	DefaultPreTag = "<em>"
+	// DefaultPostTag 默认高亮结束标记
+	DefaultPostTag = "</em>"
+	// DefaultSnippetLength 默认摘要最大字数
+	DefaultSnippetLength = 120
+	// Ellipsis 摘要被截断时使用的省略号
+	Ellipsis = "…"
+)
+
+// Highlighter 关键词高亮器
+type Highlighter struct {
+	preTag        string
+	postTag       string
+	snippetLength int
+	escapeHTML    bool // 标记为 HTML 标签时对文本做 HTML 转义，避免资料标题等内容被当作 HTML 渲染
+}
+
+// New 创建高亮器，参数为空时使用默认值
+func New(preTag, postTag string, snippetLength int) *Highlighter {
+	if preTag == "" && postTag == "" {
+		preTag, postTag = DefaultPreTag, DefaultPostTag
+	}
+	if snippetLength <= 0 {
+		snippetLength = DefaultSnippetLength
+	}
+	return &Highlighter{
+		preTag:        preTag,
+		postTag:       postTag,
+		snippetLength: snippetLength,
+		escapeHTML:    strings.HasPrefix(preTag, "<"),
+	}
+}
+
+// PreTag 返回高亮开始标记
+func (h *Highlighter) PreTag() string {
+	return h.preTag
+}
+
+// PostTag 返回高亮结束标记
+func (h *Highlighter) PostTag() string {
+	return h.postTag
+}
+
+// SnippetLength 返回摘要最大字数
+func (h *Highlighter) SnippetLength() int {
+	return h.snippetLength
+}
+
+// Highlight 标记文本中出现的全部关键词，返回标记后的文本和是否命中
+func (h *Highlighter) Highlight(text string, keywords []string) (string, bool) {
+	runes := []rune(text)
+	spans := findSpans(runes, keywords)
+	return h.render(runes, spans), len(spans) > 0
+}
+
+// Snippet 截取第一个命中位置附近的摘要并标记关键词，未命中时返回文本开头
+// 摘要中的连续空白合并为一个空格，被截断的一端以省略号表示
+func (h *Highlighter) Snippet(text string, keywords []string) (string, bool) {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	spans := findSpans(runes, keywords)
+
+	start, end := 0, len(runes)
+	if end > h.snippetLength {
+		if len(spans) > 0 {
+			// 命中位置放在摘要前三分之一处，保留少量上文
+			first := spans[0]
+			start = first.start - (h.snippetLength-(first.end-first.start))/3
+			if start < 0 {
+				start = 0
+			}
+		}
+		end = start + h.snippetLength
+		if end > len(runes) {
+			end = len(runes)
+			start = end - h.snippetLength
+		}
+	}
+
+	// 只保留完整落在摘要内的命中
+	visible := make([]span, 0, len(spans))
+	for _, s := range spans {
+		if s.start >= start && s.end <= end {
+			visible = append(visible, span{start: s.start - start, end: s.end - start})
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(Ellipsis)
+	}
+	b.WriteString(h.render(runes[start:end], visible))
+	if end < len(runes) {
+		b.WriteString(Ellipsis)
+	}
+	return b.String(), len(visible) > 0
+}
+
+// render 在命中区间两侧插入高亮标记
+func (h *Highlighter) render(runes []rune, spans []span) string {
+	var b strings.Builder
+	pos := 0
+	for _, s := range spans {
+		b.WriteString(h.escape(string(runes[pos:s.start])))
+		b.WriteString(h.preTag)
+		b.WriteString(h.escape(string(runes[s.start:s.end])))
+		b.WriteString(h.postTag)
+		pos = s.end
+	}
+	b.WriteString(h.escape(string(runes[pos:])))
+	return b.String()
+}
+
+// escape 按需对文本做 HTML 转义
+func (h *Highlighter) escape(text string) string {
+	if h.escapeHTML {
+		return html.EscapeString(text)
+	}
+	return text
+}
+
+// span 命中区间，以字为单位的左闭右开区间
+type span struct {
+	start int
+	end   int
+}
+
+// findSpans 查找文本中全部不重叠的关键词命中区间，同一位置优先匹配最长的关键词
+func findSpans(runes []rune, keywords []string) []span {
+	patterns := make([][]rune, 0, len(keywords))
+	for _, keyword := range keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			patterns = append(patterns, toLower([]rune(keyword)))
+		}
+	}
+	if len(patterns) == 0 {
+		return nil
+	}
+	sort.SliceStable(patterns, func(i, j int) bool {
+		return len(patterns[i]) > len(patterns[j])
+	})
+
+	lower := toLower(runes)
+	var spans []span
+	for i := 0; i < len(lower); {
+		matched := 0
+		for _, pattern := range patterns {
+			if hasPrefix(lower[i:], pattern) {
+				matched = len(pattern)
+				break
+			}
+		}
+		if matched == 0 {
+			i++
+			continue
+		}
+		// 与上一个命中相邻时合并，避免输出 <em>线性</em><em>代数</em>
+		if n := len(spans); n > 0 && spans[n-1].end == i {
+			spans[n-1].end = i + matched
+		} else {
+			spans = append(spans, span{start: i, end: i + matched})
+		}
+		i += matched
+	}
+	return spans
+}
+
+// toLower 逐字转为小写，保持字数不变
+func toLower(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+// hasPrefix 判断 runes 是否以 prefix 开头
+func hasPrefix(runes, prefix []rune) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if runes[i] != r {
+			return false
+		}
+	}
+	return true
+}
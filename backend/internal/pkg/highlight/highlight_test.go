@@ -0,0 +1,108 @@
+package highlight
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHighlight(t *testing.T) {
+	h := New("[", "]", 0)
+
+	tests := []struct {
+		text      string
+		keywords  []string
+		want      string
+		wantMatch bool
+	}{
+		{"线性代数期末复习", []string{"期末"}, "线性代数[期末]复习", true},
+		{"线性代数期末复习", []string{"线性代数", "复习"}, "[线性代数]期末[复习]", true},
+		{"Data Structures 笔记", []string{"data"}, "[Data] Structures 笔记", true},
+		{"DATA data Data", []string{"data"}, "[DATA] [data] [Data]", true},
+		// 同一位置优先匹配最长的关键词
+		{"线性代数", []string{"线性", "线性代数"}, "[线性代数]", true},
+		// 相邻的命中合并为一个
+		{"线性代数", []string{"线性", "代数"}, "[线性代数]", true},
+		{"高等数学", []string{"线代"}, "高等数学", false},
+		{"高等数学", nil, "高等数学", false},
+		{"高等数学", []string{"", "  "}, "高等数学", false},
+		{"", []string{"高数"}, "", false},
+	}
+	for _, tt := range tests {
+		got, matched := h.Highlight(tt.text, tt.keywords)
+		if got != tt.want || matched != tt.wantMatch {
+			t.Errorf("Highlight(%q, %v) = %q, %v, want %q, %v", tt.text, tt.keywords, got, matched, tt.want, tt.wantMatch)
+		}
+	}
+}
+
+func TestHighlightEscapeHTML(t *testing.T) {
+	// 默认标记为 HTML 标签，文本需要转义
+	h := New("", "", 0)
+	got, _ := h.Highlight(`<script>alert("期末")</script>`, []string{"期末"})
+	want := `&lt;script&gt;alert(&#34;<em>期末</em>&#34;)&lt;/script&gt;`
+	if got != want {
+		t.Errorf("Highlight() = %q, want %q", got, want)
+	}
+
+	// 非 HTML 标记时保留原文
+	h = New("**", "**", 0)
+	got, _ = h.Highlight("a<b 期末", []string{"期末"})
+	if got != "a<b **期末**" {
+		t.Errorf("Highlight() = %q, want %q", got, "a<b **期末**")
+	}
+}
+
+func TestNewDefaults(t *testing.T) {
+	h := New("", "", 0)
+	if h.PreTag() != DefaultPreTag || h.PostTag() != DefaultPostTag {
+		t.Errorf("标记 = %q %q, want %q %q", h.PreTag(), h.PostTag(), DefaultPreTag, DefaultPostTag)
+	}
+	if h.SnippetLength() != DefaultSnippetLength {
+		t.Errorf("SnippetLength() = %d, want %d", h.SnippetLength(), DefaultSnippetLength)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	h := New("[", "]", 10)
+
+	tests := []struct {
+		name      string
+		text      string
+		keywords  []string
+		want      string
+		wantMatch bool
+	}{
+		{"短文本不截断", "线性代数期末复习", []string{"期末"}, "线性代数[期末]复习", true},
+		{"命中位于开头", "期末复习资料汇总与答案解析", []string{"期末"}, "[期末]复习资料汇总与答…", true},
+		{"命中位于中间", "本资料包含线性代数第三章的期末复习题和答案", []string{"期末"}, "…章的[期末]复习题和答案", true},
+		{"命中位于末尾", "本资料包含线性代数第三章的复习题和期末", []string{"期末"}, "…第三章的复习题和[期末]", true},
+		{"未命中时返回开头", "本资料包含线性代数第三章的复习题", []string{"高数"}, "本资料包含线性代数第…", false},
+		{"合并空白", "期末\n\n  复习", []string{"复习"}, "期末 [复习]", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, matched := h.Snippet(tt.text, tt.keywords)
+			if got != tt.want || matched != tt.wantMatch {
+				t.Errorf("Snippet() = %q, %v, want %q, %v", got, matched, tt.want, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestSnippetLength(t *testing.T) {
+	h := New("[", "]", 20)
+	text := strings.Repeat("资料", 50) + "期末" + strings.Repeat("复习", 50)
+
+	got, matched := h.Snippet(text, []string{"期末"})
+	if !matched {
+		t.Fatalf("Snippet() 应命中关键词")
+	}
+	plain := strings.NewReplacer("[", "", "]", "", Ellipsis, "").Replace(got)
+	if n := utf8.RuneCountInString(plain); n != 20 {
+		t.Errorf("Snippet() 长度 = %d, want 20", n)
+	}
+	if !strings.HasPrefix(got, Ellipsis) || !strings.HasSuffix(got, Ellipsis) {
+		t.Errorf("Snippet() = %q, 两端都应有省略号", got)
+	}
+}
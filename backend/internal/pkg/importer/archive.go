@@ -0,0 +1,102 @@
+package importer
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	// ErrInvalidArchive 无法读取的压缩包
+	ErrInvalidArchive = errors.New("无效的 ZIP 压缩包")
+	// ErrFileNotInArchive 压缩包中不存在清单引用的文件
+	ErrFileNotInArchive = errors.New("压缩包中不存在该文件")
+	// ErrInvalidEntryPath 清单中的文件路径无效
+	ErrInvalidEntryPath = errors.New("无效的文件路径")
+)
+
+// Archive 批量导入的压缩包：包含若干资料文件和一个描述它们的清单
+type Archive struct {
+	Manifest string          // 清单在压缩包中的路径
+	Entries  []ManifestEntry // 清单条目
+	baseDir  string
+	files    map[string]*zip.File
+}
+
+// OpenArchive 读取压缩包并解析清单
+// 清单可以位于根目录或子目录中（直接压缩文件夹时常见），存在多个时使用层级最浅的一个，
+// 清单中的文件路径相对于清单所在目录
+func OpenArchive(r io.ReaderAt, size int64) (*Archive, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	archive := &Archive{files: make(map[string]*zip.File)}
+	var manifest *zip.File
+	for _, file := range reader.File {
+		name := strings.ReplaceAll(file.Name, "\\", "/")
+		if file.FileInfo().IsDir() || isIgnoredEntry(name) {
+			continue
+		}
+		archive.files[name] = file
+
+		if IsManifestName(name) && (manifest == nil || strings.Count(name, "/") < strings.Count(archive.Manifest, "/")) {
+			manifest = file
+			archive.Manifest = name
+		}
+	}
+	if manifest == nil {
+		return nil, ErrManifestNotFound
+	}
+	if manifest.UncompressedSize64 > MaxManifestSize {
+		return nil, fmt.Errorf("%w: 清单文件过大", ErrInvalidManifest)
+	}
+
+	rc, err := manifest.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, MaxManifestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	archive.Entries, err = ParseManifest(archive.Manifest, data)
+	if err != nil {
+		return nil, err
+	}
+	if dir := path.Dir(archive.Manifest); dir != "." {
+		archive.baseDir = dir + "/"
+	}
+	return archive, nil
+}
+
+// File 返回清单条目引用的文件
+func (a *Archive) File(name string) (*zip.File, error) {
+	name = strings.TrimSpace(strings.ReplaceAll(name, "\\", "/"))
+	if name == "" || strings.HasPrefix(name, "/") {
+		return nil, ErrInvalidEntryPath
+	}
+	name = path.Clean(name)
+	if name == ".." || strings.HasPrefix(name, "../") {
+		return nil, ErrInvalidEntryPath
+	}
+
+	file, ok := a.files[a.baseDir+name]
+	if !ok || IsManifestName(name) {
+		return nil, ErrFileNotInArchive
+	}
+	return file, nil
+}
+
+// isIgnoredEntry 是否为 macOS、Windows 压缩时附带的系统文件
+func isIgnoredEntry(name string) bool {
+	base := path.Base(name)
+	return strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(base, "._") ||
+		base == ".DS_Store" || base == "Thumbs.db"
+}
@@ -0,0 +1,152 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// buildZip 生成包含指定文件的 zip 内容
+func buildZip(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("创建 zip 条目失败: %v", err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("生成 zip 失败: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestParseManifestCSV(t *testing.T) {
+	data := "\xef\xbb\xbf标题,File,课程,category,description,备注\n" +
+		"高数期末复习,notes/calculus.pdf,高等数学,notes,\"第一章, 第二章\",忽略\n" +
+		",,,,\n" +
+		"线代笔记,linear.docx,线性代数,notes\n"
+
+	entries, err := ParseManifest("manifest.csv", []byte(data))
+	if err != nil {
+		t.Fatalf("ParseManifest() 失败: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ParseManifest() 条目数 = %d, want 2", len(entries))
+	}
+
+	want := ManifestEntry{Row: 1, File: "notes/calculus.pdf", Title: "高数期末复习", CourseName: "高等数学", Category: "notes", Description: "第一章, 第二章"}
+	if entries[0] != want {
+		t.Errorf("entries[0] = %+v, want %+v", entries[0], want)
+	}
+	if entries[1].Row != 2 || entries[1].File != "linear.docx" || entries[1].Description != "" {
+		t.Errorf("entries[1] = %+v", entries[1])
+	}
+}
+
+func TestParseManifestJSON(t *testing.T) {
+	data := `[{"file": "a.pdf", "title": "A", "course": "数据结构", "category": "exam", "extra": 1},
+		{"file": "b.pdf", "title": "B", "description": null}]`
+
+	entries, err := ParseManifest("MANIFEST.JSON", []byte(data))
+	if err != nil {
+		t.Fatalf("ParseManifest() 失败: %v", err)
+	}
+	if len(entries) != 2 || entries[0].CourseName != "数据结构" || entries[1].Row != 2 {
+		t.Errorf("ParseManifest() = %+v", entries)
+	}
+
+	if _, err := ParseManifest("manifest.json", []byte(`[{"file": "a.pdf", "title": 1}]`)); !errors.Is(err, ErrInvalidManifest) {
+		t.Errorf("ParseManifest() 错误 = %v, want %v", err, ErrInvalidManifest)
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want error
+	}{
+		{"缺少 file 列", "manifest.csv", "title,course\nA,B\n", ErrInvalidManifest},
+		{"只有表头", "manifest.csv", "file,title\n", ErrInvalidManifest},
+		{"JSON 不是数组", "manifest.json", `{"file": "a.pdf"}`, ErrInvalidManifest},
+		{"不支持的格式", "manifest.xml", "<x/>", ErrManifestNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseManifest(tt.file, []byte(tt.data)); !errors.Is(err, tt.want) {
+				t.Errorf("ParseManifest() 错误 = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	many := new(bytes.Buffer)
+	many.WriteString("file\n")
+	for i := 0; i <= MaxEntries; i++ {
+		many.WriteString("a.pdf\n")
+	}
+	if _, err := ParseManifest("manifest.csv", many.Bytes()); !errors.Is(err, ErrTooManyEntries) {
+		t.Errorf("ParseManifest() 错误 = %v, want %v", err, ErrTooManyEntries)
+	}
+}
+
+func TestOpenArchive(t *testing.T) {
+	r := buildZip(t, map[string]string{
+		"期末资料/manifest.csv":            "file,title\nch1.pdf,第一章\nsub/ch2.pdf,第二章\n",
+		"期末资料/ch1.pdf":                 "%PDF-1.4",
+		"期末资料/sub/ch2.pdf":             "%PDF-1.4",
+		"期末资料/sub/manifest.json":       "[]",
+		"__MACOSX/期末资料/._manifest.csv": "",
+		"outside.pdf":                  "%PDF-1.4",
+	})
+
+	archive, err := OpenArchive(r, r.Size())
+	if err != nil {
+		t.Fatalf("OpenArchive() 失败: %v", err)
+	}
+	if archive.Manifest != "期末资料/manifest.csv" {
+		t.Errorf("Manifest = %q, want %q", archive.Manifest, "期末资料/manifest.csv")
+	}
+	if len(archive.Entries) != 2 {
+		t.Fatalf("Entries 数量 = %d, want 2", len(archive.Entries))
+	}
+
+	for _, name := range []string{"ch1.pdf", "sub/ch2.pdf", "./sub/../ch1.pdf", "sub\\ch2.pdf"} {
+		if _, err := archive.File(name); err != nil {
+			t.Errorf("File(%q) 失败: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"missing.pdf", ErrFileNotInArchive},
+		{"manifest.csv", ErrFileNotInArchive},
+		{"../outside.pdf", ErrInvalidEntryPath},
+		{"/etc/passwd", ErrInvalidEntryPath},
+		{"", ErrInvalidEntryPath},
+	}
+	for _, tt := range tests {
+		if _, err := archive.File(tt.name); !errors.Is(err, tt.want) {
+			t.Errorf("File(%q) 错误 = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestOpenArchiveErrors(t *testing.T) {
+	if _, err := OpenArchive(bytes.NewReader([]byte("not a zip")), 9); !errors.Is(err, ErrInvalidArchive) {
+		t.Errorf("OpenArchive() 错误 = %v, want %v", err, ErrInvalidArchive)
+	}
+
+	r := buildZip(t, map[string]string{"a.pdf": "%PDF-1.4"})
+	if _, err := OpenArchive(r, r.Size()); !errors.Is(err, ErrManifestNotFound) {
+		t.Errorf("OpenArchive() 错误 = %v, want %v", err, ErrManifestNotFound)
+	}
+}
@@ -0,0 +1,201 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	// ErrManifestNotFound 压缩包中没有清单文件
+	ErrManifestNotFound = errors.New("压缩包中缺少 manifest.csv 或 manifest.json")
+	// ErrInvalidManifest 清单格式错误
+	ErrInvalidManifest = errors.New("清单格式错误")
+	// ErrTooManyEntries 清单条目过多
+	ErrTooManyEntries = errors.New("清单条目过多")
+)
+
+const (
+	// MaxManifestSize 清单文件的最大大小
+	MaxManifestSize = 1024 * 1024
+	// MaxEntries 单次导入的最大条目数
+	MaxEntries = 500
+)
+
+// ManifestEntry 清单中的一行，描述压缩包中的一个文件及其资料信息
+type ManifestEntry struct {
+	Row         int    // 行号，从 1 开始（CSV 不计表头）
+	File        string // 文件在压缩包中的路径，相对于清单所在目录
+	Title       string
+	CourseName  string
+	Category    string
+	Description string
+}
+
+// manifestColumns 清单字段的别名，CSV 表头和 JSON 键均不区分大小写
+var manifestColumns = map[string]string{
+	"file":        "file",
+	"file_name":   "file",
+	"path":        "file",
+	"文件":          "file",
+	"文件名":         "file",
+	"title":       "title",
+	"标题":          "title",
+	"course":      "course_name",
+	"course_name": "course_name",
+	"课程":          "course_name",
+	"课程名称":        "course_name",
+	"category":    "category",
+	"分类":          "category",
+	"类型":          "category",
+	"description": "description",
+	"描述":          "description",
+	"简介":          "description",
+}
+
+// IsManifestName 文件名是否为清单文件
+func IsManifestName(name string) bool {
+	switch strings.ToLower(path.Base(name)) {
+	case "manifest.csv", "manifest.json":
+		return true
+	}
+	return false
+}
+
+// ParseManifest 根据文件扩展名解析 CSV 或 JSON 清单
+// CSV 第一行为表头；JSON 为对象数组。未知字段会被忽略
+func ParseManifest(name string, data []byte) ([]ManifestEntry, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel 导出的 CSV 带有 UTF-8 BOM
+
+	var entries []ManifestEntry
+	var err error
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv":
+		entries, err = parseCSVManifest(data)
+	case ".json":
+		entries, err = parseJSONManifest(data)
+	default:
+		return nil, ErrManifestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: 清单为空", ErrInvalidManifest)
+	}
+	if len(entries) > MaxEntries {
+		return nil, fmt.Errorf("%w: 最多 %d 条，实际 %d 条", ErrTooManyEntries, MaxEntries, len(entries))
+	}
+	return entries, nil
+}
+
+// parseCSVManifest 解析 CSV 清单
+func parseCSVManifest(data []byte) ([]ManifestEntry, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: 清单为空", ErrInvalidManifest)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		if field, ok := manifestColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			if _, exists := columns[field]; !exists {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["file"]; !ok {
+		return nil, fmt.Errorf("%w: 表头缺少 file 列", ErrInvalidManifest)
+	}
+
+	entries := make([]ManifestEntry, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		value := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		entries = append(entries, ManifestEntry{
+			Row:         len(entries) + 1,
+			File:        value("file"),
+			Title:       value("title"),
+			CourseName:  value("course_name"),
+			Category:    value("category"),
+			Description: value("description"),
+		})
+		if len(entries) > MaxEntries {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// parseJSONManifest 解析 JSON 清单
+func parseJSONManifest(data []byte) ([]ManifestEntry, error) {
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	entries := make([]ManifestEntry, 0, len(rows))
+	for i, row := range rows {
+		fields := make(map[string]string)
+		for key, raw := range row {
+			field, ok := manifestColumns[strings.ToLower(strings.TrimSpace(key))]
+			if !ok {
+				continue
+			}
+			switch v := raw.(type) {
+			case string:
+				fields[field] = strings.TrimSpace(v)
+			case nil:
+			default:
+				return nil, fmt.Errorf("%w: 第 %d 条的 %s 必须是字符串", ErrInvalidManifest, i+1, key)
+			}
+		}
+		entries = append(entries, ManifestEntry{
+			Row:         i + 1,
+			File:        fields["file"],
+			Title:       fields["title"],
+			CourseName:  fields["course_name"],
+			Category:    fields["category"],
+			Description: fields["description"],
+		})
+	}
+	return entries, nil
+}
+
+// isBlankRecord 是否为空行
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
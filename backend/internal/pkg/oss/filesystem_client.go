@@ -0,0 +1,628 @@
+package oss
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSignatureInvalid 签名无效
+	ErrSignatureInvalid = errors.New("签名无效")
+	// ErrSignatureExpired 签名已过期
+	ErrSignatureExpired = errors.New("签名已过期")
+	// ErrMultipartUploadNotFound 分片上传不存在
+	ErrMultipartUploadNotFound = errors.New("分片上传不存在")
+)
+
+const (
+	// FilesystemObjectRoute 本地存储对象的访问路由前缀
+	FilesystemObjectRoute = "/oss/objects"
+
+	// 存储根目录下的子目录
+	fsObjectsDir   = "objects"
+	fsMetaDir      = "meta"
+	fsMultipartDir = "multipart"
+)
+
+// FilesystemConfig 本地文件系统存储配置
+type FilesystemConfig struct {
+	// RootDir 存储根目录
+	RootDir string
+	// BaseURL 签名 URL 的访问地址，如 http://localhost:8080
+	BaseURL string
+	// SecretKey 签名密钥
+	SecretKey string
+}
+
+// FilesystemClient 本地文件系统存储实现，用于离线和开发环境
+// 上传与下载通过 HMAC 签名的 URL 由后端路由提供，语义与 OSS 预签名 URL 一致
+type FilesystemClient struct {
+	rootDir string
+	baseURL string
+	secret  []byte
+}
+
+// fsObjectMeta 对象元信息，以 JSON 形式保存在 meta 目录
+type fsObjectMeta struct {
+	ContentType string `json:"content_type"`
+	ETag        string `json:"etag"`
+}
+
+// fsMultipartMeta 分片上传元信息
+type fsMultipartMeta struct {
+	FileKey     string `json:"file_key"`
+	ContentType string `json:"content_type"`
+}
+
+// NewFilesystemClient 创建本地文件系统存储客户端实例
+func NewFilesystemClient(config *FilesystemConfig) (*FilesystemClient, error) {
+	if strings.TrimSpace(config.RootDir) == "" {
+		return nil, fmt.Errorf("本地存储目录不能为空")
+	}
+	if config.SecretKey == "" {
+		return nil, fmt.Errorf("本地存储签名密钥不能为空")
+	}
+
+	rootDir, err := filepath.Abs(config.RootDir)
+	if err != nil {
+		return nil, fmt.Errorf("解析本地存储目录失败: %w", err)
+	}
+	for _, dir := range []string{fsObjectsDir, fsMetaDir, fsMultipartDir} {
+		if err := os.MkdirAll(filepath.Join(rootDir, dir), 0o755); err != nil {
+			return nil, fmt.Errorf("创建本地存储目录失败: %w", err)
+		}
+	}
+
+	return &FilesystemClient{
+		rootDir: rootDir,
+		baseURL: strings.TrimRight(config.BaseURL, "/"),
+		secret:  []byte(config.SecretKey),
+	}, nil
+}
+
+// TestConnection 检查存储目录是否可写
+func (c *FilesystemClient) TestConnection(ctx context.Context) error {
+	file, err := os.CreateTemp(filepath.Join(c.rootDir, fsMultipartDir), ".probe-*")
+	if err != nil {
+		return fmt.Errorf("本地存储目录不可写: %w", err)
+	}
+	name := file.Name()
+	file.Close()
+	return os.Remove(name)
+}
+
+// GeneratePresignedUploadURL 生成签名上传 URL
+func (c *FilesystemClient) GeneratePresignedUploadURL(ctx context.Context, fileKey string, expiresIn time.Duration) (string, error) {
+	return c.signURL(http.MethodPut, fileKey, expiresIn, nil)
+}
+
+// GeneratePresignedDownloadURL 生成签名下载 URL
+func (c *FilesystemClient) GeneratePresignedDownloadURL(ctx context.Context, fileKey string, expiresIn time.Duration) (string, error) {
+	return c.signURL(http.MethodGet, fileKey, expiresIn, nil)
+}
+
+// DeleteFile 删除文件，文件不存在时不报错
+func (c *FilesystemClient) DeleteFile(ctx context.Context, fileKey string) error {
+	objectPath, metaPath, err := c.objectPaths(fileKey)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(objectPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("删除文件失败: %w", err)
+	}
+	if err := os.Remove(metaPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("删除文件失败: %w", err)
+	}
+	return nil
+}
+
+// GetFile 获取文件
+func (c *FilesystemClient) GetFile(ctx context.Context, fileKey string) ([]byte, error) {
+	objectPath, _, err := c.objectPaths(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(objectPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrFileNotFound
+		}
+		return nil, fmt.Errorf("获取文件失败: %w", err)
+	}
+	return data, nil
+}
+
+// OpenFile 以流的方式读取文件
+func (c *FilesystemClient) OpenFile(ctx context.Context, fileKey string) (io.ReadCloser, error) {
+	file, _, err := c.OpenObject(ctx, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// PutFile 由服务端直接写入文件
+func (c *FilesystemClient) PutFile(ctx context.Context, fileKey string, reader io.Reader, size int64, contentType string) error {
+	_, err := c.writeObject(fileKey, reader, contentType)
+	return err
+}
+
+// CopyFile 复制文件及其元信息
+func (c *FilesystemClient) CopyFile(ctx context.Context, srcKey, dstKey string) error {
+	file, info, err := c.OpenObject(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = c.writeObject(dstKey, file, info.ContentType)
+	return err
+}
+
+// FileExists 检查文件是否存在
+func (c *FilesystemClient) FileExists(ctx context.Context, fileKey string) (bool, error) {
+	_, err := c.StatFile(ctx, fileKey)
+	if err != nil {
+		if errors.Is(err, ErrFileNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("检查文件是否存在失败: %w", err)
+	}
+	return true, nil
+}
+
+// StatFile 获取文件元信息
+func (c *FilesystemClient) StatFile(ctx context.Context, fileKey string) (*ObjectInfo, error) {
+	objectPath, metaPath, err := c.objectPaths(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(objectPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrFileNotFound
+		}
+		return nil, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+	if info.IsDir() {
+		return nil, ErrFileNotFound
+	}
+
+	meta := c.readObjectMeta(metaPath)
+	return &ObjectInfo{
+		Key:          fileKey,
+		Size:         info.Size(),
+		ContentType:  meta.ContentType,
+		ETag:         meta.ETag,
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+// ListFiles 递归列出指定前缀下的全部文件
+func (c *FilesystemClient) ListFiles(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objectsDir := filepath.Join(c.rootDir, fsObjectsDir)
+	objects := make([]ObjectInfo, 0)
+
+	err := filepath.WalkDir(objectsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// 跳过目录和正在写入的临时文件
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(objectsDir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		meta := c.readObjectMeta(filepath.Join(c.rootDir, fsMetaDir, rel+".json"))
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			ContentType:  meta.ContentType,
+			ETag:         meta.ETag,
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("列出文件失败: %w", err)
+	}
+	return objects, nil
+}
+
+// InitiateMultipartUpload 初始化分片上传
+func (c *FilesystemClient) InitiateMultipartUpload(ctx context.Context, fileKey, contentType string) (string, error) {
+	if _, _, err := c.objectPaths(fileKey); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("初始化分片上传失败: %w", err)
+	}
+	uploadID := hex.EncodeToString(buf)
+
+	dir := filepath.Join(c.rootDir, fsMultipartDir, uploadID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("初始化分片上传失败: %w", err)
+	}
+	data, _ := json.Marshal(fsMultipartMeta{FileKey: fileKey, ContentType: contentType})
+	if err := os.WriteFile(filepath.Join(dir, "upload.json"), data, 0o644); err != nil {
+		return "", fmt.Errorf("初始化分片上传失败: %w", err)
+	}
+	return uploadID, nil
+}
+
+// GeneratePresignedPartURL 生成分片的签名上传 URL
+func (c *FilesystemClient) GeneratePresignedPartURL(ctx context.Context, fileKey, uploadID string, partNumber int, expiresIn time.Duration) (string, error) {
+	params := url.Values{}
+	params.Set("uploadId", uploadID)
+	params.Set("partNumber", strconv.Itoa(partNumber))
+	return c.signURL(http.MethodPut, fileKey, expiresIn, params)
+}
+
+// ListUploadedParts 列出已上传的分片
+func (c *FilesystemClient) ListUploadedParts(ctx context.Context, fileKey, uploadID string) ([]UploadedPart, error) {
+	dir, err := c.multipartDir(fileKey, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("列出已上传分片失败: %w", err)
+	}
+
+	parts := make([]UploadedPart, 0)
+	for _, entry := range entries {
+		partNumber, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".part"))
+		if err != nil || !strings.HasSuffix(entry.Name(), ".part") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("列出已上传分片失败: %w", err)
+		}
+		etag, err := fileMD5(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("列出已上传分片失败: %w", err)
+		}
+		parts = append(parts, UploadedPart{
+			PartNumber:   partNumber,
+			ETag:         etag,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+// CompleteMultipartUpload 合并分片完成上传
+// ETag 与 S3 规则一致：各分片 MD5 拼接后再取 MD5，并附加分片数
+func (c *FilesystemClient) CompleteMultipartUpload(ctx context.Context, fileKey, uploadID string, parts []UploadedPart) error {
+	dir, err := c.multipartDir(fileKey, uploadID)
+	if err != nil {
+		return err
+	}
+	meta, err := c.readMultipartMeta(dir)
+	if err != nil {
+		return err
+	}
+
+	sorted := make([]UploadedPart, len(parts))
+	copy(sorted, parts)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartNumber < sorted[j].PartNumber })
+
+	readers := make([]io.Reader, 0, len(sorted))
+	files := make([]*os.File, 0, len(sorted))
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	partHash := md5.New()
+	for _, part := range sorted {
+		partPath := filepath.Join(dir, fmt.Sprintf("%d.part", part.PartNumber))
+		etag, err := fileMD5(partPath)
+		if err != nil {
+			return fmt.Errorf("合并分片失败: %w", err)
+		}
+		if part.ETag != "" && strings.Trim(part.ETag, `"`) != etag {
+			return fmt.Errorf("合并分片失败: 第 %d 个分片 ETag 不匹配", part.PartNumber)
+		}
+		sum, _ := hex.DecodeString(etag)
+		partHash.Write(sum)
+
+		f, err := os.Open(partPath)
+		if err != nil {
+			return fmt.Errorf("合并分片失败: %w", err)
+		}
+		files = append(files, f)
+		readers = append(readers, f)
+	}
+
+	if _, err := c.writeObject(fileKey, io.MultiReader(readers...), meta.ContentType); err != nil {
+		return fmt.Errorf("合并分片失败: %w", err)
+	}
+
+	// 覆盖为分片上传格式的 ETag
+	etag := fmt.Sprintf("%s-%d", hex.EncodeToString(partHash.Sum(nil)), len(sorted))
+	_, metaPath, _ := c.objectPaths(fileKey)
+	if err := writeObjectMeta(metaPath, fsObjectMeta{ContentType: meta.ContentType, ETag: etag}); err != nil {
+		return fmt.Errorf("合并分片失败: %w", err)
+	}
+
+	return os.RemoveAll(dir)
+}
+
+// AbortMultipartUpload 取消分片上传
+func (c *FilesystemClient) AbortMultipartUpload(ctx context.Context, fileKey, uploadID string) error {
+	dir, err := c.multipartDir(fileKey, uploadID)
+	if err != nil {
+		if errors.Is(err, ErrMultipartUploadNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("取消分片上传失败: %w", err)
+	}
+	return nil
+}
+
+// VerifySignature 校验签名 URL 的查询参数
+// 分片上传的 uploadId、partNumber 同样参与签名，不可篡改
+func (c *FilesystemClient) VerifySignature(method, fileKey string, query url.Values) error {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+
+	expected := c.sign(method, fileKey, expires, query.Get("uploadId"), query.Get("partNumber"))
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil || !hmac.Equal(signature, expected) {
+		return ErrSignatureInvalid
+	}
+
+	if time.Now().Unix() > expires {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// PutObject 写入对象，返回对象元信息
+func (c *FilesystemClient) PutObject(ctx context.Context, fileKey string, reader io.Reader, contentType string) (*ObjectInfo, error) {
+	etag, err := c.writeObject(fileKey, reader, contentType)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.StatFile(ctx, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	info.ETag = etag
+	return info, nil
+}
+
+// PutPart 写入分片，返回分片 ETag
+func (c *FilesystemClient) PutPart(ctx context.Context, fileKey, uploadID string, partNumber int, reader io.Reader) (string, error) {
+	if partNumber < 1 || partNumber > MaxPartCount {
+		return "", ErrInvalidPartNumber
+	}
+	dir, err := c.multipartDir(fileKey, uploadID)
+	if err != nil {
+		return "", err
+	}
+	return writeFileAtomic(filepath.Join(dir, fmt.Sprintf("%d.part", partNumber)), reader)
+}
+
+// OpenObject 打开对象用于读取，调用方负责关闭文件
+func (c *FilesystemClient) OpenObject(ctx context.Context, fileKey string) (*os.File, *ObjectInfo, error) {
+	info, err := c.StatFile(ctx, fileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	objectPath, _, _ := c.objectPaths(fileKey)
+	file, err := os.Open(objectPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, ErrFileNotFound
+		}
+		return nil, nil, fmt.Errorf("打开文件失败: %w", err)
+	}
+	return file, info, nil
+}
+
+// signURL 生成带过期时间和签名的访问 URL
+func (c *FilesystemClient) signURL(method, fileKey string, expiresIn time.Duration, params url.Values) (string, error) {
+	if _, _, err := c.objectPaths(fileKey); err != nil {
+		return "", err
+	}
+	if params == nil {
+		params = url.Values{}
+	}
+
+	expires := time.Now().Add(expiresIn).Unix()
+	params.Set("expires", strconv.FormatInt(expires, 10))
+	params.Set("signature", hex.EncodeToString(c.sign(method, fileKey, expires, params.Get("uploadId"), params.Get("partNumber"))))
+
+	segments := strings.Split(fileKey, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf("%s%s/%s?%s", c.baseURL, FilesystemObjectRoute, strings.Join(segments, "/"), params.Encode()), nil
+}
+
+// sign 计算签名：HMAC-SHA256(method \n key \n expires \n uploadId \n partNumber)
+func (c *FilesystemClient) sign(method, fileKey string, expires int64, uploadID, partNumber string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(strings.Join([]string{
+		strings.ToUpper(method),
+		fileKey,
+		strconv.FormatInt(expires, 10),
+		uploadID,
+		partNumber,
+	}, "\n")))
+	return mac.Sum(nil)
+}
+
+// objectPaths 获取对象文件和元信息文件的路径，拒绝越出存储目录的 key
+func (c *FilesystemClient) objectPaths(fileKey string) (string, string, error) {
+	if fileKey == "" || strings.HasPrefix(fileKey, "/") || strings.Contains(fileKey, "\\") {
+		return "", "", ErrInvalidFileName
+	}
+	cleaned := path.Clean(fileKey)
+	if cleaned != fileKey || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", "", ErrInvalidFileName
+	}
+
+	rel := filepath.FromSlash(cleaned)
+	return filepath.Join(c.rootDir, fsObjectsDir, rel), filepath.Join(c.rootDir, fsMetaDir, rel+".json"), nil
+}
+
+// multipartDir 获取分片上传目录，并校验上传ID与文件 key 是否匹配
+func (c *FilesystemClient) multipartDir(fileKey, uploadID string) (string, error) {
+	if _, err := hex.DecodeString(uploadID); err != nil || uploadID == "" {
+		return "", ErrMultipartUploadNotFound
+	}
+	dir := filepath.Join(c.rootDir, fsMultipartDir, uploadID)
+	meta, err := c.readMultipartMeta(dir)
+	if err != nil {
+		return "", err
+	}
+	if meta.FileKey != fileKey {
+		return "", ErrMultipartUploadNotFound
+	}
+	return dir, nil
+}
+
+// readMultipartMeta 读取分片上传元信息
+func (c *FilesystemClient) readMultipartMeta(dir string) (*fsMultipartMeta, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "upload.json"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrMultipartUploadNotFound
+		}
+		return nil, fmt.Errorf("读取分片上传信息失败: %w", err)
+	}
+	var meta fsMultipartMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("读取分片上传信息失败: %w", err)
+	}
+	return &meta, nil
+}
+
+// readObjectMeta 读取对象元信息，缺失时使用默认值
+func (c *FilesystemClient) readObjectMeta(metaPath string) fsObjectMeta {
+	meta := fsObjectMeta{ContentType: "application/octet-stream"}
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return meta
+	}
+	_ = json.Unmarshal(data, &meta)
+	return meta
+}
+
+// writeObject 写入对象内容和元信息，返回内容 MD5
+func (c *FilesystemClient) writeObject(fileKey string, reader io.Reader, contentType string) (string, error) {
+	objectPath, metaPath, err := c.objectPaths(fileKey)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(objectPath), 0o755); err != nil {
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+
+	etag, err := writeFileAtomic(objectPath, reader)
+	if err != nil {
+		return "", err
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if err := writeObjectMeta(metaPath, fsObjectMeta{ContentType: contentType, ETag: etag}); err != nil {
+		return "", err
+	}
+	return etag, nil
+}
+
+// writeObjectMeta 写入对象元信息
+func writeObjectMeta(metaPath string, meta fsObjectMeta) error {
+	if err := os.MkdirAll(filepath.Dir(metaPath), 0o755); err != nil {
+		return fmt.Errorf("写入文件信息失败: %w", err)
+	}
+	data, _ := json.Marshal(meta)
+	if err := os.WriteFile(metaPath, data, 0o644); err != nil {
+		return fmt.Errorf("写入文件信息失败: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免读到写了一半的文件，返回内容 MD5
+func writeFileAtomic(target string, reader io.Reader) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := md5.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), reader); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// fileMD5 计算文件内容的 MD5
+func fileMD5(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
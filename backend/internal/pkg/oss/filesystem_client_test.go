@@ -0,0 +1,280 @@
+package oss
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestFilesystemClient(t *testing.T) *FilesystemClient {
+	t.Helper()
+	client, err := NewFilesystemClient(&FilesystemConfig{
+		RootDir:   t.TempDir(),
+		BaseURL:   "http://localhost:8080/",
+		SecretKey: "test-secret-key",
+	})
+	if err != nil {
+		t.Fatalf("NewFilesystemClient() 失败: %v", err)
+	}
+	return client
+}
+
+// parseSignedURL 解析签名 URL，返回文件 key 和查询参数
+func parseSignedURL(t *testing.T, rawURL string) (string, url.Values) {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("解析 URL 失败: %v", err)
+	}
+	if !strings.HasPrefix(u.Path, FilesystemObjectRoute+"/") {
+		t.Fatalf("URL 路径 = %s, 应以 %s 开头", u.Path, FilesystemObjectRoute)
+	}
+	return strings.TrimPrefix(u.Path, FilesystemObjectRoute+"/"), u.Query()
+}
+
+func TestFilesystemClient_TestConnection(t *testing.T) {
+	client := newTestFilesystemClient(t)
+	if err := client.TestConnection(context.Background()); err != nil {
+		t.Errorf("TestConnection() 失败: %v", err)
+	}
+}
+
+func TestFilesystemClient_SignedURL(t *testing.T) {
+	client := newTestFilesystemClient(t)
+	ctx := context.Background()
+	fileKey := "materials/1/2024/01/01/abc_高数 笔记.pdf"
+
+	uploadURL, err := client.GeneratePresignedUploadURL(ctx, fileKey, time.Hour)
+	if err != nil {
+		t.Fatalf("GeneratePresignedUploadURL() 失败: %v", err)
+	}
+	if !strings.HasPrefix(uploadURL, "http://localhost:8080"+FilesystemObjectRoute) {
+		t.Errorf("上传 URL = %s, 访问地址不正确", uploadURL)
+	}
+
+	key, query := parseSignedURL(t, uploadURL)
+	if key != fileKey {
+		t.Errorf("文件 key = %s, want %s", key, fileKey)
+	}
+	if err := client.VerifySignature(http.MethodPut, key, query); err != nil {
+		t.Errorf("VerifySignature() 失败: %v", err)
+	}
+
+	// 上传签名不能用于下载
+	if err := client.VerifySignature(http.MethodGet, key, query); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("方法不匹配时应返回 ErrSignatureInvalid, got %v", err)
+	}
+
+	// 篡改 key
+	if err := client.VerifySignature(http.MethodPut, "materials/2/other.pdf", query); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("key 被篡改时应返回 ErrSignatureInvalid, got %v", err)
+	}
+
+	// 篡改过期时间
+	tampered := url.Values{}
+	for k, v := range query {
+		tampered[k] = v
+	}
+	tampered.Set("expires", "9999999999")
+	if err := client.VerifySignature(http.MethodPut, key, tampered); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("过期时间被篡改时应返回 ErrSignatureInvalid, got %v", err)
+	}
+}
+
+func TestFilesystemClient_SignedURLExpired(t *testing.T) {
+	client := newTestFilesystemClient(t)
+
+	downloadURL, err := client.GeneratePresignedDownloadURL(context.Background(), "materials/1/a.pdf", -time.Minute)
+	if err != nil {
+		t.Fatalf("GeneratePresignedDownloadURL() 失败: %v", err)
+	}
+
+	key, query := parseSignedURL(t, downloadURL)
+	if err := client.VerifySignature(http.MethodGet, key, query); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("签名过期时应返回 ErrSignatureExpired, got %v", err)
+	}
+}
+
+func TestFilesystemClient_InvalidKey(t *testing.T) {
+	client := newTestFilesystemClient(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "materials/../../secret", "materials//a.pdf", `materials\a.pdf`} {
+		if _, err := client.PutObject(ctx, key, strings.NewReader("x"), "text/plain"); !errors.Is(err, ErrInvalidFileName) {
+			t.Errorf("PutObject(%q) 应返回 ErrInvalidFileName, got %v", key, err)
+		}
+	}
+}
+
+func TestFilesystemClient_ObjectLifecycle(t *testing.T) {
+	client := newTestFilesystemClient(t)
+	ctx := context.Background()
+	fileKey := "materials/1/a.txt"
+	content := []byte("hello world")
+
+	info, err := client.PutObject(ctx, fileKey, bytes.NewReader(content), "text/plain")
+	if err != nil {
+		t.Fatalf("PutObject() 失败: %v", err)
+	}
+	// md5("hello world")
+	if info.ETag != "5eb63bbbe01eeed093cb22bb8f5acdc3" {
+		t.Errorf("ETag = %s, 应为文件 MD5", info.ETag)
+	}
+
+	stat, err := client.StatFile(ctx, fileKey)
+	if err != nil {
+		t.Fatalf("StatFile() 失败: %v", err)
+	}
+	if stat.Size != int64(len(content)) || stat.ContentType != "text/plain" {
+		t.Errorf("StatFile() = %+v, 元信息不正确", stat)
+	}
+
+	data, err := client.GetFile(ctx, fileKey)
+	if err != nil || !bytes.Equal(data, content) {
+		t.Errorf("GetFile() = %q, %v", data, err)
+	}
+
+	if _, err := client.PutObject(ctx, "covers/b.png", strings.NewReader("png"), "image/png"); err != nil {
+		t.Fatalf("PutObject() 失败: %v", err)
+	}
+	objects, err := client.ListFiles(ctx, "materials/")
+	if err != nil {
+		t.Fatalf("ListFiles() 失败: %v", err)
+	}
+	if len(objects) != 1 || objects[0].Key != fileKey {
+		t.Errorf("ListFiles() = %+v, 应只包含 %s", objects, fileKey)
+	}
+
+	if err := client.DeleteFile(ctx, fileKey); err != nil {
+		t.Fatalf("DeleteFile() 失败: %v", err)
+	}
+	if exists, err := client.FileExists(ctx, fileKey); err != nil || exists {
+		t.Errorf("FileExists() = %v, %v, 删除后应不存在", exists, err)
+	}
+	if _, err := client.StatFile(ctx, fileKey); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("StatFile() 删除后应返回 ErrFileNotFound, got %v", err)
+	}
+}
+
+func TestFilesystemClient_CopyFile(t *testing.T) {
+	client := newTestFilesystemClient(t)
+	ctx := context.Background()
+	content := []byte("archived content")
+
+	if _, err := client.PutObject(ctx, "materials/1/a.pdf", bytes.NewReader(content), "application/pdf"); err != nil {
+		t.Fatalf("PutObject() 失败: %v", err)
+	}
+	if err := client.CopyFile(ctx, "materials/1/a.pdf", "archive/materials/1/a.pdf"); err != nil {
+		t.Fatalf("CopyFile() 失败: %v", err)
+	}
+
+	data, err := client.GetFile(ctx, "archive/materials/1/a.pdf")
+	if err != nil || !bytes.Equal(data, content) {
+		t.Errorf("GetFile() = %q, %v, 应与源文件相同", data, err)
+	}
+	stat, err := client.StatFile(ctx, "archive/materials/1/a.pdf")
+	if err != nil || stat.ContentType != "application/pdf" {
+		t.Errorf("StatFile() = %+v, %v, 应保留源文件的类型", stat, err)
+	}
+	if exists, _ := client.FileExists(ctx, "materials/1/a.pdf"); !exists {
+		t.Error("复制后源文件应仍然存在")
+	}
+
+	if err := client.CopyFile(ctx, "materials/1/missing.pdf", "archive/materials/1/missing.pdf"); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("CopyFile() 源文件不存在时应返回 ErrFileNotFound, got %v", err)
+	}
+}
+
+func TestFilesystemClient_MultipartUpload(t *testing.T) {
+	client := newTestFilesystemClient(t)
+	ctx := context.Background()
+	fileKey := "materials/1/big.zip"
+
+	uploadID, err := client.InitiateMultipartUpload(ctx, fileKey, "application/zip")
+	if err != nil {
+		t.Fatalf("InitiateMultipartUpload() 失败: %v", err)
+	}
+
+	partURL, err := client.GeneratePresignedPartURL(ctx, fileKey, uploadID, 2, time.Hour)
+	if err != nil {
+		t.Fatalf("GeneratePresignedPartURL() 失败: %v", err)
+	}
+	key, query := parseSignedURL(t, partURL)
+	if err := client.VerifySignature(http.MethodPut, key, query); err != nil {
+		t.Errorf("VerifySignature() 失败: %v", err)
+	}
+	query.Set("partNumber", "3")
+	if err := client.VerifySignature(http.MethodPut, key, query); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("分片编号被篡改时应返回 ErrSignatureInvalid, got %v", err)
+	}
+
+	// 乱序上传分片
+	if _, err := client.PutPart(ctx, fileKey, uploadID, 2, strings.NewReader("world")); err != nil {
+		t.Fatalf("PutPart(2) 失败: %v", err)
+	}
+	if _, err := client.PutPart(ctx, fileKey, uploadID, 1, strings.NewReader("hello ")); err != nil {
+		t.Fatalf("PutPart(1) 失败: %v", err)
+	}
+	if _, err := client.PutPart(ctx, "materials/1/other.zip", uploadID, 1, strings.NewReader("x")); !errors.Is(err, ErrMultipartUploadNotFound) {
+		t.Errorf("key 与上传ID不匹配时应返回 ErrMultipartUploadNotFound, got %v", err)
+	}
+
+	parts, err := client.ListUploadedParts(ctx, fileKey, uploadID)
+	if err != nil {
+		t.Fatalf("ListUploadedParts() 失败: %v", err)
+	}
+	if len(parts) != 2 || parts[0].PartNumber != 1 || parts[1].Size != 5 {
+		t.Fatalf("ListUploadedParts() = %+v, 分片信息不正确", parts)
+	}
+
+	if err := client.CompleteMultipartUpload(ctx, fileKey, uploadID, parts); err != nil {
+		t.Fatalf("CompleteMultipartUpload() 失败: %v", err)
+	}
+
+	data, err := client.GetFile(ctx, fileKey)
+	if err != nil || string(data) != "hello world" {
+		t.Errorf("GetFile() = %q, %v, 分片合并结果不正确", data, err)
+	}
+	stat, err := client.StatFile(ctx, fileKey)
+	if err != nil {
+		t.Fatalf("StatFile() 失败: %v", err)
+	}
+	if !strings.HasSuffix(stat.ETag, "-2") || stat.ContentType != "application/zip" {
+		t.Errorf("StatFile() = %+v, 分片上传的元信息不正确", stat)
+	}
+
+	// 合并后上传ID失效
+	if _, err := client.ListUploadedParts(ctx, fileKey, uploadID); !errors.Is(err, ErrMultipartUploadNotFound) {
+		t.Errorf("合并后应返回 ErrMultipartUploadNotFound, got %v", err)
+	}
+}
+
+func TestFilesystemClient_AbortMultipartUpload(t *testing.T) {
+	client := newTestFilesystemClient(t)
+	ctx := context.Background()
+	fileKey := "materials/1/big.zip"
+
+	uploadID, err := client.InitiateMultipartUpload(ctx, fileKey, "application/zip")
+	if err != nil {
+		t.Fatalf("InitiateMultipartUpload() 失败: %v", err)
+	}
+	if _, err := client.PutPart(ctx, fileKey, uploadID, 1, strings.NewReader("data")); err != nil {
+		t.Fatalf("PutPart() 失败: %v", err)
+	}
+
+	if err := client.AbortMultipartUpload(ctx, fileKey, uploadID); err != nil {
+		t.Fatalf("AbortMultipartUpload() 失败: %v", err)
+	}
+	// 重复取消不报错
+	if err := client.AbortMultipartUpload(ctx, fileKey, uploadID); err != nil {
+		t.Errorf("重复 AbortMultipartUpload() 失败: %v", err)
+	}
+	if exists, _ := client.FileExists(ctx, fileKey); exists {
+		t.Error("取消后不应生成文件")
+	}
+}
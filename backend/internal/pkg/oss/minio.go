@@ -1,9 +1,13 @@
 package oss
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 	"time"
@@ -18,8 +22,19 @@ var (
 	ErrFileTooLarge = errors.New("文件大小超过限制")
 	// ErrInvalidFileName 无效的文件名
 	ErrInvalidFileName = errors.New("无效的文件名")
+	// ErrFileNotFound 文件不存在
+	ErrFileNotFound = errors.New("文件不存在")
 )
 
+// ObjectInfo 存储对象元信息
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
 // OSSClient OSS 客户端接口
 type OSSClient interface {
 	// TestConnection ?? OSS/MinIO ??
@@ -32,8 +47,28 @@ type OSSClient interface {
 	DeleteFile(ctx context.Context, fileKey string) error
 	// GetFile 获取文件
 	GetFile(ctx context.Context, fileKey string) ([]byte, error)
+	// OpenFile 以流的方式读取文件，文件不存在时返回 ErrFileNotFound，调用方负责关闭
+	OpenFile(ctx context.Context, fileKey string) (io.ReadCloser, error)
+	// PutFile 由服务端直接写入文件，size 未知时传 -1
+	PutFile(ctx context.Context, fileKey string, reader io.Reader, size int64, contentType string) error
+	// CopyFile 在存储内复制文件，源文件不存在时返回 ErrFileNotFound
+	CopyFile(ctx context.Context, srcKey, dstKey string) error
 	// FileExists 检查文件是否存在
 	FileExists(ctx context.Context, fileKey string) (bool, error)
+	// StatFile 获取文件元信息，文件不存在时返回 ErrFileNotFound
+	StatFile(ctx context.Context, fileKey string) (*ObjectInfo, error)
+	// ListFiles 递归列出指定前缀下的全部文件
+	ListFiles(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// InitiateMultipartUpload 初始化分片上传，返回 OSS 分片上传ID
+	InitiateMultipartUpload(ctx context.Context, fileKey, contentType string) (string, error)
+	// GeneratePresignedPartURL 生成分片的预签名上传 URL
+	GeneratePresignedPartURL(ctx context.Context, fileKey, uploadID string, partNumber int, expiresIn time.Duration) (string, error)
+	// ListUploadedParts 列出已上传的分片
+	ListUploadedParts(ctx context.Context, fileKey, uploadID string) ([]UploadedPart, error)
+	// CompleteMultipartUpload 合并分片完成上传
+	CompleteMultipartUpload(ctx context.Context, fileKey, uploadID string, parts []UploadedPart) error
+	// AbortMultipartUpload 取消分片上传
+	AbortMultipartUpload(ctx context.Context, fileKey, uploadID string) error
 }
 
 // FileValidator 文件验证器
@@ -56,34 +91,10 @@ func NewFileValidatorWithTypes(maxFileSize int64, allowedTypes []string) *FileVa
 	allowedMimeTypes := make(map[string]bool)
 	allowedExtensions := make(map[string]bool)
 
-	// MIME 类型映射表
-	mimeMap := map[string]string{
-		"pdf":  "application/pdf",
-		"doc":  "application/msword",
-		"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
-		"xls":  "application/vnd.ms-excel",
-		"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
-		"ppt":  "application/vnd.ms-powerpoint",
-		"pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
-		"txt":  "text/plain",
-		"md":   "text/markdown",
-		"csv":  "text/csv",
-		"zip":  "application/zip",
-		"rar":  "application/x-rar-compressed",
-		"7z":   "application/x-7z-compressed",
-		"tar":  "application/x-tar",
-		"jpg":  "image/jpeg",
-		"jpeg": "image/jpeg",
-		"png":  "image/png",
-		"gif":  "image/gif",
-		"bmp":  "image/bmp",
-		"webp": "image/webp",
-	}
-
 	// 根据允许的类型构建 MIME 类型和扩展名映射
 	for _, fileType := range allowedTypes {
 		ext := "." + strings.ToLower(strings.TrimSpace(fileType))
-		if mime, ok := mimeMap[strings.ToLower(fileType)]; ok {
+		if mime, ok := extensionMimeTypes[strings.ToLower(fileType)]; ok {
 			allowedMimeTypes[mime] = true
 			allowedExtensions[ext] = true
 		}
@@ -96,6 +107,36 @@ func NewFileValidatorWithTypes(maxFileSize int64, allowedTypes []string) *FileVa
 	}
 }
 
+// extensionMimeTypes 文件扩展名到 MIME 类型的映射表
+var extensionMimeTypes = map[string]string{
+	"pdf":  "application/pdf",
+	"doc":  "application/msword",
+	"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"xls":  "application/vnd.ms-excel",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"ppt":  "application/vnd.ms-powerpoint",
+	"pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"txt":  "text/plain",
+	"md":   "text/markdown",
+	"csv":  "text/csv",
+	"zip":  "application/zip",
+	"rar":  "application/x-rar-compressed",
+	"7z":   "application/x-7z-compressed",
+	"tar":  "application/x-tar",
+	"jpg":  "image/jpeg",
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+	"bmp":  "image/bmp",
+	"webp": "image/webp",
+}
+
+// MimeTypeByExtension 根据文件扩展名返回 MIME 类型，未知扩展名返回空字符串
+func MimeTypeByExtension(fileName string) string {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	return extensionMimeTypes[ext]
+}
+
 // getDefaultAllowedTypes 获取默认允许的文件类型
 func getDefaultAllowedTypes() []string {
 	return []string{"pdf", "docx", "doc", "pptx", "ppt", "txt", "md", "zip", "rar"}
@@ -142,8 +183,38 @@ type OSSService interface {
 	GenerateUploadSignature(ctx context.Context, userID uint, fileName string, fileSize int64, mimeType string) (*UploadSignatureResult, error)
 	// GenerateDownloadSignature 生成下载签名
 	GenerateDownloadSignature(ctx context.Context, fileKey string) (string, error)
+	// GenerateDownloadSignatureWithExpiry 生成指定有效期的下载签名
+	GenerateDownloadSignatureWithExpiry(ctx context.Context, fileKey string, expiresIn time.Duration) (string, error)
 	// DeleteFile 删除文件
 	DeleteFile(ctx context.Context, fileKey string) error
+	// StatFile 获取文件元信息
+	StatFile(ctx context.Context, fileKey string) (*ObjectInfo, error)
+	// ListFiles 列出指定前缀下的全部文件
+	ListFiles(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// OpenFile 以流的方式读取文件
+	OpenFile(ctx context.Context, fileKey string) (io.ReadCloser, error)
+	// HashFile 计算文件内容的 SHA-256
+	HashFile(ctx context.Context, fileKey string) (string, error)
+	// SniffFile 读取文件头检测文件的真实类型
+	SniffFile(ctx context.Context, fileKey string) (string, error)
+	// UploadFile 由服务端写入生成的文件（如预览缩略图）
+	UploadFile(ctx context.Context, fileKey string, data []byte, contentType string) error
+	// MoveFile 将文件移动到新的存储键（先复制再删除源文件）
+	MoveFile(ctx context.Context, srcKey, dstKey string) error
+	// PutUserFile 由服务端写入用户的资料文件（如批量导入时从压缩包中解出的文件），返回文件存储键
+	PutUserFile(ctx context.Context, userID uint, fileName string, reader io.Reader, size int64, mimeType string) (string, error)
+	// InitiateMultipartUpload 初始化分片上传
+	InitiateMultipartUpload(ctx context.Context, userID uint, fileName string, fileSize int64, mimeType string, partSize int64) (*MultipartUploadResult, error)
+	// GeneratePartUploadURLs 生成分片的预签名上传 URL
+	GeneratePartUploadURLs(ctx context.Context, fileKey, uploadID string, partNumbers []int) (map[int]string, error)
+	// ListUploadedParts 列出已上传的分片
+	ListUploadedParts(ctx context.Context, fileKey, uploadID string) ([]UploadedPart, error)
+	// CompleteMultipartUpload 合并分片完成上传
+	CompleteMultipartUpload(ctx context.Context, fileKey, uploadID string, parts []UploadedPart) error
+	// AbortMultipartUpload 取消分片上传
+	AbortMultipartUpload(ctx context.Context, fileKey, uploadID string) error
+	// UploadExpiresIn 获取上传签名有效期
+	UploadExpiresIn() time.Duration
 	// ValidateFile 验证文件
 	ValidateFile(fileName string, fileSize int64, mimeType string) error
 	// UpdateConfig 更新配置
@@ -222,7 +293,12 @@ func (s *ossService) GenerateUploadSignature(ctx context.Context, userID uint, f
 
 // GenerateDownloadSignature 生成下载签名
 func (s *ossService) GenerateDownloadSignature(ctx context.Context, fileKey string) (string, error) {
-	downloadURL, err := s.client.GeneratePresignedDownloadURL(ctx, fileKey, s.downloadExpireIn)
+	return s.GenerateDownloadSignatureWithExpiry(ctx, fileKey, s.downloadExpireIn)
+}
+
+// GenerateDownloadSignatureWithExpiry 生成指定有效期的下载签名
+func (s *ossService) GenerateDownloadSignatureWithExpiry(ctx context.Context, fileKey string, expiresIn time.Duration) (string, error) {
+	downloadURL, err := s.client.GeneratePresignedDownloadURL(ctx, fileKey, expiresIn)
 	if err != nil {
 		return "", fmt.Errorf("生成预签名下载 URL 失败: %w", err)
 	}
@@ -234,6 +310,66 @@ func (s *ossService) DeleteFile(ctx context.Context, fileKey string) error {
 	return s.client.DeleteFile(ctx, fileKey)
 }
 
+// StatFile 获取文件元信息
+func (s *ossService) StatFile(ctx context.Context, fileKey string) (*ObjectInfo, error) {
+	return s.client.StatFile(ctx, fileKey)
+}
+
+// ListFiles 列出指定前缀下的全部文件
+func (s *ossService) ListFiles(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	return s.client.ListFiles(ctx, prefix)
+}
+
+// OpenFile 以流的方式读取文件
+func (s *ossService) OpenFile(ctx context.Context, fileKey string) (io.ReadCloser, error) {
+	return s.client.OpenFile(ctx, fileKey)
+}
+
+// UploadFile 由服务端写入生成的文件
+func (s *ossService) UploadFile(ctx context.Context, fileKey string, data []byte, contentType string) error {
+	return s.client.PutFile(ctx, fileKey, bytes.NewReader(data), int64(len(data)), contentType)
+}
+
+// MoveFile 将文件移动到新的存储键
+// 源文件删除失败时只会多留下一份副本，不影响新存储键的可用性，因此不返回错误
+func (s *ossService) MoveFile(ctx context.Context, srcKey, dstKey string) error {
+	if err := s.client.CopyFile(ctx, srcKey, dstKey); err != nil {
+		return err
+	}
+	if err := s.client.DeleteFile(ctx, srcKey); err != nil {
+		fmt.Printf("删除已移动的源文件失败: %s: %v\n", srcKey, err)
+	}
+	return nil
+}
+
+// PutUserFile 由服务端写入用户的资料文件，存储键格式与预签名上传相同
+func (s *ossService) PutUserFile(ctx context.Context, userID uint, fileName string, reader io.Reader, size int64, mimeType string) (string, error) {
+	if err := s.validator.ValidateFile(fileName, size, mimeType); err != nil {
+		return "", fmt.Errorf("文件验证失败: %w", err)
+	}
+
+	fileKey := s.generateFileKey(userID, fileName)
+	if err := s.client.PutFile(ctx, fileKey, reader, size, mimeType); err != nil {
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	return fileKey, nil
+}
+
+// HashFile 计算文件内容的 SHA-256，以流的方式读取，不会将整个文件载入内存
+func (s *ossService) HashFile(ctx context.Context, fileKey string) (string, error) {
+	reader, err := s.client.OpenFile(ctx, fileKey)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", fmt.Errorf("读取文件内容失败: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // ValidateFile 验证文件
 func (s *ossService) ValidateFile(fileName string, fileSize int64, mimeType string) error {
 	return s.validator.ValidateFile(fileName, fileSize, mimeType)
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/minio/minio-go/v7"
@@ -24,6 +27,7 @@ type MinIOConfig struct {
 // MinIOClient MinIO 客户端实现
 type MinIOClient struct {
 	client *minio.Client
+	core   *minio.Core
 	bucket string
 }
 
@@ -75,6 +79,7 @@ func NewMinIOClient(config *MinIOConfig) (*MinIOClient, error) {
 
 	return &MinIOClient{
 		client: client,
+		core:   &minio.Core{Client: client},
 		bucket: config.Bucket,
 	}, nil
 }
@@ -130,6 +135,49 @@ func (c *MinIOClient) GetFile(ctx context.Context, fileKey string) ([]byte, erro
 	return buf.Bytes(), nil
 }
 
+// OpenFile 以流的方式读取文件
+func (c *MinIOClient) OpenFile(ctx context.Context, fileKey string) (io.ReadCloser, error) {
+	obj, err := c.client.GetObject(ctx, c.bucket, fileKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("获取文件失败: %w", err)
+	}
+	// GetObject 不会立即发起请求，通过 Stat 提前发现文件不存在的情况
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrFileNotFound
+		}
+		return nil, fmt.Errorf("获取文件失败: %w", err)
+	}
+	return obj, nil
+}
+
+// PutFile 由服务端直接写入文件
+func (c *MinIOClient) PutFile(ctx context.Context, fileKey string, reader io.Reader, size int64, contentType string) error {
+	_, err := c.client.PutObject(ctx, c.bucket, fileKey, reader, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("上传文件失败: %w", err)
+	}
+	return nil
+}
+
+// CopyFile 在存储桶内复制文件（服务端复制，不经过本机）
+func (c *MinIOClient) CopyFile(ctx context.Context, srcKey, dstKey string) error {
+	_, err := c.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: c.bucket, Object: dstKey},
+		minio.CopySrcOptions{Bucket: c.bucket, Object: srcKey},
+	)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return ErrFileNotFound
+		}
+		return fmt.Errorf("复制文件失败: %w", err)
+	}
+	return nil
+}
+
 // FileExists 检查文件是否存在
 func (c *MinIOClient) FileExists(ctx context.Context, fileKey string) (bool, error) {
 	_, err := c.client.StatObject(ctx, c.bucket, fileKey, minio.StatObjectOptions{})
@@ -142,3 +190,116 @@ func (c *MinIOClient) FileExists(ctx context.Context, fileKey string) (bool, err
 	}
 	return true, nil
 }
+
+// StatFile 获取文件元信息
+func (c *MinIOClient) StatFile(ctx context.Context, fileKey string) (*ObjectInfo, error) {
+	info, err := c.client.StatObject(ctx, c.bucket, fileKey, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrFileNotFound
+		}
+		return nil, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+
+	return &ObjectInfo{
+		Key:          info.Key,
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+// ListFiles 递归列出指定前缀下的全部文件
+func (c *MinIOClient) ListFiles(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := make([]ObjectInfo, 0)
+	for obj := range c.client.ListObjects(ctx, c.bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("列出文件失败: %w", obj.Err)
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          obj.Key,
+			Size:         obj.Size,
+			ContentType:  obj.ContentType,
+			ETag:         obj.ETag,
+			LastModified: obj.LastModified,
+		})
+	}
+	return objects, nil
+}
+
+// InitiateMultipartUpload 初始化分片上传
+func (c *MinIOClient) InitiateMultipartUpload(ctx context.Context, fileKey, contentType string) (string, error) {
+	uploadID, err := c.core.NewMultipartUpload(ctx, c.bucket, fileKey, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("初始化分片上传失败: %w", err)
+	}
+	return uploadID, nil
+}
+
+// GeneratePresignedPartURL 生成分片的预签名上传 URL
+func (c *MinIOClient) GeneratePresignedPartURL(ctx context.Context, fileKey, uploadID string, partNumber int, expiresIn time.Duration) (string, error) {
+	reqParams := make(url.Values)
+	reqParams.Set("partNumber", strconv.Itoa(partNumber))
+	reqParams.Set("uploadId", uploadID)
+
+	presignedURL, err := c.client.Presign(ctx, http.MethodPut, c.bucket, fileKey, expiresIn, reqParams)
+	if err != nil {
+		return "", fmt.Errorf("生成分片上传 URL 失败: %w", err)
+	}
+	return presignedURL.String(), nil
+}
+
+// ListUploadedParts 列出已上传的分片
+func (c *MinIOClient) ListUploadedParts(ctx context.Context, fileKey, uploadID string) ([]UploadedPart, error) {
+	parts := make([]UploadedPart, 0)
+	marker := 0
+	for {
+		result, err := c.core.ListObjectParts(ctx, c.bucket, fileKey, uploadID, marker, 1000)
+		if err != nil {
+			return nil, fmt.Errorf("列出已上传分片失败: %w", err)
+		}
+		for _, part := range result.ObjectParts {
+			parts = append(parts, UploadedPart{
+				PartNumber:   part.PartNumber,
+				ETag:         part.ETag,
+				Size:         part.Size,
+				LastModified: part.LastModified,
+			})
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+	return parts, nil
+}
+
+// CompleteMultipartUpload 合并分片完成上传
+func (c *MinIOClient) CompleteMultipartUpload(ctx context.Context, fileKey, uploadID string, parts []UploadedPart) error {
+	completeParts := make([]minio.CompletePart, 0, len(parts))
+	for _, part := range parts {
+		completeParts = append(completeParts, minio.CompletePart{
+			PartNumber: part.PartNumber,
+			ETag:       part.ETag,
+		})
+	}
+
+	if _, err := c.core.CompleteMultipartUpload(ctx, c.bucket, fileKey, uploadID, completeParts, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("合并分片失败: %w", err)
+	}
+	return nil
+}
+
+// AbortMultipartUpload 取消分片上传
+func (c *MinIOClient) AbortMultipartUpload(ctx context.Context, fileKey, uploadID string) error {
+	if err := c.core.AbortMultipartUpload(ctx, c.bucket, fileKey, uploadID); err != nil {
+		return fmt.Errorf("取消分片上传失败: %w", err)
+	}
+	return nil
+}
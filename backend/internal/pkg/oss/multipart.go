@@ -0,0 +1,120 @@
+package oss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// MinPartSize 分片最小大小（最后一个分片除外），与 S3 协议一致
+	MinPartSize int64 = 5 * 1024 * 1024
+	// DefaultPartSize 默认分片大小
+	DefaultPartSize int64 = 16 * 1024 * 1024
+	// MaxPartSize 分片最大大小
+	MaxPartSize int64 = 128 * 1024 * 1024
+	// MaxPartCount 分片最大数量
+	MaxPartCount = 10000
+)
+
+var (
+	// ErrInvalidPartSize 无效的分片大小
+	ErrInvalidPartSize = errors.New("无效的分片大小")
+	// ErrInvalidPartNumber 无效的分片编号
+	ErrInvalidPartNumber = errors.New("无效的分片编号")
+)
+
+// UploadedPart 已上传的分片
+type UploadedPart struct {
+	PartNumber   int       `json:"part_number"`
+	ETag         string    `json:"etag"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// MultipartUploadResult 分片上传初始化结果
+type MultipartUploadResult struct {
+	UploadID  string    `json:"upload_id"` // OSS 分片上传ID
+	FileKey   string    `json:"file_key"`
+	PartSize  int64     `json:"part_size"`
+	PartCount int       `json:"part_count"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CalculatePartCount 计算文件按指定分片大小切分后的分片数量
+func CalculatePartCount(fileSize, partSize int64) int {
+	if fileSize <= 0 || partSize <= 0 {
+		return 0
+	}
+	return int((fileSize + partSize - 1) / partSize)
+}
+
+// InitiateMultipartUpload 初始化分片上传
+func (s *ossService) InitiateMultipartUpload(ctx context.Context, userID uint, fileName string, fileSize int64, mimeType string, partSize int64) (*MultipartUploadResult, error) {
+	// 验证文件
+	if err := s.validator.ValidateFile(fileName, fileSize, mimeType); err != nil {
+		return nil, fmt.Errorf("文件验证失败: %w", err)
+	}
+
+	if partSize == 0 {
+		partSize = DefaultPartSize
+	}
+	if partSize < MinPartSize || partSize > MaxPartSize {
+		return nil, ErrInvalidPartSize
+	}
+	partCount := CalculatePartCount(fileSize, partSize)
+	if partCount > MaxPartCount {
+		return nil, ErrInvalidPartSize
+	}
+
+	fileKey := s.generateFileKey(userID, fileName)
+	uploadID, err := s.client.InitiateMultipartUpload(ctx, fileKey, mimeType)
+	if err != nil {
+		return nil, fmt.Errorf("初始化分片上传失败: %w", err)
+	}
+
+	return &MultipartUploadResult{
+		UploadID:  uploadID,
+		FileKey:   fileKey,
+		PartSize:  partSize,
+		PartCount: partCount,
+		ExpiresAt: time.Now().Add(s.uploadExpireIn),
+	}, nil
+}
+
+// GeneratePartUploadURLs 生成分片的预签名上传 URL
+func (s *ossService) GeneratePartUploadURLs(ctx context.Context, fileKey, uploadID string, partNumbers []int) (map[int]string, error) {
+	urls := make(map[int]string, len(partNumbers))
+	for _, partNumber := range partNumbers {
+		if partNumber < 1 || partNumber > MaxPartCount {
+			return nil, ErrInvalidPartNumber
+		}
+		partURL, err := s.client.GeneratePresignedPartURL(ctx, fileKey, uploadID, partNumber, s.uploadExpireIn)
+		if err != nil {
+			return nil, fmt.Errorf("生成分片上传 URL 失败: %w", err)
+		}
+		urls[partNumber] = partURL
+	}
+	return urls, nil
+}
+
+// ListUploadedParts 列出已上传的分片
+func (s *ossService) ListUploadedParts(ctx context.Context, fileKey, uploadID string) ([]UploadedPart, error) {
+	return s.client.ListUploadedParts(ctx, fileKey, uploadID)
+}
+
+// CompleteMultipartUpload 合并分片完成上传
+func (s *ossService) CompleteMultipartUpload(ctx context.Context, fileKey, uploadID string, parts []UploadedPart) error {
+	return s.client.CompleteMultipartUpload(ctx, fileKey, uploadID, parts)
+}
+
+// AbortMultipartUpload 取消分片上传
+func (s *ossService) AbortMultipartUpload(ctx context.Context, fileKey, uploadID string) error {
+	return s.client.AbortMultipartUpload(ctx, fileKey, uploadID)
+}
+
+// UploadExpiresIn 获取上传签名有效期
+func (s *ossService) UploadExpiresIn() time.Duration {
+	return s.uploadExpireIn
+}
@@ -0,0 +1,233 @@
+package oss
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// SniffLength 类型检测读取的文件头长度
+// OOXML 需要从 zip 本地文件头中识别 word/、xl/、ppt/ 目录，因此读取较多字节
+const SniffLength = 64 * 1024
+
+// 检测结果中使用的通用类型，无法进一步区分具体格式时返回
+const (
+	MimeTypeOctetStream = "application/octet-stream"
+	MimeTypeZip         = "application/zip"
+	MimeTypeOLE         = "application/x-ole-storage"
+	MimeTypeTextPlain   = "text/plain"
+	MimeTypeExecutable  = "application/x-executable"
+)
+
+const (
+	mimeTypeDocx = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	mimeTypeXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	mimeTypePptx = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	mimeTypeDoc  = "application/msword"
+	mimeTypeXls  = "application/vnd.ms-excel"
+	mimeTypePpt  = "application/vnd.ms-powerpoint"
+)
+
+// magicSignature 文件头魔数
+type magicSignature struct {
+	offset   int
+	magic    []byte
+	mimeType string
+}
+
+var magicSignatures = []magicSignature{
+	{0, []byte("%PDF-"), "application/pdf"},
+	{0, []byte("Rar!\x1a\x07"), "application/x-rar-compressed"},
+	{0, []byte("7z\xbc\xaf\x27\x1c"), "application/x-7z-compressed"},
+	{0, []byte("\x1f\x8b"), "application/gzip"},
+	{257, []byte("ustar"), "application/x-tar"},
+	{0, []byte("\x7fELF"), MimeTypeExecutable},
+	{0, []byte("\xcf\xfa\xed\xfe"), MimeTypeExecutable},
+	{0, []byte("\xce\xfa\xed\xfe"), MimeTypeExecutable},
+}
+
+var (
+	zipLocalHeader = []byte("PK\x03\x04")
+	zipEmptyHeader = []byte("PK\x05\x06")
+	oleHeader      = []byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")
+)
+
+// DetectContentType 根据文件头识别文件的真实类型
+// 可识别 PDF、OOXML（docx/xlsx/pptx）、旧版 Office（OLE 复合文档）、常见图片、压缩包、纯文本及可执行文件，
+// 无法识别时返回 application/octet-stream
+func DetectContentType(head []byte) string {
+	if len(head) == 0 {
+		return MimeTypeOctetStream
+	}
+
+	for _, sig := range magicSignatures {
+		if len(head) >= sig.offset+len(sig.magic) && bytes.Equal(head[sig.offset:sig.offset+len(sig.magic)], sig.magic) {
+			return sig.mimeType
+		}
+	}
+
+	switch {
+	case isPortableExecutable(head):
+		return MimeTypeExecutable
+	case bytes.HasPrefix(head, zipLocalHeader):
+		return detectZipContainer(head)
+	case bytes.HasPrefix(head, zipEmptyHeader):
+		return MimeTypeZip
+	case bytes.HasPrefix(head, oleHeader):
+		return detectOLEDocument(head)
+	}
+
+	detected := http.DetectContentType(head)
+	switch {
+	case strings.HasPrefix(detected, "image/"):
+		return detected
+	case strings.HasPrefix(detected, "text/"):
+		return MimeTypeTextPlain
+	}
+
+	// http.DetectContentType 对不含 BOM 的中文文本可能返回 octet-stream，这里补充判断
+	if isPlainText(head) {
+		return MimeTypeTextPlain
+	}
+	return MimeTypeOctetStream
+}
+
+// detectZipContainer 扫描 zip 本地文件头中的文件名，识别 OOXML 文档
+func detectZipContainer(head []byte) string {
+	for offset := 0; ; {
+		idx := bytes.Index(head[offset:], zipLocalHeader)
+		if idx < 0 {
+			break
+		}
+		pos := offset + idx
+		offset = pos + len(zipLocalHeader)
+
+		// 本地文件头：文件名长度位于偏移 26，文件名从偏移 30 开始
+		if pos+30 > len(head) {
+			break
+		}
+		nameLen := int(head[pos+26]) | int(head[pos+27])<<8
+		if pos+30+nameLen > len(head) {
+			break
+		}
+
+		name := string(head[pos+30 : pos+30+nameLen])
+		switch {
+		case strings.HasPrefix(name, "word/"):
+			return mimeTypeDocx
+		case strings.HasPrefix(name, "xl/"):
+			return mimeTypeXlsx
+		case strings.HasPrefix(name, "ppt/"):
+			return mimeTypePptx
+		}
+	}
+	return MimeTypeZip
+}
+
+// detectOLEDocument 在目录项中查找 Word/Excel/PowerPoint 的流名称（UTF-16LE）
+// 目录扇区不一定位于文件头部，找不到时返回通用的 OLE 类型
+func detectOLEDocument(head []byte) string {
+	streams := []struct {
+		name     string
+		mimeType string
+	}{
+		{"WordDocument", mimeTypeDoc},
+		{"Workbook", mimeTypeXls},
+		{"Book", mimeTypeXls},
+		{"PowerPoint Document", mimeTypePpt},
+	}
+	for _, stream := range streams {
+		if bytes.Contains(head, utf16LE(stream.name)) {
+			return stream.mimeType
+		}
+	}
+	return MimeTypeOLE
+}
+
+// isPortableExecutable 判断是否为 Windows 可执行文件（MZ 头且 e_lfanew 指向 PE 签名）
+func isPortableExecutable(head []byte) bool {
+	if len(head) < 0x40 || !bytes.HasPrefix(head, []byte("MZ")) {
+		return false
+	}
+	peOffset := int(head[0x3c]) | int(head[0x3d])<<8 | int(head[0x3e])<<16 | int(head[0x3f])<<24
+	if peOffset < 0x40 || peOffset+4 > len(head) {
+		return false
+	}
+	return bytes.Equal(head[peOffset:peOffset+4], []byte("PE\x00\x00"))
+}
+
+// utf16LE 将 ASCII 字符串编码为 UTF-16LE
+func utf16LE(s string) []byte {
+	buf := make([]byte, 0, len(s)*2)
+	for i := 0; i < len(s); i++ {
+		buf = append(buf, s[i], 0)
+	}
+	return buf
+}
+
+// isPlainText 判断内容是否为 UTF-8 文本（不含控制字符）
+func isPlainText(head []byte) bool {
+	if len(head) == 0 {
+		return false
+	}
+	// 截断位置可能落在多字节字符中间，去掉末尾不完整的字符
+	for i := 0; i < utf8.UTFMax && len(head) > 0 && !utf8.Valid(head); i++ {
+		head = head[:len(head)-1]
+	}
+	if !utf8.Valid(head) {
+		return false
+	}
+	for _, b := range head {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' && b != '\f' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsCompatibleMimeType 判断检测到的类型是否与声明的类型一致
+// 文件头无法区分的情况（如通用 zip 与 OOXML、通用 OLE 与 doc/xls/ppt、纯文本与 md/csv）视为一致
+func IsCompatibleMimeType(declared, detected string) bool {
+	declared = strings.ToLower(strings.TrimSpace(declared))
+	if i := strings.Index(declared, ";"); i >= 0 {
+		declared = strings.TrimSpace(declared[:i])
+	}
+
+	if declared == detected {
+		return true
+	}
+
+	switch detected {
+	case MimeTypeZip:
+		return declared == mimeTypeDocx || declared == mimeTypeXlsx || declared == mimeTypePptx ||
+			declared == "application/x-zip-compressed"
+	case MimeTypeOLE:
+		return declared == mimeTypeDoc || declared == mimeTypeXls || declared == mimeTypePpt
+	case MimeTypeTextPlain:
+		return strings.HasPrefix(declared, "text/")
+	case "application/gzip":
+		return declared == "application/x-gzip" || declared == "application/x-tar"
+	case "image/jpeg":
+		return declared == "image/jpg"
+	}
+	return false
+}
+
+// SniffFile 读取文件头并检测文件的真实类型
+func (s *ossService) SniffFile(ctx context.Context, fileKey string) (string, error) {
+	reader, err := s.client.OpenFile(ctx, fileKey)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	head, err := io.ReadAll(io.LimitReader(reader, SniffLength))
+	if err != nil {
+		return "", fmt.Errorf("读取文件头失败: %w", err)
+	}
+	return DetectContentType(head), nil
+}
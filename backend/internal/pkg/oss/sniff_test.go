@@ -0,0 +1,112 @@
+package oss
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+// buildZip 生成包含指定文件的 zip 内容
+func buildZip(t *testing.T, names ...string) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for _, name := range names {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("创建 zip 条目失败: %v", err)
+		}
+		f.Write([]byte("<xml/>"))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("生成 zip 失败: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// buildOLE 生成带有指定 UTF-16LE 流名称的 OLE 复合文档头
+func buildOLE(stream string) []byte {
+	head := append([]byte{}, oleHeader...)
+	head = append(head, make([]byte, 504)...)
+	return append(head, utf16LE(stream)...)
+}
+
+// buildPE 生成最小的 PE 文件头
+func buildPE() []byte {
+	head := make([]byte, 0x100)
+	copy(head, "MZ")
+	head[0x3c] = 0x80
+	copy(head[0x80:], "PE\x00\x00")
+	return head
+}
+
+func TestDetectContentType(t *testing.T) {
+	tar := make([]byte, 512)
+	copy(tar[257:], "ustar")
+
+	tests := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{"PDF", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3"), "application/pdf"},
+		{"DOCX", buildZip(t, "[Content_Types].xml", "_rels/.rels", "word/document.xml"), mimeTypeDocx},
+		{"XLSX", buildZip(t, "[Content_Types].xml", "xl/workbook.xml"), mimeTypeXlsx},
+		{"PPTX", buildZip(t, "[Content_Types].xml", "ppt/presentation.xml"), mimeTypePptx},
+		{"普通 zip", buildZip(t, "notes/a.txt"), MimeTypeZip},
+		{"DOC", buildOLE("WordDocument"), mimeTypeDoc},
+		{"XLS", buildOLE("Workbook"), mimeTypeXls},
+		{"PPT", buildOLE("PowerPoint Document"), mimeTypePpt},
+		{"未知 OLE", buildOLE("Other"), MimeTypeOLE},
+		{"PNG", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png"},
+		{"JPEG", []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), "image/jpeg"},
+		{"RAR", []byte("Rar!\x1a\x07\x01\x00"), "application/x-rar-compressed"},
+		{"7Z", []byte("7z\xbc\xaf\x27\x1c\x00\x04"), "application/x-7z-compressed"},
+		{"TAR", tar, "application/x-tar"},
+		{"英文文本", []byte("# Calculus notes\n\nchapter 1"), MimeTypeTextPlain},
+		{"中文文本", []byte("高等数学期末复习提纲\n第一章 函数与极限"), MimeTypeTextPlain},
+		{"截断的中文文本", []byte("高等数学")[:10], MimeTypeTextPlain},
+		{"Windows 可执行文件", buildPE(), MimeTypeExecutable},
+		{"ELF 可执行文件", []byte("\x7fELF\x02\x01\x01\x00"), MimeTypeExecutable},
+		{"脚本按文本处理", []byte("#!/usr/bin/env python3\nprint(1)"), MimeTypeTextPlain},
+		{"以 MZ 开头的文本", []byte("MZ is short for Mark Zbikowski"), MimeTypeTextPlain},
+		{"二进制数据", []byte{0x00, 0x01, 0x02, 0x03, 0xfe}, MimeTypeOctetStream},
+		{"空文件", []byte{}, MimeTypeOctetStream},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectContentType(tt.head); got != tt.want {
+				t.Errorf("DetectContentType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCompatibleMimeType(t *testing.T) {
+	tests := []struct {
+		name     string
+		declared string
+		detected string
+		want     bool
+	}{
+		{"类型一致", "application/pdf", "application/pdf", true},
+		{"声明带参数", "text/plain; charset=utf-8", MimeTypeTextPlain, true},
+		{"Markdown 为纯文本", "text/markdown", MimeTypeTextPlain, true},
+		{"OOXML 无法识别子类型", mimeTypeDocx, MimeTypeZip, true},
+		{"OLE 无法识别子类型", mimeTypePpt, MimeTypeOLE, true},
+		{"docx 与 xlsx 不符", mimeTypeDocx, mimeTypeXlsx, false},
+		{"压缩包伪装为 PDF", "application/pdf", MimeTypeZip, false},
+		{"可执行文件伪装为 PDF", "application/pdf", MimeTypeExecutable, false},
+		{"文本伪装为 docx", mimeTypeDocx, MimeTypeTextPlain, false},
+		{"无法识别", "application/pdf", MimeTypeOctetStream, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCompatibleMimeType(tt.declared, tt.detected); got != tt.want {
+				t.Errorf("IsCompatibleMimeType(%q, %q) = %v, want %v", tt.declared, tt.detected, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package searchquery
+
+import "strings"
+
+// Options 编译选项
+type Options struct {
+	// Segment 将检索词切分为 tsquery 词素，为空时按空白切分并转为小写
+	Segment func(text string) []string
+	// TermFilter 为普通检索词追加的匹配条件（如同名标签、课程别名），与全文匹配取或；返回空字符串表示不追加
+	TermFilter func(term string) (string, []interface{})
+	// FieldFilter 构建字段条件，返回空字符串时使用默认条件
+	FieldFilter func(field, value string) (string, []interface{})
+	// Expand 返回普通检索词的扩展词（如拼写相近的词），命中原词或任一扩展词即可
+	Expand func(term string) []string
+}
+
+// Query 编译后的查询
+type Query struct {
+	SQL       string        // WHERE 条件，参数以 ? 占位
+	Args      []interface{} // SQL 参数
+	RankQuery string        // 用于相关度排序的 tsquery，只包含非排除的检索词；为空表示无法按相关度排序
+	Terms     []string      // 非排除的检索词和短语原文，用于标注命中字段和高亮
+}
+
+// Compile 将语法树编译为参数化的 SQL 条件，node 为 nil 时返回 nil
+//
+// 所有用户输入都通过参数传递；tsquery 中的词素以单引号包裹并转义，不会产生 tsquery 语法错误。
+func Compile(node *Node, opts Options) *Query {
+	if node == nil {
+		return nil
+	}
+	if opts.Segment == nil {
+		opts.Segment = func(text string) []string {
+			return strings.Fields(strings.ToLower(text))
+		}
+	}
+
+	c := &compiler{opts: opts, expansions: make(map[string][]string)}
+	sql := c.compile(node)
+	return &Query{
+		SQL:       sql,
+		Args:      c.args,
+		RankQuery: c.rankQuery(node),
+		Terms:     c.terms,
+	}
+}
+
+// compiler 编译器
+type compiler struct {
+	opts       Options
+	args       []interface{}
+	terms      []string
+	expansions map[string][]string
+}
+
+// compile 编译节点，返回 SQL 条件并收集参数
+func (c *compiler) compile(node *Node) string {
+	switch node.Kind {
+	case NodeTerm:
+		return c.compileTerm(node.Value)
+	case NodePhrase:
+		return c.compilePhrase(node.Value)
+	case NodeField:
+		return c.compileField(node.Field, node.Value)
+	case NodeNot:
+		// 字段为 NULL 时条件结果为 NULL，需视为未命中，否则排除条件会把这些资料一起过滤掉
+		return "NOT COALESCE(" + c.compile(node.Children[0]) + ", false)"
+	}
+
+	parts := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		parts = append(parts, c.compile(child))
+	}
+	if node.Kind == NodeOr {
+		return "(" + strings.Join(parts, " OR ") + ")"
+	}
+	return "(" + strings.Join(parts, " AND ") + ")"
+}
+
+// compileTerm 编译普通检索词：全文匹配（最后一个词素按前缀匹配）或标题、描述、课程名称模糊匹配
+// 扩展词按相同方式匹配，但不按前缀匹配
+func (c *compiler) compileTerm(term string) string {
+	conds := c.termConds(term, true)
+	for _, word := range c.expand(term) {
+		conds = append(conds, c.termConds(word, false)...)
+	}
+
+	if c.opts.TermFilter != nil {
+		if cond, args := c.opts.TermFilter(term); cond != "" {
+			conds = append(conds, cond)
+			c.args = append(c.args, args...)
+		}
+	}
+	return "(" + strings.Join(conds, " OR ") + ")"
+}
+
+// termConds 构建单个词的全文匹配和模糊匹配条件
+func (c *compiler) termConds(term string, prefix bool) []string {
+	var conds []string
+	if tsquery := c.tsquery(term, prefix); tsquery != "" {
+		conds = append(conds, "search_vector @@ to_tsquery('simple', ?)")
+		c.args = append(c.args, tsquery)
+	}
+
+	pattern := "%" + EscapeLike(term) + "%"
+	conds = append(conds, "title ILIKE ?", "description ILIKE ?", "course_name ILIKE ?")
+	c.args = append(c.args, pattern, pattern, pattern)
+	return conds
+}
+
+// expand 返回检索词的扩展词，同一检索词只调用一次 Expand
+func (c *compiler) expand(term string) []string {
+	if c.opts.Expand == nil {
+		return nil
+	}
+	words, ok := c.expansions[term]
+	if !ok {
+		words = c.opts.Expand(term)
+		c.expansions[term] = words
+	}
+	return words
+}
+
+// compilePhrase 编译短语：标题、描述、课程名称或全文中完整出现该短语
+// 全文较长，先用全文索引筛选包含全部词素的资料，再做模糊匹配
+func (c *compiler) compilePhrase(phrase string) string {
+	pattern := "%" + EscapeLike(phrase) + "%"
+	conds := []string{"title ILIKE ?", "description ILIKE ?", "course_name ILIKE ?"}
+	c.args = append(c.args, pattern, pattern, pattern)
+
+	if tsquery := c.tsquery(phrase, false); tsquery != "" {
+		conds = append(conds, "(search_vector @@ to_tsquery('simple', ?) AND content_text ILIKE ?)")
+		c.args = append(c.args, tsquery, pattern)
+	} else {
+		conds = append(conds, "content_text ILIKE ?")
+		c.args = append(c.args, pattern)
+	}
+	return "(" + strings.Join(conds, " OR ") + ")"
+}
+
+// compileField 编译字段条件
+func (c *compiler) compileField(field, value string) string {
+	if c.opts.FieldFilter != nil {
+		if cond, args := c.opts.FieldFilter(field, value); cond != "" {
+			c.args = append(c.args, args...)
+			return "(" + cond + ")"
+		}
+	}
+
+	switch field {
+	case FieldCourse:
+		c.args = append(c.args, "%"+EscapeLike(value)+"%")
+		return "course_name ILIKE ?"
+	case FieldCategory:
+		c.args = append(c.args, value)
+		return "category = ?"
+	default:
+		c.args = append(c.args, strings.ToLower(value))
+		return "id IN (SELECT material_tags.material_id FROM material_tags JOIN tags ON tags.id = material_tags.tag_id WHERE tags.name = ?)"
+	}
+}
+
+// rankQuery 构建用于相关度排序的 tsquery，并收集非排除的检索词
+func (c *compiler) rankQuery(node *Node) string {
+	switch node.Kind {
+	case NodeTerm:
+		c.terms = append(c.terms, node.Value)
+		parts := []string{c.tsquery(node.Value, true)}
+		for _, word := range c.expand(node.Value) {
+			parts = append(parts, c.tsquery(word, false))
+		}
+		return joinTSQuery(parts, " | ")
+	case NodePhrase:
+		c.terms = append(c.terms, node.Value)
+		return c.tsquery(node.Value, false)
+	case NodeField, NodeNot:
+		return ""
+	}
+
+	op := " & "
+	if node.Kind == NodeOr {
+		op = " | "
+	}
+	parts := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		parts = append(parts, c.rankQuery(child))
+	}
+	return joinTSQuery(parts, op)
+}
+
+// joinTSQuery 以 op 连接非空的 tsquery，多于一个时加括号
+func joinTSQuery(parts []string, op string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	switch len(nonEmpty) {
+	case 0:
+		return ""
+	case 1:
+		return nonEmpty[0]
+	}
+	return "(" + strings.Join(nonEmpty, op) + ")"
+}
+
+// tsquery 将文本分词后以 & 连接为 tsquery，prefix 为 true 时最后一个词素按前缀匹配
+// 搜索向量中长词之前插入了其包含的短词，词素位置并不连续，因此短语也按 & 而非 <-> 连接
+func (c *compiler) tsquery(text string, prefix bool) string {
+	tokens := c.opts.Segment(text)
+	lexemes := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token != "" {
+			lexemes = append(lexemes, quoteLexeme(token))
+		}
+	}
+	if len(lexemes) == 0 {
+		return ""
+	}
+
+	if prefix {
+		lexemes[len(lexemes)-1] += ":*"
+	}
+	if len(lexemes) == 1 {
+		return lexemes[0]
+	}
+	return "(" + strings.Join(lexemes, " & ") + ")"
+}
+
+// quoteLexeme 以单引号包裹词素，使其中的运算符按普通字符处理
+func quoteLexeme(lexeme string) string {
+	lexeme = strings.ReplaceAll(lexeme, `\`, `\\`)
+	lexeme = strings.ReplaceAll(lexeme, `'`, `''`)
+	return "'" + lexeme + "'"
+}
+
+// EscapeLike 转义 LIKE 模式中的通配符，与默认的反斜杠转义字符配合使用
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
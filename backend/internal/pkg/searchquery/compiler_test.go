@@ -0,0 +1,249 @@
+package searchquery
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// compile 解析并编译查询，使用默认选项
+func compile(t *testing.T, input string, opts Options) *Query {
+	t.Helper()
+	node, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q) 失败: %v", input, err)
+	}
+	return Compile(node, opts)
+}
+
+func TestCompileTerm(t *testing.T) {
+	q := compile(t, "高数", Options{})
+
+	wantSQL := "(search_vector @@ to_tsquery('simple', ?) OR title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?)"
+	if q.SQL != wantSQL {
+		t.Errorf("SQL = %s, want %s", q.SQL, wantSQL)
+	}
+	wantArgs := []interface{}{"'高数':*", "%高数%", "%高数%", "%高数%"}
+	if !reflect.DeepEqual(q.Args, wantArgs) {
+		t.Errorf("Args = %v, want %v", q.Args, wantArgs)
+	}
+	if q.RankQuery != "'高数':*" {
+		t.Errorf("RankQuery = %s, want '高数':*", q.RankQuery)
+	}
+	if !reflect.DeepEqual(q.Terms, []string{"高数"}) {
+		t.Errorf("Terms = %v, want [高数]", q.Terms)
+	}
+}
+
+func TestCompileSegmentedTerm(t *testing.T) {
+	segment := func(text string) []string {
+		if text == "线性代数期末" {
+			return []string{"线性代数", "期末"}
+		}
+		return []string{text}
+	}
+	q := compile(t, "线性代数期末", Options{Segment: segment})
+
+	// 分词后各词素以 & 连接，最后一个词素按前缀匹配
+	if q.Args[0] != "('线性代数' & '期末':*)" {
+		t.Errorf("tsquery = %v, want ('线性代数' & '期末':*)", q.Args[0])
+	}
+	if q.Args[1] != "%线性代数期末%" {
+		t.Errorf("ILIKE 模式 = %v, want %%线性代数期末%%", q.Args[1])
+	}
+}
+
+func TestCompileInjection(t *testing.T) {
+	// 用户输入只会出现在参数中，SQL 中只有占位符
+	inputs := []string{
+		`高数'); DROP TABLE materials; --`,
+		`a' | 'b`,
+		`x:* & !y`,
+		`%_\`,
+	}
+	for _, input := range inputs {
+		q := compile(t, input, Options{})
+		for _, forbidden := range []string{"DROP", "'b", "!y", "%_"} {
+			if strings.Contains(q.SQL, forbidden) {
+				t.Errorf("Compile(%q) SQL 中包含用户输入: %s", input, q.SQL)
+			}
+		}
+		if strings.Count(q.SQL, "?") != len(q.Args) {
+			t.Errorf("Compile(%q) 占位符数量 %d 与参数数量 %d 不一致", input, strings.Count(q.SQL, "?"), len(q.Args))
+		}
+	}
+}
+
+func TestQuoteLexeme(t *testing.T) {
+	tests := []struct {
+		lexeme string
+		want   string
+	}{
+		{"高数", "'高数'"},
+		{"it's", "'it''s'"},
+		{`a\b`, `'a\\b'`},
+		{"a&b|c:*", "'a&b|c:*'"},
+	}
+	for _, tt := range tests {
+		if got := quoteLexeme(tt.lexeme); got != tt.want {
+			t.Errorf("quoteLexeme(%q) = %s, want %s", tt.lexeme, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	if got := EscapeLike(`100%_a\b`); got != `100\%\_a\\b` {
+		t.Errorf("EscapeLike() = %s, want %s", got, `100\%\_a\\b`)
+	}
+}
+
+func TestCompilePhrase(t *testing.T) {
+	q := compile(t, `"期末 复习"`, Options{})
+
+	wantSQL := "(title ILIKE ? OR description ILIKE ? OR course_name ILIKE ? OR (search_vector @@ to_tsquery('simple', ?) AND content_text ILIKE ?))"
+	if q.SQL != wantSQL {
+		t.Errorf("SQL = %s, want %s", q.SQL, wantSQL)
+	}
+	wantArgs := []interface{}{"%期末 复习%", "%期末 复习%", "%期末 复习%", "('期末' & '复习')", "%期末 复习%"}
+	if !reflect.DeepEqual(q.Args, wantArgs) {
+		t.Errorf("Args = %v, want %v", q.Args, wantArgs)
+	}
+	// 短语不按前缀匹配
+	if q.RankQuery != "('期末' & '复习')" {
+		t.Errorf("RankQuery = %s, want ('期末' & '复习')", q.RankQuery)
+	}
+}
+
+func TestCompileBoolean(t *testing.T) {
+	segment := func(string) []string { return nil }
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"a b", "((title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?) AND (title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?))"},
+		{"a OR b", "((title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?) OR (title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?))"},
+		{"-a", "NOT COALESCE((title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?), false)"},
+	}
+	for _, tt := range tests {
+		q := compile(t, tt.input, Options{Segment: segment})
+		if q.SQL != tt.want {
+			t.Errorf("Compile(%q) SQL = %s, want %s", tt.input, q.SQL, tt.want)
+		}
+	}
+}
+
+func TestCompileRankQuery(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantRank  string
+		wantTerms []string
+	}{
+		{"高数 真题", "('高数':* & '真题':*)", []string{"高数", "真题"}},
+		{"高数 OR 线代", "('高数':* | '线代':*)", []string{"高数", "线代"}},
+		{"(高数 | 线代) 真题", "(('高数':* | '线代':*) & '真题':*)", []string{"高数", "线代", "真题"}},
+		// 排除的检索词和字段条件不参与相关度排序
+		{"高数 -答案", "'高数':*", []string{"高数"}},
+		{"course:高数 真题", "'真题':*", []string{"真题"}},
+		{"-答案", "", nil},
+		{"category:exam", "", nil},
+	}
+	for _, tt := range tests {
+		q := compile(t, tt.input, Options{})
+		if q.RankQuery != tt.wantRank {
+			t.Errorf("Compile(%q) RankQuery = %s, want %s", tt.input, q.RankQuery, tt.wantRank)
+		}
+		if !reflect.DeepEqual(q.Terms, tt.wantTerms) {
+			t.Errorf("Compile(%q) Terms = %v, want %v", tt.input, q.Terms, tt.wantTerms)
+		}
+	}
+}
+
+func TestCompileField(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{"course:高数", "course_name ILIKE ?", []interface{}{"%高数%"}},
+		{"category:exam", "category = ?", []interface{}{"exam"}},
+		{"tag:Go", "id IN (SELECT material_tags.material_id FROM material_tags JOIN tags ON tags.id = material_tags.tag_id WHERE tags.name = ?)", []interface{}{"go"}},
+	}
+	for _, tt := range tests {
+		q := compile(t, tt.input, Options{})
+		if q.SQL != tt.wantSQL {
+			t.Errorf("Compile(%q) SQL = %s, want %s", tt.input, q.SQL, tt.wantSQL)
+		}
+		if !reflect.DeepEqual(q.Args, tt.wantArgs) {
+			t.Errorf("Compile(%q) Args = %v, want %v", tt.input, q.Args, tt.wantArgs)
+		}
+	}
+}
+
+func TestCompileFilters(t *testing.T) {
+	opts := Options{
+		Segment: func(string) []string { return nil },
+		TermFilter: func(term string) (string, []interface{}) {
+			if term == "高数" {
+				return "course_id = ?", []interface{}{uint(1)}
+			}
+			return "", nil
+		},
+		FieldFilter: func(field, value string) (string, []interface{}) {
+			if field == FieldCourse {
+				return "course_id = ? OR course_name ILIKE ?", []interface{}{uint(2), "%" + value + "%"}
+			}
+			return "", nil
+		},
+	}
+
+	q := compile(t, "高数 course:线代 category:exam", opts)
+	wantSQL := "((title ILIKE ? OR description ILIKE ? OR course_name ILIKE ? OR course_id = ?) AND (course_id = ? OR course_name ILIKE ?) AND category = ?)"
+	if q.SQL != wantSQL {
+		t.Errorf("SQL = %s, want %s", q.SQL, wantSQL)
+	}
+	wantArgs := []interface{}{"%高数%", "%高数%", "%高数%", uint(1), uint(2), "%线代%", "exam"}
+	if !reflect.DeepEqual(q.Args, wantArgs) {
+		t.Errorf("Args = %v, want %v", q.Args, wantArgs)
+	}
+}
+
+func TestCompileExpand(t *testing.T) {
+	calls := 0
+	opts := Options{
+		Expand: func(term string) []string {
+			calls++
+			if term == "线行代数" {
+				return []string{"线性代数"}
+			}
+			return nil
+		},
+	}
+	q := compile(t, "线行代数 期末", opts)
+
+	wantSQL := "((search_vector @@ to_tsquery('simple', ?) OR title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?" +
+		" OR search_vector @@ to_tsquery('simple', ?) OR title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?)" +
+		" AND (search_vector @@ to_tsquery('simple', ?) OR title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?))"
+	if q.SQL != wantSQL {
+		t.Errorf("SQL = %s, want %s", q.SQL, wantSQL)
+	}
+	// 扩展词不按前缀匹配
+	if q.Args[4] != "'线性代数'" || q.Args[5] != "%线性代数%" {
+		t.Errorf("扩展词参数 = %v %v, want '线性代数' %%线性代数%%", q.Args[4], q.Args[5])
+	}
+	if q.RankQuery != "(('线行代数':* | '线性代数') & '期末':*)" {
+		t.Errorf("RankQuery = %s", q.RankQuery)
+	}
+	// 扩展词不计入检索词
+	if !reflect.DeepEqual(q.Terms, []string{"线行代数", "期末"}) {
+		t.Errorf("Terms = %v, want [线行代数 期末]", q.Terms)
+	}
+	if calls != 2 {
+		t.Errorf("Expand 调用次数 = %d, want 2", calls)
+	}
+}
+
+func TestCompileNil(t *testing.T) {
+	if q := Compile(nil, Options{}); q != nil {
+		t.Errorf("Compile(nil) = %+v, want nil", q)
+	}
+}
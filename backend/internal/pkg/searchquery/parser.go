@@ -0,0 +1,406 @@
+// Package searchquery 解析资料搜索框中的查询语法，并编译为参数化的 SQL 条件和 tsquery。
+//
+// 支持的语法：
+//
+//	线性代数 期末        多个检索词同时命中（AND）
+//	"期末 复习"          引号内为短语，需整体出现
+//	-答案                排除包含该词的资料
+//	高数 OR 线代         任一命中即可，也可写作 |
+//	(高数 | 线代) 真题    括号分组
+//	course:高数          字段前缀，支持 course、category、tag 及中文别名
+//
+// 解析器对不完整的输入保持宽容：未闭合的引号和括号自动闭合，多余的右括号和运算符被忽略，
+// 未知的字段前缀按普通检索词处理。
+package searchquery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxQueryLength 查询的最大字数
+	MaxQueryLength = 200
+	// MaxTerms 查询中检索词、短语和字段条件的最大数量
+	MaxTerms = 20
+	// MaxDepth 括号的最大嵌套层数
+	MaxDepth = 5
+)
+
+var (
+	// ErrQueryTooLong 查询过长错误
+	ErrQueryTooLong = errors.New("搜索内容过长")
+	// ErrTooManyTerms 检索词过多错误
+	ErrTooManyTerms = errors.New("检索词过多")
+	// ErrTooDeep 括号嵌套过深错误
+	ErrTooDeep = errors.New("括号嵌套过深")
+)
+
+// 支持的字段前缀
+const (
+	FieldCourse   = "course"   // 课程名称或别名
+	FieldCategory = "category" // 资料分类代码或名称
+	FieldTag      = "tag"      // 标签
+)
+
+// fieldAliases 字段前缀及其别名
+var fieldAliases = map[string]string{
+	"course":   FieldCourse,
+	"课程":       FieldCourse,
+	"category": FieldCategory,
+	"分类":       FieldCategory,
+	"类型":       FieldCategory,
+	"tag":      FieldTag,
+	"标签":       FieldTag,
+}
+
+// NodeKind 语法树节点类型
+type NodeKind int
+
+const (
+	NodeTerm   NodeKind = iota // 普通检索词
+	NodePhrase                 // 引号短语
+	NodeField                  // 字段条件
+	NodeAnd                    // 全部命中
+	NodeOr                     // 任一命中
+	NodeNot                    // 排除
+)
+
+// Node 语法树节点
+type Node struct {
+	Kind     NodeKind
+	Field    string  // 字段条件的字段名
+	Value    string  // 检索词、短语或字段值
+	Children []*Node // AND、OR、NOT 的子节点
+}
+
+// String 返回节点的规范表示，便于调试和测试
+func (n *Node) String() string {
+	switch n.Kind {
+	case NodeTerm:
+		return n.Value
+	case NodePhrase:
+		return fmt.Sprintf("%q", n.Value)
+	case NodeField:
+		if strings.ContainsFunc(n.Value, unicode.IsSpace) {
+			return fmt.Sprintf("%s:%q", n.Field, n.Value)
+		}
+		return n.Field + ":" + n.Value
+	case NodeNot:
+		return "NOT(" + n.Children[0].String() + ")"
+	}
+
+	parts := make([]string, 0, len(n.Children))
+	for _, child := range n.Children {
+		parts = append(parts, child.String())
+	}
+	if n.Kind == NodeOr {
+		return "OR(" + strings.Join(parts, ", ") + ")"
+	}
+	return "AND(" + strings.Join(parts, ", ") + ")"
+}
+
+// Parse 解析查询，查询中没有任何检索内容时返回 nil
+func Parse(input string) (*Node, error) {
+	if utf8.RuneCountInString(input) > MaxQueryLength {
+		return nil, ErrQueryTooLong
+	}
+
+	p := &parser{tokens: tokenize(input)}
+	var nodes []*Node
+	for !p.done() {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		// 跳过多余的右括号
+		if p.peek(tokenRParen) {
+			p.pos++
+		}
+	}
+	return group(NodeAnd, nodes), nil
+}
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenPhrase
+	tokenField
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+// token 词法单元
+type token struct {
+	kind  tokenKind
+	field string
+	text  string
+}
+
+// isQuote 是否为引号，兼容中文输入法的全角引号
+func isQuote(r rune) bool {
+	return r == '"' || r == '“' || r == '”'
+}
+
+// isDelimiter 是否为结束普通检索词的字符
+func isDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || isQuote(r) || r == '|' || r == '(' || r == ')' || r == '（' || r == '）'
+}
+
+// lexer 词法分析器
+type lexer struct {
+	runes []rune
+	pos   int
+}
+
+// tokenize 将查询切分为词法单元
+func tokenize(input string) []token {
+	l := &lexer{runes: []rune(input)}
+	var tokens []token
+	for {
+		l.skipSpaces()
+		if l.pos >= len(l.runes) {
+			return tokens
+		}
+
+		r := l.runes[l.pos]
+		switch {
+		case r == '(' || r == '（':
+			l.pos++
+			tokens = append(tokens, token{kind: tokenLParen})
+		case r == ')' || r == '）':
+			l.pos++
+			tokens = append(tokens, token{kind: tokenRParen})
+		case isQuote(r):
+			tokens = append(tokens, token{kind: tokenPhrase, text: l.readPhrase()})
+		case r == '|':
+			l.pos++
+			tokens = append(tokens, token{kind: tokenOr})
+		case r == '-':
+			l.pos++
+			// 检索词开头的 - 表示排除，单独的 - 没有排除对象，直接忽略
+			if l.pos < len(l.runes) && !unicode.IsSpace(l.runes[l.pos]) {
+				tokens = append(tokens, token{kind: tokenNot})
+			}
+		default:
+			if tok, ok := l.readWord(); ok {
+				tokens = append(tokens, tok)
+			}
+		}
+	}
+}
+
+// skipSpaces 跳过空白字符（包括全角空格）
+func (l *lexer) skipSpaces() {
+	for l.pos < len(l.runes) && unicode.IsSpace(l.runes[l.pos]) {
+		l.pos++
+	}
+}
+
+// readPhrase 读取引号内的短语，缺少右引号时读到末尾
+func (l *lexer) readPhrase() string {
+	l.pos++ // 左引号
+	start := l.pos
+	for l.pos < len(l.runes) && !isQuote(l.runes[l.pos]) {
+		l.pos++
+	}
+	text := string(l.runes[start:l.pos])
+	if l.pos < len(l.runes) {
+		l.pos++ // 右引号
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// readWord 读取普通检索词、运算符或字段条件，返回 false 表示没有需要保留的词法单元
+func (l *lexer) readWord() (token, bool) {
+	start := l.pos
+	for l.pos < len(l.runes) && !isDelimiter(l.runes[l.pos]) {
+		l.pos++
+	}
+	word := string(l.runes[start:l.pos])
+
+	switch word {
+	case "OR":
+		return token{kind: tokenOr}, true
+	case "AND", "&":
+		// 多个检索词默认即为 AND，显式的 AND 按普通分隔处理
+		return token{}, false
+	}
+
+	if i := strings.IndexAny(word, ":："); i > 0 {
+		if field, ok := fieldAliases[strings.ToLower(word[:i])]; ok {
+			_, size := utf8.DecodeRuneInString(word[i:])
+			value := word[i+size:]
+			if value == "" {
+				// 允许 course:"高等 数学" 和 course: 高数 两种写法
+				value = l.readFieldValue()
+			}
+			if value == "" {
+				return token{}, false
+			}
+			return token{kind: tokenField, field: field, text: value}, true
+		}
+	}
+	return token{kind: tokenWord, text: word}, true
+}
+
+// readFieldValue 读取字段前缀后与之分隔的值
+func (l *lexer) readFieldValue() string {
+	l.skipSpaces()
+	if l.pos >= len(l.runes) {
+		return ""
+	}
+	if isQuote(l.runes[l.pos]) {
+		return l.readPhrase()
+	}
+	start := l.pos
+	for l.pos < len(l.runes) && !isDelimiter(l.runes[l.pos]) {
+		l.pos++
+	}
+	return string(l.runes[start:l.pos])
+}
+
+// parser 递归下降语法分析器
+//
+//	query  = or { or }
+//	or     = and { "OR" and }
+//	and    = unary { unary }
+//	unary  = "-" unary | primary
+//	primary = word | phrase | field | "(" or ")"
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+	terms  int
+}
+
+// done 是否已解析完全部词法单元
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// peek 下一个词法单元是否为指定类型
+func (p *parser) peek(kind tokenKind) bool {
+	return !p.done() && p.tokens[p.pos].kind == kind
+}
+
+// parseOr 解析以 OR 连接的表达式
+func (p *parser) parseOr() (*Node, error) {
+	var nodes []*Node
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		if !p.peek(tokenOr) {
+			return group(NodeOr, nodes), nil
+		}
+		p.pos++
+	}
+}
+
+// parseAnd 解析连续的检索条件
+func (p *parser) parseAnd() (*Node, error) {
+	var nodes []*Node
+	for !p.done() && !p.peek(tokenOr) && !p.peek(tokenRParen) {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return group(NodeAnd, nodes), nil
+}
+
+// parseUnary 解析排除条件
+func (p *parser) parseUnary() (*Node, error) {
+	if !p.peek(tokenNot) {
+		return p.parsePrimary()
+	}
+	p.pos++
+	if p.done() || p.peek(tokenOr) || p.peek(tokenRParen) {
+		return nil, nil
+	}
+
+	node, err := p.parseUnary()
+	if err != nil || node == nil {
+		return nil, err
+	}
+	// 双重排除相互抵消
+	if node.Kind == NodeNot {
+		return node.Children[0], nil
+	}
+	return &Node{Kind: NodeNot, Children: []*Node{node}}, nil
+}
+
+// parsePrimary 解析检索词、短语、字段条件和括号分组
+func (p *parser) parsePrimary() (*Node, error) {
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	switch tok.kind {
+	case tokenLParen:
+		p.depth++
+		if p.depth > MaxDepth {
+			return nil, ErrTooDeep
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		// 缺少右括号时视为在末尾闭合
+		if p.peek(tokenRParen) {
+			p.pos++
+		}
+		p.depth--
+		return node, nil
+	case tokenWord, tokenPhrase, tokenField:
+		if tok.text == "" {
+			return nil, nil
+		}
+		p.terms++
+		if p.terms > MaxTerms {
+			return nil, ErrTooManyTerms
+		}
+		switch tok.kind {
+		case tokenPhrase:
+			return &Node{Kind: NodePhrase, Value: tok.text}, nil
+		case tokenField:
+			return &Node{Kind: NodeField, Field: tok.field, Value: tok.text}, nil
+		}
+		return &Node{Kind: NodeTerm, Value: tok.text}, nil
+	}
+	return nil, nil
+}
+
+// group 组合子节点：忽略空节点，展开同类子节点，只有一个子节点时直接返回该节点
+func group(kind NodeKind, nodes []*Node) *Node {
+	children := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		if node.Kind == kind {
+			children = append(children, node.Children...)
+		} else {
+			children = append(children, node)
+		}
+	}
+
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		return children[0]
+	}
+	return &Node{Kind: kind, Children: children}
+}
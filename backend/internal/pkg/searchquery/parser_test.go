@@ -0,0 +1,123 @@
+package searchquery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		// 检索词
+		{"线性代数", "线性代数"},
+		{"  线性代数   期末  ", "AND(线性代数, 期末)"},
+		{"线性代数　期末", "AND(线性代数, 期末)"}, // 全角空格
+		{"高数 AND 真题 & 答案", "AND(高数, 真题, 答案)"},
+		{"c++ node.js", "AND(c++, node.js)"},
+		{"2023-2024 期末", "AND(2023-2024, 期末)"},
+
+		// 短语
+		{`"期末 复习"`, `"期末 复习"`},
+		{`“期末   复习” 提纲`, `AND("期末 复习", 提纲)`},
+		{`线性代数"期末复习"`, `AND(线性代数, "期末复习")`},
+		{`"期末 复习`, `"期末 复习"`}, // 缺少右引号
+		{`"" 期末`, "期末"},
+
+		// 排除
+		{"期末 -答案", "AND(期末, NOT(答案))"},
+		{`期末 -"参考 答案"`, `AND(期末, NOT("参考 答案"))`},
+		{"--答案", "答案"},
+		{"期末 - 答案", "AND(期末, 答案)"},
+		{"期末 -", "期末"},
+
+		// OR
+		{"高数 OR 线代", "OR(高数, 线代)"},
+		{"高数 | 线代 | 概率论", "OR(高数, 线代, 概率论)"},
+		{"高数|线代", "OR(高数, 线代)"},
+		{"高数 or 线代", "AND(高数, or, 线代)"}, // 只有大写 OR 是运算符
+		{"期末 高数 OR 线代 真题", "OR(AND(期末, 高数), AND(线代, 真题))"},
+		{"OR 高数 OR", "高数"},
+		{"高数 OR OR 线代", "OR(高数, 线代)"},
+
+		// 括号
+		{"(高数 OR 线代) 真题", "AND(OR(高数, 线代), 真题)"},
+		{"（高数 | 线代）真题", "AND(OR(高数, 线代), 真题)"},
+		{"-(高数 OR 线代) 真题", "AND(NOT(OR(高数, 线代)), 真题)"},
+		{"((高数))", "高数"},
+		{"(高数 OR 线代", "OR(高数, 线代)"}, // 缺少右括号
+		{"高数) 线代", "AND(高数, 线代)"},   // 多余的右括号
+		{"() 高数", "高数"},
+
+		// 字段
+		{"course:高数", "course:高数"},
+		{"课程：高等数学 期末", "AND(course:高等数学, 期末)"},
+		{"Category:exam", "category:exam"},
+		{"分类:试卷 类型:笔记", "AND(category:试卷, category:笔记)"},
+		{"tag:真题 标签:重点", "AND(tag:真题, tag:重点)"},
+		{`course:"高等 数学"`, `course:"高等 数学"`},
+		{"course: 高数", "course:高数"},
+		{"-course:高数 期末", "AND(NOT(course:高数), 期末)"},
+		{"course:", ""},
+		{"author:张三", "author:张三"}, // 未知字段按普通检索词处理
+		{"http://example.com", "http://example.com"},
+
+		// 空查询
+		{"", ""},
+		{"   ", ""},
+		{"OR | ()", ""},
+	}
+	for _, tt := range tests {
+		node, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) 失败: %v", tt.input, err)
+			continue
+		}
+		got := ""
+		if node != nil {
+			got = node.String()
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseFieldNode(t *testing.T) {
+	node, err := Parse("课程:高数")
+	if err != nil {
+		t.Fatalf("Parse() 失败: %v", err)
+	}
+	if node.Kind != NodeField || node.Field != FieldCourse || node.Value != "高数" {
+		t.Errorf("Parse() = %+v, want 课程字段", node)
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"过长", strings.Repeat("高", MaxQueryLength+1), ErrQueryTooLong},
+		{"检索词过多", strings.Repeat("高数 ", MaxTerms+1), ErrTooManyTerms},
+		{"嵌套过深", strings.Repeat("(", MaxDepth+1) + "高数", ErrTooDeep},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.input); !errors.Is(err, tt.want) {
+				t.Errorf("Parse() 错误 = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// 恰好达到上限时可以正常解析
+	if _, err := Parse(strings.Repeat("高数 ", MaxTerms)); err != nil {
+		t.Errorf("Parse() 检索词数量达到上限时失败: %v", err)
+	}
+	if _, err := Parse(strings.Repeat("(", MaxDepth) + "高数"); err != nil {
+		t.Errorf("Parse() 嵌套层数达到上限时失败: %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+// Package segmenter 提供基于词典的中文分词，用于构建和查询全文搜索向量。
+//
+// PostgreSQL 的 simple 解析器会把连续的中文整体当作一个词，导致“线性代数”无法命中
+// “线性代数期末复习”。这里先在 Go 中分词，再以空格连接交给 to_tsvector / to_tsquery。
+package segmenter
+
+import (
+	"bufio"
+	_ "embed"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed dict.txt
+var builtinDict string
+
+const (
+	// DefaultFrequency 未指定词频时的默认词频
+	DefaultFrequency = 1000
+	// MaxWordLength 词条的最大字数
+	MaxWordLength = 20
+)
+
+// Segmenter 基于词典的分词器
+// 中文按词典构建有向无环图，取概率最大的切分路径；字母和数字按连续片段切分并转为小写
+type Segmenter struct {
+	mu      sync.RWMutex
+	builtin map[string]int // 内置词条
+	custom  map[string]int // 自定义词条（管理员维护的词条、课程名称和别名）
+	maxLen  int            // 最长词条的字数
+	logSum  float64        // 词频总和的对数
+}
+
+var defaultSegmenter = New()
+
+// Default 返回全局分词器
+func Default() *Segmenter {
+	return defaultSegmenter
+}
+
+// New 创建分词器，加载内置词典
+func New() *Segmenter {
+	s := &Segmenter{
+		builtin: parseDict(builtinDict),
+		custom:  make(map[string]int),
+	}
+	s.rebuild()
+	return s
+}
+
+// parseDict 解析“词 词频”格式的词典
+func parseDict(content string) map[string]int {
+	words := make(map[string]int)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		freq := DefaultFrequency
+		if len(fields) > 1 {
+			if n, err := strconv.Atoi(fields[1]); err == nil && n > 0 {
+				freq = n
+			}
+		}
+		if word := NormalizeWord(fields[0]); word != "" {
+			words[word] = freq
+		}
+	}
+	return words
+}
+
+// NormalizeWord 规范化词条：去掉首尾空白并转小写，包含空白或超过最大字数时返回空字符串
+func NormalizeWord(word string) string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word == "" || strings.IndexFunc(word, unicode.IsSpace) >= 0 || utf8.RuneCountInString(word) > MaxWordLength {
+		return ""
+	}
+	return word
+}
+
+// SetCustomWords 用 words 替换全部自定义词条，词频不大于 0 时使用默认词频
+func (s *Segmenter) SetCustomWords(words map[string]int) {
+	custom := make(map[string]int, len(words))
+	for word, freq := range words {
+		if word = NormalizeWord(word); word == "" {
+			continue
+		}
+		if freq <= 0 {
+			freq = DefaultFrequency
+		}
+		custom[word] = freq
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.custom = custom
+	s.rebuild()
+}
+
+// CustomWordCount 返回自定义词条数
+func (s *Segmenter) CustomWordCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.custom)
+}
+
+// rebuild 重新计算最长词条字数和词频总和，调用方需持有写锁
+func (s *Segmenter) rebuild() {
+	maxLen := 1
+	total := 0
+	for _, dict := range []map[string]int{s.builtin, s.custom} {
+		for word, freq := range dict {
+			if n := utf8.RuneCountInString(word); n > maxLen {
+				maxLen = n
+			}
+			total += freq
+		}
+	}
+	s.maxLen = maxLen
+	s.logSum = math.Log(float64(total + 1))
+}
+
+// frequency 返回词条的词频，自定义词条优先，不在词典中时返回 0
+func (s *Segmenter) frequency(word string) int {
+	if freq, ok := s.custom[word]; ok {
+		return freq
+	}
+	return s.builtin[word]
+}
+
+// Cut 精确模式分词，返回不重叠的词序列，适合解析搜索关键词
+func (s *Segmenter) Cut(text string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens := make([]string, 0, utf8.RuneCountInString(text)/2+1)
+	for _, piece := range splitPieces(text) {
+		if piece.han {
+			tokens = append(tokens, s.cutHan(piece.runes)...)
+		} else {
+			tokens = append(tokens, string(piece.runes))
+		}
+	}
+	return tokens
+}
+
+// CutForSearch 搜索模式分词：在精确模式的基础上，对长词再切出其中包含的词典词，
+// 使“线性代数”既能被“线性代数”也能被“代数”命中，适合构建搜索向量
+func (s *Segmenter) CutForSearch(text string) []string {
+	words := s.Cut(text)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens := make([]string, 0, len(words)*2)
+	for _, word := range words {
+		runes := []rune(word)
+		if len(runes) > 2 && isHan(runes[0]) {
+			for size := 2; size < len(runes); size++ {
+				for i := 0; i+size <= len(runes); i++ {
+					if sub := string(runes[i : i+size]); s.frequency(sub) > 0 {
+						tokens = append(tokens, sub)
+					}
+				}
+			}
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// IndexText 返回用于 to_tsvector 的分词文本（搜索模式，以空格连接）
+func (s *Segmenter) IndexText(text string) string {
+	return strings.Join(s.CutForSearch(text), " ")
+}
+
+// QueryText 返回用于 plainto_tsquery 的分词文本（精确模式，以空格连接）
+func (s *Segmenter) QueryText(text string) string {
+	return strings.Join(s.Cut(text), " ")
+}
+
+// PrefixQuery 返回 to_tsquery 格式的查询：各词以 & 连接，最后一个词按前缀匹配
+// 分词结果只包含字母、数字和汉字，不会产生 tsquery 语法错误；没有可搜索的词时返回空字符串
+func (s *Segmenter) PrefixQuery(text string) string {
+	tokens := s.Cut(text)
+	if len(tokens) == 0 {
+		return ""
+	}
+	tokens[len(tokens)-1] += ":*"
+	return strings.Join(tokens, " & ")
+}
+
+// cutHan 对连续汉字按最大概率路径切分
+func (s *Segmenter) cutHan(runes []rune) []string {
+	n := len(runes)
+	// route[i] 为从 i 开始到结尾的最大对数概率，next[i] 为该路径上第一个词的结束位置
+	route := make([]float64, n+1)
+	next := make([]int, n+1)
+	for i := n - 1; i >= 0; i-- {
+		route[i] = math.Inf(-1)
+		for j := i + 1; j <= n && j-i <= s.maxLen; j++ {
+			freq := s.frequency(string(runes[i:j]))
+			if freq == 0 {
+				if j-i > 1 {
+					continue
+				}
+				// 单字不在词典中时按词频 1 处理
+				freq = 1
+			}
+			score := math.Log(float64(freq)) - s.logSum + route[j]
+			if score > route[i] {
+				route[i] = score
+				next[i] = j
+			}
+		}
+	}
+
+	words := make([]string, 0, n)
+	for i := 0; i < n; i = next[i] {
+		words = append(words, string(runes[i:next[i]]))
+	}
+	return words
+}
+
+// piece 连续的汉字或字母数字片段
+type piece struct {
+	runes []rune
+	han   bool
+}
+
+// splitPieces 将文本拆分为连续的汉字片段和字母数字片段，其他字符视为分隔符
+func splitPieces(text string) []piece {
+	var pieces []piece
+	var current []rune
+	currentHan := false
+
+	flush := func() {
+		if len(current) > 0 {
+			pieces = append(pieces, piece{runes: current, han: currentHan})
+			current = nil
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case isHan(r):
+			if !currentHan {
+				flush()
+			}
+			currentHan = true
+			current = append(current, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if currentHan {
+				flush()
+			}
+			currentHan = false
+			current = append(current, unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+	return pieces
+}
+
+// isHan 判断是否为汉字
+func isHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
@@ -0,0 +1,138 @@
+package segmenter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCut(t *testing.T) {
+	s := New()
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"线性代数期末复习", []string{"线性代数", "期末", "复习"}},
+		{"高等数学（上册）课后习题答案", []string{"高等数学", "上册", "课后习题", "答案"}},
+		{"C语言程序设计2024", []string{"c", "语言", "程序设计", "2024"}},
+		{"Data Structures 笔记", []string{"data", "structures", "笔记"}},
+		{"  ,.!  ", []string{}},
+	}
+	for _, tt := range tests {
+		got := s.Cut(tt.text)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Cut(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCutUnknownCharacters(t *testing.T) {
+	s := New()
+
+	// 不在词典中的汉字逐字切分
+	got := s.Cut("甲乙复习")
+	want := []string{"甲", "乙", "复习"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Cut() = %v, want %v", got, want)
+	}
+}
+
+func TestCutForSearch(t *testing.T) {
+	s := New()
+
+	got := s.CutForSearch("线性代数期末复习")
+	for _, token := range []string{"线性", "代数", "线性代数", "期末", "复习"} {
+		if !contains(got, token) {
+			t.Errorf("CutForSearch() = %v, 缺少 %q", got, token)
+		}
+	}
+}
+
+func TestQueryMatchesIndex(t *testing.T) {
+	s := New()
+
+	// 查询分出的每个词都应出现在文档的搜索模式分词中
+	index := s.CutForSearch("线性代数期末复习资料")
+	for _, query := range []string{"线性代数", "代数", "期末复习", "线性代数 复习"} {
+		for _, token := range s.Cut(query) {
+			if !contains(index, token) {
+				t.Errorf("查询 %q 的词 %q 未出现在索引 %v 中", query, token, index)
+			}
+		}
+	}
+}
+
+func TestSetCustomWords(t *testing.T) {
+	s := New()
+
+	if got := s.Cut("油层物理"); reflect.DeepEqual(got, []string{"油层物理"}) {
+		t.Fatalf("添加词条前不应整体切分: %v", got)
+	}
+
+	s.SetCustomWords(map[string]int{"油层物理": 0, "  ": 10, "含 空格": 10})
+	if got := s.Cut("油层物理期末"); !reflect.DeepEqual(got, []string{"油层物理", "期末"}) {
+		t.Errorf("Cut() = %v, want [油层物理 期末]", got)
+	}
+	if n := s.CustomWordCount(); n != 1 {
+		t.Errorf("CustomWordCount() = %d, want 1", n)
+	}
+
+	// 替换后旧词条失效
+	s.SetCustomWords(nil)
+	if got := s.Cut("油层物理"); reflect.DeepEqual(got, []string{"油层物理"}) {
+		t.Errorf("移除词条后不应整体切分: %v", got)
+	}
+}
+
+func TestPrefixQuery(t *testing.T) {
+	s := New()
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"线性代数", "线性代数:*"},
+		{"线性代数 复习", "线性代数 & 复习:*"},
+		{"a' & b:*|c", "a & b & c:*"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := s.PrefixQuery(tt.text); got != tt.want {
+			t.Errorf("PrefixQuery(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIndexText(t *testing.T) {
+	s := New()
+
+	got := s.IndexText("数据结构")
+	if !strings.Contains(got, "数据结构") || strings.ContainsAny(got, "'&|:") {
+		t.Errorf("IndexText() = %q", got)
+	}
+}
+
+func TestNormalizeWord(t *testing.T) {
+	tests := map[string]string{
+		" MATLAB ":              "matlab",
+		"油层 物理":                 "",
+		"":                      "",
+		strings.Repeat("长", 21): "",
+		strings.Repeat("长", 20): strings.Repeat("长", 20),
+	}
+	for input, want := range tests {
+		if got := NormalizeWord(input); got != want {
+			t.Errorf("NormalizeWord(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func contains(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
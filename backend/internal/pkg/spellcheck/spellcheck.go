@@ -0,0 +1,228 @@
+// Package spellcheck 基于字符二元组索引的拼写纠错，用于搜索结果过少时给出“您是不是要找”的建议。
+//
+// 词库由资料标题、课程名称和热门搜索词构建。查询时先按共同的二元组数量召回候选词，
+// 再结合编辑距离计算相似度，中文的错别字（如“线行代数”）和英文的拼写错误都能纠正。
+package spellcheck
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// MinWordLength 参与纠错的最短词长（字数），单个字无法可靠纠错
+	MinWordLength = 2
+	// MaxWordLength 参与纠错的最长词长（字数）
+	MaxWordLength = 50
+	// MinSimilarity 候选词与查询的最小相似度
+	MinSimilarity = 0.5
+
+	// minDice 召回候选词所需的最小二元组重合度
+	minDice = 0.3
+)
+
+// Suggestion 纠错建议
+type Suggestion struct {
+	Word  string  // 建议的词
+	Score float64 // 与查询的相似度（0-1）
+}
+
+// entry 词条
+type entry struct {
+	word   string
+	runes  []rune
+	weight int
+	grams  []string
+}
+
+// Checker 拼写纠错器，可并发使用
+type Checker struct {
+	mu    sync.RWMutex
+	words map[string]*entry   // 规范化的词 → 词条
+	grams map[string][]*entry // 二元组 → 包含该二元组的词条
+}
+
+// New 创建空词库的纠错器
+func New() *Checker {
+	return &Checker{
+		words: make(map[string]*entry),
+		grams: make(map[string][]*entry),
+	}
+}
+
+// Normalize 规范化词：转为小写并合并连续空白
+func Normalize(word string) string {
+	return strings.Join(strings.Fields(strings.ToLower(word)), " ")
+}
+
+// Load 替换词库，words 的值为词的权重（如出现次数、搜索次数），权重高的词在相似度相同时优先
+// 规范化后相同的词权重累加，过短或过长的词被忽略
+func (c *Checker) Load(words map[string]int) {
+	entries := make(map[string]*entry, len(words))
+	for word, weight := range words {
+		word = Normalize(word)
+		runes := []rune(word)
+		if len(runes) < MinWordLength || len(runes) > MaxWordLength {
+			continue
+		}
+		if e, ok := entries[word]; ok {
+			e.weight += weight
+			continue
+		}
+		entries[word] = &entry{word: word, runes: runes, weight: weight, grams: bigrams(runes)}
+	}
+
+	grams := make(map[string][]*entry)
+	for _, e := range entries {
+		for _, gram := range e.grams {
+			grams[gram] = append(grams[gram], e)
+		}
+	}
+
+	c.mu.Lock()
+	c.words = entries
+	c.grams = grams
+	c.mu.Unlock()
+}
+
+// Len 返回词库中的词数
+func (c *Checker) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.words)
+}
+
+// Contains 词库中是否包含该词
+func (c *Checker) Contains(word string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.words[Normalize(word)]
+	return ok
+}
+
+// Suggest 返回与 word 拼写相近的词，按相似度从高到低排列，不包含 word 本身
+func (c *Checker) Suggest(word string, limit int) []Suggestion {
+	word = Normalize(word)
+	runes := []rune(word)
+	if limit <= 0 || len(runes) < MinWordLength || len(runes) > MaxWordLength {
+		return nil
+	}
+	queryGrams := bigrams(runes)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// 统计每个候选词与查询共同的二元组数量
+	shared := make(map[*entry]int)
+	for _, gram := range queryGrams {
+		for _, e := range c.grams[gram] {
+			shared[e]++
+		}
+	}
+
+	type candidate struct {
+		entry *entry
+		score float64
+		rank  float64
+	}
+	maxDistance := maxEditDistance(len(runes))
+	candidates := make([]candidate, 0, len(shared))
+	for e, count := range shared {
+		if e.word == word {
+			continue
+		}
+		dice := 2 * float64(count) / float64(len(queryGrams)+len(e.grams))
+		if dice < minDice {
+			continue
+		}
+		if diff := len(e.runes) - len(runes); diff > maxDistance || -diff > maxDistance {
+			continue
+		}
+		distance := editDistance(runes, e.runes)
+		if distance > maxDistance {
+			continue
+		}
+
+		longest := math.Max(float64(len(runes)), float64(len(e.runes)))
+		score := (dice + 1 - float64(distance)/longest) / 2
+		if score < MinSimilarity {
+			continue
+		}
+		// 排序时给常用词少量加分，使相似度接近时优先建议常用词
+		candidates = append(candidates, candidate{
+			entry: e,
+			score: score,
+			rank:  score + 0.02*math.Log1p(float64(e.weight)),
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].rank != candidates[j].rank {
+			return candidates[i].rank > candidates[j].rank
+		}
+		return candidates[i].entry.word < candidates[j].entry.word
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	suggestions := make([]Suggestion, 0, len(candidates))
+	for _, cand := range candidates {
+		suggestions = append(suggestions, Suggestion{Word: cand.entry.word, Score: cand.score})
+	}
+	return suggestions
+}
+
+// maxEditDistance 允许的最大编辑距离，随词长增加
+func maxEditDistance(length int) int {
+	switch {
+	case length <= 4:
+		return 1
+	case length <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// bigrams 返回词首尾补位后的全部不重复二元组，补位使词首和词尾的字也能参与匹配
+func bigrams(runes []rune) []string {
+	padded := make([]rune, 0, len(runes)+2)
+	padded = append(padded, '\x02')
+	padded = append(padded, runes...)
+	padded = append(padded, '\x03')
+
+	seen := make(map[string]bool, len(padded))
+	grams := make([]string, 0, len(padded)-1)
+	for i := 0; i+1 < len(padded); i++ {
+		gram := string(padded[i : i+2])
+		if !seen[gram] {
+			seen[gram] = true
+			grams = append(grams, gram)
+		}
+	}
+	return grams
+}
+
+// editDistance 计算两个词的编辑距离（Levenshtein 距离）
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,140 @@
+package spellcheck
+
+import (
+	"reflect"
+	"testing"
+)
+
+// words 测试词库
+var words = map[string]int{
+	"线性代数":        10,
+	"线性代数期末复习":    2,
+	"高等数学":        20,
+	"概率论与数理统计":    5,
+	"数据结构":        8,
+	"数据库原理":       3,
+	"calculus":    4,
+	"Data Mining": 1,
+	"高":           100, // 单字不入词库
+}
+
+// suggestWords 返回建议的词
+func suggestWords(c *Checker, word string, limit int) []string {
+	var got []string
+	for _, s := range c.Suggest(word, limit) {
+		got = append(got, s.Word)
+	}
+	return got
+}
+
+func TestSuggest(t *testing.T) {
+	c := New()
+	c.Load(words)
+
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"线行代数", []string{"线性代数"}},
+		{"高等数字", []string{"高等数学"}},
+		{"概率论与数里统计", []string{"概率论与数理统计"}},
+		{"calculas", []string{"calculus"}},
+		{"CALCULAS", []string{"calculus"}},
+		{"data  minning", []string{"data mining"}},
+		{"数据结够", []string{"数据结构"}},
+		// 差异过大时不给建议
+		{"操作系统", nil},
+		{"线性", nil},
+		// 本身就在词库中的词不建议自身
+		{"线性代数", nil},
+		// 过短的词不纠错
+		{"高", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := suggestWords(c, tt.word, 3); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestOrder(t *testing.T) {
+	c := New()
+	c.Load(map[string]int{
+		"数据结构": 1,
+		"数据结果": 1,
+		"数据挖掘": 1,
+	})
+
+	// 两个候选词相似度相同时按字典序排列
+	got := suggestWords(c, "数据结沟", 3)
+	want := []string{"数据结构", "数据结果"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Suggest() = %v, want %v", got, want)
+	}
+
+	// 相似度相同时权重高的优先
+	c.Load(map[string]int{
+		"数据结构": 1,
+		"数据结果": 100,
+	})
+	got = suggestWords(c, "数据结沟", 1)
+	if !reflect.DeepEqual(got, []string{"数据结果"}) {
+		t.Errorf("Suggest() = %v, want [数据结果]", got)
+	}
+}
+
+func TestSuggestScore(t *testing.T) {
+	c := New()
+	c.Load(words)
+
+	suggestions := c.Suggest("线行代数", 1)
+	if len(suggestions) != 1 {
+		t.Fatalf("Suggest() 数量 = %d, want 1", len(suggestions))
+	}
+	if score := suggestions[0].Score; score < MinSimilarity || score >= 1 {
+		t.Errorf("Score = %v, want [%v, 1)", score, MinSimilarity)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	c := New()
+	c.Load(words)
+
+	if c.Len() != len(words)-1 {
+		t.Errorf("Len() = %d, want %d", c.Len(), len(words)-1)
+	}
+	if !c.Contains("DATA   mining") {
+		t.Errorf("Contains() 应忽略大小写和多余空白")
+	}
+	if c.Contains("高") {
+		t.Errorf("单字不应加入词库")
+	}
+
+	// 重新加载会替换整个词库
+	c.Load(map[string]int{"操作系统": 1})
+	if c.Contains("线性代数") || !c.Contains("操作系统") {
+		t.Errorf("Load() 应替换原有词库")
+	}
+	if got := suggestWords(c, "线行代数", 3); got != nil {
+		t.Errorf("Suggest() = %v, want nil", got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"线性代数", "线行代数", 1},
+		{"高数", "高等数学", 2},
+	}
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
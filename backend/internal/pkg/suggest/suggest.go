@@ -0,0 +1,285 @@
+// Package suggest 基于 Redis 有序集合的搜索联想前缀索引。
+//
+// 每个前缀对应一个有序集合，成员为“来源:联想词”，分数为权重，输入时只需读取一个有序集合的前若干名，
+// 适合逐字调用。除联想词开头外，分词后每个词的开头也建立前缀（权重减半），输入“期末”也能联想到
+// “线性代数期末复习”。
+//
+// 索引按版本存放：全量重建时写入新版本并切换版本号，旧版本的键随过期时间自动清理；
+// 资料审核通过或删除时直接增减当前版本中的权重。
+package suggest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 联想词来源
+const (
+	KindTitle   = "title"   // 资料标题
+	KindCourse  = "course"  // 课程名称或别名
+	KindTag     = "tag"     // 标签
+	KindKeyword = "keyword" // 热门搜索词
+)
+
+const (
+	// MaxPrefixLength 建立索引的最长前缀（字数），更长的输入按该长度查找后再过滤
+	MaxPrefixLength = 16
+	// MaxTextLength 参与联想的最长文本（字数）
+	MaxTextLength = 100
+
+	// infixFactor 从词中间开始匹配的前缀的权重系数，低于从开头匹配
+	infixFactor = 0.5
+	// batchSize 每次提交到 Redis 的命令数
+	batchSize = 1000
+)
+
+// Entry 联想词条
+type Entry struct {
+	Kind   string
+	Text   string
+	Weight float64
+}
+
+// Suggestion 联想结果
+type Suggestion struct {
+	Kind  string
+	Text  string
+	Score float64
+}
+
+// Index Redis 前缀索引
+type Index struct {
+	client    *redis.Client
+	keyPrefix string
+	ttl       time.Duration
+	segment   func(string) []string
+}
+
+// NewIndex 创建前缀索引，keyPrefix 为 Redis 键前缀，ttl 为索引键的过期时间（应大于全量重建间隔），
+// segment 用于切分词的开头位置，为 nil 时只从文本开头建立前缀
+func NewIndex(client *redis.Client, keyPrefix string, ttl time.Duration, segment func(string) []string) *Index {
+	return &Index{
+		client:    client,
+		keyPrefix: keyPrefix,
+		ttl:       ttl,
+		segment:   segment,
+	}
+}
+
+// Normalize 规范化文本：转为小写并合并连续空白
+func Normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// Rebuild 用 entries 全量重建索引，完成后切换到新版本
+// 重建期间的增量更新写入旧版本，会在下一次重建时补上
+func (i *Index) Rebuild(ctx context.Context, entries []Entry) error {
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+	keys := make(map[string]bool)
+
+	pipe := i.client.Pipeline()
+	for _, entry := range entries {
+		member := encodeMember(entry.Kind, entry.Text)
+		for prefix, factor := range prefixes(entry.Text, i.segment) {
+			key := i.key(version, prefix)
+			keys[key] = true
+			pipe.ZIncrBy(ctx, key, entry.Weight*factor, member)
+			if err := flush(ctx, pipe, false); err != nil {
+				return err
+			}
+		}
+	}
+	for key := range keys {
+		pipe.Expire(ctx, key, i.ttl)
+		if err := flush(ctx, pipe, false); err != nil {
+			return err
+		}
+	}
+	if err := flush(ctx, pipe, true); err != nil {
+		return err
+	}
+
+	if err := i.client.Set(ctx, i.versionKey(), version, i.ttl).Err(); err != nil {
+		return fmt.Errorf("切换联想索引版本失败: %w", err)
+	}
+	return nil
+}
+
+// Add 将词条加入当前版本的索引，同一联想词的权重累加；索引尚未构建时忽略
+func (i *Index) Add(ctx context.Context, entries ...Entry) error {
+	return i.incr(ctx, entries, 1)
+}
+
+// Remove 从当前版本的索引中减去词条的权重，权重减到 0 时移除联想词；索引尚未构建时忽略
+func (i *Index) Remove(ctx context.Context, entries ...Entry) error {
+	return i.incr(ctx, entries, -1)
+}
+
+// incr 按 sign 增减词条在各前缀下的权重
+func (i *Index) incr(ctx context.Context, entries []Entry, sign float64) error {
+	version, err := i.version(ctx)
+	if err != nil || version == "" {
+		return err
+	}
+
+	pipe := i.client.Pipeline()
+	for _, entry := range entries {
+		member := encodeMember(entry.Kind, entry.Text)
+		for prefix, factor := range prefixes(entry.Text, i.segment) {
+			key := i.key(version, prefix)
+			pipe.ZIncrBy(ctx, key, sign*entry.Weight*factor, member)
+			if sign < 0 {
+				pipe.ZRemRangeByScore(ctx, key, "-inf", "0")
+			} else {
+				pipe.Expire(ctx, key, i.ttl)
+			}
+		}
+	}
+	return flush(ctx, pipe, true)
+}
+
+// Lookup 返回以 prefix 开头的联想词，按权重从高到低排列，同一文本只保留权重最高的来源
+func (i *Index) Lookup(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	prefix = Normalize(prefix)
+	if prefix == "" || limit <= 0 {
+		return nil, nil
+	}
+	version, err := i.version(ctx)
+	if err != nil || version == "" {
+		return nil, err
+	}
+
+	// 超过最长前缀时按截断的前缀查找，多取一些候选再按完整输入过滤
+	lookup := prefix
+	fetch := limit * 2
+	if runes := []rune(prefix); len(runes) > MaxPrefixLength {
+		lookup = string(runes[:MaxPrefixLength])
+		fetch = limit * 10
+	}
+
+	members, err := i.client.ZRevRangeWithScores(ctx, i.key(version, lookup), 0, int64(fetch-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("查询联想索引失败: %w", err)
+	}
+
+	seen := make(map[string]bool, len(members))
+	suggestions := make([]Suggestion, 0, limit)
+	for _, z := range members {
+		member, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+		kind, text, ok := decodeMember(member)
+		if !ok {
+			continue
+		}
+		normalized := Normalize(text)
+		if seen[normalized] || (lookup != prefix && !strings.Contains(normalized, prefix)) {
+			continue
+		}
+		seen[normalized] = true
+		suggestions = append(suggestions, Suggestion{Kind: kind, Text: text, Score: z.Score})
+		if len(suggestions) == limit {
+			break
+		}
+	}
+	return suggestions, nil
+}
+
+// version 返回当前索引版本，索引尚未构建时返回空字符串
+func (i *Index) version(ctx context.Context) (string, error) {
+	version, err := i.client.Get(ctx, i.versionKey()).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("获取联想索引版本失败: %w", err)
+	}
+	return version, nil
+}
+
+// versionKey 当前索引版本号的键
+func (i *Index) versionKey() string {
+	return i.keyPrefix + "version"
+}
+
+// key 前缀对应的有序集合的键
+func (i *Index) key(version, prefix string) string {
+	return i.keyPrefix + version + ":" + prefix
+}
+
+// flush 提交管道中的命令，force 为 false 时只在命令数达到批大小时提交
+func flush(ctx context.Context, pipe redis.Pipeliner, force bool) error {
+	if pipe.Len() == 0 || (!force && pipe.Len() < batchSize) {
+		return nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("写入联想索引失败: %w", err)
+	}
+	return nil
+}
+
+// encodeMember 将来源和文本编码为有序集合成员
+func encodeMember(kind, text string) string {
+	return kind + ":" + strings.TrimSpace(text)
+}
+
+// decodeMember 解析有序集合成员
+func decodeMember(member string) (kind, text string, ok bool) {
+	kind, text, ok = strings.Cut(member, ":")
+	return kind, text, ok && kind != "" && text != ""
+}
+
+// prefixes 返回文本需要建立索引的全部前缀及其权重系数：从文本开头的前缀系数为 1，
+// 从分词后每个词开头的前缀系数为 infixFactor；同一前缀取较大的系数
+func prefixes(text string, segment func(string) []string) map[string]float64 {
+	normalized := []rune(Normalize(text))
+	if len(normalized) == 0 || len(normalized) > MaxTextLength {
+		return nil
+	}
+
+	result := make(map[string]float64)
+	add := func(start int, factor float64) {
+		end := start + MaxPrefixLength
+		if end > len(normalized) {
+			end = len(normalized)
+		}
+		for i := start + 1; i <= end; i++ {
+			prefix := strings.TrimSpace(string(normalized[start:i]))
+			if prefix != "" && result[prefix] < factor {
+				result[prefix] = factor
+			}
+		}
+	}
+
+	add(0, 1)
+	if segment == nil {
+		return result
+	}
+
+	// 依次定位每个词在文本中的位置，分词结果可能略去空白和标点
+	lower := string(normalized)
+	offset := 0
+	for _, token := range segment(lower) {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		pos := strings.Index(lower[offset:], token)
+		if pos < 0 {
+			continue
+		}
+		byteStart := offset + pos
+		offset = byteStart + len(token)
+		if start := len([]rune(lower[:byteStart])); start > 0 {
+			add(start, infixFactor)
+		}
+	}
+	return result
+}
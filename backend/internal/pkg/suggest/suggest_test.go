@@ -0,0 +1,94 @@
+package suggest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"线性代数", "线性代数"},
+		{"  Data   Structures ", "data structures"},
+		{"期末\t复习", "期末 复习"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.input); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixes(t *testing.T) {
+	got := prefixes("高数真题", nil)
+	want := map[string]float64{"高": 1, "高数": 1, "高数真": 1, "高数真题": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("prefixes() = %v, want %v", got, want)
+	}
+}
+
+func TestPrefixesSegmented(t *testing.T) {
+	segment := func(text string) []string {
+		if text == "线性代数 期末" {
+			return []string{"线性代数", "期末"}
+		}
+		return nil
+	}
+	got := prefixes("线性代数 期末", segment)
+
+	// 从开头匹配的前缀系数为 1
+	for _, prefix := range []string{"线", "线性代数", "线性代数 期", "线性代数 期末"} {
+		if got[prefix] != 1 {
+			t.Errorf("prefixes()[%q] = %v, want 1", prefix, got[prefix])
+		}
+	}
+	// 从词开头匹配的前缀系数较低
+	for _, prefix := range []string{"期", "期末"} {
+		if got[prefix] != infixFactor {
+			t.Errorf("prefixes()[%q] = %v, want %v", prefix, got[prefix], infixFactor)
+		}
+	}
+	// 空格结尾的前缀与去掉空格后相同，不单独建立
+	if _, ok := got["线性代数 "]; ok {
+		t.Errorf("prefixes() 不应包含以空格结尾的前缀")
+	}
+	// 不从词中间开始建立前缀
+	if _, ok := got["性代"]; ok {
+		t.Errorf("prefixes() 不应包含从词中间开始的前缀")
+	}
+}
+
+func TestPrefixesLength(t *testing.T) {
+	got := prefixes(strings.Repeat("资", MaxPrefixLength+5), nil)
+	if len(got) != MaxPrefixLength {
+		t.Errorf("len(prefixes()) = %d, want %d", len(got), MaxPrefixLength)
+	}
+
+	if got := prefixes(strings.Repeat("资", MaxTextLength+1), nil); got != nil {
+		t.Errorf("prefixes() 过长文本 = %v, want nil", got)
+	}
+	if got := prefixes("   ", nil); got != nil {
+		t.Errorf("prefixes() 空文本 = %v, want nil", got)
+	}
+}
+
+func TestMember(t *testing.T) {
+	member := encodeMember(KindTitle, " 高数: 期末真题 ")
+	if member != "title:高数: 期末真题" {
+		t.Errorf("encodeMember() = %q, want %q", member, "title:高数: 期末真题")
+	}
+	kind, text, ok := decodeMember(member)
+	if !ok || kind != KindTitle || text != "高数: 期末真题" {
+		t.Errorf("decodeMember(%q) = %q, %q, %v", member, kind, text, ok)
+	}
+
+	for _, invalid := range []string{"title", ":高数", "title:"} {
+		if _, _, ok := decodeMember(invalid); ok {
+			t.Errorf("decodeMember(%q) 应失败", invalid)
+		}
+	}
+}
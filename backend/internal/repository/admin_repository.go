@@ -20,9 +20,12 @@ type AdminRepository interface {
 	GetUserByUsername(username string) (*model.User, error)
 	UpdateUser(user *model.User) error
 	UpdateUserStatus(id uint, status, reason string) error
+	UpdateUserStorageQuota(id uint, quotaMB *int64) error
+	UpdateUserDownloadLimits(id uint, req *model.UpdateUserDownloadLimitsRequest) error
 	DeleteUser(id uint) error
 	CountUserDownloads(userID uint) (int64, error)
 	CountUserUploads(userID uint) (int64, error)
+	SumUserUploadSize(userID uint) (int64, error)
 	CountUserFavorites(userID uint) (int64, error)
 }
 
@@ -210,6 +213,24 @@ func (r *adminRepository) UpdateUserStatus(id uint, status, reason string) error
 		Updates(updates).Error
 }
 
+// UpdateUserStorageQuota 设置用户存储配额，quotaMB 为空时恢复按角色配置
+func (r *adminRepository) UpdateUserStorageQuota(id uint, quotaMB *int64) error {
+	return r.db.Model(&model.User{}).
+		Where("id = ?", id).
+		Update("storage_quota_mb", quotaMB).Error
+}
+
+// UpdateUserDownloadLimits 设置用户下载限制，各项为空时恢复按角色配置
+func (r *adminRepository) UpdateUserDownloadLimits(id uint, req *model.UpdateUserDownloadLimitsRequest) error {
+	return r.db.Model(&model.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"download_daily_limit":  req.DailyLimit,
+			"download_weekly_limit": req.WeeklyLimit,
+			"download_burst_limit":  req.BurstLimit,
+		}).Error
+}
+
 // DeleteUser 删除用户 (软删除)
 func (r *adminRepository) DeleteUser(id uint) error {
 	return r.db.Delete(&model.User{}, id).Error
@@ -233,6 +254,16 @@ func (r *adminRepository) CountUserUploads(userID uint) (int64, error) {
 	return count, nil
 }
 
+func (r *adminRepository) SumUserUploadSize(userID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&model.Material{}).Where("uploader_id = ?", userID).
+		Select("COALESCE(SUM(file_size), 0)").Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
 func (r *adminRepository) CountUserFavorites(userID uint) (int64, error) {
 	var count int64
 	err := r.db.Model(&model.Favorite{}).Where("user_id = ?", userID).Count(&count).Error
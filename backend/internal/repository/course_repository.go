@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCourseNotFound 课程不存在错误
+	ErrCourseNotFound = errors.New("课程不存在")
+)
+
+// CourseListOptions 课程列表查询选项
+type CourseListOptions struct {
+	Keyword    string // 匹配课程代码、名称和别名
+	Department string
+}
+
+// CourseRepository 课程数据访问层接口
+type CourseRepository interface {
+	// Create 创建课程（连同别名）
+	Create(ctx context.Context, course *model.Course) error
+	// FindByID 根据ID查找课程（包含别名）
+	FindByID(ctx context.Context, id uint) (*model.Course, error)
+	// FindByIDs 批量查找课程，返回 ID -> 课程
+	FindByIDs(ctx context.Context, ids []uint) (map[uint]*model.Course, error)
+	// Resolve 按课程名称、别名或课程代码查找课程，忽略大小写和多余空白
+	Resolve(ctx context.Context, name string) (*model.Course, error)
+	// FindByTerms 查找名称、别名或课程代码与 terms 中任一项一致的全部课程（包含别名），忽略大小写和多余空白
+	FindByTerms(ctx context.Context, terms []string) ([]*model.Course, error)
+	// Update 更新课程，并用 aliases 替换原有别名
+	Update(ctx context.Context, course *model.Course, aliases []string) error
+	// Delete 删除课程及其别名，关联资料的 course_id 置空
+	Delete(ctx context.Context, id uint) error
+	// List 获取课程列表
+	List(ctx context.Context, page, pageSize int, opts CourseListOptions) ([]*model.Course, int64, error)
+	// CountMaterials 统计各课程关联的资料数（不含已删除）
+	CountMaterials(ctx context.Context, ids []uint) (map[uint]int64, error)
+	// LinkMaterials 将课程名称与课程名称或别名一致、尚未关联课程的资料关联到该课程，返回关联的资料数
+	LinkMaterials(ctx context.Context, course *model.Course) (int64, error)
+	// ListTerms 获取全部课程名称和别名，用于搜索分词词典
+	ListTerms(ctx context.Context) ([]string, error)
+}
+
+// courseRepository 课程数据访问层实现
+type courseRepository struct {
+	db *gorm.DB
+}
+
+// NewCourseRepository 创建课程数据访问层实例
+func NewCourseRepository(db *gorm.DB) CourseRepository {
+	return &courseRepository{db: db}
+}
+
+// courseMatchKey 课程名称的比较键：规范化后转小写
+func courseMatchKey(name string) string {
+	return strings.ToLower(model.NormalizeCourseName(name))
+}
+
+// Create 创建课程（连同别名）
+func (r *courseRepository) Create(ctx context.Context, course *model.Course) error {
+	return r.db.WithContext(ctx).Create(course).Error
+}
+
+// FindByID 根据ID查找课程（包含别名）
+func (r *courseRepository) FindByID(ctx context.Context, id uint) (*model.Course, error) {
+	var course model.Course
+	result := r.db.WithContext(ctx).
+		Preload("Aliases", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&course, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, result.Error
+	}
+	return &course, nil
+}
+
+// FindByIDs 批量查找课程
+func (r *courseRepository) FindByIDs(ctx context.Context, ids []uint) (map[uint]*model.Course, error) {
+	result := make(map[uint]*model.Course, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var courses []*model.Course
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&courses).Error; err != nil {
+		return nil, err
+	}
+	for _, course := range courses {
+		result[course.ID] = course
+	}
+	return result, nil
+}
+
+// Resolve 按课程名称、别名或课程代码查找课程
+func (r *courseRepository) Resolve(ctx context.Context, name string) (*model.Course, error) {
+	key := courseMatchKey(name)
+	if key == "" {
+		return nil, ErrCourseNotFound
+	}
+
+	var course model.Course
+	result := r.db.WithContext(ctx).
+		Where("lower(name) = ? OR (code <> '' AND lower(code) = ?)", key, key).
+		Or("id IN (?)", r.db.Model(&model.CourseAlias{}).Select("course_id").Where("lower(alias) = ?", key)).
+		Order("id ASC").
+		First(&course)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, result.Error
+	}
+	return &course, nil
+}
+
+// FindByTerms 查找名称、别名或课程代码与 terms 中任一项一致的全部课程
+func (r *courseRepository) FindByTerms(ctx context.Context, terms []string) ([]*model.Course, error) {
+	keys := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if key := courseMatchKey(term); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	var courses []*model.Course
+	err := r.db.WithContext(ctx).
+		Where("lower(name) IN ? OR (code <> '' AND lower(code) IN ?)", keys, keys).
+		Or("id IN (?)", r.db.Model(&model.CourseAlias{}).Select("course_id").Where("lower(alias) IN ?", keys)).
+		Preload("Aliases").
+		Order("id ASC").
+		Find(&courses).Error
+	return courses, err
+}
+
+// Update 更新课程，并用 aliases 替换原有别名
+func (r *courseRepository) Update(ctx context.Context, course *model.Course, aliases []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Aliases").Save(course).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("course_id = ?", course.ID).Delete(&model.CourseAlias{}).Error; err != nil {
+			return err
+		}
+
+		course.Aliases = make([]model.CourseAlias, 0, len(aliases))
+		for _, alias := range aliases {
+			course.Aliases = append(course.Aliases, model.CourseAlias{CourseID: course.ID, Alias: alias})
+		}
+		if len(course.Aliases) == 0 {
+			return nil
+		}
+		return tx.Create(&course.Aliases).Error
+	})
+}
+
+// Delete 删除课程及其别名，关联资料的 course_id 置空
+func (r *courseRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Material{}).Where("course_id = ?", id).
+			UpdateColumn("course_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("course_id = ?", id).Delete(&model.CourseAlias{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&model.Course{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCourseNotFound
+		}
+		return nil
+	})
+}
+
+// List 获取课程列表
+func (r *courseRepository) List(ctx context.Context, page, pageSize int, opts CourseListOptions) ([]*model.Course, int64, error) {
+	var courses []*model.Course
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Course{})
+	if opts.Keyword != "" {
+		pattern := "%" + strings.ToLower(strings.TrimSpace(opts.Keyword)) + "%"
+		query = query.Where(
+			"lower(name) LIKE ? OR lower(code) LIKE ? OR id IN (?)",
+			pattern, pattern,
+			r.db.Model(&model.CourseAlias{}).Select("course_id").Where("lower(alias) LIKE ?", pattern),
+		)
+	}
+	if opts.Department != "" {
+		query = query.Where("department = ?", opts.Department)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Preload("Aliases", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Order("name ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&courses).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return courses, total, nil
+}
+
+// CountMaterials 统计各课程关联的资料数
+func (r *courseRepository) CountMaterials(ctx context.Context, ids []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		CourseID uint
+		Count    int64
+	}
+	if err := r.db.WithContext(ctx).Model(&model.Material{}).
+		Select("course_id, COUNT(*) AS count").
+		Where("course_id IN ?", ids).
+		Group("course_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.CourseID] = row.Count
+	}
+	return counts, nil
+}
+
+// LinkMaterials 将尚未关联课程、课程名称匹配的资料关联到该课程
+func (r *courseRepository) LinkMaterials(ctx context.Context, course *model.Course) (int64, error) {
+	keys := []string{courseMatchKey(course.Name)}
+	for _, alias := range course.Aliases {
+		keys = append(keys, courseMatchKey(alias.Alias))
+	}
+
+	// 历史资料的课程名称未规范化，比较前合并空白
+	result := r.db.WithContext(ctx).Model(&model.Material{}).
+		Where("course_id IS NULL").
+		Where(`lower(regexp_replace(trim(course_name), '\s+', ' ', 'g')) IN ?`, keys).
+		UpdateColumn("course_id", course.ID)
+	return result.RowsAffected, result.Error
+}
+
+// ListTerms 获取全部课程名称和别名
+func (r *courseRepository) ListTerms(ctx context.Context) ([]string, error) {
+	var terms []string
+	err := r.db.WithContext(ctx).Raw(
+		`SELECT name FROM courses UNION SELECT alias FROM course_aliases`,
+	).Scan(&terms).Error
+	return terms, err
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrDownloadQuotaBoostNotFound 临时下载额度提升不存在错误
+	ErrDownloadQuotaBoostNotFound = errors.New("临时下载额度提升不存在")
+)
+
+// DownloadQuotaBoostListOptions 临时下载额度提升列表查询选项
+type DownloadQuotaBoostListOptions struct {
+	UserID   uint
+	ActiveAt *time.Time // 只返回在该时间生效的
+}
+
+// DownloadQuotaBoostRepository 临时下载额度提升数据访问层接口
+type DownloadQuotaBoostRepository interface {
+	// Create 创建临时提升
+	Create(ctx context.Context, boost *model.DownloadQuotaBoost) error
+	// FindByID 根据ID查找临时提升
+	FindByID(ctx context.Context, id uint) (*model.DownloadQuotaBoost, error)
+	// Delete 删除临时提升
+	Delete(ctx context.Context, id uint) error
+	// List 分页获取临时提升
+	List(ctx context.Context, page, pageSize int, opts *DownloadQuotaBoostListOptions) ([]*model.DownloadQuotaBoost, int64, error)
+	// FindActive 查找在指定时间对用户生效的临时提升（用户本人、所属角色或全体用户）
+	FindActive(ctx context.Context, userID uint, role model.UserRole, at time.Time) ([]*model.DownloadQuotaBoost, error)
+}
+
+// downloadQuotaBoostRepository 临时下载额度提升数据访问层实现
+type downloadQuotaBoostRepository struct {
+	db *gorm.DB
+}
+
+// NewDownloadQuotaBoostRepository 创建临时下载额度提升数据访问层实例
+func NewDownloadQuotaBoostRepository(db *gorm.DB) DownloadQuotaBoostRepository {
+	return &downloadQuotaBoostRepository{db: db}
+}
+
+// Create 创建临时提升
+func (r *downloadQuotaBoostRepository) Create(ctx context.Context, boost *model.DownloadQuotaBoost) error {
+	return r.db.WithContext(ctx).Create(boost).Error
+}
+
+// FindByID 根据ID查找临时提升
+func (r *downloadQuotaBoostRepository) FindByID(ctx context.Context, id uint) (*model.DownloadQuotaBoost, error) {
+	var boost model.DownloadQuotaBoost
+	result := r.db.WithContext(ctx).First(&boost, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrDownloadQuotaBoostNotFound
+		}
+		return nil, result.Error
+	}
+	return &boost, nil
+}
+
+// Delete 删除临时提升
+func (r *downloadQuotaBoostRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.DownloadQuotaBoost{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDownloadQuotaBoostNotFound
+	}
+	return nil
+}
+
+// List 分页获取临时提升
+func (r *downloadQuotaBoostRepository) List(ctx context.Context, page, pageSize int, opts *DownloadQuotaBoostListOptions) ([]*model.DownloadQuotaBoost, int64, error) {
+	var boosts []*model.DownloadQuotaBoost
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.DownloadQuotaBoost{})
+	if opts != nil {
+		if opts.UserID > 0 {
+			query = query.Where("user_id = ?", opts.UserID)
+		}
+		if opts.ActiveAt != nil {
+			query = query.Where("starts_at <= ? AND ends_at > ?", *opts.ActiveAt, *opts.ActiveAt)
+		}
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("ends_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&boosts).Error; err != nil {
+		return nil, 0, err
+	}
+	return boosts, total, nil
+}
+
+// FindActive 查找在指定时间对用户生效的临时提升
+func (r *downloadQuotaBoostRepository) FindActive(ctx context.Context, userID uint, role model.UserRole, at time.Time) ([]*model.DownloadQuotaBoost, error) {
+	var boosts []*model.DownloadQuotaBoost
+	err := r.db.WithContext(ctx).
+		Where("starts_at <= ? AND ends_at > ?", at, at).
+		Where("user_id = ? OR (user_id IS NULL AND (role = '' OR role IS NULL OR role = ?))", userID, role).
+		Order("id ASC").
+		Find(&boosts).Error
+	if err != nil {
+		return nil, err
+	}
+	return boosts, nil
+}
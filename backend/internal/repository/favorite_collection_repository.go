@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCollectionNotFound 收藏夹不存在错误
+	ErrCollectionNotFound = errors.New("收藏夹不存在")
+	// ErrCollectionItemNotFound 收藏夹中不存在该资料错误
+	ErrCollectionItemNotFound = errors.New("收藏夹中不存在该资料")
+	// ErrCollectionItemExists 资料已在收藏夹中错误
+	ErrCollectionItemExists = errors.New("资料已在收藏夹中")
+)
+
+// FavoriteCollectionRepository 收藏夹数据访问层接口
+type FavoriteCollectionRepository interface {
+	// Create 创建收藏夹，排在用户收藏夹列表末尾
+	Create(ctx context.Context, collection *model.FavoriteCollection) error
+	// FindByID 根据ID查找收藏夹
+	FindByID(ctx context.Context, id uint) (*model.FavoriteCollection, error)
+	// FindByShareToken 根据分享令牌查找收藏夹
+	FindByShareToken(ctx context.Context, token string) (*model.FavoriteCollection, error)
+	// Update 更新收藏夹名称、描述和分享令牌
+	Update(ctx context.Context, collection *model.FavoriteCollection) error
+	// Delete 删除收藏夹及其中的资料
+	Delete(ctx context.Context, id uint) error
+	// ListByUser 获取用户的全部收藏夹，按位置排序
+	ListByUser(ctx context.Context, userID uint) ([]*model.FavoriteCollection, error)
+	// CountByUser 统计用户的收藏夹数
+	CountByUser(ctx context.Context, userID uint) (int64, error)
+	// Reorder 按 ids 的顺序重排用户的收藏夹
+	Reorder(ctx context.Context, userID uint, ids []uint) error
+
+	// AddItem 向收藏夹添加资料，排在末尾
+	AddItem(ctx context.Context, item *model.FavoriteCollectionItem) error
+	// UpdateItemNote 更新收藏夹中资料的备注
+	UpdateItemNote(ctx context.Context, collectionID, materialID uint, note string) error
+	// RemoveItem 从收藏夹移除资料
+	RemoveItem(ctx context.Context, collectionID, materialID uint) error
+	// RemoveMaterialFromUser 从用户的所有收藏夹中移除资料
+	RemoveMaterialFromUser(ctx context.Context, userID, materialID uint) error
+	// ReorderItems 按 materialIDs 的顺序重排收藏夹中的资料
+	ReorderItems(ctx context.Context, collectionID uint, materialIDs []uint) error
+	// ListItems 分页获取收藏夹中的资料，approvedOnly 为 true 时只返回已通过的资料
+	ListItems(ctx context.Context, collectionID uint, page, pageSize int, approvedOnly bool) ([]*model.FavoriteCollectionItem, int64, error)
+	// ListCollectionIDsByMaterial 获取用户包含指定资料的收藏夹ID
+	ListCollectionIDsByMaterial(ctx context.Context, userID, materialID uint) ([]uint, error)
+}
+
+// favoriteCollectionRepository 收藏夹数据访问层实现
+type favoriteCollectionRepository struct {
+	db *gorm.DB
+}
+
+// NewFavoriteCollectionRepository 创建收藏夹数据访问层实例
+func NewFavoriteCollectionRepository(db *gorm.DB) FavoriteCollectionRepository {
+	return &favoriteCollectionRepository{db: db}
+}
+
+// Create 创建收藏夹
+func (r *favoriteCollectionRepository) Create(ctx context.Context, collection *model.FavoriteCollection) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var maxPosition int
+		if err := tx.Model(&model.FavoriteCollection{}).
+			Where("user_id = ?", collection.UserID).
+			Select("COALESCE(MAX(position), -1)").
+			Scan(&maxPosition).Error; err != nil {
+			return err
+		}
+		collection.Position = maxPosition + 1
+		return tx.Create(collection).Error
+	})
+}
+
+// FindByID 根据ID查找收藏夹
+func (r *favoriteCollectionRepository) FindByID(ctx context.Context, id uint) (*model.FavoriteCollection, error) {
+	var collection model.FavoriteCollection
+	result := r.db.WithContext(ctx).First(&collection, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, result.Error
+	}
+	return &collection, nil
+}
+
+// FindByShareToken 根据分享令牌查找收藏夹
+func (r *favoriteCollectionRepository) FindByShareToken(ctx context.Context, token string) (*model.FavoriteCollection, error) {
+	var collection model.FavoriteCollection
+	result := r.db.WithContext(ctx).Preload("User").Where("share_token = ?", token).First(&collection)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, result.Error
+	}
+	return &collection, nil
+}
+
+// Update 更新收藏夹
+func (r *favoriteCollectionRepository) Update(ctx context.Context, collection *model.FavoriteCollection) error {
+	return r.db.WithContext(ctx).Model(collection).
+		Select("name", "description", "share_token").
+		Updates(collection).Error
+}
+
+// Delete 删除收藏夹及其中的资料
+func (r *favoriteCollectionRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&model.FavoriteCollectionItem{}).Error; err != nil {
+			return err
+		}
+		// 软删除后释放分享令牌，避免旧链接继续可用
+		if err := tx.Model(&model.FavoriteCollection{}).Where("id = ?", id).Update("share_token", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.FavoriteCollection{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCollectionNotFound
+		}
+		return nil
+	})
+}
+
+// ListByUser 获取用户的全部收藏夹
+func (r *favoriteCollectionRepository) ListByUser(ctx context.Context, userID uint) ([]*model.FavoriteCollection, error) {
+	var collections []*model.FavoriteCollection
+	result := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("position ASC, id ASC").
+		Find(&collections)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return collections, nil
+}
+
+// CountByUser 统计用户的收藏夹数
+func (r *favoriteCollectionRepository) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&model.FavoriteCollection{}).Where("user_id = ?", userID).Count(&count)
+	return count, result.Error
+}
+
+// Reorder 重排用户的收藏夹
+func (r *favoriteCollectionRepository) Reorder(ctx context.Context, userID uint, ids []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, id := range ids {
+			if err := tx.Model(&model.FavoriteCollection{}).
+				Where("id = ? AND user_id = ?", id, userID).
+				UpdateColumn("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddItem 向收藏夹添加资料
+func (r *favoriteCollectionRepository) AddItem(ctx context.Context, item *model.FavoriteCollectionItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.FavoriteCollectionItem{}).
+			Where("collection_id = ? AND material_id = ?", item.CollectionID, item.MaterialID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrCollectionItemExists
+		}
+
+		var maxPosition int
+		if err := tx.Model(&model.FavoriteCollectionItem{}).
+			Where("collection_id = ?", item.CollectionID).
+			Select("COALESCE(MAX(position), -1)").
+			Scan(&maxPosition).Error; err != nil {
+			return err
+		}
+		item.Position = maxPosition + 1
+
+		if err := tx.Create(item).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrCollectionItemExists
+			}
+			return err
+		}
+		return tx.Model(&model.FavoriteCollection{}).
+			Where("id = ?", item.CollectionID).
+			UpdateColumn("item_count", gorm.Expr("item_count + 1")).Error
+	})
+}
+
+// UpdateItemNote 更新收藏夹中资料的备注
+func (r *favoriteCollectionRepository) UpdateItemNote(ctx context.Context, collectionID, materialID uint, note string) error {
+	result := r.db.WithContext(ctx).Model(&model.FavoriteCollectionItem{}).
+		Where("collection_id = ? AND material_id = ?", collectionID, materialID).
+		Update("note", note)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCollectionItemNotFound
+	}
+	return nil
+}
+
+// RemoveItem 从收藏夹移除资料
+func (r *favoriteCollectionRepository) RemoveItem(ctx context.Context, collectionID, materialID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("collection_id = ? AND material_id = ?", collectionID, materialID).
+			Delete(&model.FavoriteCollectionItem{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCollectionItemNotFound
+		}
+		return tx.Model(&model.FavoriteCollection{}).
+			Where("id = ?", collectionID).
+			UpdateColumn("item_count", gorm.Expr("GREATEST(item_count - 1, 0)")).Error
+	})
+}
+
+// RemoveMaterialFromUser 从用户的所有收藏夹中移除资料
+func (r *favoriteCollectionRepository) RemoveMaterialFromUser(ctx context.Context, userID, materialID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		collectionIDs := tx.Model(&model.FavoriteCollection{}).Select("id").Where("user_id = ?", userID)
+
+		var affected []uint
+		if err := tx.Model(&model.FavoriteCollectionItem{}).
+			Where("material_id = ? AND collection_id IN (?)", materialID, collectionIDs).
+			Pluck("collection_id", &affected).Error; err != nil {
+			return err
+		}
+		if len(affected) == 0 {
+			return nil
+		}
+
+		if err := tx.Where("material_id = ? AND collection_id IN ?", materialID, affected).
+			Delete(&model.FavoriteCollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.FavoriteCollection{}).
+			Where("id IN ?", affected).
+			UpdateColumn("item_count", gorm.Expr("GREATEST(item_count - 1, 0)")).Error
+	})
+}
+
+// ReorderItems 重排收藏夹中的资料
+func (r *favoriteCollectionRepository) ReorderItems(ctx context.Context, collectionID uint, materialIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, materialID := range materialIDs {
+			if err := tx.Model(&model.FavoriteCollectionItem{}).
+				Where("collection_id = ? AND material_id = ?", collectionID, materialID).
+				UpdateColumn("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListItems 分页获取收藏夹中的资料
+func (r *favoriteCollectionRepository) ListItems(ctx context.Context, collectionID uint, page, pageSize int, approvedOnly bool) ([]*model.FavoriteCollectionItem, int64, error) {
+	var items []*model.FavoriteCollectionItem
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.FavoriteCollectionItem{}).
+		Joins("JOIN materials ON materials.id = favorite_collection_items.material_id AND materials.deleted_at IS NULL").
+		Where("favorite_collection_items.collection_id = ?", collectionID)
+	if approvedOnly {
+		query = query.Where("materials.status = ?", model.StatusApproved)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.
+		Order("favorite_collection_items.position ASC, favorite_collection_items.id ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Preload("Material.Uploader").
+		Preload("Material.Tags").
+		Find(&items)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return items, total, nil
+}
+
+// ListCollectionIDsByMaterial 获取用户包含指定资料的收藏夹ID
+func (r *favoriteCollectionRepository) ListCollectionIDsByMaterial(ctx context.Context, userID, materialID uint) ([]uint, error) {
+	var ids []uint
+	result := r.db.WithContext(ctx).Model(&model.FavoriteCollectionItem{}).
+		Joins("JOIN favorite_collections ON favorite_collections.id = favorite_collection_items.collection_id AND favorite_collections.deleted_at IS NULL").
+		Where("favorite_collections.user_id = ? AND favorite_collection_items.material_id = ?", userID, materialID).
+		Pluck("favorite_collection_items.collection_id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ids, nil
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrMaterialArchiveRuleNotFound 归档规则不存在错误
+	ErrMaterialArchiveRuleNotFound = errors.New("归档规则不存在")
+)
+
+// MaterialArchiveRepository 资料归档数据访问层接口
+type MaterialArchiveRepository interface {
+	// CreateRule 创建归档规则
+	CreateRule(ctx context.Context, rule *model.MaterialArchiveRule) error
+	// FindRuleByID 根据ID查找归档规则
+	FindRuleByID(ctx context.Context, id uint) (*model.MaterialArchiveRule, error)
+	// UpdateRule 更新归档规则
+	UpdateRule(ctx context.Context, rule *model.MaterialArchiveRule) error
+	// DeleteRule 删除归档规则
+	DeleteRule(ctx context.Context, id uint) error
+	// ListRules 获取全部归档规则
+	ListRules(ctx context.Context) ([]*model.MaterialArchiveRule, error)
+	// TouchRule 记录规则的最近执行时间
+	TouchRule(ctx context.Context, id uint, at time.Time) error
+
+	// FindCandidates 查找命中规则、尚未计划归档的已通过资料
+	// exemptSince 之后从归档恢复的资料不会再次被自动归档
+	FindCandidates(ctx context.Context, rule *model.MaterialArchiveRule, now, exemptSince time.Time, limit int) ([]*model.Material, error)
+	// FilterCandidates 返回给定资料中仍命中规则的资料ID
+	FilterCandidates(ctx context.Context, rule *model.MaterialArchiveRule, now, exemptSince time.Time, ids []uint) (map[uint]bool, error)
+	// ListScheduled 获取已计划归档的已通过资料，按计划时间排序
+	ListScheduled(ctx context.Context, limit int) ([]*model.Material, error)
+	// Schedule 计划在指定时间归档资料，资料已计划或不再是已通过状态时返回 false
+	Schedule(ctx context.Context, id, ruleID uint, at time.Time) (bool, error)
+	// CancelSchedule 取消计划归档
+	CancelSchedule(ctx context.Context, id uint) error
+	// Archive 将已通过的资料改为已归档，并按 keyMoves（旧存储键 -> 新存储键）更新资料及其版本的文件存储键
+	Archive(ctx context.Context, id uint, ruleID *uint, keyMoves map[string]string) (bool, error)
+	// Restore 将已归档的资料恢复为已通过，并按 keyMoves 更新文件存储键
+	Restore(ctx context.Context, id uint, keyMoves map[string]string) (bool, error)
+}
+
+// materialArchiveRepository 资料归档数据访问层实现
+type materialArchiveRepository struct {
+	db *gorm.DB
+}
+
+// NewMaterialArchiveRepository 创建资料归档数据访问层实例
+func NewMaterialArchiveRepository(db *gorm.DB) MaterialArchiveRepository {
+	return &materialArchiveRepository{db: db}
+}
+
+// CreateRule 创建归档规则
+func (r *materialArchiveRepository) CreateRule(ctx context.Context, rule *model.MaterialArchiveRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// FindRuleByID 根据ID查找归档规则
+func (r *materialArchiveRepository) FindRuleByID(ctx context.Context, id uint) (*model.MaterialArchiveRule, error) {
+	var rule model.MaterialArchiveRule
+	result := r.db.WithContext(ctx).First(&rule, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrMaterialArchiveRuleNotFound
+		}
+		return nil, result.Error
+	}
+	return &rule, nil
+}
+
+// UpdateRule 更新归档规则
+func (r *materialArchiveRepository) UpdateRule(ctx context.Context, rule *model.MaterialArchiveRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+// DeleteRule 删除归档规则
+func (r *materialArchiveRepository) DeleteRule(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.MaterialArchiveRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMaterialArchiveRuleNotFound
+	}
+	return nil
+}
+
+// ListRules 获取全部归档规则
+func (r *materialArchiveRepository) ListRules(ctx context.Context) ([]*model.MaterialArchiveRule, error) {
+	var rules []*model.MaterialArchiveRule
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// TouchRule 记录规则的最近执行时间
+func (r *materialArchiveRepository) TouchRule(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.MaterialArchiveRule{}).
+		Where("id = ?", id).
+		UpdateColumn("last_run_at", at).Error
+}
+
+// candidateQuery 构建命中规则的已通过资料查询
+func (r *materialArchiveRepository) candidateQuery(ctx context.Context, rule *model.MaterialArchiveRule, now, exemptSince time.Time) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&model.Material{}).
+		Where("status = ?", model.StatusApproved).
+		Where("archive_restored_at IS NULL OR archive_restored_at < ?", exemptSince)
+	if rule.Category != "" {
+		query = query.Where("category = ?", rule.Category)
+	}
+
+	switch rule.Type {
+	case model.ArchiveRuleAge:
+		query = query.Where("created_at < ?", now.AddDate(0, -rule.AgeMonths, 0))
+	case model.ArchiveRuleInactivity:
+		cutoff := now.AddDate(0, -rule.InactiveMonths, 0)
+		query = query.Where("created_at < ?", cutoff).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM download_records dr
+				WHERE dr.material_id = materials.id AND dr.created_at >= ? AND dr.deleted_at IS NULL
+			)`, cutoff)
+	case model.ArchiveRuleTerm:
+		// 学期字符串格式固定，字典序即时间先后
+		query = query.Where("course_term <> '' AND course_term < ?", rule.BeforeTerm)
+	default:
+		query = query.Where("1 = 0")
+	}
+	return query
+}
+
+// FindCandidates 查找命中规则、尚未计划归档的已通过资料
+func (r *materialArchiveRepository) FindCandidates(ctx context.Context, rule *model.MaterialArchiveRule, now, exemptSince time.Time, limit int) ([]*model.Material, error) {
+	var materials []*model.Material
+	err := r.candidateQuery(ctx, rule, now, exemptSince).
+		Where("archive_scheduled_at IS NULL").
+		Order("id ASC").
+		Limit(limit).
+		Find(&materials).Error
+	if err != nil {
+		return nil, err
+	}
+	return materials, nil
+}
+
+// FilterCandidates 返回给定资料中仍命中规则的资料ID
+func (r *materialArchiveRepository) FilterCandidates(ctx context.Context, rule *model.MaterialArchiveRule, now, exemptSince time.Time, ids []uint) (map[uint]bool, error) {
+	matched := make(map[uint]bool)
+	if len(ids) == 0 {
+		return matched, nil
+	}
+
+	var matchedIDs []uint
+	err := r.candidateQuery(ctx, rule, now, exemptSince).
+		Where("id IN ?", ids).
+		Pluck("id", &matchedIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range matchedIDs {
+		matched[id] = true
+	}
+	return matched, nil
+}
+
+// ListScheduled 获取已计划归档的已通过资料
+func (r *materialArchiveRepository) ListScheduled(ctx context.Context, limit int) ([]*model.Material, error) {
+	var materials []*model.Material
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND archive_scheduled_at IS NOT NULL", model.StatusApproved).
+		Order("archive_scheduled_at ASC, id ASC").
+		Limit(limit).
+		Find(&materials).Error
+	if err != nil {
+		return nil, err
+	}
+	return materials, nil
+}
+
+// Schedule 计划在指定时间归档资料
+func (r *materialArchiveRepository) Schedule(ctx context.Context, id, ruleID uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Material{}).
+		Where("id = ? AND status = ? AND archive_scheduled_at IS NULL", id, model.StatusApproved).
+		UpdateColumns(map[string]interface{}{
+			"archive_scheduled_at": at,
+			"archive_rule_id":      ruleID,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CancelSchedule 取消计划归档
+func (r *materialArchiveRepository) CancelSchedule(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&model.Material{}).
+		Where("id = ? AND status = ?", id, model.StatusApproved).
+		UpdateColumns(map[string]interface{}{
+			"archive_scheduled_at": nil,
+			"archive_rule_id":      nil,
+		}).Error
+}
+
+// Archive 将已通过的资料改为已归档
+func (r *materialArchiveRepository) Archive(ctx context.Context, id uint, ruleID *uint, keyMoves map[string]string) (bool, error) {
+	archived := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Material{}).
+			Where("id = ? AND status = ?", id, model.StatusApproved).
+			Updates(map[string]interface{}{
+				"status":               model.StatusArchived,
+				"archived_at":          time.Now(),
+				"archive_rule_id":      ruleID,
+				"archive_scheduled_at": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		archived = true
+		return moveFileKeys(tx, id, keyMoves)
+	})
+	if err != nil {
+		return false, err
+	}
+	return archived, nil
+}
+
+// Restore 将已归档的资料恢复为已通过
+func (r *materialArchiveRepository) Restore(ctx context.Context, id uint, keyMoves map[string]string) (bool, error) {
+	restored := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Material{}).
+			Where("id = ? AND status = ?", id, model.StatusArchived).
+			Updates(map[string]interface{}{
+				"status":              model.StatusApproved,
+				"archived_at":         nil,
+				"archive_rule_id":     nil,
+				"archive_restored_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		restored = true
+		return moveFileKeys(tx, id, keyMoves)
+	})
+	if err != nil {
+		return false, err
+	}
+	return restored, nil
+}
+
+// moveFileKeys 更新资料及其版本引用的文件存储键
+func moveFileKeys(tx *gorm.DB, materialID uint, keyMoves map[string]string) error {
+	for oldKey, newKey := range keyMoves {
+		if err := tx.Model(&model.Material{}).
+			Where("id = ? AND file_key = ?", materialID, oldKey).
+			UpdateColumn("file_key", newKey).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.MaterialVersion{}).
+			Where("material_id = ? AND file_key = ?", materialID, oldKey).
+			UpdateColumn("file_key", newKey).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrCommentNotFound 评论不存在错误
+	ErrCommentNotFound = errors.New("评论不存在")
+)
+
+// CommentListOptions 评论管理列表查询选项
+type CommentListOptions struct {
+	Page       int
+	PageSize   int
+	MaterialID *uint
+	UserID     *uint
+	Status     model.CommentStatus
+	Keyword    string
+}
+
+// MaterialCommentRepository 资料评论数据访问层接口
+// 资料的 comment_count 和顶层评论的 reply_count 只统计正常显示的评论，由本层在状态变化时同步维护
+type MaterialCommentRepository interface {
+	// Create 创建评论
+	Create(ctx context.Context, comment *model.MaterialComment) error
+	// FindByID 根据ID查找评论
+	FindByID(ctx context.Context, id uint) (*model.MaterialComment, error)
+	// UpdateContent 更新评论内容并记录编辑时间
+	UpdateContent(ctx context.Context, comment *model.MaterialComment) error
+	// SetStatus 修改评论状态并同步评论计数，fields 为同时更新的其他字段
+	SetStatus(ctx context.Context, id uint, status model.CommentStatus, fields map[string]interface{}) error
+	// Delete 删除评论并同步评论计数
+	Delete(ctx context.Context, id uint) error
+	// CountReplies 统计顶层评论下未删除的回复数（含已隐藏）
+	CountReplies(ctx context.Context, rootID uint) (int64, error)
+	// ListRoots 分页获取资料的顶层评论，按时间倒序
+	ListRoots(ctx context.Context, materialID uint, page, pageSize int) ([]*model.MaterialComment, int64, error)
+	// ListReplies 分页获取顶层评论的回复，按时间正序
+	ListReplies(ctx context.Context, rootID uint, page, pageSize int) ([]*model.MaterialComment, int64, error)
+	// ListReplyPreviews 批量获取顶层评论最早的 limit 条回复用于列表预览，返回 顶层评论ID -> 回复
+	ListReplyPreviews(ctx context.Context, rootIDs []uint, limit int) (map[uint][]*model.MaterialComment, error)
+	// List 分页获取评论列表（管理员）
+	List(ctx context.Context, opts CommentListOptions) ([]*model.MaterialComment, int64, error)
+}
+
+// materialCommentRepository 资料评论数据访问层实现
+type materialCommentRepository struct {
+	db *gorm.DB
+}
+
+// NewMaterialCommentRepository 创建资料评论数据访问层实例
+func NewMaterialCommentRepository(db *gorm.DB) MaterialCommentRepository {
+	return &materialCommentRepository{db: db}
+}
+
+// Create 创建评论
+func (r *materialCommentRepository) Create(ctx context.Context, comment *model.MaterialComment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if comment.Status != model.CommentVisible {
+			return nil
+		}
+		return adjustCommentCounts(tx, comment, 1)
+	})
+}
+
+// FindByID 根据ID查找评论
+func (r *materialCommentRepository) FindByID(ctx context.Context, id uint) (*model.MaterialComment, error) {
+	var comment model.MaterialComment
+	result := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("ReplyToUser").
+		First(&comment, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, result.Error
+	}
+	return &comment, nil
+}
+
+// UpdateContent 更新评论内容
+func (r *materialCommentRepository) UpdateContent(ctx context.Context, comment *model.MaterialComment) error {
+	return r.db.WithContext(ctx).Model(comment).
+		Select("content", "edited_at").
+		Updates(comment).Error
+}
+
+// SetStatus 修改评论状态并同步评论计数
+func (r *materialCommentRepository) SetStatus(ctx context.Context, id uint, status model.CommentStatus, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var comment model.MaterialComment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&comment, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCommentNotFound
+			}
+			return err
+		}
+
+		updates := map[string]interface{}{"status": status}
+		for key, value := range fields {
+			updates[key] = value
+		}
+		if err := tx.Model(&comment).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		delta := visibleDelta(comment.Status, status)
+		if delta == 0 {
+			return nil
+		}
+		return adjustCommentCounts(tx, &comment, delta)
+	})
+}
+
+// Delete 删除评论
+func (r *materialCommentRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var comment model.MaterialComment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&comment, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCommentNotFound
+			}
+			return err
+		}
+		if err := tx.Delete(&comment).Error; err != nil {
+			return err
+		}
+		if comment.Status != model.CommentVisible {
+			return nil
+		}
+		return adjustCommentCounts(tx, &comment, -1)
+	})
+}
+
+// CountReplies 统计顶层评论下未删除的回复数
+func (r *materialCommentRepository) CountReplies(ctx context.Context, rootID uint) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&model.MaterialComment{}).
+		Where("root_id = ?", rootID).
+		Count(&count)
+	return count, result.Error
+}
+
+// ListRoots 分页获取资料的顶层评论
+func (r *materialCommentRepository) ListRoots(ctx context.Context, materialID uint, page, pageSize int) ([]*model.MaterialComment, int64, error) {
+	var comments []*model.MaterialComment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.MaterialComment{}).
+		Where("material_id = ? AND root_id IS NULL", materialID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Preload("User").
+		Find(&comments)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return comments, total, nil
+}
+
+// ListReplies 分页获取顶层评论的回复
+func (r *materialCommentRepository) ListReplies(ctx context.Context, rootID uint, page, pageSize int) ([]*model.MaterialComment, int64, error) {
+	var comments []*model.MaterialComment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.MaterialComment{}).Where("root_id = ?", rootID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Order("created_at ASC, id ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Preload("User").
+		Preload("ReplyToUser").
+		Find(&comments)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return comments, total, nil
+}
+
+// ListReplyPreviews 批量获取顶层评论最早的 limit 条回复
+func (r *materialCommentRepository) ListReplyPreviews(ctx context.Context, rootIDs []uint, limit int) (map[uint][]*model.MaterialComment, error) {
+	result := make(map[uint][]*model.MaterialComment, len(rootIDs))
+	if len(rootIDs) == 0 {
+		return result, nil
+	}
+
+	ranked := r.db.WithContext(ctx).Model(&model.MaterialComment{}).
+		Select("id, ROW_NUMBER() OVER (PARTITION BY root_id ORDER BY created_at ASC, id ASC) AS rn").
+		Where("root_id IN ?", rootIDs)
+
+	var comments []*model.MaterialComment
+	if err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.WithContext(ctx).Table("(?) AS ranked", ranked).Select("id").Where("rn <= ?", limit)).
+		Order("created_at ASC, id ASC").
+		Preload("User").
+		Preload("ReplyToUser").
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		result[*comment.RootID] = append(result[*comment.RootID], comment)
+	}
+	return result, nil
+}
+
+// List 分页获取评论列表
+func (r *materialCommentRepository) List(ctx context.Context, opts CommentListOptions) ([]*model.MaterialComment, int64, error) {
+	var comments []*model.MaterialComment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.MaterialComment{})
+	if opts.MaterialID != nil {
+		query = query.Where("material_id = ?", *opts.MaterialID)
+	}
+	if opts.UserID != nil {
+		query = query.Where("user_id = ?", *opts.UserID)
+	}
+	if opts.Status != "" {
+		query = query.Where("status = ?", opts.Status)
+	}
+	if opts.Keyword != "" {
+		query = query.Where("content LIKE ?", "%"+opts.Keyword+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (opts.Page - 1) * opts.PageSize
+	result := query.Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(opts.PageSize).
+		Preload("User").
+		Preload("ReplyToUser").
+		Find(&comments)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return comments, total, nil
+}
+
+// visibleDelta 计算状态变化对可见评论数的影响
+func visibleDelta(from, to model.CommentStatus) int {
+	switch {
+	case from != model.CommentVisible && to == model.CommentVisible:
+		return 1
+	case from == model.CommentVisible && to != model.CommentVisible:
+		return -1
+	}
+	return 0
+}
+
+// adjustCommentCounts 同步资料评论数和顶层评论回复数
+func adjustCommentCounts(tx *gorm.DB, comment *model.MaterialComment, delta int) error {
+	if err := tx.Model(&model.Material{}).
+		Where("id = ?", comment.MaterialID).
+		UpdateColumn("comment_count", gorm.Expr("GREATEST(comment_count + ?, 0)", delta)).Error; err != nil {
+		return err
+	}
+	if comment.RootID == nil {
+		return nil
+	}
+	return tx.Model(&model.MaterialComment{}).
+		Where("id = ?", *comment.RootID).
+		UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count + ?, 0)", delta)).Error
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrMaterialImportNotFound 批量导入任务不存在错误
+	ErrMaterialImportNotFound = errors.New("批量导入任务不存在")
+)
+
+// MaterialImportRepository 资料批量导入数据访问层接口
+type MaterialImportRepository interface {
+	// Create 创建导入任务
+	Create(ctx context.Context, materialImport *model.MaterialImport) error
+	// FindByID 根据ID查找导入任务，包含每行的导入结果
+	FindByID(ctx context.Context, id uint) (*model.MaterialImport, error)
+	// Update 更新导入任务（不含条目）
+	Update(ctx context.Context, materialImport *model.MaterialImport) error
+	// ListByUploader 分页获取用户的导入任务（不含条目）
+	ListByUploader(ctx context.Context, uploaderID uint, page, pageSize int) ([]*model.MaterialImport, int64, error)
+	// CreateItem 记录一行的导入结果
+	CreateItem(ctx context.Context, item *model.MaterialImportItem) error
+	// ClaimPending 领取一个待执行的导入任务并标记为执行中，没有任务时返回 nil
+	ClaimPending(ctx context.Context) (*model.MaterialImport, error)
+	// ResetStale 将开始时间早于 before 的执行中任务重置为待执行（进程异常退出遗留的任务）
+	ResetStale(ctx context.Context, before time.Time) (int64, error)
+}
+
+// materialImportRepository 资料批量导入数据访问层实现
+type materialImportRepository struct {
+	db *gorm.DB
+}
+
+// NewMaterialImportRepository 创建资料批量导入数据访问层实例
+func NewMaterialImportRepository(db *gorm.DB) MaterialImportRepository {
+	return &materialImportRepository{db: db}
+}
+
+// Create 创建导入任务
+func (r *materialImportRepository) Create(ctx context.Context, materialImport *model.MaterialImport) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(materialImport).Error
+}
+
+// FindByID 根据ID查找导入任务
+func (r *materialImportRepository) FindByID(ctx context.Context, id uint) (*model.MaterialImport, error) {
+	var materialImport model.MaterialImport
+	result := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("row_number ASC")
+		}).
+		First(&materialImport, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrMaterialImportNotFound
+		}
+		return nil, result.Error
+	}
+	return &materialImport, nil
+}
+
+// Update 更新导入任务
+func (r *materialImportRepository) Update(ctx context.Context, materialImport *model.MaterialImport) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(materialImport).Error
+}
+
+// ListByUploader 分页获取用户的导入任务
+func (r *materialImportRepository) ListByUploader(ctx context.Context, uploaderID uint, page, pageSize int) ([]*model.MaterialImport, int64, error) {
+	var imports []*model.MaterialImport
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.MaterialImport{}).Where("uploader_id = ?", uploaderID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&imports).Error; err != nil {
+		return nil, 0, err
+	}
+	return imports, total, nil
+}
+
+// CreateItem 记录一行的导入结果
+func (r *materialImportRepository) CreateItem(ctx context.Context, item *model.MaterialImportItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// ClaimPending 领取一个待执行的导入任务
+// 使用 FOR UPDATE SKIP LOCKED，多个实例同时运行时不会重复领取同一任务
+func (r *materialImportRepository) ClaimPending(ctx context.Context) (*model.MaterialImport, error) {
+	var claimed *model.MaterialImport
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var materialImport model.MaterialImport
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", model.MaterialImportPending).
+			Order("created_at ASC").
+			First(&materialImport).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		now := time.Now()
+		materialImport.Status = model.MaterialImportRunning
+		materialImport.StartedAt = &now
+		if err := tx.Model(&materialImport).Updates(map[string]interface{}{
+			"status":     model.MaterialImportRunning,
+			"started_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		claimed = &materialImport
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// ResetStale 将长时间处于执行中的任务重置为待执行
+func (r *materialImportRepository) ResetStale(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.MaterialImport{}).
+		Where("status = ? AND started_at < ?", model.MaterialImportRunning, before).
+		Update("status", model.MaterialImportPending)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrRatingNotFound 评分不存在错误
+	ErrRatingNotFound = errors.New("评分不存在")
+)
+
+// MaterialRatingRepository 资料评分数据访问层接口
+type MaterialRatingRepository interface {
+	// FindByUserAndMaterial 查找用户对资料的评分
+	FindByUserAndMaterial(ctx context.Context, userID, materialID uint) (*model.MaterialRating, error)
+	// Upsert 创建或更新用户对资料的评分，并刷新资料的平均分
+	Upsert(ctx context.Context, rating *model.MaterialRating) error
+	// Delete 删除用户对资料的评分，并刷新资料的平均分
+	Delete(ctx context.Context, userID, materialID uint) error
+}
+
+// materialRatingRepository 资料评分数据访问层实现
+type materialRatingRepository struct {
+	db *gorm.DB
+}
+
+// NewMaterialRatingRepository 创建资料评分数据访问层实例
+func NewMaterialRatingRepository(db *gorm.DB) MaterialRatingRepository {
+	return &materialRatingRepository{db: db}
+}
+
+// FindByUserAndMaterial 查找用户对资料的评分
+func (r *materialRatingRepository) FindByUserAndMaterial(ctx context.Context, userID, materialID uint) (*model.MaterialRating, error) {
+	var rating model.MaterialRating
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND material_id = ?", userID, materialID).
+		First(&rating)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRatingNotFound
+		}
+		return nil, result.Error
+	}
+	return &rating, nil
+}
+
+// Upsert 创建或更新评分
+func (r *materialRatingRepository) Upsert(ctx context.Context, rating *model.MaterialRating) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "material_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"score", "updated_at"}),
+		}).Create(rating).Error; err != nil {
+			return err
+		}
+		return refreshRatingStats(tx, rating.MaterialID)
+	})
+}
+
+// Delete 删除评分
+func (r *materialRatingRepository) Delete(ctx context.Context, userID, materialID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND material_id = ?", userID, materialID).Delete(&model.MaterialRating{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRatingNotFound
+		}
+		return refreshRatingStats(tx, materialID)
+	})
+}
+
+// refreshRatingStats 根据评分表重新计算资料的平均分和评分人数
+// 每次全量聚合而非增量加减，避免并发修改同一资料时统计漂移
+func refreshRatingStats(tx *gorm.DB, materialID uint) error {
+	return tx.Exec(`
+		UPDATE materials SET
+			rating_average = COALESCE((SELECT ROUND(AVG(score), 2) FROM material_ratings WHERE material_id = ?), 0),
+			rating_count = (SELECT COUNT(*) FROM material_ratings WHERE material_id = ?)
+		WHERE id = ?
+	`, materialID, materialID, materialID).Error
+}
@@ -3,9 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/searchquery"
+	"github.com/study-upc/backend/internal/pkg/segmenter"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -29,14 +33,30 @@ var (
 type MaterialListOptions struct {
 	Category      *model.MaterialCategoryType
 	CourseName    string
+	CourseID      *uint  // 课程ID筛选，优先于 CourseName
+	Tags          []string // 标签筛选，命中任一标签即可
 	Status        *model.MaterialStatus
 	Statuses      []model.MaterialStatus // 支持多个状态查询(用于管理员查询"已审核"资料)
 	Keyword       string
-	SortBy        string // created_at, download_count, favorite_count, view_count, title
+	SortBy        string // created_at, download_count, favorite_count, view_count, title, rating_average
 	SortOrder     string // asc, desc
 	UploaderID    *uint  // 上传者ID筛选,用于"我的资料"查询
 }
 
+// MaterialPreview 资料预览信息
+type MaterialPreview struct {
+	ThumbnailKey string
+	Excerpt      string
+	Status       model.PreviewStatus
+}
+
+// MaterialSearchHit 搜索结果的相关度和全文片段
+type MaterialSearchHit struct {
+	ID             uint
+	Rank           float64 // 相关度，已归一化到 0-1
+	ContentExcerpt string  // 全文中第一个命中位置附近的片段
+}
+
 // MaterialRepository 资料数据访问层接口
 type MaterialRepository interface {
 	// Create 创建资料
@@ -65,8 +85,29 @@ type MaterialRepository interface {
 	UpdateReviewStatus(ctx context.Context, id uint, status model.MaterialStatus, reviewerID *uint, rejectionReason string) error
 	// FindByFileKey 根据文件存储键查找资料
 	FindByFileKey(ctx context.Context, fileKey string) (*model.Material, error)
+	// ExistsByFileKey 检查文件存储键是否已被资料使用（包括已删除的资料）
+	ExistsByFileKey(ctx context.Context, fileKey string) (bool, error)
+	// FindApprovedByContentHashes 按文件内容哈希查找已通过的资料，每个哈希返回 ID 最小的一条
+	FindApprovedByContentHashes(ctx context.Context, hashes []string) (map[string]*model.Material, error)
+	// UpdatePreview 更新资料预览信息，仅当资料的当前文件仍为 fileKey 时生效，返回是否已更新
+	UpdatePreview(ctx context.Context, id uint, fileKey string, preview *MaterialPreview) (bool, error)
+	// SumFileSizeByUploader 统计用户上传的资料占用的存储空间（字节），包括资料当前文件和用户上传的非当前版本，不含已删除的资料
+	SumFileSizeByUploader(ctx context.Context, uploaderID uint) (int64, error)
+	// UpdateContentText 更新从文件中提取的全文并重新计算搜索向量，仅当资料的当前文件仍为 fileKey 时生效，返回是否已更新
+	UpdateContentText(ctx context.Context, id uint, fileKey string, text string) (bool, error)
+	// FindMatchedFields 返回每个资料中命中检索词的字段（title、description、course_name、content）
+	// tsQuery 为 to_tsquery 格式的全文查询，terms 为用于模糊匹配的检索词原文
+	FindMatchedFields(ctx context.Context, ids []uint, tsQuery string, terms []string) (map[uint][]string, error)
+	// FindSearchHits 计算每个资料的相关度，并截取全文中第一个命中关键词附近长度为 excerptLength 的片段
+	FindSearchHits(ctx context.Context, ids []uint, tsQuery string, keywords []string, excerptLength int) (map[uint]*MaterialSearchHit, error)
 	// SearchByKeyword 全文搜索
 	SearchByKeyword(ctx context.Context, keyword string, page, pageSize int) ([]*model.Material, int64, error)
+	// RefreshSearchVector 根据标题、描述、课程名称和全文重新计算搜索向量
+	RefreshSearchVector(ctx context.Context, id uint) error
+	// ListApprovedTitles 获取已审核通过资料的标题，按下载次数从高到低排列
+	ListApprovedTitles(ctx context.Context, limit int) ([]string, error)
+	// ListIDsForReindex 按ID升序获取 afterID 之后的资料ID（不含已删除），missingOnly 为 true 时只返回尚无搜索向量的资料
+	ListIDsForReindex(ctx context.Context, afterID uint, limit int, missingOnly bool) ([]uint, error)
 }
 
 // FavoriteRepository 收藏数据访问层接口
@@ -115,6 +156,8 @@ type ReportRepository interface {
 	ListByMaterial(ctx context.Context, materialID uint) ([]*model.Report, error)
 	// FindPendingByUserAndMaterial 查找用户对指定资料的待处理举报
 	FindPendingByUserAndMaterial(ctx context.Context, userID, materialID uint) (*model.Report, error)
+	// FindPendingByUserAndComment 查找用户对指定评论的待处理举报
+	FindPendingByUserAndComment(ctx context.Context, userID, commentID uint) (*model.Report, error)
 	// CountPending 统计待处理举报数
 	CountPending(ctx context.Context) (int64, error)
 }
@@ -131,7 +174,6 @@ func NewMaterialRepository(db *gorm.DB) MaterialRepository {
 
 // Create 创建资料
 func (r *materialRepository) Create(ctx context.Context, material *model.Material) error {
-	searchText := material.SearchVector
 	result := r.db.WithContext(ctx).Omit("search_vector").Create(material)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
@@ -139,10 +181,7 @@ func (r *materialRepository) Create(ctx context.Context, material *model.Materia
 		}
 		return result.Error
 	}
-	if err := r.updateSearchVector(ctx, material.ID, searchText); err != nil {
-		return err
-	}
-	return nil
+	return r.RefreshSearchVector(ctx, material.ID)
 }
 
 // FindByID 根据ID查找资料
@@ -161,7 +200,7 @@ func (r *materialRepository) FindByID(ctx context.Context, id uint) (*model.Mate
 // FindByIDWithUploader 根据ID查找资料（包含上传者信息）
 func (r *materialRepository) FindByIDWithUploader(ctx context.Context, id uint) (*model.Material, error) {
 	var material model.Material
-	result := r.db.WithContext(ctx).Preload("Uploader").Preload("Reviewer").First(&material, id)
+	result := r.db.WithContext(ctx).Preload("Uploader").Preload("Reviewer").Preload("Tags").First(&material, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrMaterialNotFound
@@ -185,13 +224,13 @@ func (r *materialRepository) FindByIDForUpdate(ctx context.Context, id uint) (*m
 }
 
 // Update 更新资料
+// 预览字段和全文由后台处理任务通过 UpdatePreview、UpdateContentText 单独维护，这里不覆盖
 func (r *materialRepository) Update(ctx context.Context, material *model.Material) error {
-	searchText := material.SearchVector
-	result := r.db.WithContext(ctx).Omit("search_vector").Save(material)
+	result := r.db.WithContext(ctx).Omit("search_vector", "thumbnail_key", "preview_excerpt", "preview_status", "content_text", "Tags").Save(material)
 	if result.Error != nil {
 		return result.Error
 	}
-	return r.updateSearchVector(ctx, material.ID, searchText)
+	return r.RefreshSearchVector(ctx, material.ID)
 }
 
 // Delete 删除资料（软删除）
@@ -206,11 +245,66 @@ func (r *materialRepository) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
-func (r *materialRepository) updateSearchVector(ctx context.Context, id uint, text string) error {
+// RefreshSearchVector 重新计算搜索向量
+// 中文需先经分词器切分，数据库的 to_tsvector 无法完成，因此由应用在资料写入后计算。
+// 权重与原触发器一致：A 标题、B 描述、C 课程名称、D 全文
+func (r *materialRepository) RefreshSearchVector(ctx context.Context, id uint) error {
+	var row struct {
+		Title       string
+		Description string
+		CourseName  string
+		ContentText *string
+	}
+	result := r.db.WithContext(ctx).Model(&model.Material{}).
+		Select("title", "description", "course_name", "content_text").
+		Where("id = ?", id).
+		Take(&row)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return ErrMaterialNotFound
+		}
+		return result.Error
+	}
+
+	content := ""
+	if row.ContentText != nil {
+		content = *row.ContentText
+	}
+	seg := segmenter.Default()
 	return r.db.WithContext(ctx).Model(&model.Material{}).
 		Where("id = ?", id).
-		Update("search_vector", gorm.Expr("to_tsvector('simple', ?)", text)).
-		Error
+		UpdateColumn("search_vector", gorm.Expr(
+			`setweight(to_tsvector('simple', ?), 'A') ||
+			setweight(to_tsvector('simple', ?), 'B') ||
+			setweight(to_tsvector('simple', ?), 'C') ||
+			setweight(to_tsvector('simple', ?), 'D')`,
+			seg.IndexText(row.Title),
+			seg.IndexText(row.Description),
+			seg.IndexText(row.CourseName),
+			seg.IndexText(content),
+		)).Error
+}
+
+// ListIDsForReindex 按ID升序分批获取需要重建搜索向量的资料ID
+func (r *materialRepository) ListIDsForReindex(ctx context.Context, afterID uint, limit int, missingOnly bool) ([]uint, error) {
+	query := r.db.WithContext(ctx).Model(&model.Material{}).Where("id > ?", afterID)
+	if missingOnly {
+		query = query.Where("search_vector IS NULL")
+	}
+	var ids []uint
+	err := query.Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ListApprovedTitles 获取已审核通过资料的标题
+func (r *materialRepository) ListApprovedTitles(ctx context.Context, limit int) ([]string, error) {
+	var titles []string
+	err := r.db.WithContext(ctx).Model(&model.Material{}).
+		Where("status = ?", model.StatusApproved).
+		Order("download_count DESC, id DESC").
+		Limit(limit).
+		Pluck("title", &titles).Error
+	return titles, err
 }
 
 // List 分页获取资料列表
@@ -225,7 +319,9 @@ func (r *materialRepository) List(ctx context.Context, page, pageSize int, opts
 		if opts.Category != nil {
 			query = query.Where("category = ?", *opts.Category)
 		}
-		if opts.CourseName != "" {
+		if opts.CourseID != nil {
+			query = query.Where("course_id = ?", *opts.CourseID)
+		} else if opts.CourseName != "" {
 			query = query.Where("course_name LIKE ?", "%"+opts.CourseName+"%")
 		}
 		// 支持单状态和多状态查询
@@ -235,12 +331,18 @@ func (r *materialRepository) List(ctx context.Context, page, pageSize int, opts
 			query = query.Where("status IN ?", opts.Statuses)
 		}
 		if opts.Keyword != "" {
-			// 模糊全文搜索 - 使用 plainto_tsquery 支持分词和模糊匹配
-			query = query.Where("search_vector @@ plainto_tsquery('simple', ?)", opts.Keyword)
+			// 全文搜索 - 关键词先经分词器切分，再由 plainto_tsquery 组合为各词同时命中
+			query = query.Where("search_vector @@ plainto_tsquery('simple', ?)", segmenter.Default().QueryText(opts.Keyword))
 		}
 		if opts.UploaderID != nil {
 			query = query.Where("uploader_id = ?", *opts.UploaderID)
 		}
+		if len(opts.Tags) > 0 {
+			query = query.Where("id IN (?)", r.db.Table("material_tags").
+				Select("material_tags.material_id").
+				Joins("JOIN tags ON tags.id = material_tags.tag_id").
+				Where("tags.name IN ?", opts.Tags))
+		}
 	}
 
 	// 获取总数
@@ -260,7 +362,7 @@ func (r *materialRepository) List(ctx context.Context, page, pageSize int, opts
 
 	// 获取分页数据
 	offset := (page - 1) * pageSize
-	result := query.Order(orderBy).Offset(offset).Limit(pageSize).Preload("Uploader").Preload("Reviewer").Find(&materials)
+	result := query.Order(orderBy).Offset(offset).Limit(pageSize).Preload("Uploader").Preload("Reviewer").Preload("Tags").Find(&materials)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
@@ -305,8 +407,16 @@ func (r *materialRepository) UpdateReviewStatus(ctx context.Context, id uint, st
 		updates["rejection_reason"] = rejectionReason
 	}
 
-	result := r.db.WithContext(ctx).Model(&model.Material{}).Where("id = ?", id).Updates(updates)
-	return result.Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Material{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// 同步当前版本的审核状态
+		return tx.Model(&model.MaterialVersion{}).
+			Where("id = (SELECT current_version_id FROM materials WHERE id = ?)", id).
+			Updates(updates).Error
+	})
 }
 
 // FindByFileKey 根据文件存储键查找资料
@@ -322,6 +432,202 @@ func (r *materialRepository) FindByFileKey(ctx context.Context, fileKey string)
 	return &material, nil
 }
 
+// ExistsByFileKey 检查文件存储键是否已被资料使用（包括已删除的资料）
+func (r *materialRepository) ExistsByFileKey(ctx context.Context, fileKey string) (bool, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Unscoped().Model(&model.Material{}).Where("file_key = ?", fileKey).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// FindApprovedByContentHashes 按文件内容哈希查找已通过的资料，每个哈希返回 ID 最小的一条
+func (r *materialRepository) FindApprovedByContentHashes(ctx context.Context, hashes []string) (map[string]*model.Material, error) {
+	result := make(map[string]*model.Material)
+	if len(hashes) == 0 {
+		return result, nil
+	}
+
+	var materials []*model.Material
+	err := r.db.WithContext(ctx).
+		Where("content_hash IN ? AND status = ?", hashes, model.StatusApproved).
+		Order("id ASC").
+		Find(&materials).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, material := range materials {
+		if _, ok := result[material.ContentHash]; !ok {
+			result[material.ContentHash] = material
+		}
+	}
+	return result, nil
+}
+
+// UpdatePreview 更新资料预览信息
+// 资料更换版本后，旧文件的处理结果不会覆盖新版本的预览
+func (r *materialRepository) UpdatePreview(ctx context.Context, id uint, fileKey string, preview *MaterialPreview) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Material{}).
+		Where("id = ? AND file_key = ?", id, fileKey).
+		UpdateColumns(map[string]interface{}{
+			"thumbnail_key":   preview.ThumbnailKey,
+			"preview_excerpt": preview.Excerpt,
+			"preview_status":  preview.Status,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SumFileSizeByUploader 统计用户上传的资料占用的存储空间
+// 当前版本的文件即资料的文件，已计入资料大小；其余版本（历史、待审核、已拒绝）的文件仍占用存储，按版本上传者计入
+func (r *materialRepository) SumFileSizeByUploader(ctx context.Context, uploaderID uint) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			COALESCE((
+				SELECT SUM(file_size) FROM materials
+				WHERE uploader_id = @uploader AND deleted_at IS NULL
+			), 0) +
+			COALESCE((
+				SELECT SUM(v.file_size) FROM material_versions v
+				JOIN materials m ON m.id = v.material_id AND m.deleted_at IS NULL
+				WHERE v.uploader_id = @uploader AND v.deleted_at IS NULL
+					AND v.id <> COALESCE(m.current_version_id, 0)
+			), 0)
+	`, map[string]interface{}{"uploader": uploaderID}).
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// UpdateContentText 更新从文件中提取的全文，并通过 RefreshSearchVector 重新计算搜索向量
+func (r *materialRepository) UpdateContentText(ctx context.Context, id uint, fileKey string, text string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Material{}).
+		Where("id = ? AND file_key = ?", id, fileKey).
+		Update("content_text", text)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, r.RefreshSearchVector(ctx, id)
+}
+
+// FindMatchedFields 返回每个资料中命中检索词的字段
+// 使用 ts_filter 按权重拆分搜索向量（A 标题、B 描述、C 课程名称、D 全文），同时兼容模糊匹配
+func (r *materialRepository) FindMatchedFields(ctx context.Context, ids []uint, tsQuery string, terms []string) (map[uint][]string, error) {
+	matched := make(map[uint][]string)
+	if len(ids) == 0 || (tsQuery == "" && len(terms) == 0) {
+		return matched, nil
+	}
+
+	args := map[string]interface{}{"query": tsQuery, "ids": ids}
+	for i, term := range terms {
+		args[fmt.Sprintf("pattern%d", i)] = "%" + searchquery.EscapeLike(term) + "%"
+	}
+	// match 构建单个字段的命中条件，字段名和权重均为常量
+	match := func(weight, column string) string {
+		conds := make([]string, 0, len(terms)+1)
+		if tsQuery != "" {
+			conds = append(conds, fmt.Sprintf("ts_filter(search_vector, '{%s}') @@ to_tsquery('simple', @query)", weight))
+		}
+		for i := range terms {
+			conds = append(conds, fmt.Sprintf("%s ILIKE @pattern%d", column, i))
+		}
+		return "COALESCE(" + strings.Join(conds, " OR ") + ", false)"
+	}
+
+	var rows []struct {
+		ID          uint
+		Title       bool
+		Description bool
+		CourseName  bool
+		Content     bool
+	}
+	err := r.db.WithContext(ctx).Raw(
+		`SELECT id, `+
+			match("a", "title")+` AS title, `+
+			match("b", "description")+` AS description, `+
+			match("c", "course_name")+` AS course_name, `+
+			match("d", "content_text")+` AS content
+		FROM materials
+		WHERE id IN @ids`,
+		args,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		fields := make([]string, 0, 4)
+		if row.Title {
+			fields = append(fields, model.MatchedFieldTitle)
+		}
+		if row.Description {
+			fields = append(fields, model.MatchedFieldDescription)
+		}
+		if row.CourseName {
+			fields = append(fields, model.MatchedFieldCourseName)
+		}
+		if row.Content {
+			fields = append(fields, model.MatchedFieldContent)
+		}
+		matched[row.ID] = fields
+	}
+	return matched, nil
+}
+
+// FindSearchHits 计算每个资料的相关度，并截取全文中第一个命中关键词附近的片段
+// 全文可能很长，只在数据库中截取片段，高亮在服务层完成
+func (r *materialRepository) FindSearchHits(ctx context.Context, ids []uint, tsQuery string, keywords []string, excerptLength int) (map[uint]*MaterialSearchHit, error) {
+	hits := make(map[uint]*MaterialSearchHit)
+	if len(ids) == 0 {
+		return hits, nil
+	}
+
+	args := map[string]interface{}{"query": tsQuery, "ids": ids, "before": excerptLength / 3, "length": excerptLength}
+	rank := "0"
+	if tsQuery != "" {
+		// 标志 32 将相关度归一化为 rank/(rank+1)
+		rank = "COALESCE(ts_rank(search_vector, to_tsquery('simple', @query), 32), 0)"
+	}
+	// 片段从最早命中的关键词之前开始，LEAST 会忽略未命中（NULL）的关键词
+	start := "1"
+	if len(keywords) > 0 {
+		positions := make([]string, 0, len(keywords))
+		for i, keyword := range keywords {
+			name := fmt.Sprintf("keyword%d", i)
+			args[name] = strings.ToLower(keyword)
+			positions = append(positions, fmt.Sprintf("NULLIF(strpos(lower(content_text), @%s), 0)", name))
+		}
+		start = "GREATEST(COALESCE(LEAST(" + strings.Join(positions, ", ") + "), 1) - @before, 1)"
+	}
+
+	var rows []*MaterialSearchHit
+	err := r.db.WithContext(ctx).Raw(
+		`SELECT id, `+rank+` AS rank,
+			COALESCE(substring(content_text from `+start+` for @length), '') AS content_excerpt
+		FROM materials
+		WHERE id IN @ids`,
+		args,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		hits[row.ID] = row
+	}
+	return hits, nil
+}
+
 // SearchByKeyword 全文搜索（支持模糊匹配，只搜索已审核通过的资料）
 func (r *materialRepository) SearchByKeyword(ctx context.Context, keyword string, page, pageSize int) ([]*model.Material, int64, error) {
 	var materials []*model.Material
@@ -330,7 +636,7 @@ func (r *materialRepository) SearchByKeyword(ctx context.Context, keyword string
 	// 只搜索已审核通过的资料
 	query := r.db.WithContext(ctx).Model(&model.Material{}).Where(
 		"search_vector @@ plainto_tsquery('simple', ?) AND status = ?",
-		keyword,
+		segmenter.Default().QueryText(keyword),
 		model.StatusApproved,
 	)
 
@@ -341,7 +647,7 @@ func (r *materialRepository) SearchByKeyword(ctx context.Context, keyword string
 
 	// 获取分页数据
 	offset := (page - 1) * pageSize
-	result := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Preload("Uploader").Preload("Reviewer").Find(&materials)
+	result := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Preload("Uploader").Preload("Reviewer").Preload("Tags").Find(&materials)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
@@ -574,6 +880,10 @@ func (r *reportRepository) FindByID(ctx context.Context, id uint) (*model.Report
 			return db.Unscoped() // 包含已删除的资料
 		}).
 		Preload("Material.Uploader").
+		Preload("Comment", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped() // 包含已删除的评论
+		}).
+		Preload("Comment.User").
 		Preload("Handler").
 		First(&report, id)
 	if result.Error != nil {
@@ -617,6 +927,10 @@ func (r *reportRepository) List(ctx context.Context, page, pageSize int, status
 			return db.Unscoped() // 包含已删除的资料
 		}).
 		Preload("Material.Uploader").
+		Preload("Comment", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped() // 包含已删除的评论
+		}).
+		Preload("Comment.User").
 		Preload("Handler").
 		Find(&reports)
 	if result.Error != nil {
@@ -639,7 +953,7 @@ func (r *reportRepository) ListByMaterial(ctx context.Context, materialID uint)
 // FindPendingByUserAndMaterial 查找用户对指定资料的待处理举报
 func (r *reportRepository) FindPendingByUserAndMaterial(ctx context.Context, userID, materialID uint) (*model.Report, error) {
 	var report model.Report
-	result := r.db.WithContext(ctx).Where("user_id = ? AND material_id = ? AND status = ?", userID, materialID, model.ReportStatusPending).First(&report)
+	result := r.db.WithContext(ctx).Where("user_id = ? AND material_id = ? AND comment_id IS NULL AND status = ?", userID, materialID, model.ReportStatusPending).First(&report)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, gorm.ErrRecordNotFound
@@ -649,6 +963,16 @@ func (r *reportRepository) FindPendingByUserAndMaterial(ctx context.Context, use
 	return &report, nil
 }
 
+// FindPendingByUserAndComment 查找用户对指定评论的待处理举报
+func (r *reportRepository) FindPendingByUserAndComment(ctx context.Context, userID, commentID uint) (*model.Report, error) {
+	var report model.Report
+	result := r.db.WithContext(ctx).Where("user_id = ? AND comment_id = ? AND status = ?", userID, commentID, model.ReportStatusPending).First(&report)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &report, nil
+}
+
 // CountPending 统计待处理举报数
 func (r *reportRepository) CountPending(ctx context.Context) (int64, error) {
 	var count int64
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrMaterialVersionNotFound 资料版本不存在错误
+	ErrMaterialVersionNotFound = errors.New("资料版本不存在")
+)
+
+// MaterialVersionRepository 资料版本数据访问层接口
+type MaterialVersionRepository interface {
+	// Create 创建版本（自动分配版本号）
+	Create(ctx context.Context, version *model.MaterialVersion) error
+	// FindByID 根据ID查找版本
+	FindByID(ctx context.Context, id uint) (*model.MaterialVersion, error)
+	// FindByMaterialAndID 查找指定资料下的版本
+	FindByMaterialAndID(ctx context.Context, materialID, versionID uint) (*model.MaterialVersion, error)
+	// ExistsByFileKey 检查文件存储键是否已被任一版本使用（包括已删除的版本）
+	ExistsByFileKey(ctx context.Context, fileKey string) (bool, error)
+	// ListByMaterial 获取资料的版本列表（按版本号倒序）
+	ListByMaterial(ctx context.Context, materialID uint, statuses []model.MaterialStatus) ([]*model.MaterialVersion, error)
+	// Update 更新版本
+	Update(ctx context.Context, version *model.MaterialVersion) error
+	// UpdateReviewStatus 更新版本审核状态
+	UpdateReviewStatus(ctx context.Context, id uint, status model.MaterialStatus, reviewerID *uint, rejectionReason string) error
+	// ListPending 分页获取待审核的版本列表
+	ListPending(ctx context.Context, page, pageSize int) ([]*model.MaterialVersion, int64, error)
+	// CountPendingByMaterial 统计资料待审核的版本数
+	CountPendingByMaterial(ctx context.Context, materialID uint) (int64, error)
+	// DeleteByMaterial 删除资料的全部版本（软删除）
+	DeleteByMaterial(ctx context.Context, materialID uint) error
+}
+
+// materialVersionRepository 资料版本数据访问层实现
+type materialVersionRepository struct {
+	db *gorm.DB
+}
+
+// NewMaterialVersionRepository 创建资料版本数据访问层实例
+func NewMaterialVersionRepository(db *gorm.DB) MaterialVersionRepository {
+	return &materialVersionRepository{db: db}
+}
+
+// Create 创建版本（在事务中分配下一个版本号）
+// 分配版本号前锁定所属资料行，同一资料的并发上传依次分配版本号
+func (r *materialVersionRepository) Create(ctx context.Context, version *model.MaterialVersion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if version.VersionNumber == 0 {
+			var material model.Material
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id").
+				First(&material, version.MaterialID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrMaterialNotFound
+				}
+				return err
+			}
+
+			var maxVersion int
+			if err := tx.Unscoped().Model(&model.MaterialVersion{}).
+				Where("material_id = ?", version.MaterialID).
+				Select("COALESCE(MAX(version_number), 0)").
+				Scan(&maxVersion).Error; err != nil {
+				return err
+			}
+			version.VersionNumber = maxVersion + 1
+		}
+
+		if err := tx.Create(version).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrMaterialAlreadyExists
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// FindByID 根据ID查找版本
+func (r *materialVersionRepository) FindByID(ctx context.Context, id uint) (*model.MaterialVersion, error) {
+	var version model.MaterialVersion
+	result := r.db.WithContext(ctx).Preload("Uploader").First(&version, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrMaterialVersionNotFound
+		}
+		return nil, result.Error
+	}
+	return &version, nil
+}
+
+// FindByMaterialAndID 查找指定资料下的版本
+func (r *materialVersionRepository) FindByMaterialAndID(ctx context.Context, materialID, versionID uint) (*model.MaterialVersion, error) {
+	var version model.MaterialVersion
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND material_id = ?", versionID, materialID).
+		Preload("Uploader").
+		First(&version)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrMaterialVersionNotFound
+		}
+		return nil, result.Error
+	}
+	return &version, nil
+}
+
+// ExistsByFileKey 检查文件存储键是否已被任一版本使用（包括已删除的版本）
+func (r *materialVersionRepository) ExistsByFileKey(ctx context.Context, fileKey string) (bool, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Unscoped().Model(&model.MaterialVersion{}).
+		Where("file_key = ?", fileKey).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// ListByMaterial 获取资料的版本列表（按版本号倒序），statuses 为空时返回全部状态
+func (r *materialVersionRepository) ListByMaterial(ctx context.Context, materialID uint, statuses []model.MaterialStatus) ([]*model.MaterialVersion, error) {
+	var versions []*model.MaterialVersion
+	query := r.db.WithContext(ctx).Where("material_id = ?", materialID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	result := query.Order("version_number DESC").Preload("Uploader").Find(&versions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return versions, nil
+}
+
+// Update 更新版本
+func (r *materialVersionRepository) Update(ctx context.Context, version *model.MaterialVersion) error {
+	return r.db.WithContext(ctx).Omit("Uploader", "Reviewer").Save(version).Error
+}
+
+// UpdateReviewStatus 更新版本审核状态
+func (r *materialVersionRepository) UpdateReviewStatus(ctx context.Context, id uint, status model.MaterialStatus, reviewerID *uint, rejectionReason string) error {
+	updates := map[string]interface{}{
+		"status":           status,
+		"reviewed_at":      gorm.Expr("NOW()"),
+		"rejection_reason": rejectionReason,
+	}
+	if reviewerID != nil {
+		updates["reviewer_id"] = *reviewerID
+	}
+
+	return r.db.WithContext(ctx).Model(&model.MaterialVersion{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// ListPending 分页获取待审核的版本列表
+func (r *materialVersionRepository) ListPending(ctx context.Context, page, pageSize int) ([]*model.MaterialVersion, int64, error) {
+	var versions []*model.MaterialVersion
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.MaterialVersion{}).Where("status = ?", model.StatusPending)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Order("created_at ASC").Offset(offset).Limit(pageSize).Preload("Uploader").Find(&versions)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return versions, total, nil
+}
+
+// CountPendingByMaterial 统计资料待审核的版本数
+func (r *materialVersionRepository) CountPendingByMaterial(ctx context.Context, materialID uint) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&model.MaterialVersion{}).
+		Where("material_id = ? AND status = ?", materialID, model.StatusPending).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// DeleteByMaterial 删除资料的全部版本（软删除）
+func (r *materialVersionRepository) DeleteByMaterial(ctx context.Context, materialID uint) error {
+	return r.db.WithContext(ctx).Where("material_id = ?", materialID).Delete(&model.MaterialVersion{}).Error
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrProcessingJobNotFound 处理任务不存在错误
+	ErrProcessingJobNotFound = errors.New("处理任务不存在")
+)
+
+// ProcessingJobListOptions 处理任务列表查询选项
+type ProcessingJobListOptions struct {
+	Status     model.ProcessingJobStatus
+	JobType    model.ProcessingJobType
+	MaterialID uint
+}
+
+// ProcessingJobRepository 资料后台处理任务数据访问层接口
+type ProcessingJobRepository interface {
+	// Create 创建处理任务
+	Create(ctx context.Context, job *model.ProcessingJob) error
+	// FindByID 根据ID查找处理任务
+	FindByID(ctx context.Context, id uint) (*model.ProcessingJob, error)
+	// Update 更新处理任务
+	Update(ctx context.Context, job *model.ProcessingJob) error
+	// List 分页获取处理任务
+	List(ctx context.Context, page, pageSize int, opts *ProcessingJobListOptions) ([]*model.ProcessingJob, int64, error)
+	// FindPending 查找资料指定类型的待执行任务
+	FindPending(ctx context.Context, materialID uint, jobType model.ProcessingJobType) (*model.ProcessingJob, error)
+	// ClaimDue 领取已到执行时间的待执行任务，标记为执行中并增加执行次数
+	ClaimDue(ctx context.Context, limit int) ([]*model.ProcessingJob, error)
+	// ResetStale 将开始时间早于 before 的执行中任务重置为待执行（进程异常退出遗留的任务）
+	ResetStale(ctx context.Context, before time.Time) (int64, error)
+}
+
+// processingJobRepository 资料后台处理任务数据访问层实现
+type processingJobRepository struct {
+	db *gorm.DB
+}
+
+// NewProcessingJobRepository 创建资料后台处理任务数据访问层实例
+func NewProcessingJobRepository(db *gorm.DB) ProcessingJobRepository {
+	return &processingJobRepository{db: db}
+}
+
+// Create 创建处理任务
+func (r *processingJobRepository) Create(ctx context.Context, job *model.ProcessingJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// FindByID 根据ID查找处理任务
+func (r *processingJobRepository) FindByID(ctx context.Context, id uint) (*model.ProcessingJob, error) {
+	var job model.ProcessingJob
+	result := r.db.WithContext(ctx).First(&job, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrProcessingJobNotFound
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+// Update 更新处理任务
+func (r *processingJobRepository) Update(ctx context.Context, job *model.ProcessingJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// List 分页获取处理任务
+func (r *processingJobRepository) List(ctx context.Context, page, pageSize int, opts *ProcessingJobListOptions) ([]*model.ProcessingJob, int64, error) {
+	var jobs []*model.ProcessingJob
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.ProcessingJob{})
+	if opts != nil {
+		if opts.Status != "" {
+			query = query.Where("status = ?", opts.Status)
+		}
+		if opts.JobType != "" {
+			query = query.Where("job_type = ?", opts.JobType)
+		}
+		if opts.MaterialID > 0 {
+			query = query.Where("material_id = ?", opts.MaterialID)
+		}
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// FindPending 查找资料指定类型的待执行任务
+func (r *processingJobRepository) FindPending(ctx context.Context, materialID uint, jobType model.ProcessingJobType) (*model.ProcessingJob, error) {
+	var job model.ProcessingJob
+	result := r.db.WithContext(ctx).
+		Where("material_id = ? AND job_type = ? AND status = ?", materialID, jobType, model.ProcessingJobPending).
+		Order("id DESC").
+		First(&job)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrProcessingJobNotFound
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+// ClaimDue 领取已到执行时间的待执行任务
+// 使用 FOR UPDATE SKIP LOCKED，多个实例同时运行时不会重复领取同一任务
+func (r *processingJobRepository) ClaimDue(ctx context.Context, limit int) ([]*model.ProcessingJob, error) {
+	var jobs []*model.ProcessingJob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_after <= ?", model.ProcessingJobPending, time.Now()).
+			Order("run_after ASC").
+			Limit(limit).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		now := time.Now()
+		ids := make([]uint, 0, len(jobs))
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+			job.Status = model.ProcessingJobRunning
+			job.Attempts++
+			job.StartedAt = &now
+		}
+		return tx.Model(&model.ProcessingJob{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":     model.ProcessingJobRunning,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"started_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ResetStale 将长时间处于执行中的任务重置为待执行
+func (r *processingJobRepository) ResetStale(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.ProcessingJob{}).
+		Where("status = ? AND started_at < ?", model.ProcessingJobRunning, before).
+		Updates(map[string]interface{}{
+			"status":    model.ProcessingJobPending,
+			"run_after": time.Now(),
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
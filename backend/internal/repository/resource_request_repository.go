@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrResourceRequestNotFound 求资料不存在错误
+	ErrResourceRequestNotFound = errors.New("求资料不存在")
+	// ErrResourceRequestStatusChanged 求资料状态已变更错误
+	ErrResourceRequestStatusChanged = errors.New("求资料状态已变更")
+	// ErrAlreadyVoted 已投票错误
+	ErrAlreadyVoted = errors.New("已投票")
+	// ErrVoteNotFound 未投票错误
+	ErrVoteNotFound = errors.New("未投票")
+)
+
+// ResourceRequestListOptions 求资料列表查询选项
+type ResourceRequestListOptions struct {
+	Statuses   []model.ResourceRequestStatus
+	Category   *model.MaterialCategoryType
+	CourseID   *uint
+	CourseName string
+	Keyword    string
+	UserID     *uint
+	ClaimedBy  *uint
+	SortBy     string // vote_count, created_at
+	Page       int
+	PageSize   int
+}
+
+// ResourceRequestRepository 求资料数据访问层接口
+type ResourceRequestRepository interface {
+	// Create 创建求资料
+	Create(ctx context.Context, request *model.ResourceRequest) error
+	// FindByID 根据ID查找求资料，预加载发布者、认领人和关联资料
+	FindByID(ctx context.Context, id uint) (*model.ResourceRequest, error)
+	// Update 更新求资料内容
+	Update(ctx context.Context, request *model.ResourceRequest) error
+	// List 分页获取求资料列表
+	List(ctx context.Context, opts *ResourceRequestListOptions) ([]*model.ResourceRequest, int64, error)
+	// SearchActive 搜索全部检索词都命中的等待资料的求资料，courseID 不为空时同时匹配关联到该课程的求资料，按票数排序
+	SearchActive(ctx context.Context, terms []string, courseID *uint, limit int) ([]*model.ResourceRequest, error)
+
+	// Claim 认领求资料，仅等待认领的求资料可被认领
+	Claim(ctx context.Context, id, userID uint) error
+	// Release 取消认领，求资料回到等待认领状态
+	Release(ctx context.Context, id uint) error
+	// Fulfill 关联资料并完成求资料
+	Fulfill(ctx context.Context, id, materialID uint) error
+	// Close 关闭求资料
+	Close(ctx context.Context, id uint, reason string) error
+
+	// AddVote 投票，同时增加票数
+	AddVote(ctx context.Context, requestID, userID uint) error
+	// RemoveVote 取消投票，同时减少票数
+	RemoveVote(ctx context.Context, requestID, userID uint) error
+	// ListVotedIDs 获取用户在指定求资料中已投票的ID集合
+	ListVotedIDs(ctx context.Context, userID uint, requestIDs []uint) (map[uint]bool, error)
+	// ListVoterIDs 获取求资料的全部投票用户ID
+	ListVoterIDs(ctx context.Context, requestID uint) ([]uint, error)
+}
+
+// resourceRequestRepository 求资料数据访问层实现
+type resourceRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewResourceRequestRepository 创建求资料数据访问层实例
+func NewResourceRequestRepository(db *gorm.DB) ResourceRequestRepository {
+	return &resourceRequestRepository{db: db}
+}
+
+// Create 创建求资料
+func (r *resourceRequestRepository) Create(ctx context.Context, request *model.ResourceRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+// FindByID 根据ID查找求资料
+func (r *resourceRequestRepository) FindByID(ctx context.Context, id uint) (*model.ResourceRequest, error) {
+	var request model.ResourceRequest
+	result := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Claimer").
+		Preload("FulfilledMaterial").
+		First(&request, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceRequestNotFound
+		}
+		return nil, result.Error
+	}
+	return &request, nil
+}
+
+// Update 更新求资料内容
+func (r *resourceRequestRepository) Update(ctx context.Context, request *model.ResourceRequest) error {
+	return r.db.WithContext(ctx).Model(request).
+		Select("title", "description", "category", "course_name", "course_id").
+		Updates(request).Error
+}
+
+// List 分页获取求资料列表
+func (r *resourceRequestRepository) List(ctx context.Context, opts *ResourceRequestListOptions) ([]*model.ResourceRequest, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.ResourceRequest{})
+
+	if len(opts.Statuses) > 0 {
+		query = query.Where("status IN ?", opts.Statuses)
+	}
+	if opts.Category != nil {
+		query = query.Where("category = ?", *opts.Category)
+	}
+	if opts.CourseID != nil {
+		query = query.Where("course_id = ?", *opts.CourseID)
+	} else if opts.CourseName != "" {
+		query = query.Where("course_name ILIKE ?", fmt.Sprintf("%%%s%%", opts.CourseName))
+	}
+	if opts.Keyword != "" {
+		pattern := fmt.Sprintf("%%%s%%", opts.Keyword)
+		query = query.Where("title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?", pattern, pattern, pattern)
+	}
+	if opts.UserID != nil {
+		query = query.Where("user_id = ?", *opts.UserID)
+	}
+	if opts.ClaimedBy != nil {
+		query = query.Where("claimed_by = ?", *opts.ClaimedBy)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	switch opts.SortBy {
+	case "created_at":
+		query = query.Order("created_at DESC")
+	default:
+		query = query.Order("vote_count DESC, created_at DESC")
+	}
+
+	var requests []*model.ResourceRequest
+	offset := (opts.Page - 1) * opts.PageSize
+	result := query.
+		Preload("User").
+		Preload("Claimer").
+		Preload("FulfilledMaterial").
+		Offset(offset).
+		Limit(opts.PageSize).
+		Find(&requests)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return requests, total, nil
+}
+
+// SearchActive 按检索词搜索等待资料的求资料
+func (r *resourceRequestRepository) SearchActive(ctx context.Context, terms []string, courseID *uint, limit int) ([]*model.ResourceRequest, error) {
+	conds := make([]string, 0, len(terms))
+	args := make([]interface{}, 0, len(terms)*3+1)
+	for _, term := range terms {
+		pattern := fmt.Sprintf("%%%s%%", term)
+		conds = append(conds, "(title ILIKE ? OR description ILIKE ? OR course_name ILIKE ?)")
+		args = append(args, pattern, pattern, pattern)
+	}
+	cond := strings.Join(conds, " AND ")
+	if courseID != nil {
+		cond = "(" + cond + ") OR course_id = ?"
+		args = append(args, *courseID)
+	}
+
+	var requests []*model.ResourceRequest
+	result := r.db.WithContext(ctx).
+		Preload("User").
+		Where("status IN ?", []model.ResourceRequestStatus{model.ResourceRequestOpen, model.ResourceRequestClaimed}).
+		Where(cond, args...).
+		Order("vote_count DESC, created_at DESC").
+		Limit(limit).
+		Find(&requests)
+	return requests, result.Error
+}
+
+// Claim 认领求资料
+func (r *resourceRequestRepository) Claim(ctx context.Context, id, userID uint) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&model.ResourceRequest{}).
+		Where("id = ? AND status = ?", id, model.ResourceRequestOpen).
+		Updates(map[string]interface{}{
+			"status":     model.ResourceRequestClaimed,
+			"claimed_by": userID,
+			"claimed_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrResourceRequestStatusChanged
+	}
+	return nil
+}
+
+// Release 取消认领
+func (r *resourceRequestRepository) Release(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.ResourceRequest{}).
+		Where("id = ? AND status = ?", id, model.ResourceRequestClaimed).
+		Updates(map[string]interface{}{
+			"status":     model.ResourceRequestOpen,
+			"claimed_by": nil,
+			"claimed_at": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrResourceRequestStatusChanged
+	}
+	return nil
+}
+
+// Fulfill 关联资料并完成求资料
+func (r *resourceRequestRepository) Fulfill(ctx context.Context, id, materialID uint) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&model.ResourceRequest{}).
+		Where("id = ? AND status IN ?", id, []model.ResourceRequestStatus{model.ResourceRequestOpen, model.ResourceRequestClaimed}).
+		Updates(map[string]interface{}{
+			"status":                model.ResourceRequestFulfilled,
+			"fulfilled_material_id": materialID,
+			"fulfilled_at":          now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrResourceRequestStatusChanged
+	}
+	return nil
+}
+
+// Close 关闭求资料
+func (r *resourceRequestRepository) Close(ctx context.Context, id uint, reason string) error {
+	result := r.db.WithContext(ctx).Model(&model.ResourceRequest{}).
+		Where("id = ? AND status IN ?", id, []model.ResourceRequestStatus{model.ResourceRequestOpen, model.ResourceRequestClaimed}).
+		Updates(map[string]interface{}{
+			"status":       model.ResourceRequestClosed,
+			"close_reason": reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrResourceRequestStatusChanged
+	}
+	return nil
+}
+
+// AddVote 投票
+func (r *resourceRequestRepository) AddVote(ctx context.Context, requestID, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		vote := &model.ResourceRequestVote{RequestID: requestID, UserID: userID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(vote)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyVoted
+		}
+		return tx.Model(&model.ResourceRequest{}).
+			Where("id = ?", requestID).
+			UpdateColumn("vote_count", gorm.Expr("vote_count + 1")).Error
+	})
+}
+
+// RemoveVote 取消投票
+func (r *resourceRequestRepository) RemoveVote(ctx context.Context, requestID, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("request_id = ? AND user_id = ?", requestID, userID).Delete(&model.ResourceRequestVote{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVoteNotFound
+		}
+		return tx.Model(&model.ResourceRequest{}).
+			Where("id = ?", requestID).
+			UpdateColumn("vote_count", gorm.Expr("GREATEST(vote_count - 1, 0)")).Error
+	})
+}
+
+// ListVotedIDs 获取用户在指定求资料中已投票的ID集合
+func (r *resourceRequestRepository) ListVotedIDs(ctx context.Context, userID uint, requestIDs []uint) (map[uint]bool, error) {
+	voted := make(map[uint]bool)
+	if userID == 0 || len(requestIDs) == 0 {
+		return voted, nil
+	}
+
+	var ids []uint
+	result := r.db.WithContext(ctx).Model(&model.ResourceRequestVote{}).
+		Where("user_id = ? AND request_id IN ?", userID, requestIDs).
+		Pluck("request_id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, id := range ids {
+		voted[id] = true
+	}
+	return voted, nil
+}
+
+// ListVoterIDs 获取求资料的全部投票用户ID
+func (r *resourceRequestRepository) ListVoterIDs(ctx context.Context, requestID uint) ([]uint, error) {
+	var ids []uint
+	result := r.db.WithContext(ctx).Model(&model.ResourceRequestVote{}).
+		Where("request_id = ?", requestID).
+		Order("id ASC").
+		Pluck("user_id", &ids)
+	return ids, result.Error
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrDictionaryWordNotFound 分词词条不存在错误
+	ErrDictionaryWordNotFound = errors.New("分词词条不存在")
+	// ErrDictionaryWordExists 分词词条已存在错误
+	ErrDictionaryWordExists = errors.New("分词词条已存在")
+)
+
+// SearchDictionaryRepository 搜索分词词典数据访问层接口
+type SearchDictionaryRepository interface {
+	// Create 添加词条
+	Create(ctx context.Context, word *model.SearchDictionaryWord) error
+	// Delete 删除词条
+	Delete(ctx context.Context, id uint) error
+	// List 分页获取词条，keyword 不为空时按词条模糊匹配
+	List(ctx context.Context, page, pageSize int, keyword string) ([]*model.SearchDictionaryWord, int64, error)
+	// ListAll 获取全部词条
+	ListAll(ctx context.Context) ([]*model.SearchDictionaryWord, error)
+}
+
+// searchDictionaryRepository 搜索分词词典数据访问层实现
+type searchDictionaryRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchDictionaryRepository 创建搜索分词词典数据访问层实例
+func NewSearchDictionaryRepository(db *gorm.DB) SearchDictionaryRepository {
+	return &searchDictionaryRepository{db: db}
+}
+
+// Create 添加词条
+func (r *searchDictionaryRepository) Create(ctx context.Context, word *model.SearchDictionaryWord) error {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(word)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDictionaryWordExists
+	}
+	return nil
+}
+
+// Delete 删除词条
+func (r *searchDictionaryRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.SearchDictionaryWord{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDictionaryWordNotFound
+	}
+	return nil
+}
+
+// List 分页获取词条
+func (r *searchDictionaryRepository) List(ctx context.Context, page, pageSize int, keyword string) ([]*model.SearchDictionaryWord, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.SearchDictionaryWord{})
+	if keyword != "" {
+		query = query.Where("word LIKE ?", "%"+keyword+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var words []*model.SearchDictionaryWord
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&words).Error; err != nil {
+		return nil, 0, err
+	}
+	return words, total, nil
+}
+
+// ListAll 获取全部词条
+func (r *searchDictionaryRepository) ListAll(ctx context.Context) ([]*model.SearchDictionaryWord, error) {
+	var words []*model.SearchDictionaryWord
+	err := r.db.WithContext(ctx).Order("id ASC").Find(&words).Error
+	return words, err
+}
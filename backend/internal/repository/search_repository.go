@@ -14,6 +14,8 @@ type SearchHistoryRepository interface {
 	CreateSearchHistory(ctx context.Context, history *model.SearchHistory) error
 	// GetUserSearchHistories 获取用户搜索历史
 	GetUserSearchHistories(ctx context.Context, userID uint, limit int) ([]*model.SearchHistory, error)
+	// GetRecentKeywordsByPrefix 获取用户以 prefix 开头（忽略大小写）的最近搜索词，已去重
+	GetRecentKeywordsByPrefix(ctx context.Context, userID uint, prefix string, limit int) ([]string, error)
 	// DeleteSearchHistory 删除搜索历史
 	DeleteSearchHistory(ctx context.Context, id uint) error
 	// ClearUserSearchHistories 清空用户搜索历史
@@ -26,6 +28,8 @@ type HotKeywordRepository interface {
 	IncrementKeywordCount(ctx context.Context, keyword string) error
 	// GetHotKeywords 获取热门搜索词
 	GetHotKeywords(ctx context.Context, limit int) ([]*model.HotKeyword, error)
+	// GetEffectiveHotKeywords 获取曾搜到过资料的热门搜索词，排除无结果的（多为拼写错误）
+	GetEffectiveHotKeywords(ctx context.Context, limit int) ([]*model.HotKeyword, error)
 	// UpdateLastSearchedAt 更新最后搜索时间
 	UpdateLastSearchedAt(ctx context.Context, keyword string) error
 }
@@ -56,6 +60,23 @@ func (r *searchHistoryRepository) GetUserSearchHistories(ctx context.Context, us
 	return histories, err
 }
 
+// GetRecentKeywordsByPrefix 获取用户以 prefix 开头的最近搜索词
+func (r *searchHistoryRepository) GetRecentKeywordsByPrefix(ctx context.Context, userID uint, prefix string, limit int) ([]string, error) {
+	var keywords []string
+	query := r.db.WithContext(ctx).Model(&model.SearchHistory{}).
+		Where("user_id = ? AND keyword <> ''", userID)
+	if prefix != "" {
+		// starts_with 不需要转义 LIKE 通配符
+		query = query.Where("starts_with(lower(keyword), lower(?))", prefix)
+	}
+	err := query.
+		Group("keyword").
+		Order("MAX(created_at) DESC").
+		Limit(limit).
+		Pluck("keyword", &keywords).Error
+	return keywords, err
+}
+
 // DeleteSearchHistory 删除搜索历史
 func (r *searchHistoryRepository) DeleteSearchHistory(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&model.SearchHistory{}, id).Error
@@ -103,6 +124,17 @@ func (r *hotKeywordRepository) GetHotKeywords(ctx context.Context, limit int) ([
 	return keywords, err
 }
 
+// GetEffectiveHotKeywords 获取曾搜到过资料的热门搜索词
+func (r *hotKeywordRepository) GetEffectiveHotKeywords(ctx context.Context, limit int) ([]*model.HotKeyword, error) {
+	var keywords []*model.HotKeyword
+	err := r.db.WithContext(ctx).
+		Where("EXISTS (SELECT 1 FROM search_histories WHERE search_histories.keyword = hot_keywords.keyword AND search_histories.result_count > 0)").
+		Order("search_count DESC, last_searched_at DESC").
+		Limit(limit).
+		Find(&keywords).Error
+	return keywords, err
+}
+
 // UpdateLastSearchedAt 更新最后搜索时间
 func (r *hotKeywordRepository) UpdateLastSearchedAt(ctx context.Context, keyword string) error {
 	return r.db.WithContext(ctx).
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrTagNotFound 标签不存在错误
+	ErrTagNotFound = errors.New("标签不存在")
+	// ErrTagAlreadyExists 标签已存在错误
+	ErrTagAlreadyExists = errors.New("标签已存在")
+)
+
+// TagWithCount 标签及使用该标签的已通过资料数
+type TagWithCount struct {
+	model.Tag
+	MaterialCount int64
+}
+
+// TagRepository 标签数据访问层接口
+type TagRepository interface {
+	// FindByID 根据ID查找标签
+	FindByID(ctx context.Context, id uint) (*model.Tag, error)
+	// SetMaterialTags 用 names 替换资料的标签，不存在的标签自动创建
+	SetMaterialTags(ctx context.Context, materialID uint, names []string) ([]model.Tag, error)
+	// ListByMaterials 批量获取资料的标签，返回 资料ID -> 标签
+	ListByMaterials(ctx context.Context, materialIDs []uint) (map[uint][]model.Tag, error)
+	// ListPopular 按已通过资料数获取热门标签
+	ListPopular(ctx context.Context, limit int) ([]*TagWithCount, error)
+	// List 分页获取全部标签（含未使用的），按资料数排序
+	List(ctx context.Context, page, pageSize int, keyword string) ([]*TagWithCount, int64, error)
+	// Rename 重命名标签，新名称已被其他标签使用时返回 ErrTagAlreadyExists
+	Rename(ctx context.Context, id uint, name string) error
+	// Merge 将 sourceIDs 的资料关联转移到 targetID 并删除源标签
+	Merge(ctx context.Context, sourceIDs []uint, targetID uint) error
+	// Delete 删除标签及其资料关联
+	Delete(ctx context.Context, id uint) error
+}
+
+// tagRepository 标签数据访问层实现
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository 创建标签数据访问层实例
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// FindByID 根据ID查找标签
+func (r *tagRepository) FindByID(ctx context.Context, id uint) (*model.Tag, error) {
+	var tag model.Tag
+	result := r.db.WithContext(ctx).First(&tag, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, result.Error
+	}
+	return &tag, nil
+}
+
+// SetMaterialTags 用 names 替换资料的标签
+func (r *tagRepository) SetMaterialTags(ctx context.Context, materialID uint, names []string) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("material_id = ?", materialID).Delete(&model.MaterialTag{}).Error; err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return nil
+		}
+
+		// 并发创建同名标签时依赖唯一索引去重
+		newTags := make([]model.Tag, 0, len(names))
+		for _, name := range names {
+			newTags = append(newTags, model.Tag{Name: name})
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("name IN ?", names).Find(&tags).Error; err != nil {
+			return err
+		}
+
+		links := make([]model.MaterialTag, 0, len(tags))
+		for _, tag := range tags {
+			links = append(links, model.MaterialTag{MaterialID: materialID, TagID: tag.ID})
+		}
+		return tx.Create(&links).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 保持调用方传入的顺序
+	order := make(map[string]int, len(names))
+	for i, name := range names {
+		order[name] = i
+	}
+	sorted := make([]model.Tag, len(tags))
+	for _, tag := range tags {
+		sorted[order[tag.Name]] = tag
+	}
+	return sorted, nil
+}
+
+// ListByMaterials 批量获取资料的标签
+func (r *tagRepository) ListByMaterials(ctx context.Context, materialIDs []uint) (map[uint][]model.Tag, error) {
+	result := make(map[uint][]model.Tag, len(materialIDs))
+	if len(materialIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		MaterialID uint
+		model.Tag
+	}
+	if err := r.db.WithContext(ctx).Table("material_tags").
+		Select("material_tags.material_id, tags.*").
+		Joins("JOIN tags ON tags.id = material_tags.tag_id").
+		Where("material_tags.material_id IN ?", materialIDs).
+		Order("material_tags.created_at ASC, tags.id ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.MaterialID] = append(result[row.MaterialID], row.Tag)
+	}
+	return result, nil
+}
+
+// countQuery 统计各标签关联的已通过资料数
+func (r *tagRepository) countQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Table("material_tags").
+		Select("material_tags.tag_id, COUNT(*) AS material_count").
+		Joins("JOIN materials ON materials.id = material_tags.material_id").
+		Where("materials.status = ? AND materials.deleted_at IS NULL", model.StatusApproved).
+		Group("material_tags.tag_id")
+}
+
+// ListPopular 按已通过资料数获取热门标签
+func (r *tagRepository) ListPopular(ctx context.Context, limit int) ([]*TagWithCount, error) {
+	var tags []*TagWithCount
+	if err := r.db.WithContext(ctx).Model(&model.Tag{}).
+		Select("tags.*, counts.material_count").
+		Joins("JOIN (?) AS counts ON counts.tag_id = tags.id", r.countQuery(ctx)).
+		Order("counts.material_count DESC, tags.name ASC").
+		Limit(limit).
+		Scan(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// List 分页获取全部标签
+func (r *tagRepository) List(ctx context.Context, page, pageSize int, keyword string) ([]*TagWithCount, int64, error) {
+	var tags []*TagWithCount
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Tag{})
+	if keyword != "" {
+		query = query.Where("tags.name LIKE ?", "%"+keyword+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Select("tags.*, COALESCE(counts.material_count, 0) AS material_count").
+		Joins("LEFT JOIN (?) AS counts ON counts.tag_id = tags.id", r.countQuery(ctx)).
+		Order("material_count DESC, tags.name ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Scan(&tags).Error; err != nil {
+		return nil, 0, err
+	}
+	return tags, total, nil
+}
+
+// Rename 重命名标签
+func (r *tagRepository) Rename(ctx context.Context, id uint, name string) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.Tag{}).
+		Where("name = ? AND id <> ?", name, id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrTagAlreadyExists
+	}
+
+	result := r.db.WithContext(ctx).Model(&model.Tag{}).Where("id = ?", id).Update("name", name)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrTagAlreadyExists
+		}
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// Merge 将源标签的资料关联转移到目标标签并删除源标签
+func (r *tagRepository) Merge(ctx context.Context, sourceIDs []uint, targetID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 同时带有源标签和目标标签的资料只保留一条关联
+		if err := tx.Exec(`
+			INSERT INTO material_tags (material_id, tag_id, created_at)
+			SELECT material_id, CAST(? AS BIGINT), MIN(created_at) FROM material_tags
+			WHERE tag_id IN ?
+			GROUP BY material_id
+			ON CONFLICT DO NOTHING
+		`, targetID, sourceIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id IN ?", sourceIDs).Delete(&model.MaterialTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", sourceIDs).Delete(&model.Tag{}).Error
+	})
+}
+
+// Delete 删除标签及其资料关联
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", id).Delete(&model.MaterialTag{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTagNotFound
+		}
+		return nil
+	})
+}
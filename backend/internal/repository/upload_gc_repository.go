@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// UploadGCRepository 孤立上传文件清理数据访问层接口
+type UploadGCRepository interface {
+	// CreateRun 创建清理记录
+	CreateRun(ctx context.Context, run *model.UploadGCRun) error
+	// UpdateRun 更新清理记录
+	UpdateRun(ctx context.Context, run *model.UploadGCRun) error
+	// ListRuns 分页获取清理记录
+	ListRuns(ctx context.Context, page, pageSize int) ([]*model.UploadGCRun, int64, error)
+	// FindReferencedFileKeys 返回给定文件存储键中仍被资料或资料版本引用的键（包括已软删除的记录），
+	// 以及尚未执行完的批量导入任务的压缩包
+	FindReferencedFileKeys(ctx context.Context, fileKeys []string) (map[string]bool, error)
+}
+
+// uploadGCRepository 孤立上传文件清理数据访问层实现
+type uploadGCRepository struct {
+	db *gorm.DB
+}
+
+// NewUploadGCRepository 创建孤立上传文件清理数据访问层实例
+func NewUploadGCRepository(db *gorm.DB) UploadGCRepository {
+	return &uploadGCRepository{db: db}
+}
+
+// CreateRun 创建清理记录
+func (r *uploadGCRepository) CreateRun(ctx context.Context, run *model.UploadGCRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+// UpdateRun 更新清理记录
+func (r *uploadGCRepository) UpdateRun(ctx context.Context, run *model.UploadGCRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+// ListRuns 分页获取清理记录
+func (r *uploadGCRepository) ListRuns(ctx context.Context, page, pageSize int) ([]*model.UploadGCRun, int64, error) {
+	var runs []*model.UploadGCRun
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.UploadGCRun{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+// FindReferencedFileKeys 返回给定文件存储键中仍被引用的键
+func (r *uploadGCRepository) FindReferencedFileKeys(ctx context.Context, fileKeys []string) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	if len(fileKeys) == 0 {
+		return referenced, nil
+	}
+
+	var keys []string
+	err := r.db.WithContext(ctx).Raw(
+		`SELECT file_key FROM materials WHERE file_key IN (?)
+		UNION
+		SELECT file_key FROM material_versions WHERE file_key IN (?)
+		UNION
+		SELECT file_key FROM material_imports WHERE status IN ('pending', 'running') AND file_key IN (?)`,
+		fileKeys, fileKeys, fileKeys,
+	).Scan(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		referenced[key] = true
+	}
+	return referenced, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUploadSessionNotFound 上传会话不存在错误
+	ErrUploadSessionNotFound = errors.New("上传会话不存在")
+)
+
+// UploadSessionRepository 分片上传会话数据访问层接口
+type UploadSessionRepository interface {
+	// Create 创建上传会话
+	Create(ctx context.Context, session *model.UploadSession) error
+	// FindByIDAndUser 查找用户的上传会话
+	FindByIDAndUser(ctx context.Context, id, userID uint) (*model.UploadSession, error)
+	// UpdateStatus 更新上传会话状态
+	UpdateStatus(ctx context.Context, id uint, status model.UploadSessionStatus) error
+	// ListExpired 获取已过期但仍处于上传中的会话
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*model.UploadSession, error)
+	// CountActiveByUser 统计用户进行中的上传会话数
+	CountActiveByUser(ctx context.Context, userID uint) (int64, error)
+}
+
+// uploadSessionRepository 分片上传会话数据访问层实现
+type uploadSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewUploadSessionRepository 创建分片上传会话数据访问层实例
+func NewUploadSessionRepository(db *gorm.DB) UploadSessionRepository {
+	return &uploadSessionRepository{db: db}
+}
+
+// Create 创建上传会话
+func (r *uploadSessionRepository) Create(ctx context.Context, session *model.UploadSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// FindByIDAndUser 查找用户的上传会话
+func (r *uploadSessionRepository) FindByIDAndUser(ctx context.Context, id, userID uint) (*model.UploadSession, error) {
+	var session model.UploadSession
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&session)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrUploadSessionNotFound
+		}
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// UpdateStatus 更新上传会话状态
+func (r *uploadSessionRepository) UpdateStatus(ctx context.Context, id uint, status model.UploadSessionStatus) error {
+	updates := map[string]interface{}{
+		"status": status,
+	}
+	if status == model.UploadSessionCompleted {
+		updates["completed_at"] = gorm.Expr("NOW()")
+	}
+	return r.db.WithContext(ctx).Model(&model.UploadSession{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// ListExpired 获取已过期但仍处于上传中的会话
+func (r *uploadSessionRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*model.UploadSession, error) {
+	var sessions []*model.UploadSession
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", model.UploadSessionUploading, before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sessions, nil
+}
+
+// CountActiveByUser 统计用户进行中的上传会话数
+func (r *uploadSessionRepository) CountActiveByUser(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&model.UploadSession{}).
+		Where("user_id = ? AND status = ? AND expires_at > ?", userID, model.UploadSessionUploading, time.Now()).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/study-upc/backend/internal/pkg/config"
 	"github.com/study-upc/backend/internal/pkg/database"
 	"github.com/study-upc/backend/internal/pkg/email"
+	"github.com/study-upc/backend/internal/pkg/highlight"
 	"github.com/study-upc/backend/internal/pkg/logger"
 	"github.com/study-upc/backend/internal/pkg/oss"
 	"github.com/study-upc/backend/internal/pkg/utils"
@@ -56,14 +57,28 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	userRepo := repository.NewUserRepository(db)
 	materialRepo := repository.NewMaterialRepository(db)
 	materialCategoryRepo := repository.NewMaterialCategoryRepository(db)
+	materialVersionRepo := repository.NewMaterialVersionRepository(db)
+	uploadGCRepo := repository.NewUploadGCRepository(db)
+	processingJobRepo := repository.NewProcessingJobRepository(db)
+	materialImportRepo := repository.NewMaterialImportRepository(db)
+	uploadSessionRepo := repository.NewUploadSessionRepository(db)
 	favoriteRepo := repository.NewFavoriteRepository(db)
+	favoriteCollectionRepo := repository.NewFavoriteCollectionRepository(db)
+	resourceRequestRepo := repository.NewResourceRequestRepository(db)
 	downloadRepo := repository.NewDownloadRecordRepository(db)
+	downloadBoostRepo := repository.NewDownloadQuotaBoostRepository(db)
+	materialArchiveRepo := repository.NewMaterialArchiveRepository(db)
+	courseRepo := repository.NewCourseRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	materialRatingRepo := repository.NewMaterialRatingRepository(db)
+	materialCommentRepo := repository.NewMaterialCommentRepository(db)
 	reportRepo := repository.NewReportRepository(db)
 	committeeRepo := repository.NewCommitteeRepository(db)
 	reviewRepo := repository.NewReviewRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
 	searchHistoryRepo := repository.NewSearchHistoryRepository(db)
 	hotKeywordRepo := repository.NewHotKeywordRepository(db)
+	searchDictionaryRepo := repository.NewSearchDictionaryRepository(db)
 	statisticsRepo := repository.NewStatisticsRepository(db)
 	adminRepo := repository.NewAdminRepository(db)
 	announcementRepo := repository.NewAnnouncementRepository(db)
@@ -71,6 +86,7 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 
 	// 初始化 OSS 服务
 	var ossClient oss.OSSClient
+	var fsClient *oss.FilesystemClient
 	var err error
 	ossProvider := strings.ToLower(strings.TrimSpace(cfg.OSS.Provider))
 	if ossProvider == "" {
@@ -86,6 +102,22 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 			Region:    cfg.OSS.Region,
 			UseSSL:    cfg.OSS.UseSSL,
 		})
+	case "filesystem":
+		// 本地文件存储，上传/下载经由后端签名路由，无需 MinIO
+		localDir := cfg.OSS.LocalDir
+		if localDir == "" {
+			localDir = "data/oss"
+		}
+		publicURL := cfg.OSS.PublicURL
+		if publicURL == "" {
+			publicURL = fmt.Sprintf("http://localhost%s", cfg.Server.GetAddr())
+		}
+		fsClient, err = oss.NewFilesystemClient(&oss.FilesystemConfig{
+			RootDir:   localDir,
+			BaseURL:   publicURL,
+			SecretKey: cfg.OSS.SecretKey,
+		})
+		ossClient = fsClient
 	default:
 		panic(fmt.Sprintf("不支持的 OSS provider: %s", cfg.OSS.Provider))
 	}
@@ -102,6 +134,13 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	// 最大文件大小 512MB，上传签名 1 小时有效期，下载签名 24 小时有效期
 	ossService := oss.NewOSSService(ossClient, 536870912, 1*time.Hour, 24*time.Hour)
 
+	// 本地文件存储的签名上传/下载路由（无需认证，由 URL 签名鉴权）
+	if fsClient != nil {
+		localOSSHandler := handler.NewLocalOSSHandler(fsClient, 536870912)
+		r.PUT(oss.FilesystemObjectRoute+"/*key", localOSSHandler.Upload)
+		r.GET(oss.FilesystemObjectRoute+"/*key", localOSSHandler.Download)
+	}
+
 	// 初始化 SMTP 邮件客户端
 	smtpClient := email.NewSMTPClient(&email.SMTPConfig{
 		Host:     cfg.SMTP.Host,
@@ -115,18 +154,51 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	// 初始化 Service 层
 	authService := service.NewAuthService(userRepo, jwtManager, redisClient)
 	emailVerificationService := service.NewEmailVerificationService(userRepo, emailVerificationRepo, smtpClient)
-	materialService := service.NewMaterialService(materialRepo, favoriteRepo, downloadRepo, materialCategoryRepo, adminRepo, ossService, redisClient)
+	materialService := service.NewMaterialService(materialRepo, favoriteRepo, downloadRepo, materialVersionRepo, materialCategoryRepo, adminRepo, userRepo, downloadBoostRepo, courseRepo, tagRepo, ossService, redisClient)
 	materialCategoryService := service.NewMaterialCategoryService(materialCategoryRepo)
-	favoriteService := service.NewFavoriteService(favoriteRepo, materialRepo)
-	reportService := service.NewReportService(reportRepo, materialRepo)
+	courseService := service.NewCourseService(courseRepo)
+	tagService := service.NewTagService(tagRepo)
+	favoriteService := service.NewFavoriteService(favoriteRepo, materialRepo, favoriteCollectionRepo)
+	favoriteCollectionService := service.NewFavoriteCollectionService(favoriteCollectionRepo, favoriteService)
+	reportService := service.NewReportService(reportRepo, materialRepo, materialCommentRepo)
+	materialRatingService := service.NewMaterialRatingService(materialRatingRepo, materialRepo)
+	materialCommentService := service.NewMaterialCommentService(materialCommentRepo, materialRepo)
 	committeeService := service.NewCommitteeService(committeeRepo, userRepo, reviewRepo)
-	reviewService := service.NewReviewService(materialRepo, committeeRepo, reportRepo, reviewRepo, userRepo)
+	reviewService := service.NewReviewService(materialRepo, committeeRepo, reportRepo, reviewRepo, userRepo, materialCommentRepo)
 	notificationService := service.NewNotificationService(notificationRepo, userRepo)
-	searchService := service.NewSearchService(db, materialRepo, searchHistoryRepo, hotKeywordRepo, downloadRepo)
-	recommendationService := service.NewRecommendationService(db, materialRepo, downloadRepo, favoriteRepo)
+	resourceRequestService := service.NewResourceRequestService(resourceRequestRepo, materialRepo, materialCategoryRepo, courseRepo)
+	searchHighlighter := highlight.New(cfg.Search.HighlightPreTag, cfg.Search.HighlightPostTag, cfg.Search.SnippetLength)
+	searchService := service.NewSearchService(db, materialRepo, searchHistoryRepo, hotKeywordRepo, downloadRepo, courseRepo, resourceRequestRepo, searchHighlighter)
+	searchSuggestService := service.NewSearchSuggestService(materialRepo, courseRepo, tagRepo, hotKeywordRepo, searchHistoryRepo, redisClient)
+	searchDictionaryService := service.NewSearchDictionaryService(searchDictionaryRepo, courseRepo, materialRepo)
+	recommendationService := service.NewRecommendationService(db, materialRepo, downloadRepo, favoriteRepo, courseRepo)
 	statisticsService := service.NewStatisticsService(statisticsRepo)
 	adminService := service.NewAdminService(adminRepo, userRepo, materialRepo)
 	announcementService := service.NewAnnouncementService(announcementRepo, userRepo)
+	uploadGCService := service.NewUploadGCService(uploadGCRepo, adminRepo, ossService)
+	uploadSessionService := service.NewUploadSessionService(uploadSessionRepo, materialService, ossService)
+	materialProcessingService := service.NewMaterialProcessingService(processingJobRepo, materialRepo, ossService)
+	materialService.SetProcessingService(materialProcessingService)
+	materialImportService := service.NewMaterialImportService(materialImportRepo, materialCategoryRepo, materialService, ossService)
+	downloadBoostService := service.NewDownloadBoostService(downloadBoostRepo, userRepo)
+	materialArchiveService := service.NewMaterialArchiveService(materialArchiveRepo, materialRepo, materialVersionRepo, materialCategoryRepo, adminRepo, ossService)
+
+	// 启动孤立上传文件定时清理
+	uploadGCService.Start(context.Background())
+	// 启动过期分片上传会话的自动取消
+	uploadSessionService.Start(context.Background())
+	// 启动资料预览生成任务
+	materialProcessingService.Start(context.Background())
+	// 启动资料批量导入任务
+	materialImportService.Start(context.Background())
+	// 启动资料定时归档任务
+	materialArchiveService.Start(context.Background())
+	// 加载搜索分词词典并定期重新加载
+	searchDictionaryService.Start(context.Background())
+	// 构建搜索拼写纠错词库并定期重新构建
+	searchService.Start(context.Background())
+	// 构建搜索联想索引并定期全量重建
+	searchSuggestService.Start(context.Background())
 
 	// 访问日志中间件(需要在 statisticsService 初始化后注册)
 	r.Use(middleware.AccessLog(statisticsService))
@@ -134,6 +206,12 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	// 设置通知服务以解决循环依赖
 	committeeService.SetNotificationService(notificationService)
 	reviewService.SetNotificationService(notificationService)
+	reviewService.SetSearchSuggestService(searchSuggestService)
+	reportService.SetSearchSuggestService(searchSuggestService)
+	materialService.SetSearchSuggestService(searchSuggestService)
+	materialArchiveService.SetNotificationService(notificationService)
+	materialCommentService.SetNotificationService(notificationService)
+	resourceRequestService.SetNotificationService(notificationService)
 
 	// 初始化 Handler 层
 	authHandler := handler.NewAuthHandler(authService, statisticsService)
@@ -144,14 +222,27 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	authHandler.SetJWTManager(jwtManager)
 	materialHandler := handler.NewMaterialHandler(materialService, favoriteService, reportService, downloadRepo)
 	materialCategoryHandler := handler.NewMaterialCategoryHandler(materialCategoryService)
+	courseHandler := handler.NewCourseHandler(courseService)
+	tagHandler := handler.NewTagHandler(tagService)
+	materialRatingHandler := handler.NewMaterialRatingHandler(materialRatingService)
+	materialCommentHandler := handler.NewMaterialCommentHandler(materialCommentService, reportService)
+	favoriteCollectionHandler := handler.NewFavoriteCollectionHandler(favoriteCollectionService)
+	resourceRequestHandler := handler.NewResourceRequestHandler(resourceRequestService)
 	committeeHandler := handler.NewCommitteeHandler(committeeService)
 	reviewHandler := handler.NewReviewHandler(reviewService)
 	notificationHandler := handler.NewNotificationHandler(notificationService)
-	searchHandler := handler.NewSearchHandler(searchService, recommendationService)
+	searchHandler := handler.NewSearchHandler(searchService, searchSuggestService, recommendationService)
+	searchDictionaryHandler := handler.NewSearchDictionaryHandler(searchDictionaryService)
 	statisticsHandler := handler.NewStatisticsHandler(statisticsService)
 	adminHandler := handler.NewAdminHandler(adminService)
 	announcementHandler := handler.NewAnnouncementHandler(announcementService)
 	systemHandler := handler.NewSystemHandler(adminService)
+	uploadGCHandler := handler.NewUploadGCHandler(uploadGCService)
+	uploadSessionHandler := handler.NewUploadSessionHandler(uploadSessionService)
+	processingJobHandler := handler.NewProcessingJobHandler(materialProcessingService)
+	materialImportHandler := handler.NewMaterialImportHandler(materialImportService)
+	downloadBoostHandler := handler.NewDownloadBoostHandler(downloadBoostService)
+	materialArchiveHandler := handler.NewMaterialArchiveHandler(materialArchiveService)
 
 	// 从数据库加载系统配置并应用到 OSS 服务
 	if uploadConfig, err := adminService.GetSystemConfig("allowed_file_types"); err == nil && uploadConfig.ConfigValue != "" {
@@ -230,6 +321,14 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 				system.GET("/configs", systemHandler.GetPublicSystemConfigs)
 				system.GET("/upload-config", systemHandler.GetUploadConfig)
 			}
+
+			// 兑换下载令牌（通过一次性令牌鉴权，浏览器直接访问时无法携带 Authorization 头）
+			public.GET("/downloads/redirect/:token", materialHandler.RedirectDownload)
+			public.GET("/downloads/stream/:token", materialHandler.StreamDownload)
+
+			// 公开分享的收藏夹（由分享令牌鉴权）
+			public.GET("/shared/collections/:token", favoriteCollectionHandler.GetSharedCollection)
+			public.GET("/shared/collections/:token/items", favoriteCollectionHandler.ListSharedItems)
 		}
 
 		// 需要认证的路由
@@ -253,6 +352,53 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 				adminMaterialCategories.POST("/:id/toggle", materialCategoryHandler.ToggleStatus) // 切换启用状态
 			}
 
+			// 课程目录（所有认证用户可访问）
+			courses := protected.Group("/courses")
+			{
+				courses.GET("", courseHandler.List)        // 获取课程列表
+				courses.GET("/:id", courseHandler.GetByID) // 获取课程详情
+			}
+
+			// 课程管理（管理员权限）
+			adminCourses := protected.Group("/admin/courses")
+			adminCourses.Use(middleware.RequireAdmin())
+			{
+				adminCourses.POST("", courseHandler.Create)       // 创建课程
+				adminCourses.PUT("/:id", courseHandler.Update)    // 更新课程
+				adminCourses.DELETE("/:id", courseHandler.Delete) // 删除课程
+			}
+
+			// 标签（所有认证用户可访问）
+			protected.GET("/tags/popular", tagHandler.ListPopular) // 热门标签
+
+			// 标签管理（管理员权限）
+			adminTags := protected.Group("/admin/tags")
+			adminTags.Use(middleware.RequireAdmin())
+			{
+				adminTags.GET("", tagHandler.List)          // 获取标签列表
+				adminTags.POST("/merge", tagHandler.Merge)  // 合并标签
+				adminTags.PUT("/:id", tagHandler.Rename)    // 重命名标签
+				adminTags.DELETE("/:id", tagHandler.Delete) // 删除标签
+			}
+
+			// 评论（所有认证用户可访问，编辑仅限作者，删除限作者或管理员）
+			comments := protected.Group("/comments")
+			{
+				comments.GET("/:id/replies", materialCommentHandler.ListReplies)   // 评论回复列表
+				comments.PUT("/:id", materialCommentHandler.UpdateComment)         // 编辑评论
+				comments.DELETE("/:id", materialCommentHandler.DeleteComment)      // 删除评论
+				comments.POST("/:id/report", materialCommentHandler.ReportComment) // 举报评论
+			}
+
+			// 评论管理（管理员权限）
+			adminComments := protected.Group("/admin/comments")
+			adminComments.Use(middleware.RequireAdmin())
+			{
+				adminComments.GET("", materialCommentHandler.ListAllComments)               // 评论管理列表
+				adminComments.POST("/:id/moderate", materialCommentHandler.ModerateComment) // 隐藏或恢复评论
+				adminComments.DELETE("/:id", materialCommentHandler.DeleteComment)          // 删除评论
+			}
+
 			// 公告相关（所有认证用户可访问）
 			announcements := protected.Group("/announcements")
 			{
@@ -288,20 +434,46 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 				// 下载接口（所有认证用户可访问）
 				materials.GET("/:id/download", materialHandler.GetDownloadURL) // 获取下载链接
 
+				// 版本历史（所有认证用户可访问）
+				materials.GET("/:id/versions", materialHandler.ListVersions)                              // 版本列表
+				materials.GET("/:id/versions/:versionId/download", materialHandler.GetVersionDownloadURL) // 下载指定版本
+
 				// 收藏相关（所有认证用户）
-				materials.POST("/:id/favorite", materialHandler.AddFavorite)      // 添加收藏
-				materials.DELETE("/:id/favorite", materialHandler.RemoveFavorite) // 取消收藏
+				materials.POST("/:id/favorite", materialHandler.AddFavorite)                         // 添加收藏
+				materials.DELETE("/:id/favorite", materialHandler.RemoveFavorite)                    // 取消收藏
+				materials.GET("/:id/collections", favoriteCollectionHandler.ListMaterialCollections) // 资料所在的收藏夹
 
 				// 举报相关（所有认证用户）
 				materials.POST("/:id/report", materialHandler.CreateReport) // 创建举报
 
+				// 评分与评论（所有认证用户）
+				materials.GET("/:id/rating", materialRatingHandler.GetRating)         // 获取评分
+				materials.PUT("/:id/rating", materialRatingHandler.RateMaterial)      // 评分
+				materials.DELETE("/:id/rating", materialRatingHandler.DeleteRating)   // 撤销评分
+				materials.GET("/:id/comments", materialCommentHandler.ListComments)   // 评论列表
+				materials.POST("/:id/comments", materialCommentHandler.CreateComment) // 发表评论
+
 				// 学委及以上权限
 				committee := materials.Use(middleware.RequireCommittee())
 				{
-					committee.POST("", materialHandler.CreateMaterial)                          // 创建资料
-					committee.PUT("/:id", materialHandler.UpdateMaterial)                       // 更新资料
-					committee.POST("/upload-signature", materialHandler.GetUploadSignature)     // 获取上传签名
-					committee.POST("/delete-uploaded-file", materialHandler.DeleteUploadedFile) // 删除已上传文件
+					committee.POST("", materialHandler.CreateMaterial)                      // 创建资料
+					committee.PUT("/:id", materialHandler.UpdateMaterial)                   // 更新资料
+					committee.POST("/upload-signature", materialHandler.GetUploadSignature) // 获取上传签名
+					committee.POST("/finalize-upload", materialHandler.FinalizeUpload)      // 确认上传完成
+
+					// 分片上传（大文件断点续传）
+					committee.POST("/upload-sessions", uploadSessionHandler.CreateSession)                       // 创建上传会话
+					committee.GET("/upload-sessions/:sessionId", uploadSessionHandler.GetSession)                // 获取会话及已上传分片
+					committee.POST("/upload-sessions/:sessionId/parts", uploadSessionHandler.PresignParts)       // 获取分片上传地址
+					committee.POST("/upload-sessions/:sessionId/complete", uploadSessionHandler.CompleteSession) // 完成上传
+					committee.DELETE("/upload-sessions/:sessionId", uploadSessionHandler.AbortSession)           // 取消上传
+					committee.POST("/delete-uploaded-file", materialHandler.DeleteUploadedFile)                  // 删除已上传文件
+					committee.POST("/:id/versions", materialHandler.UploadVersion)                               // 上传新版本
+
+					// 批量导入（ZIP 压缩包 + 清单）
+					committee.POST("/imports", materialImportHandler.CreateImport)       // 创建导入任务
+					committee.GET("/imports", materialImportHandler.ListImports)         // 我的导入任务
+					committee.GET("/imports/:importId", materialImportHandler.GetImport) // 导入任务详情及每行结果
 				}
 
 				// 管理员权限
@@ -312,13 +484,54 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 				}
 			}
 
-			// 收藏列表
+			// 收藏列表（即默认收藏夹）
 			protected.GET("/favorites", materialHandler.ListFavorites)
 
+			// 收藏夹（仅限所有者操作）
+			collections := protected.Group("/collections")
+			{
+				collections.GET("", favoriteCollectionHandler.ListCollections)                     // 我的收藏夹
+				collections.POST("", favoriteCollectionHandler.CreateCollection)                   // 创建收藏夹
+				collections.PUT("/order", favoriteCollectionHandler.ReorderCollections)            // 调整收藏夹顺序
+				collections.PUT("/:id", favoriteCollectionHandler.UpdateCollection)                // 更新收藏夹
+				collections.DELETE("/:id", favoriteCollectionHandler.DeleteCollection)             // 删除收藏夹
+				collections.GET("/:id/items", favoriteCollectionHandler.ListItems)                 // 收藏夹中的资料
+				collections.POST("/:id/items", favoriteCollectionHandler.AddItem)                  // 添加资料
+				collections.PUT("/:id/items/order", favoriteCollectionHandler.ReorderItems)        // 调整资料顺序
+				collections.PUT("/:id/items/:materialId", favoriteCollectionHandler.UpdateItem)    // 更新资料备注
+				collections.DELETE("/:id/items/:materialId", favoriteCollectionHandler.RemoveItem) // 移除资料
+				collections.POST("/:id/share", favoriteCollectionHandler.ShareCollection)          // 开启公开分享
+				collections.DELETE("/:id/share", favoriteCollectionHandler.UnshareCollection)      // 关闭公开分享
+			}
+
+			// 求资料（所有认证用户）
+			resourceRequests := protected.Group("/resource-requests")
+			{
+				resourceRequests.GET("", resourceRequestHandler.ListRequests)            // 求资料列表
+				resourceRequests.POST("", resourceRequestHandler.CreateRequest)          // 发布求资料
+				resourceRequests.GET("/:id", resourceRequestHandler.GetRequest)          // 求资料详情
+				resourceRequests.PUT("/:id", resourceRequestHandler.UpdateRequest)       // 编辑求资料
+				resourceRequests.POST("/:id/close", resourceRequestHandler.CloseRequest) // 关闭求资料
+				resourceRequests.POST("/:id/vote", resourceRequestHandler.Vote)          // 投票
+				resourceRequests.DELETE("/:id/vote", resourceRequestHandler.Unvote)      // 取消投票
+			}
+
+			// 求资料认领与完成（学委及以上权限）
+			resourceRequestClaims := protected.Group("/resource-requests")
+			resourceRequestClaims.Use(middleware.RequireCommittee())
+			{
+				resourceRequestClaims.POST("/:id/claim", resourceRequestHandler.ClaimRequest)     // 认领
+				resourceRequestClaims.DELETE("/:id/claim", resourceRequestHandler.ReleaseRequest) // 取消认领
+				resourceRequestClaims.POST("/:id/fulfill", resourceRequestHandler.FulfillRequest) // 关联资料完成求资料
+			}
+
 			// 下载记录列表
 			protected.GET("/downloads", materialHandler.ListDownloadRecords)
 			protected.GET("/downloads/quota", materialHandler.GetDownloadQuota)
 
+			// 存储配额
+			protected.GET("/uploads/quota", materialHandler.GetUploadQuota)
+
 			// 举报管理（管理员）
 			adminReports := protected.Group("/admin/reports")
 			adminReports.Use(middleware.RequireAdmin())
@@ -363,11 +576,13 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 				// 用户管理
 				users := admin.Group("/users")
 				{
-					users.GET("", adminHandler.ListUsers)                   // 用户列表
-					users.GET("/:id", adminHandler.GetUserDetail)           // 用户详情
-					users.PUT("/:id", adminHandler.UpdateUserInfo)          // 更新用户信息
-					users.PUT("/:id/status", adminHandler.UpdateUserStatus) // 更新用户状态
-					users.DELETE("/:id", adminHandler.DeleteUser)           // 删除用户
+					users.GET("", adminHandler.ListUsers)                                    // 用户列表
+					users.GET("/:id", adminHandler.GetUserDetail)                            // 用户详情
+					users.PUT("/:id", adminHandler.UpdateUserInfo)                           // 更新用户信息
+					users.PUT("/:id/status", adminHandler.UpdateUserStatus)                  // 更新用户状态
+					users.PUT("/:id/storage-quota", adminHandler.UpdateUserStorageQuota)     // 设置存储配额
+					users.PUT("/:id/download-limits", adminHandler.UpdateUserDownloadLimits) // 设置下载限制
+					users.DELETE("/:id", adminHandler.DeleteUser)                            // 删除用户
 				}
 
 				// 系统配置管理
@@ -380,6 +595,31 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 					configs.DELETE("/:key", adminHandler.DeleteSystemConfig) // 删除配置
 				}
 
+				// 孤立上传文件清理
+				uploads := admin.Group("/uploads")
+				{
+					uploads.POST("/gc", uploadGCHandler.Run)          // 执行清理
+					uploads.GET("/gc/runs", uploadGCHandler.ListRuns) // 清理记录
+				}
+
+				// 临时下载额度提升（如考试周）
+				admin.GET("/download-boosts", downloadBoostHandler.ListBoosts)
+				admin.POST("/download-boosts", downloadBoostHandler.CreateBoost)
+				admin.DELETE("/download-boosts/:id", downloadBoostHandler.DeleteBoost)
+
+				// 资料后台处理任务（缩略图和文本摘要）
+				admin.GET("/processing-jobs", processingJobHandler.ListJobs)
+				admin.POST("/processing-jobs/:id/retry", processingJobHandler.RetryJob)
+				admin.POST("/materials/:id/preview", processingJobHandler.RegeneratePreview)
+
+				// 资料归档规则与恢复
+				admin.GET("/archive-rules", materialArchiveHandler.ListRules)
+				admin.POST("/archive-rules", materialArchiveHandler.CreateRule)
+				admin.POST("/archive-rules/run", materialArchiveHandler.Run)
+				admin.PUT("/archive-rules/:id", materialArchiveHandler.UpdateRule)
+				admin.DELETE("/archive-rules/:id", materialArchiveHandler.DeleteRule)
+				admin.POST("/materials/:id/restore", materialArchiveHandler.RestoreMaterial)
+
 				// 学委申请列表
 				admin.GET("/applications", committeeHandler.ListApplications)
 				// 审核学委申请
@@ -393,6 +633,12 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 				admin.GET("/materials/reviewed", materialHandler.ListReviewedMaterials)
 				// 审核资料
 				admin.POST("/materials/:id/review", reviewHandler.ReviewMaterial)
+				// 待审核资料版本列表
+				admin.GET("/material-versions/pending", materialHandler.ListPendingVersions)
+				// 审核资料版本
+				admin.POST("/materials/:id/versions/:versionId/review", materialHandler.ReviewVersion)
+				// 回滚资料版本
+				admin.POST("/materials/:id/versions/:versionId/rollback", materialHandler.RollbackVersion)
 
 				// 审核历史
 				admin.GET("/review/history", reviewHandler.GetReviewHistory)
@@ -416,10 +662,22 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 			{
 				search.GET("", searchHandler.Search)                        // 搜索资料
 				search.GET("/hot-keywords", searchHandler.GetHotKeywords)   // 热门搜索词
+				search.GET("/suggest", searchHandler.Suggest)               // 搜索联想
 				search.GET("/history", searchHandler.GetSearchHistory)      // 搜索历史
 				search.DELETE("/history", searchHandler.ClearSearchHistory) // 清空搜索历史
 			}
 
+			// 搜索分词词典管理（管理员权限）
+			adminSearch := protected.Group("/admin/search")
+			adminSearch.Use(middleware.RequireAdmin())
+			{
+				adminSearch.GET("/dictionary", searchDictionaryHandler.ListWords)         // 自定义词条列表
+				adminSearch.POST("/dictionary", searchDictionaryHandler.AddWord)          // 添加词条
+				adminSearch.DELETE("/dictionary/:id", searchDictionaryHandler.DeleteWord) // 删除词条
+				adminSearch.GET("/segment", searchDictionaryHandler.Segment)              // 预览分词结果
+				adminSearch.POST("/reindex", searchDictionaryHandler.Reindex)             // 重建搜索索引
+			}
+
 			// 推荐相关
 			recommendations := protected.Group("/materials")
 			{
@@ -35,6 +35,8 @@ type AdminService interface {
 	GetUserDetail(id uint) (*model.UserDetailResponse, error)
 	UpdateUserStatus(id uint, status, reason string) error
 	UpdateUserInfo(id uint, updates map[string]interface{}) error
+	UpdateUserStorageQuota(id uint, quotaMB *int64) error
+	UpdateUserDownloadLimits(id uint, req *model.UpdateUserDownloadLimitsRequest) error
 	DeleteUser(id uint) error
 }
 
@@ -139,10 +141,15 @@ func (s *adminService) GetUserDetail(id uint) (*model.UserDetailResponse, error)
 	if err != nil {
 		return nil, err
 	}
+	storageUsed, err := s.adminRepo.SumUserUploadSize(id)
+	if err != nil {
+		return nil, err
+	}
 
 	response.DownloadTotal = downloadTotal
 	response.UploadTotal = uploadTotal
 	response.FavoriteTotal = favoriteTotal
+	response.StorageUsed = storageUsed
 
 	return response, nil
 }
@@ -209,6 +216,22 @@ func (s *adminService) UpdateUserInfo(id uint, updates map[string]interface{}) e
 	return s.adminRepo.UpdateUser(user)
 }
 
+// UpdateUserStorageQuota 设置用户存储配额，quotaMB 为空时恢复按角色配置
+func (s *adminService) UpdateUserStorageQuota(id uint, quotaMB *int64) error {
+	if _, err := s.adminRepo.GetUserByID(id); err != nil {
+		return err
+	}
+	return s.adminRepo.UpdateUserStorageQuota(id, quotaMB)
+}
+
+// UpdateUserDownloadLimits 设置用户下载限制，各项为空时恢复按角色配置
+func (s *adminService) UpdateUserDownloadLimits(id uint, req *model.UpdateUserDownloadLimitsRequest) error {
+	if _, err := s.adminRepo.GetUserByID(id); err != nil {
+		return err
+	}
+	return s.adminRepo.UpdateUserDownloadLimits(id, req)
+}
+
 // DeleteUser 删除用户
 func (s *adminService) DeleteUser(id uint) error {
 	// 检查用户是否存在
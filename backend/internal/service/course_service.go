@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/repository"
+)
+
+var (
+	// ErrCourseNotFound 课程不存在
+	ErrCourseNotFound = errors.New("课程不存在")
+	// ErrInvalidCourse 课程参数错误
+	ErrInvalidCourse = errors.New("课程参数错误")
+	// ErrCourseConflict 课程名称、别名或代码与已有课程重复
+	ErrCourseConflict = errors.New("课程名称、别名或代码与已有课程重复")
+)
+
+// CourseService 课程服务接口
+type CourseService interface {
+	// CreateCourse 创建课程，并关联课程名称与其名称或别名一致的已有资料
+	CreateCourse(ctx context.Context, req *model.CourseRequest) (*model.CourseResponse, error)
+	// UpdateCourse 更新课程，别名整体替换
+	UpdateCourse(ctx context.Context, id uint, req *model.CourseRequest) (*model.CourseResponse, error)
+	// DeleteCourse 删除课程，关联资料保留原课程名称
+	DeleteCourse(ctx context.Context, id uint) error
+	// GetCourse 获取课程详情
+	GetCourse(ctx context.Context, id uint) (*model.CourseResponse, error)
+	// ListCourses 获取课程列表
+	ListCourses(ctx context.Context, req *model.CourseListRequest) ([]*model.CourseResponse, int64, error)
+}
+
+// courseService 课程服务实现
+type courseService struct {
+	courseRepo repository.CourseRepository
+}
+
+// NewCourseService 创建课程服务实例
+func NewCourseService(courseRepo repository.CourseRepository) CourseService {
+	return &courseService{
+		courseRepo: courseRepo,
+	}
+}
+
+// CreateCourse 创建课程
+func (s *courseService) CreateCourse(ctx context.Context, req *model.CourseRequest) (*model.CourseResponse, error) {
+	course := &model.Course{}
+	aliases, err := s.applyRequest(ctx, course, req)
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		course.Aliases = append(course.Aliases, model.CourseAlias{Alias: alias})
+	}
+
+	if err := s.courseRepo.Create(ctx, course); err != nil {
+		return nil, fmt.Errorf("创建课程失败: %w", err)
+	}
+
+	return s.linkAndRespond(ctx, course)
+}
+
+// UpdateCourse 更新课程
+func (s *courseService) UpdateCourse(ctx context.Context, id uint, req *model.CourseRequest) (*model.CourseResponse, error) {
+	course, err := s.findCourse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := s.applyRequest(ctx, course, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.courseRepo.Update(ctx, course, aliases); err != nil {
+		return nil, fmt.Errorf("更新课程失败: %w", err)
+	}
+
+	return s.linkAndRespond(ctx, course)
+}
+
+// DeleteCourse 删除课程
+func (s *courseService) DeleteCourse(ctx context.Context, id uint) error {
+	if err := s.courseRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrCourseNotFound) {
+			return ErrCourseNotFound
+		}
+		return fmt.Errorf("删除课程失败: %w", err)
+	}
+	return nil
+}
+
+// GetCourse 获取课程详情
+func (s *courseService) GetCourse(ctx context.Context, id uint) (*model.CourseResponse, error) {
+	course, err := s.findCourse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.toResponses(ctx, []*model.Course{course})
+	if err != nil {
+		return nil, err
+	}
+	return responses[0], nil
+}
+
+// ListCourses 获取课程列表
+func (s *courseService) ListCourses(ctx context.Context, req *model.CourseListRequest) ([]*model.CourseResponse, int64, error) {
+	courses, total, err := s.courseRepo.List(ctx, req.Page, req.PageSize, repository.CourseListOptions{
+		Keyword:    req.Keyword,
+		Department: req.Department,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取课程列表失败: %w", err)
+	}
+
+	responses, err := s.toResponses(ctx, courses)
+	if err != nil {
+		return nil, 0, err
+	}
+	return responses, total, nil
+}
+
+// findCourse 查找课程并转换错误
+func (s *courseService) findCourse(ctx context.Context, id uint) (*model.Course, error) {
+	course, err := s.courseRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCourseNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, fmt.Errorf("获取课程失败: %w", err)
+	}
+	return course, nil
+}
+
+// applyRequest 校验请求并写入课程字段，返回去重后的别名
+// 课程名称、代码和别名都不能与其他课程的名称、代码或别名重复，否则同一个名称会解析到多门课程
+func (s *courseService) applyRequest(ctx context.Context, course *model.Course, req *model.CourseRequest) ([]string, error) {
+	name := model.NormalizeCourseName(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: 课程名称不能为空", ErrInvalidCourse)
+	}
+	code := strings.TrimSpace(req.Code)
+
+	seen := map[string]bool{strings.ToLower(name): true}
+	aliases := make([]string, 0, len(req.Aliases))
+	for _, alias := range req.Aliases {
+		alias = model.NormalizeCourseName(alias)
+		key := strings.ToLower(alias)
+		if alias == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		aliases = append(aliases, alias)
+	}
+
+	names := append([]string{name}, aliases...)
+	if code != "" {
+		names = append(names, code)
+	}
+	// 逐一比对所有匹配的课程，别名与其他课程的规范名称相同同样视为重复
+	existing, err := s.courseRepo.FindByTerms(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("检查课程名称失败: %w", err)
+	}
+	for _, other := range existing {
+		if other.ID == course.ID {
+			continue
+		}
+		terms := courseTermKeys(other)
+		for _, n := range names {
+			if terms[strings.ToLower(model.NormalizeCourseName(n))] {
+				return nil, fmt.Errorf("%w: %s（%s）", ErrCourseConflict, n, other.Name)
+			}
+		}
+	}
+
+	course.Code = code
+	course.Name = name
+	course.Department = strings.TrimSpace(req.Department)
+	course.Semester = strings.TrimSpace(req.Semester)
+	course.Description = req.Description
+	return aliases, nil
+}
+
+// courseTermKeys 返回课程名称、代码和别名的比较键（规范化后转小写）
+func courseTermKeys(course *model.Course) map[string]bool {
+	keys := map[string]bool{strings.ToLower(model.NormalizeCourseName(course.Name)): true}
+	if code := strings.TrimSpace(course.Code); code != "" {
+		keys[strings.ToLower(model.NormalizeCourseName(code))] = true
+	}
+	for _, alias := range course.Aliases {
+		keys[strings.ToLower(model.NormalizeCourseName(alias.Alias))] = true
+	}
+	return keys
+}
+
+// linkAndRespond 关联已有资料并返回课程详情
+func (s *courseService) linkAndRespond(ctx context.Context, course *model.Course) (*model.CourseResponse, error) {
+	if _, err := s.courseRepo.LinkMaterials(ctx, course); err != nil {
+		// 关联失败不影响课程本身，资料在下次编辑时也会重新解析
+		fmt.Printf("关联课程资料失败: %v\n", err)
+	}
+
+	responses, err := s.toResponses(ctx, []*model.Course{course})
+	if err != nil {
+		return nil, err
+	}
+	return responses[0], nil
+}
+
+// toResponses 转换为响应并填充资料数
+func (s *courseService) toResponses(ctx context.Context, courses []*model.Course) ([]*model.CourseResponse, error) {
+	ids := make([]uint, 0, len(courses))
+	for _, course := range courses {
+		ids = append(ids, course.ID)
+	}
+	counts, err := s.courseRepo.CountMaterials(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("统计课程资料失败: %w", err)
+	}
+
+	responses := make([]*model.CourseResponse, 0, len(courses))
+	for _, course := range courses {
+		response := course.ToCourseResponse()
+		response.MaterialCount = counts[course.ID]
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// resolveCourse 解析资料的课程：指定 courseID 时校验课程存在，否则按课程名称或别名查找
+// 返回关联的课程（未找到时为 nil）
+func resolveCourse(ctx context.Context, courseRepo repository.CourseRepository, courseID *uint, name string) (*model.Course, error) {
+	if courseID != nil {
+		course, err := courseRepo.FindByID(ctx, *courseID)
+		if err != nil {
+			if errors.Is(err, repository.ErrCourseNotFound) {
+				return nil, ErrCourseNotFound
+			}
+			return nil, fmt.Errorf("获取课程失败: %w", err)
+		}
+		return course, nil
+	}
+
+	course, err := courseRepo.Resolve(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrCourseNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("解析课程失败: %w", err)
+	}
+	return course, nil
+}
+
+// courseIDOf 返回课程ID，课程为 nil 时返回 nil
+func courseIDOf(course *model.Course) *uint {
+	if course == nil {
+		return nil
+	}
+	id := course.ID
+	return &id
+}
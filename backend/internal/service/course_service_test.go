@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/repository"
+)
+
+// memoryCourseRepo 内存中的课程仓库，按名称、代码和别名匹配课程，其余方法调用时 panic
+type memoryCourseRepo struct {
+	repository.CourseRepository
+	courses []*model.Course
+}
+
+func newMemoryCourseRepo() *memoryCourseRepo {
+	return &memoryCourseRepo{courses: []*model.Course{
+		{ID: 1, Name: "高等数学", Aliases: []model.CourseAlias{{Alias: "高数"}, {Alias: "微积分"}}},
+		{ID: 2, Name: "线性代数", Aliases: []model.CourseAlias{{Alias: "线代"}}},
+		{ID: 3, Code: "CS101", Name: "C语言程序设计", Aliases: []model.CourseAlias{{Alias: "C语言"}}},
+	}}
+}
+
+func (r *memoryCourseRepo) FindByID(ctx context.Context, id uint) (*model.Course, error) {
+	for _, course := range r.courses {
+		if course.ID == id {
+			return course, nil
+		}
+	}
+	return nil, repository.ErrCourseNotFound
+}
+
+func (r *memoryCourseRepo) Resolve(ctx context.Context, name string) (*model.Course, error) {
+	courses, _ := r.FindByTerms(ctx, []string{name})
+	if len(courses) == 0 {
+		return nil, repository.ErrCourseNotFound
+	}
+	return courses[0], nil
+}
+
+func (r *memoryCourseRepo) FindByTerms(ctx context.Context, terms []string) ([]*model.Course, error) {
+	var result []*model.Course
+	for _, course := range r.courses {
+		keys := courseTermKeys(course)
+		for _, term := range terms {
+			if key := strings.ToLower(model.NormalizeCourseName(term)); key != "" && keys[key] {
+				result = append(result, course)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryCourseRepo) Create(ctx context.Context, course *model.Course) error {
+	course.ID = uint(len(r.courses) + 1)
+	r.courses = append(r.courses, course)
+	return nil
+}
+
+func (r *memoryCourseRepo) Update(ctx context.Context, course *model.Course, aliases []string) error {
+	course.Aliases = course.Aliases[:0]
+	for _, alias := range aliases {
+		course.Aliases = append(course.Aliases, model.CourseAlias{CourseID: course.ID, Alias: alias})
+	}
+	return nil
+}
+
+func (r *memoryCourseRepo) LinkMaterials(ctx context.Context, course *model.Course) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryCourseRepo) CountMaterials(ctx context.Context, ids []uint) (map[uint]int64, error) {
+	return map[uint]int64{}, nil
+}
+
+func TestResolveCourseByAlias(t *testing.T) {
+	repo := newMemoryCourseRepo()
+	tests := []struct {
+		name string
+		want uint
+	}{
+		{"高等数学", 1},
+		{"高数", 1},
+		{" 线代 ", 2},
+		{"c语言", 3},
+		{"cs101", 3},
+		{"大学物理", 0},
+	}
+	for _, tt := range tests {
+		course, err := resolveCourse(context.Background(), repo, nil, tt.name)
+		if err != nil {
+			t.Fatalf("resolveCourse(%q) 失败: %v", tt.name, err)
+		}
+		var got uint
+		if course != nil {
+			got = course.ID
+		}
+		if got != tt.want {
+			t.Errorf("resolveCourse(%q) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCreateCourseAliasConflict(t *testing.T) {
+	svc := NewCourseService(newMemoryCourseRepo())
+
+	// 别名与其他课程的规范名称相同
+	_, err := svc.CreateCourse(context.Background(), &model.CourseRequest{Name: "高等数学A", Aliases: []string{"线性代数"}})
+	if !errors.Is(err, ErrCourseConflict) {
+		t.Errorf("CreateCourse() 别名与课程名称重复 错误 = %v, want %v", err, ErrCourseConflict)
+	}
+
+	// 名称与其他课程的别名相同（忽略大小写）
+	_, err = svc.CreateCourse(context.Background(), &model.CourseRequest{Name: "c语言"})
+	if !errors.Is(err, ErrCourseConflict) {
+		t.Errorf("CreateCourse() 名称与别名重复 错误 = %v, want %v", err, ErrCourseConflict)
+	}
+
+	if _, err := svc.CreateCourse(context.Background(), &model.CourseRequest{Name: "大学物理", Aliases: []string{"大物"}}); err != nil {
+		t.Errorf("CreateCourse() 失败: %v", err)
+	}
+}
+
+func TestUpdateCourseKeepsOwnAliases(t *testing.T) {
+	svc := NewCourseService(newMemoryCourseRepo())
+
+	// 保留自身的名称和别名不视为重复
+	resp, err := svc.UpdateCourse(context.Background(), 1, &model.CourseRequest{Name: "高等数学", Aliases: []string{"高数", "微积分", "高数A"}})
+	if err != nil {
+		t.Fatalf("UpdateCourse() 失败: %v", err)
+	}
+	if len(resp.Aliases) != 3 {
+		t.Errorf("UpdateCourse() 别名 = %v, want 3 个", resp.Aliases)
+	}
+
+	_, err = svc.UpdateCourse(context.Background(), 1, &model.CourseRequest{Name: "高等数学", Aliases: []string{"线代"}})
+	if !errors.Is(err, ErrCourseConflict) {
+		t.Errorf("UpdateCourse() 别名与其他课程别名重复 错误 = %v, want %v", err, ErrCourseConflict)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/repository"
+)
+
+var (
+	// ErrDownloadQuotaBoostNotFound 临时下载额度提升不存在
+	ErrDownloadQuotaBoostNotFound = errors.New("临时下载额度提升不存在")
+	// ErrInvalidDownloadQuotaBoost 临时下载额度提升参数错误
+	ErrInvalidDownloadQuotaBoost = errors.New("临时下载额度提升参数错误")
+)
+
+// DownloadBoostService 临时下载额度提升服务接口
+type DownloadBoostService interface {
+	// CreateBoost 创建临时提升，对指定用户、指定角色或全体用户生效
+	CreateBoost(ctx context.Context, adminID uint, req *model.CreateDownloadQuotaBoostRequest) (*model.DownloadQuotaBoost, error)
+	// ListBoosts 获取临时提升列表
+	ListBoosts(ctx context.Context, req *model.DownloadQuotaBoostListRequest) ([]*model.DownloadQuotaBoost, int64, error)
+	// DeleteBoost 删除临时提升，立即失效
+	DeleteBoost(ctx context.Context, id uint) error
+}
+
+// downloadBoostService 临时下载额度提升服务实现
+type downloadBoostService struct {
+	boostRepo repository.DownloadQuotaBoostRepository
+	userRepo  repository.UserRepository
+}
+
+// NewDownloadBoostService 创建临时下载额度提升服务实例
+func NewDownloadBoostService(
+	boostRepo repository.DownloadQuotaBoostRepository,
+	userRepo repository.UserRepository,
+) DownloadBoostService {
+	return &downloadBoostService{
+		boostRepo: boostRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// CreateBoost 创建临时提升
+func (s *downloadBoostService) CreateBoost(ctx context.Context, adminID uint, req *model.CreateDownloadQuotaBoostRequest) (*model.DownloadQuotaBoost, error) {
+	if req.ExtraDaily == 0 && req.ExtraWeekly == 0 {
+		return nil, fmt.Errorf("%w: 每日和每周额外次数不能都为 0", ErrInvalidDownloadQuotaBoost)
+	}
+	if req.UserID != nil && req.Role != "" {
+		return nil, fmt.Errorf("%w: 用户和角色只能指定一个", ErrInvalidDownloadQuotaBoost)
+	}
+
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if !req.EndsAt.After(startsAt) || !req.EndsAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: 结束时间必须晚于开始时间和当前时间", ErrInvalidDownloadQuotaBoost)
+	}
+
+	if req.UserID != nil {
+		if _, err := s.userRepo.FindByID(ctx, *req.UserID); err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return nil, fmt.Errorf("%w: 用户不存在", ErrInvalidDownloadQuotaBoost)
+			}
+			return nil, fmt.Errorf("获取用户信息失败: %w", err)
+		}
+	}
+
+	boost := &model.DownloadQuotaBoost{
+		UserID:      req.UserID,
+		Role:        req.Role,
+		ExtraDaily:  req.ExtraDaily,
+		ExtraWeekly: req.ExtraWeekly,
+		StartsAt:    startsAt,
+		EndsAt:      req.EndsAt,
+		Reason:      req.Reason,
+		CreatedBy:   adminID,
+	}
+	if err := s.boostRepo.Create(ctx, boost); err != nil {
+		return nil, fmt.Errorf("创建临时下载额度失败: %w", err)
+	}
+	return boost, nil
+}
+
+// ListBoosts 获取临时提升列表
+func (s *downloadBoostService) ListBoosts(ctx context.Context, req *model.DownloadQuotaBoostListRequest) ([]*model.DownloadQuotaBoost, int64, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	opts := &repository.DownloadQuotaBoostListOptions{UserID: req.UserID}
+	if req.ActiveOnly {
+		now := time.Now()
+		opts.ActiveAt = &now
+	}
+	boosts, total, err := s.boostRepo.List(ctx, page, pageSize, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取临时下载额度失败: %w", err)
+	}
+	return boosts, total, nil
+}
+
+// DeleteBoost 删除临时提升
+func (s *downloadBoostService) DeleteBoost(ctx context.Context, id uint) error {
+	if err := s.boostRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrDownloadQuotaBoostNotFound) {
+			return ErrDownloadQuotaBoostNotFound
+		}
+		return fmt.Errorf("删除临时下载额度失败: %w", err)
+	}
+	return nil
+}
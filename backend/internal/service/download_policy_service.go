@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+)
+
+var (
+	// ErrWeeklyDownloadLimitExceeded 超过每周下载上限
+	ErrWeeklyDownloadLimitExceeded = errors.New("已达到每周下载上限")
+	// ErrDownloadTooFrequent 短时间内下载次数过多
+	ErrDownloadTooFrequent = errors.New("下载过于频繁，请稍后再试")
+)
+
+const (
+	// downloadLimitKeyFormat 各窗口的默认下载限制，如 download_weekly_limit（每日限制沿用 download_daily_limit）
+	downloadLimitKeyFormat = "download_%s_limit"
+	// downloadLimitRoleKeyFormat 按角色配置的下载限制，如 download_daily_limit_committee
+	downloadLimitRoleKeyFormat = "download_%s_limit_%s"
+)
+
+// downloadWindows 检查顺序：先检查最短的窗口，提示用户稍后重试而不是等到明天
+var downloadWindows = []model.DownloadWindow{
+	model.DownloadWindowBurst,
+	model.DownloadWindowDaily,
+	model.DownloadWindowWeekly,
+}
+
+// downloadWindowErrors 各窗口超限时返回的错误
+var downloadWindowErrors = map[model.DownloadWindow]error{
+	model.DownloadWindowBurst:  ErrDownloadTooFrequent,
+	model.DownloadWindowDaily:  ErrDownloadLimitExceeded,
+	model.DownloadWindowWeekly: ErrWeeklyDownloadLimitExceeded,
+}
+
+// GetDownloadQuota 获取下载配额
+func (s *materialService) GetDownloadQuota(ctx context.Context, userID uint) (*model.DownloadQuotaResponse, error) {
+	windows, boosts, err := s.getDownloadQuotaWindows(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.DownloadQuotaResponse{Windows: windows, Boosts: boosts}
+	for _, window := range windows {
+		if window.Window == model.DownloadWindowDaily {
+			resp.Limit = window.Limit
+			resp.Used = window.Used
+			resp.Remaining = window.Remaining
+			resp.Unlimited = window.Unlimited
+		}
+	}
+	return resp, nil
+}
+
+// checkDownloadLimits 检查用户各统计窗口的下载次数是否已达上限
+func (s *materialService) checkDownloadLimits(ctx context.Context, userID uint) error {
+	windows, _, err := s.getDownloadQuotaWindows(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, window := range windows {
+		if !window.Unlimited && window.Remaining <= 0 {
+			return downloadWindowErrors[window.Window]
+		}
+	}
+	return nil
+}
+
+// getDownloadQuotaWindows 计算用户各统计窗口的限制和已下载次数
+// 限制依次取管理员为用户单独设置的值、按角色的配置和默认配置；生效中的临时提升叠加在有限制的窗口上
+func (s *materialService) getDownloadQuotaWindows(ctx context.Context, userID uint) ([]model.DownloadQuotaWindow, []*model.DownloadQuotaBoost, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取用户信息失败: %w", err)
+	}
+
+	now := time.Now()
+	var boosts []*model.DownloadQuotaBoost
+	if s.boostRepo != nil {
+		boosts, err = s.boostRepo.FindActive(ctx, userID, user.Role, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("获取临时下载额度失败: %w", err)
+		}
+	}
+
+	windows := make([]model.DownloadQuotaWindow, 0, len(downloadWindows))
+	for _, window := range downloadWindows {
+		limit, source := s.getDownloadLimit(ctx, user, window)
+		since, resetAt := downloadWindowRange(window, now)
+
+		boost := 0
+		if limit > 0 {
+			for _, b := range boosts {
+				switch window {
+				case model.DownloadWindowDaily:
+					boost += b.ExtraDaily
+				case model.DownloadWindowWeekly:
+					boost += b.ExtraWeekly
+				}
+			}
+		}
+
+		used, err := s.downloadRepo.CountByUserSince(ctx, userID, since)
+		if err != nil {
+			return nil, nil, fmt.Errorf("统计下载次数失败: %w", err)
+		}
+
+		quota := model.DownloadQuotaWindow{
+			Window:    window,
+			Limit:     limit + boost,
+			Boost:     boost,
+			Used:      used,
+			Unlimited: limit <= 0,
+			Source:    source,
+			ResetAt:   resetAt,
+		}
+		quota.Remaining = int64(quota.Limit) - used
+		if quota.Unlimited {
+			quota.Remaining = -1
+		} else if quota.Remaining < 0 {
+			quota.Remaining = 0
+		}
+		windows = append(windows, quota)
+	}
+	return windows, boosts, nil
+}
+
+// getDownloadLimit 获取用户在指定窗口的下载限制，0 表示不限制
+func (s *materialService) getDownloadLimit(ctx context.Context, user *model.User, window model.DownloadWindow) (int, string) {
+	var override *int
+	switch window {
+	case model.DownloadWindowBurst:
+		override = user.DownloadBurstLimit
+	case model.DownloadWindowDaily:
+		override = user.DownloadDailyLimit
+	case model.DownloadWindowWeekly:
+		override = user.DownloadWeeklyLimit
+	}
+	if override != nil && *override >= 0 {
+		return *override, model.DownloadLimitSourceUser
+	}
+
+	if limit, ok := s.getDownloadLimitConfig(fmt.Sprintf(downloadLimitRoleKeyFormat, window, user.Role)); ok {
+		return limit, model.DownloadLimitSourceRole
+	}
+	if window == model.DownloadWindowDaily {
+		return s.getDailyDownloadLimit(ctx), model.DownloadLimitSourceDefault
+	}
+	limit, _ := s.getDownloadLimitConfig(fmt.Sprintf(downloadLimitKeyFormat, window))
+	return limit, model.DownloadLimitSourceDefault
+}
+
+// getDownloadLimitConfig 读取下载限制配置，未配置或格式错误时返回 false
+func (s *materialService) getDownloadLimitConfig(key string) (int, bool) {
+	if s.configRepo == nil {
+		return 0, false
+	}
+
+	config, err := s.configRepo.GetSystemConfig(key)
+	if err != nil {
+		return 0, false
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(config.ConfigValue))
+	if err != nil || limit < 0 {
+		return 0, false
+	}
+	return limit, true
+}
+
+// downloadWindowRange 返回统计窗口的起始时间和重置时间
+func downloadWindowRange(window model.DownloadWindow, now time.Time) (time.Time, time.Time) {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch window {
+	case model.DownloadWindowBurst:
+		return now.Add(-time.Minute), now.Add(time.Minute)
+	case model.DownloadWindowWeekly:
+		// 以周一为一周的开始
+		offset := (int(now.Weekday()) + 6) % 7
+		startOfWeek := startOfDay.AddDate(0, 0, -offset)
+		return startOfWeek, startOfWeek.AddDate(0, 0, 7)
+	}
+	return startOfDay, startOfDay.AddDate(0, 0, 1)
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/repository"
+)
+
+var (
+	// ErrCollectionNotFound 收藏夹不存在
+	ErrCollectionNotFound = errors.New("收藏夹不存在")
+	// ErrInvalidCollection 收藏夹参数错误
+	ErrInvalidCollection = errors.New("收藏夹参数错误")
+	// ErrCollectionLimitExceeded 收藏夹数量或容量超出限制
+	ErrCollectionLimitExceeded = errors.New("收藏夹数量或容量超出限制")
+	// ErrCollectionItemNotFound 收藏夹中不存在该资料
+	ErrCollectionItemNotFound = errors.New("收藏夹中不存在该资料")
+	// ErrCollectionItemExists 资料已在收藏夹中
+	ErrCollectionItemExists = errors.New("资料已在收藏夹中")
+)
+
+// FavoriteCollectionService 收藏夹服务接口
+type FavoriteCollectionService interface {
+	// ListCollections 获取用户的全部收藏夹
+	ListCollections(ctx context.Context, userID uint) ([]*model.CollectionResponse, error)
+	// CreateCollection 创建收藏夹
+	CreateCollection(ctx context.Context, userID uint, req *model.CollectionRequest) (*model.CollectionResponse, error)
+	// UpdateCollection 更新收藏夹名称和描述
+	UpdateCollection(ctx context.Context, userID, collectionID uint, req *model.CollectionRequest) (*model.CollectionResponse, error)
+	// DeleteCollection 删除收藏夹，其中的资料仍保留在收藏中
+	DeleteCollection(ctx context.Context, userID, collectionID uint) error
+	// ReorderCollections 调整收藏夹顺序
+	ReorderCollections(ctx context.Context, userID uint, ids []uint) error
+
+	// ListItems 分页获取收藏夹中的资料
+	ListItems(ctx context.Context, userID, collectionID uint, req *model.CollectionItemListRequest) ([]*model.CollectionItemResponse, int64, error)
+	// AddItem 向收藏夹添加资料，未收藏的资料会同时加入收藏
+	AddItem(ctx context.Context, userID, collectionID uint, req *model.AddCollectionItemRequest) (*model.CollectionItemResponse, error)
+	// UpdateItem 更新收藏夹中资料的备注
+	UpdateItem(ctx context.Context, userID, collectionID, materialID uint, req *model.UpdateCollectionItemRequest) error
+	// RemoveItem 从收藏夹移除资料，不会取消收藏
+	RemoveItem(ctx context.Context, userID, collectionID, materialID uint) error
+	// ReorderItems 调整收藏夹中资料的顺序
+	ReorderItems(ctx context.Context, userID, collectionID uint, materialIDs []uint) error
+	// ListCollectionIDsByMaterial 获取用户包含指定资料的收藏夹ID
+	ListCollectionIDsByMaterial(ctx context.Context, userID, materialID uint) ([]uint, error)
+
+	// ShareCollection 开启公开分享并返回分享令牌，已分享时返回原令牌
+	ShareCollection(ctx context.Context, userID, collectionID uint) (*model.CollectionResponse, error)
+	// UnshareCollection 关闭公开分享，旧链接失效
+	UnshareCollection(ctx context.Context, userID, collectionID uint) error
+	// GetSharedCollection 通过分享令牌获取收藏夹
+	GetSharedCollection(ctx context.Context, token string) (*model.CollectionResponse, error)
+	// ListSharedItems 通过分享令牌分页获取收藏夹中已通过的资料
+	ListSharedItems(ctx context.Context, token string, req *model.CollectionItemListRequest) ([]*model.CollectionItemResponse, int64, error)
+}
+
+// favoriteCollectionService 收藏夹服务实现
+type favoriteCollectionService struct {
+	collectionRepo  repository.FavoriteCollectionRepository
+	favoriteService FavoriteService
+}
+
+// NewFavoriteCollectionService 创建收藏夹服务实例
+func NewFavoriteCollectionService(
+	collectionRepo repository.FavoriteCollectionRepository,
+	favoriteService FavoriteService,
+) FavoriteCollectionService {
+	return &favoriteCollectionService{
+		collectionRepo:  collectionRepo,
+		favoriteService: favoriteService,
+	}
+}
+
+// ListCollections 获取用户的全部收藏夹
+func (s *favoriteCollectionService) ListCollections(ctx context.Context, userID uint) ([]*model.CollectionResponse, error) {
+	collections, err := s.collectionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取收藏夹列表失败: %w", err)
+	}
+
+	responses := make([]*model.CollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		responses = append(responses, collection.ToCollectionResponse(true))
+	}
+	return responses, nil
+}
+
+// CreateCollection 创建收藏夹
+func (s *favoriteCollectionService) CreateCollection(ctx context.Context, userID uint, req *model.CollectionRequest) (*model.CollectionResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: 收藏夹名称不能为空", ErrInvalidCollection)
+	}
+
+	count, err := s.collectionRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("统计收藏夹失败: %w", err)
+	}
+	if count >= model.MaxCollectionsPerUser {
+		return nil, fmt.Errorf("%w: 最多创建 %d 个收藏夹", ErrCollectionLimitExceeded, model.MaxCollectionsPerUser)
+	}
+
+	collection := &model.FavoriteCollection{
+		UserID:      userID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := s.collectionRepo.Create(ctx, collection); err != nil {
+		return nil, fmt.Errorf("创建收藏夹失败: %w", err)
+	}
+	return collection.ToCollectionResponse(true), nil
+}
+
+// UpdateCollection 更新收藏夹名称和描述
+func (s *favoriteCollectionService) UpdateCollection(ctx context.Context, userID, collectionID uint, req *model.CollectionRequest) (*model.CollectionResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: 收藏夹名称不能为空", ErrInvalidCollection)
+	}
+
+	collection, err := s.findOwnCollection(ctx, userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.Name = name
+	collection.Description = strings.TrimSpace(req.Description)
+	if err := s.collectionRepo.Update(ctx, collection); err != nil {
+		return nil, fmt.Errorf("更新收藏夹失败: %w", err)
+	}
+	return collection.ToCollectionResponse(true), nil
+}
+
+// DeleteCollection 删除收藏夹
+func (s *favoriteCollectionService) DeleteCollection(ctx context.Context, userID, collectionID uint) error {
+	if _, err := s.findOwnCollection(ctx, userID, collectionID); err != nil {
+		return err
+	}
+	if err := s.collectionRepo.Delete(ctx, collectionID); err != nil {
+		if errors.Is(err, repository.ErrCollectionNotFound) {
+			return ErrCollectionNotFound
+		}
+		return fmt.Errorf("删除收藏夹失败: %w", err)
+	}
+	return nil
+}
+
+// ReorderCollections 调整收藏夹顺序
+func (s *favoriteCollectionService) ReorderCollections(ctx context.Context, userID uint, ids []uint) error {
+	collections, err := s.collectionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("获取收藏夹列表失败: %w", err)
+	}
+
+	owned := make(map[uint]bool, len(collections))
+	for _, collection := range collections {
+		owned[collection.ID] = true
+	}
+	if err := validateOrder(ids, owned); err != nil {
+		return err
+	}
+
+	if err := s.collectionRepo.Reorder(ctx, userID, ids); err != nil {
+		return fmt.Errorf("调整收藏夹顺序失败: %w", err)
+	}
+	return nil
+}
+
+// ListItems 分页获取收藏夹中的资料
+func (s *favoriteCollectionService) ListItems(ctx context.Context, userID, collectionID uint, req *model.CollectionItemListRequest) ([]*model.CollectionItemResponse, int64, error) {
+	if _, err := s.findOwnCollection(ctx, userID, collectionID); err != nil {
+		return nil, 0, err
+	}
+	return s.listItems(ctx, collectionID, req, false)
+}
+
+// AddItem 向收藏夹添加资料
+func (s *favoriteCollectionService) AddItem(ctx context.Context, userID, collectionID uint, req *model.AddCollectionItemRequest) (*model.CollectionItemResponse, error) {
+	collection, err := s.findOwnCollection(ctx, userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection.ItemCount >= model.MaxItemsPerCollection {
+		return nil, fmt.Errorf("%w: 每个收藏夹最多收录 %d 份资料", ErrCollectionLimitExceeded, model.MaxItemsPerCollection)
+	}
+
+	// 收藏夹建立在收藏之上，未收藏的资料先加入收藏（同时校验资料是否可收藏）
+	if err := s.favoriteService.AddFavorite(ctx, userID, req.MaterialID); err != nil && !errors.Is(err, ErrAlreadyFavorited) {
+		return nil, err
+	}
+
+	item := &model.FavoriteCollectionItem{
+		CollectionID: collectionID,
+		MaterialID:   req.MaterialID,
+		Note:         strings.TrimSpace(req.Note),
+	}
+	if err := s.collectionRepo.AddItem(ctx, item); err != nil {
+		if errors.Is(err, repository.ErrCollectionItemExists) {
+			return nil, ErrCollectionItemExists
+		}
+		return nil, fmt.Errorf("添加到收藏夹失败: %w", err)
+	}
+	return item.ToCollectionItemResponse(), nil
+}
+
+// UpdateItem 更新收藏夹中资料的备注
+func (s *favoriteCollectionService) UpdateItem(ctx context.Context, userID, collectionID, materialID uint, req *model.UpdateCollectionItemRequest) error {
+	if _, err := s.findOwnCollection(ctx, userID, collectionID); err != nil {
+		return err
+	}
+	if err := s.collectionRepo.UpdateItemNote(ctx, collectionID, materialID, strings.TrimSpace(req.Note)); err != nil {
+		if errors.Is(err, repository.ErrCollectionItemNotFound) {
+			return ErrCollectionItemNotFound
+		}
+		return fmt.Errorf("更新备注失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveItem 从收藏夹移除资料
+func (s *favoriteCollectionService) RemoveItem(ctx context.Context, userID, collectionID, materialID uint) error {
+	if _, err := s.findOwnCollection(ctx, userID, collectionID); err != nil {
+		return err
+	}
+	if err := s.collectionRepo.RemoveItem(ctx, collectionID, materialID); err != nil {
+		if errors.Is(err, repository.ErrCollectionItemNotFound) {
+			return ErrCollectionItemNotFound
+		}
+		return fmt.Errorf("从收藏夹移除失败: %w", err)
+	}
+	return nil
+}
+
+// ReorderItems 调整收藏夹中资料的顺序
+func (s *favoriteCollectionService) ReorderItems(ctx context.Context, userID, collectionID uint, materialIDs []uint) error {
+	collection, err := s.findOwnCollection(ctx, userID, collectionID)
+	if err != nil {
+		return err
+	}
+	if len(materialIDs) > collection.ItemCount {
+		return fmt.Errorf("%w: 排序列表包含不在收藏夹中的资料", ErrInvalidCollection)
+	}
+	if err := validateOrder(materialIDs, nil); err != nil {
+		return err
+	}
+
+	if err := s.collectionRepo.ReorderItems(ctx, collectionID, materialIDs); err != nil {
+		return fmt.Errorf("调整资料顺序失败: %w", err)
+	}
+	return nil
+}
+
+// ListCollectionIDsByMaterial 获取用户包含指定资料的收藏夹ID
+func (s *favoriteCollectionService) ListCollectionIDsByMaterial(ctx context.Context, userID, materialID uint) ([]uint, error) {
+	ids, err := s.collectionRepo.ListCollectionIDsByMaterial(ctx, userID, materialID)
+	if err != nil {
+		return nil, fmt.Errorf("获取资料所在收藏夹失败: %w", err)
+	}
+	return ids, nil
+}
+
+// ShareCollection 开启公开分享
+func (s *favoriteCollectionService) ShareCollection(ctx context.Context, userID, collectionID uint) (*model.CollectionResponse, error) {
+	collection, err := s.findOwnCollection(ctx, userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection.IsShared() {
+		return collection.ToCollectionResponse(true), nil
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, fmt.Errorf("生成分享链接失败: %w", err)
+	}
+	collection.ShareToken = &token
+	if err := s.collectionRepo.Update(ctx, collection); err != nil {
+		return nil, fmt.Errorf("开启分享失败: %w", err)
+	}
+	return collection.ToCollectionResponse(true), nil
+}
+
+// UnshareCollection 关闭公开分享
+func (s *favoriteCollectionService) UnshareCollection(ctx context.Context, userID, collectionID uint) error {
+	collection, err := s.findOwnCollection(ctx, userID, collectionID)
+	if err != nil {
+		return err
+	}
+	if !collection.IsShared() {
+		return nil
+	}
+
+	collection.ShareToken = nil
+	if err := s.collectionRepo.Update(ctx, collection); err != nil {
+		return fmt.Errorf("关闭分享失败: %w", err)
+	}
+	return nil
+}
+
+// GetSharedCollection 通过分享令牌获取收藏夹
+func (s *favoriteCollectionService) GetSharedCollection(ctx context.Context, token string) (*model.CollectionResponse, error) {
+	collection, err := s.findSharedCollection(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return collection.ToCollectionResponse(false), nil
+}
+
+// ListSharedItems 通过分享令牌分页获取收藏夹中已通过的资料
+func (s *favoriteCollectionService) ListSharedItems(ctx context.Context, token string, req *model.CollectionItemListRequest) ([]*model.CollectionItemResponse, int64, error) {
+	collection, err := s.findSharedCollection(ctx, token)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.listItems(ctx, collection.ID, req, true)
+}
+
+// listItems 分页获取收藏夹中的资料
+func (s *favoriteCollectionService) listItems(ctx context.Context, collectionID uint, req *model.CollectionItemListRequest, approvedOnly bool) ([]*model.CollectionItemResponse, int64, error) {
+	items, total, err := s.collectionRepo.ListItems(ctx, collectionID, req.Page, req.PageSize, approvedOnly)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取收藏夹资料失败: %w", err)
+	}
+
+	responses := make([]*model.CollectionItemResponse, 0, len(items))
+	for _, item := range items {
+		responses = append(responses, item.ToCollectionItemResponse())
+	}
+	return responses, total, nil
+}
+
+// findOwnCollection 获取收藏夹并校验所有者
+func (s *favoriteCollectionService) findOwnCollection(ctx context.Context, userID, collectionID uint) (*model.FavoriteCollection, error) {
+	collection, err := s.collectionRepo.FindByID(ctx, collectionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCollectionNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, fmt.Errorf("获取收藏夹失败: %w", err)
+	}
+	// 不暴露他人收藏夹是否存在
+	if collection.UserID != userID {
+		return nil, ErrCollectionNotFound
+	}
+	return collection, nil
+}
+
+// findSharedCollection 通过分享令牌获取收藏夹
+func (s *favoriteCollectionService) findSharedCollection(ctx context.Context, token string) (*model.FavoriteCollection, error) {
+	if token == "" {
+		return nil, ErrCollectionNotFound
+	}
+	collection, err := s.collectionRepo.FindByShareToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrCollectionNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, fmt.Errorf("获取收藏夹失败: %w", err)
+	}
+	return collection, nil
+}
+
+// validateOrder 校验排序列表无重复，owned 非空时要求每个ID都属于 owned
+func validateOrder(ids []uint, owned map[uint]bool) error {
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return fmt.Errorf("%w: 排序列表包含重复的ID", ErrInvalidCollection)
+		}
+		if owned != nil && !owned[id] {
+			return fmt.Errorf("%w: 排序列表包含不属于你的收藏夹", ErrInvalidCollection)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// generateShareToken 生成收藏夹分享令牌
+func generateShareToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
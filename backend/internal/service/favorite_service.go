@@ -20,7 +20,7 @@ var (
 type FavoriteService interface {
 	// AddFavorite 添加收藏
 	AddFavorite(ctx context.Context, userID, materialID uint) error
-	// RemoveFavorite 取消收藏
+	// RemoveFavorite 取消收藏，同时从所有收藏夹中移除
 	RemoveFavorite(ctx context.Context, userID, materialID uint) error
 	// ListFavorites 获取用户收藏列表
 	ListFavorites(ctx context.Context, userID uint, page, pageSize int) ([]*model.FavoriteResponse, int64, error)
@@ -30,18 +30,21 @@ type FavoriteService interface {
 
 // favoriteService 收藏服务实现
 type favoriteService struct {
-	favoriteRepo repository.FavoriteRepository
-	materialRepo repository.MaterialRepository
+	favoriteRepo   repository.FavoriteRepository
+	materialRepo   repository.MaterialRepository
+	collectionRepo repository.FavoriteCollectionRepository
 }
 
 // NewFavoriteService 创建收藏服务实例
 func NewFavoriteService(
 	favoriteRepo repository.FavoriteRepository,
 	materialRepo repository.MaterialRepository,
+	collectionRepo repository.FavoriteCollectionRepository,
 ) FavoriteService {
 	return &favoriteService{
-		favoriteRepo: favoriteRepo,
-		materialRepo: materialRepo,
+		favoriteRepo:   favoriteRepo,
+		materialRepo:   materialRepo,
+		collectionRepo: collectionRepo,
 	}
 }
 
@@ -105,6 +108,11 @@ func (s *favoriteService) RemoveFavorite(ctx context.Context, userID, materialID
 		fmt.Printf("减少收藏次数失败: %v\n", err)
 	}
 
+	// 取消收藏后从所有收藏夹中移除
+	if err := s.collectionRepo.RemoveMaterialFromUser(ctx, userID, materialID); err != nil {
+		fmt.Printf("从收藏夹移除资料失败: %v\n", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,602 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/logger"
+	"github.com/study-upc/backend/internal/pkg/oss"
+	"github.com/study-upc/backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrMaterialArchiveRunning 归档任务正在执行
+	ErrMaterialArchiveRunning = errors.New("归档任务正在执行，请稍后再试")
+	// ErrMaterialArchiveRuleNotFound 归档规则不存在
+	ErrMaterialArchiveRuleNotFound = errors.New("归档规则不存在")
+	// ErrInvalidMaterialArchiveRule 归档规则参数错误
+	ErrInvalidMaterialArchiveRule = errors.New("归档规则参数错误")
+	// ErrMaterialNotArchived 资料未归档
+	ErrMaterialNotArchived = errors.New("资料未归档")
+)
+
+const (
+	materialArchiveEnabledKey          = "material_archive_enabled"
+	materialArchiveIntervalHoursKey    = "material_archive_interval_hours"
+	materialArchiveRestoreGraceDaysKey = "material_archive_restore_grace_days"
+
+	defaultMaterialArchiveIntervalHours    = 24
+	defaultMaterialArchiveRestoreGraceDays = 180
+	defaultMaterialArchiveNoticeDays       = 7
+
+	// materialArchivePrefix 归档文件的存储前缀，可在 OSS 上为该前缀配置低频/归档存储的生命周期规则
+	// 不在 materials/ 下，孤立上传文件清理不会扫描到
+	materialArchivePrefix = "archive/"
+	// materialArchiveBatchSize 每条规则每次最多处理的资料数，剩余的留到下次执行
+	materialArchiveBatchSize = 500
+	// materialArchiveMaxReportItems 报告中返回的明细上限
+	materialArchiveMaxReportItems = 200
+)
+
+// MaterialArchiveService 资料归档服务接口
+type MaterialArchiveService interface {
+	// CreateRule 创建归档规则
+	CreateRule(ctx context.Context, adminID uint, req *model.MaterialArchiveRuleRequest) (*model.MaterialArchiveRule, error)
+	// UpdateRule 更新归档规则
+	UpdateRule(ctx context.Context, id uint, req *model.MaterialArchiveRuleRequest) (*model.MaterialArchiveRule, error)
+	// DeleteRule 删除归档规则，已计划归档的资料会在下次执行时取消
+	DeleteRule(ctx context.Context, id uint) error
+	// ListRules 获取全部归档规则
+	ListRules(ctx context.Context) ([]*model.MaterialArchiveRule, error)
+	// Run 执行一次归档：通知新命中规则的资料的上传者，归档到期的资料
+	Run(ctx context.Context, dryRun bool) (*model.MaterialArchiveReport, error)
+	// RestoreMaterial 将已归档的资料恢复为已通过
+	RestoreMaterial(ctx context.Context, materialID uint) (*model.MaterialResponse, error)
+	// Start 启动定时归档任务
+	Start(ctx context.Context)
+	// SetNotificationService 设置通知服务
+	SetNotificationService(notificationSvc NotificationService)
+}
+
+// materialArchiveService 资料归档服务实现
+type materialArchiveService struct {
+	archiveRepo     repository.MaterialArchiveRepository
+	materialRepo    repository.MaterialRepository
+	versionRepo     repository.MaterialVersionRepository
+	categoryRepo    *repository.MaterialCategoryRepository
+	configRepo      repository.SystemConfigRepository
+	ossService      oss.OSSService
+	notificationSvc NotificationService
+	mu              sync.Mutex
+}
+
+// NewMaterialArchiveService 创建资料归档服务实例
+func NewMaterialArchiveService(
+	archiveRepo repository.MaterialArchiveRepository,
+	materialRepo repository.MaterialRepository,
+	versionRepo repository.MaterialVersionRepository,
+	categoryRepo *repository.MaterialCategoryRepository,
+	configRepo repository.SystemConfigRepository,
+	ossService oss.OSSService,
+) MaterialArchiveService {
+	return &materialArchiveService{
+		archiveRepo:  archiveRepo,
+		materialRepo: materialRepo,
+		versionRepo:  versionRepo,
+		categoryRepo: categoryRepo,
+		configRepo:   configRepo,
+		ossService:   ossService,
+	}
+}
+
+// SetNotificationService 设置通知服务
+func (s *materialArchiveService) SetNotificationService(notificationSvc NotificationService) {
+	s.notificationSvc = notificationSvc
+}
+
+// CreateRule 创建归档规则
+func (s *materialArchiveService) CreateRule(ctx context.Context, adminID uint, req *model.MaterialArchiveRuleRequest) (*model.MaterialArchiveRule, error) {
+	rule := &model.MaterialArchiveRule{CreatedBy: adminID}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.archiveRepo.CreateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("创建归档规则失败: %w", err)
+	}
+	return rule, nil
+}
+
+// UpdateRule 更新归档规则
+func (s *materialArchiveService) UpdateRule(ctx context.Context, id uint, req *model.MaterialArchiveRuleRequest) (*model.MaterialArchiveRule, error) {
+	rule, err := s.archiveRepo.FindRuleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialArchiveRuleNotFound) {
+			return nil, ErrMaterialArchiveRuleNotFound
+		}
+		return nil, fmt.Errorf("获取归档规则失败: %w", err)
+	}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.archiveRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("更新归档规则失败: %w", err)
+	}
+	return rule, nil
+}
+
+// applyRuleRequest 校验请求并写入规则
+func (s *materialArchiveService) applyRuleRequest(rule *model.MaterialArchiveRule, req *model.MaterialArchiveRuleRequest) error {
+	switch req.Type {
+	case model.ArchiveRuleAge:
+		if req.AgeMonths < 1 {
+			return fmt.Errorf("%w: 按上传时间归档时 age_months 不能小于 1", ErrInvalidMaterialArchiveRule)
+		}
+	case model.ArchiveRuleInactivity:
+		if req.InactiveMonths < 1 {
+			return fmt.Errorf("%w: 按活跃度归档时 inactive_months 不能小于 1", ErrInvalidMaterialArchiveRule)
+		}
+	case model.ArchiveRuleTerm:
+		if !model.IsValidCourseTerm(req.BeforeTerm) {
+			return fmt.Errorf("%w: before_term 应为 2024-2025-1 的形式", ErrInvalidMaterialArchiveRule)
+		}
+	default:
+		return fmt.Errorf("%w: 不支持的规则类型 %s", ErrInvalidMaterialArchiveRule, req.Type)
+	}
+	if req.Category != "" {
+		if _, err := s.categoryRepo.GetByCode(string(req.Category)); err != nil {
+			return fmt.Errorf("%w: 无效的资料类型 %s", ErrInvalidMaterialArchiveRule, req.Category)
+		}
+	}
+
+	rule.Name = req.Name
+	rule.Type = req.Type
+	rule.Category = req.Category
+	rule.AgeMonths = 0
+	rule.InactiveMonths = 0
+	rule.BeforeTerm = ""
+	switch req.Type {
+	case model.ArchiveRuleAge:
+		rule.AgeMonths = req.AgeMonths
+	case model.ArchiveRuleInactivity:
+		rule.InactiveMonths = req.InactiveMonths
+	case model.ArchiveRuleTerm:
+		rule.BeforeTerm = req.BeforeTerm
+	}
+	rule.Enabled = true
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	rule.NoticeDays = defaultMaterialArchiveNoticeDays
+	if req.NoticeDays != nil {
+		rule.NoticeDays = *req.NoticeDays
+	}
+	rule.TransitionStorage = req.TransitionStorage
+	return nil
+}
+
+// DeleteRule 删除归档规则
+func (s *materialArchiveService) DeleteRule(ctx context.Context, id uint) error {
+	if err := s.archiveRepo.DeleteRule(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrMaterialArchiveRuleNotFound) {
+			return ErrMaterialArchiveRuleNotFound
+		}
+		return fmt.Errorf("删除归档规则失败: %w", err)
+	}
+	return nil
+}
+
+// ListRules 获取全部归档规则
+func (s *materialArchiveService) ListRules(ctx context.Context) ([]*model.MaterialArchiveRule, error) {
+	rules, err := s.archiveRepo.ListRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取归档规则失败: %w", err)
+	}
+	return rules, nil
+}
+
+// Run 执行一次归档
+// 先处理已计划归档的资料（不再命中规则的取消，到期的归档），再为新命中规则的资料通知上传者并计划归档
+func (s *materialArchiveService) Run(ctx context.Context, dryRun bool) (*model.MaterialArchiveReport, error) {
+	if !s.mu.TryLock() {
+		return nil, ErrMaterialArchiveRunning
+	}
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	report := &model.MaterialArchiveReport{
+		DryRun:    dryRun,
+		Items:     make([]*model.MaterialArchiveItem, 0),
+		StartedAt: now,
+	}
+	defer func() { report.FinishedAt = time.Now() }()
+
+	rules, err := s.archiveRepo.ListRules(ctx)
+	if err != nil {
+		return report, fmt.Errorf("获取归档规则失败: %w", err)
+	}
+	enabled := make(map[uint]*model.MaterialArchiveRule)
+	for _, rule := range rules {
+		if rule.Enabled {
+			enabled[rule.ID] = rule
+		}
+	}
+
+	// 从归档恢复的资料在宽限期内不再被自动归档
+	graceDays := s.getIntConfig(materialArchiveRestoreGraceDaysKey, defaultMaterialArchiveRestoreGraceDays, "从归档恢复的资料在多少天内不会再次被自动归档")
+	exemptSince := now.AddDate(0, 0, -graceDays)
+
+	if err := s.processScheduled(ctx, report, enabled, now, exemptSince); err != nil {
+		return report, err
+	}
+
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		report.RuleCount++
+
+		candidates, err := s.archiveRepo.FindCandidates(ctx, rule, now, exemptSince, materialArchiveBatchSize)
+		if err != nil {
+			return report, fmt.Errorf("查找命中归档规则 %d 的资料失败: %w", rule.ID, err)
+		}
+		for _, material := range candidates {
+			if rule.NoticeDays == 0 {
+				s.archive(ctx, report, material, rule)
+				continue
+			}
+			s.schedule(ctx, report, material, rule, now.AddDate(0, 0, rule.NoticeDays))
+		}
+
+		if !dryRun {
+			if err := s.archiveRepo.TouchRule(ctx, rule.ID, now); err != nil {
+				logger.Warn("更新归档规则执行时间失败", zap.Uint("rule_id", rule.ID), zap.Error(err))
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// processScheduled 处理已计划归档的资料
+func (s *materialArchiveService) processScheduled(ctx context.Context, report *model.MaterialArchiveReport, rules map[uint]*model.MaterialArchiveRule, now, exemptSince time.Time) error {
+	scheduled, err := s.archiveRepo.ListScheduled(ctx, materialArchiveBatchSize)
+	if err != nil {
+		return fmt.Errorf("获取计划归档的资料失败: %w", err)
+	}
+
+	idsByRule := make(map[uint][]uint)
+	for _, material := range scheduled {
+		if material.ArchiveRuleID != nil && rules[*material.ArchiveRuleID] != nil {
+			idsByRule[*material.ArchiveRuleID] = append(idsByRule[*material.ArchiveRuleID], material.ID)
+		}
+	}
+
+	// 计划归档后资料可能重新有了下载、被恢复过或规则被修改，归档前重新检查是否仍命中规则
+	matched := make(map[uint]bool)
+	for ruleID, ids := range idsByRule {
+		ruleMatched, err := s.archiveRepo.FilterCandidates(ctx, rules[ruleID], now, exemptSince, ids)
+		if err != nil {
+			return fmt.Errorf("检查归档规则 %d 失败: %w", ruleID, err)
+		}
+		for id := range ruleMatched {
+			matched[id] = true
+		}
+	}
+
+	for _, material := range scheduled {
+		if !matched[material.ID] {
+			s.cancel(ctx, report, material)
+			continue
+		}
+		if material.ArchiveScheduledAt.After(now) {
+			continue
+		}
+		s.archive(ctx, report, material, rules[*material.ArchiveRuleID])
+	}
+	return nil
+}
+
+// schedule 计划归档资料并通知上传者
+func (s *materialArchiveService) schedule(ctx context.Context, report *model.MaterialArchiveReport, material *model.Material, rule *model.MaterialArchiveRule, at time.Time) {
+	if !report.DryRun {
+		ok, err := s.archiveRepo.Schedule(ctx, material.ID, rule.ID, at)
+		if err != nil {
+			logger.Warn("计划归档资料失败", zap.Uint("material_id", material.ID), zap.Error(err))
+			return
+		}
+		if !ok {
+			return
+		}
+		s.notify(ctx, material.UploaderID, "资料即将归档",
+			fmt.Sprintf("您上传的资料《%s》%s，将于 %s 归档。归档后资料不再出现在资料列表和搜索结果中，但仍可通过链接查看和下载；如需保留，请联系管理员。",
+				material.Title, describeArchiveRule(rule), at.Format("2006-01-02")),
+			material.ID)
+	}
+
+	report.NotifiedCount++
+	s.addItem(report, &model.MaterialArchiveItem{
+		MaterialID:  material.ID,
+		Title:       material.Title,
+		RuleID:      rule.ID,
+		Action:      model.ArchiveActionNotified,
+		ScheduledAt: &at,
+	})
+}
+
+// cancel 取消不再命中规则的计划归档
+func (s *materialArchiveService) cancel(ctx context.Context, report *model.MaterialArchiveReport, material *model.Material) {
+	if !report.DryRun {
+		if err := s.archiveRepo.CancelSchedule(ctx, material.ID); err != nil {
+			logger.Warn("取消计划归档失败", zap.Uint("material_id", material.ID), zap.Error(err))
+			return
+		}
+	}
+
+	report.CancelledCount++
+	item := &model.MaterialArchiveItem{
+		MaterialID:  material.ID,
+		Title:       material.Title,
+		Action:      model.ArchiveActionCancelled,
+		ScheduledAt: material.ArchiveScheduledAt,
+	}
+	if material.ArchiveRuleID != nil {
+		item.RuleID = *material.ArchiveRuleID
+	}
+	s.addItem(report, item)
+}
+
+// archive 归档资料，规则要求时先将文件移动到归档存储前缀
+func (s *materialArchiveService) archive(ctx context.Context, report *model.MaterialArchiveReport, material *model.Material, rule *model.MaterialArchiveRule) {
+	item := &model.MaterialArchiveItem{
+		MaterialID: material.ID,
+		Title:      material.Title,
+		RuleID:     rule.ID,
+		Action:     model.ArchiveActionArchived,
+	}
+	if report.DryRun {
+		report.ArchivedCount++
+		s.addItem(report, item)
+		return
+	}
+
+	fail := func(err error) {
+		logger.Warn("归档资料失败", zap.Uint("material_id", material.ID), zap.Error(err))
+		report.FailedCount++
+		item.Action = model.ArchiveActionFailed
+		item.Error = err.Error()
+		s.addItem(report, item)
+	}
+
+	var keyMoves map[string]string
+	if rule.TransitionStorage {
+		var err error
+		keyMoves, err = s.moveFiles(ctx, material.ID, material.FileKey, func(key string) (string, bool) {
+			if strings.HasPrefix(key, materialArchivePrefix) {
+				return "", false
+			}
+			return materialArchivePrefix + key, true
+		})
+		if err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	ruleID := rule.ID
+	archived, err := s.archiveRepo.Archive(ctx, material.ID, &ruleID, keyMoves)
+	if err != nil || !archived {
+		s.revertMoves(ctx, keyMoves)
+		if err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	report.ArchivedCount++
+	s.addItem(report, item)
+	s.notify(ctx, material.UploaderID, "资料已归档",
+		fmt.Sprintf("您上传的资料《%s》%s，已归档。资料不再出现在资料列表和搜索结果中，但仍可通过链接查看和下载。", material.Title, describeArchiveRule(rule)),
+		material.ID)
+}
+
+// RestoreMaterial 将已归档的资料恢复为已通过，已移动到归档存储前缀的文件会移回原位置
+func (s *materialArchiveService) RestoreMaterial(ctx context.Context, materialID uint) (*model.MaterialResponse, error) {
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+	if material.Status != model.StatusArchived {
+		return nil, ErrMaterialNotArchived
+	}
+
+	keyMoves, err := s.moveFiles(ctx, material.ID, material.FileKey, func(key string) (string, bool) {
+		if !strings.HasPrefix(key, materialArchivePrefix) {
+			return "", false
+		}
+		return strings.TrimPrefix(key, materialArchivePrefix), true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("恢复资料文件失败: %w", err)
+	}
+
+	restored, err := s.archiveRepo.Restore(ctx, material.ID, keyMoves)
+	if err != nil || !restored {
+		s.revertMoves(ctx, keyMoves)
+		if err != nil {
+			return nil, fmt.Errorf("恢复资料失败: %w", err)
+		}
+		return nil, ErrMaterialNotArchived
+	}
+
+	s.notify(ctx, material.UploaderID, "资料已恢复",
+		fmt.Sprintf("您上传的资料《%s》已由管理员从归档中恢复，重新出现在资料列表和搜索结果中。", material.Title),
+		material.ID)
+
+	material, err = s.materialRepo.FindByIDWithUploader(ctx, material.ID)
+	if err != nil {
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+	return material.ToMaterialResponse(), nil
+}
+
+// moveFiles 按 target 移动资料及其全部版本的文件，返回 旧存储键 -> 新存储键
+// target 返回 false 的文件不移动；任一文件移动失败时撤销已移动的文件
+func (s *materialArchiveService) moveFiles(ctx context.Context, materialID uint, fileKey string, target func(string) (string, bool)) (map[string]string, error) {
+	versions, err := s.versionRepo.ListByMaterial(ctx, materialID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取资料版本失败: %w", err)
+	}
+	keys := []string{fileKey}
+	for _, version := range versions {
+		keys = append(keys, version.FileKey)
+	}
+
+	moves := make(map[string]string)
+	for _, key := range keys {
+		if _, done := moves[key]; done {
+			continue
+		}
+		dst, ok := target(key)
+		if !ok {
+			continue
+		}
+		if err := s.ossService.MoveFile(ctx, key, dst); err != nil {
+			// 文件已不存在时跳过，不影响资料状态的变更
+			if errors.Is(err, oss.ErrFileNotFound) {
+				continue
+			}
+			s.revertMoves(ctx, moves)
+			return nil, fmt.Errorf("移动文件 %s 失败: %w", key, err)
+		}
+		moves[key] = dst
+	}
+	return moves, nil
+}
+
+// revertMoves 将已移动的文件移回原位置
+func (s *materialArchiveService) revertMoves(ctx context.Context, moves map[string]string) {
+	for src, dst := range moves {
+		if err := s.ossService.MoveFile(ctx, dst, src); err != nil {
+			logger.Warn("撤销文件移动失败", zap.String("file_key", dst), zap.Error(err))
+		}
+	}
+}
+
+// notify 通知上传者
+func (s *materialArchiveService) notify(ctx context.Context, userID uint, title, content string, materialID uint) {
+	if s.notificationSvc == nil {
+		return
+	}
+	notification := &model.Notification{
+		UserID:  userID,
+		Type:    model.NotifyMaterial,
+		Title:   title,
+		Content: content,
+		Status:  model.NotifyUnread,
+		Link:    fmt.Sprintf("/materials/%d", materialID),
+	}
+	if err := s.notificationSvc.CreateNotification(ctx, notification); err != nil {
+		logger.Warn("发送归档通知失败", zap.Uint("user_id", userID), zap.Error(err))
+	}
+}
+
+// addItem 记录报告明细
+func (s *materialArchiveService) addItem(report *model.MaterialArchiveReport, item *model.MaterialArchiveItem) {
+	if len(report.Items) < materialArchiveMaxReportItems {
+		report.Items = append(report.Items, item)
+	}
+}
+
+// describeArchiveRule 归档原因的文字描述
+func describeArchiveRule(rule *model.MaterialArchiveRule) string {
+	switch rule.Type {
+	case model.ArchiveRuleAge:
+		return fmt.Sprintf("已上传超过 %d 个月", rule.AgeMonths)
+	case model.ArchiveRuleInactivity:
+		return fmt.Sprintf("已有 %d 个月无人下载", rule.InactiveMonths)
+	case model.ArchiveRuleTerm:
+		return fmt.Sprintf("开课学期早于 %s", rule.BeforeTerm)
+	}
+	return "命中归档规则"
+}
+
+// Start 启动定时归档任务，每次执行前重新读取系统配置
+func (s *materialArchiveService) Start(ctx context.Context) {
+	go func() {
+		for {
+			interval := s.getIntConfig(materialArchiveIntervalHoursKey, defaultMaterialArchiveIntervalHours, "资料归档任务执行间隔（小时）")
+			if interval <= 0 {
+				interval = defaultMaterialArchiveIntervalHours
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Duration(interval) * time.Hour):
+			}
+
+			if !s.getBoolConfig(materialArchiveEnabledKey, true) {
+				continue
+			}
+
+			report, err := s.Run(ctx, false)
+			if err != nil {
+				logger.Warn("资料归档任务失败", zap.Error(err))
+				continue
+			}
+			logger.Info("资料归档任务完成",
+				zap.Int("rules", report.RuleCount),
+				zap.Int("notified", report.NotifiedCount),
+				zap.Int("archived", report.ArchivedCount),
+				zap.Int("cancelled", report.CancelledCount),
+				zap.Int("failed", report.FailedCount))
+		}
+	}()
+}
+
+// getIntConfig 读取整数类型的系统配置，不存在时写入默认值
+func (s *materialArchiveService) getIntConfig(key string, defaultValue int, description string) int {
+	config, err := s.configRepo.GetSystemConfig(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = s.configRepo.CreateSystemConfig(&model.SystemConfig{
+				ConfigKey:   key,
+				ConfigValue: strconv.Itoa(defaultValue),
+				Description: description,
+				Category:    "material",
+			})
+		}
+		return defaultValue
+	}
+
+	value, err := strconv.Atoi(strings.TrimSpace(config.ConfigValue))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getBoolConfig 读取布尔类型的系统配置
+func (s *materialArchiveService) getBoolConfig(key string, defaultValue bool) bool {
+	config, err := s.configRepo.GetSystemConfig(key)
+	if err != nil {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(strings.TrimSpace(config.ConfigValue))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
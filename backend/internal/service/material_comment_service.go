@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/logger"
+	"github.com/study-upc/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrCommentNotFound 评论不存在
+	ErrCommentNotFound = errors.New("评论不存在")
+	// ErrInvalidComment 评论内容错误
+	ErrInvalidComment = errors.New("评论内容错误")
+	// ErrCommentNotEditable 评论不可编辑
+	ErrCommentNotEditable = errors.New("评论已被隐藏或删除，无法操作")
+)
+
+// commentReplyPreviewSize 评论列表中每条顶层评论附带的回复数
+const commentReplyPreviewSize = 3
+
+// MaterialCommentService 资料评论服务接口
+type MaterialCommentService interface {
+	// ListComments 分页获取资料的顶层评论，每条附带最早的几条回复
+	ListComments(ctx context.Context, materialID, userID uint, isAdmin bool, req *model.CommentListRequest) ([]*model.CommentResponse, int64, error)
+	// ListReplies 分页获取顶层评论的全部回复
+	ListReplies(ctx context.Context, commentID, userID uint, isAdmin bool, req *model.CommentListRequest) ([]*model.CommentResponse, int64, error)
+	// CreateComment 发表评论或回复
+	CreateComment(ctx context.Context, materialID, userID uint, req *model.CreateCommentRequest) (*model.CommentResponse, error)
+	// UpdateComment 编辑评论（仅作者）
+	UpdateComment(ctx context.Context, commentID, userID uint, req *model.UpdateCommentRequest) (*model.CommentResponse, error)
+	// DeleteComment 删除评论（作者或管理员）
+	DeleteComment(ctx context.Context, commentID, userID uint, isAdmin bool) error
+	// ListAllComments 分页获取评论列表（管理员）
+	ListAllComments(ctx context.Context, req *model.AdminCommentListRequest) ([]*model.CommentResponse, int64, error)
+	// ModerateComment 隐藏或恢复评论（管理员）
+	ModerateComment(ctx context.Context, commentID, adminID uint, req *model.ModerateCommentRequest) (*model.CommentResponse, error)
+	// SetNotificationService 设置通知服务
+	SetNotificationService(notificationSvc NotificationService)
+}
+
+// materialCommentService 资料评论服务实现
+type materialCommentService struct {
+	commentRepo     repository.MaterialCommentRepository
+	materialRepo    repository.MaterialRepository
+	notificationSvc NotificationService
+}
+
+// NewMaterialCommentService 创建资料评论服务实例
+func NewMaterialCommentService(
+	commentRepo repository.MaterialCommentRepository,
+	materialRepo repository.MaterialRepository,
+) MaterialCommentService {
+	return &materialCommentService{
+		commentRepo:  commentRepo,
+		materialRepo: materialRepo,
+	}
+}
+
+// SetNotificationService 设置通知服务
+func (s *materialCommentService) SetNotificationService(notificationSvc NotificationService) {
+	s.notificationSvc = notificationSvc
+}
+
+// ListComments 分页获取资料的顶层评论
+func (s *materialCommentService) ListComments(ctx context.Context, materialID, userID uint, isAdmin bool, req *model.CommentListRequest) ([]*model.CommentResponse, int64, error) {
+	material, err := s.findMaterial(ctx, materialID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !material.Status.IsPublic() && material.UploaderID != userID && !isAdmin {
+		return nil, 0, ErrAccessDenied
+	}
+
+	roots, total, err := s.commentRepo.ListRoots(ctx, materialID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取评论列表失败: %w", err)
+	}
+
+	rootIDs := make([]uint, 0, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+	}
+	replies, err := s.commentRepo.ListReplyPreviews(ctx, rootIDs, commentReplyPreviewSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取评论回复失败: %w", err)
+	}
+
+	responses := make([]*model.CommentResponse, 0, len(roots))
+	for _, root := range roots {
+		response := root.ToCommentResponse(isAdmin)
+		for _, reply := range replies[root.ID] {
+			response.Replies = append(response.Replies, reply.ToCommentResponse(isAdmin))
+		}
+		responses = append(responses, response)
+	}
+	return responses, total, nil
+}
+
+// ListReplies 分页获取顶层评论的全部回复
+func (s *materialCommentService) ListReplies(ctx context.Context, commentID, userID uint, isAdmin bool, req *model.CommentListRequest) ([]*model.CommentResponse, int64, error) {
+	root, err := s.findComment(ctx, commentID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if root.RootID != nil {
+		return nil, 0, fmt.Errorf("%w: 只能查看顶层评论的回复", ErrInvalidComment)
+	}
+
+	material, err := s.findMaterial(ctx, root.MaterialID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !material.Status.IsPublic() && material.UploaderID != userID && !isAdmin {
+		return nil, 0, ErrAccessDenied
+	}
+
+	replies, total, err := s.commentRepo.ListReplies(ctx, root.ID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取评论回复失败: %w", err)
+	}
+
+	responses := make([]*model.CommentResponse, 0, len(replies))
+	for _, reply := range replies {
+		responses = append(responses, reply.ToCommentResponse(isAdmin))
+	}
+	return responses, total, nil
+}
+
+// CreateComment 发表评论或回复
+func (s *materialCommentService) CreateComment(ctx context.Context, materialID, userID uint, req *model.CreateCommentRequest) (*model.CommentResponse, error) {
+	content, err := normalizeCommentContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	material, err := s.findMaterial(ctx, materialID)
+	if err != nil {
+		return nil, err
+	}
+	// 只能评论已审核通过的资料，已归档的资料不再接受新评论
+	if material.Status != model.StatusApproved {
+		return nil, ErrAccessDenied
+	}
+
+	comment := &model.MaterialComment{
+		MaterialID: materialID,
+		UserID:     userID,
+		Content:    content,
+		Status:     model.CommentVisible,
+	}
+
+	var parent *model.MaterialComment
+	if req.ParentID != nil {
+		parent, err = s.findComment(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.MaterialID != materialID {
+			return nil, fmt.Errorf("%w: 回复的评论不属于该资料", ErrInvalidComment)
+		}
+		if parent.Status != model.CommentVisible {
+			return nil, ErrCommentNotEditable
+		}
+
+		// 回复统一挂在顶层评论下，只保留两层结构
+		rootID := parent.ID
+		if parent.RootID != nil {
+			rootID = *parent.RootID
+		}
+		comment.RootID = &rootID
+		comment.ParentID = &parent.ID
+		comment.ReplyToUserID = &parent.UserID
+	}
+
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, fmt.Errorf("发表评论失败: %w", err)
+	}
+
+	if parent != nil {
+		if parent.UserID != userID {
+			s.notify(ctx, parent.UserID, "评论收到回复",
+				fmt.Sprintf("您在资料《%s》下的评论收到了新回复", material.Title), materialID)
+		}
+	} else if material.UploaderID != userID {
+		s.notify(ctx, material.UploaderID, "资料收到评论",
+			fmt.Sprintf("您上传的资料《%s》收到了新评论", material.Title), materialID)
+	}
+
+	created, err := s.findComment(ctx, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	return created.ToCommentResponse(false), nil
+}
+
+// UpdateComment 编辑评论
+func (s *materialCommentService) UpdateComment(ctx context.Context, commentID, userID uint, req *model.UpdateCommentRequest) (*model.CommentResponse, error) {
+	content, err := normalizeCommentContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.findComment(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, ErrAccessDenied
+	}
+	if comment.Status != model.CommentVisible {
+		return nil, ErrCommentNotEditable
+	}
+	if comment.Content == content {
+		return comment.ToCommentResponse(false), nil
+	}
+
+	now := time.Now()
+	comment.Content = content
+	comment.EditedAt = &now
+	if err := s.commentRepo.UpdateContent(ctx, comment); err != nil {
+		return nil, fmt.Errorf("编辑评论失败: %w", err)
+	}
+	return comment.ToCommentResponse(false), nil
+}
+
+// DeleteComment 删除评论
+// 仍有回复的顶层评论只标记为已删除并清空内容，保留回复的上下文
+func (s *materialCommentService) DeleteComment(ctx context.Context, commentID, userID uint, isAdmin bool) error {
+	comment, err := s.findComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID && !isAdmin {
+		return ErrAccessDenied
+	}
+
+	if comment.RootID == nil {
+		replies, err := s.commentRepo.CountReplies(ctx, comment.ID)
+		if err != nil {
+			return fmt.Errorf("获取评论回复失败: %w", err)
+		}
+		if replies > 0 {
+			if comment.Status == model.CommentDeleted {
+				return nil
+			}
+			if err := s.commentRepo.SetStatus(ctx, comment.ID, model.CommentDeleted, map[string]interface{}{"content": ""}); err != nil {
+				return s.wrapRepoError(err, "删除评论失败")
+			}
+			return nil
+		}
+	}
+
+	if err := s.commentRepo.Delete(ctx, comment.ID); err != nil {
+		return s.wrapRepoError(err, "删除评论失败")
+	}
+
+	// 最后一条回复被删除后，一并清理已删除的顶层评论占位
+	if comment.RootID != nil {
+		s.cleanupDeletedRoot(ctx, *comment.RootID)
+	}
+	return nil
+}
+
+// ListAllComments 分页获取评论列表（管理员）
+func (s *materialCommentService) ListAllComments(ctx context.Context, req *model.AdminCommentListRequest) ([]*model.CommentResponse, int64, error) {
+	comments, total, err := s.commentRepo.List(ctx, repository.CommentListOptions{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		MaterialID: req.MaterialID,
+		UserID:     req.UserID,
+		Status:     req.Status,
+		Keyword:    strings.TrimSpace(req.Keyword),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取评论列表失败: %w", err)
+	}
+
+	responses := make([]*model.CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		responses = append(responses, comment.ToCommentResponse(true))
+	}
+	return responses, total, nil
+}
+
+// ModerateComment 隐藏或恢复评论
+func (s *materialCommentService) ModerateComment(ctx context.Context, commentID, adminID uint, req *model.ModerateCommentRequest) (*model.CommentResponse, error) {
+	comment, err := s.findComment(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Status == model.CommentDeleted {
+		return nil, ErrCommentNotEditable
+	}
+
+	if req.Hidden {
+		if err := hideComment(ctx, s.commentRepo, comment.ID, adminID, req.Reason); err != nil {
+			return nil, s.wrapRepoError(err, "隐藏评论失败")
+		}
+		content := "您的一条评论已被管理员隐藏"
+		if req.Reason != "" {
+			content += "，原因：" + req.Reason
+		}
+		s.notify(ctx, comment.UserID, "评论已被隐藏", content, comment.MaterialID)
+	} else {
+		if err := s.commentRepo.SetStatus(ctx, comment.ID, model.CommentVisible, map[string]interface{}{
+			"hidden_by":     nil,
+			"hidden_at":     nil,
+			"hidden_reason": "",
+		}); err != nil {
+			return nil, s.wrapRepoError(err, "恢复评论失败")
+		}
+	}
+
+	updated, err := s.findComment(ctx, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToCommentResponse(true), nil
+}
+
+// cleanupDeletedRoot 删除已无回复的已删除顶层评论
+func (s *materialCommentService) cleanupDeletedRoot(ctx context.Context, rootID uint) {
+	root, err := s.commentRepo.FindByID(ctx, rootID)
+	if err != nil || root.Status != model.CommentDeleted {
+		return
+	}
+	replies, err := s.commentRepo.CountReplies(ctx, rootID)
+	if err != nil || replies > 0 {
+		return
+	}
+	if err := s.commentRepo.Delete(ctx, rootID); err != nil {
+		logger.Warn("清理已删除的顶层评论失败", zap.Uint("comment_id", rootID), zap.Error(err))
+	}
+}
+
+// findMaterial 获取资料
+func (s *materialCommentService) findMaterial(ctx context.Context, materialID uint) (*model.Material, error) {
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+	return material, nil
+}
+
+// findComment 获取评论
+func (s *materialCommentService) findComment(ctx context.Context, commentID uint) (*model.MaterialComment, error) {
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, s.wrapRepoError(err, "获取评论失败")
+	}
+	return comment, nil
+}
+
+// wrapRepoError 转换评论数据层错误
+func (s *materialCommentService) wrapRepoError(err error, action string) error {
+	if errors.Is(err, repository.ErrCommentNotFound) {
+		return ErrCommentNotFound
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// notify 发送评论相关通知
+func (s *materialCommentService) notify(ctx context.Context, userID uint, title, content string, materialID uint) {
+	if s.notificationSvc == nil {
+		return
+	}
+	notification := &model.Notification{
+		UserID:  userID,
+		Type:    model.NotifyComment,
+		Title:   title,
+		Content: content,
+		Status:  model.NotifyUnread,
+		Link:    fmt.Sprintf("/materials/%d", materialID),
+	}
+	if err := s.notificationSvc.CreateNotification(ctx, notification); err != nil {
+		logger.Warn("发送评论通知失败", zap.Uint("user_id", userID), zap.Error(err))
+	}
+}
+
+// normalizeCommentContent 去除首尾空白并校验评论长度
+func normalizeCommentContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", fmt.Errorf("%w: 评论内容不能为空", ErrInvalidComment)
+	}
+	if utf8.RuneCountInString(content) > model.MaxCommentLength {
+		return "", fmt.Errorf("%w: 评论不能超过 %d 个字符", ErrInvalidComment, model.MaxCommentLength)
+	}
+	return content, nil
+}
+
+// hideComment 由管理员隐藏评论，评论管理和举报处理共用
+func hideComment(ctx context.Context, commentRepo repository.MaterialCommentRepository, commentID, adminID uint, reason string) error {
+	return commentRepo.SetStatus(ctx, commentID, model.CommentHidden, map[string]interface{}{
+		"hidden_by":     adminID,
+		"hidden_at":     time.Now(),
+		"hidden_reason": reason,
+	})
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/oss"
+)
+
+const (
+	duplicateAutoRejectKey = "duplicate_auto_reject"
+	// contentHashCacheTTL 文件哈希缓存有效期，确认上传与创建资料之间复用同一次计算结果
+	contentHashCacheTTL = 24 * time.Hour
+)
+
+// contentHash 计算已上传文件的 SHA-256
+// 结果按 file_key + ETag 缓存，文件被覆盖后 ETag 变化会重新计算；计算失败时返回空字符串，不阻断上传
+func (s *materialService) contentHash(ctx context.Context, info *oss.ObjectInfo) string {
+	cacheKey := fmt.Sprintf("upload:hash:%s", info.Key)
+	etag := strings.Trim(info.ETag, `"`)
+
+	if s.redisClient != nil {
+		if cached, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
+			if cachedETag, hash, ok := strings.Cut(cached, ":"); ok && cachedETag == etag {
+				return hash
+			}
+		}
+	}
+
+	hash, err := s.ossService.HashFile(ctx, info.Key)
+	if err != nil {
+		fmt.Printf("计算文件哈希失败: file_key=%s, err=%v\n", info.Key, err)
+		return ""
+	}
+
+	if s.redisClient != nil {
+		_ = s.redisClient.Set(ctx, cacheKey, etag+":"+hash, contentHashCacheTTL).Err()
+	}
+	return hash
+}
+
+// findDuplicate 查找与指定哈希内容相同的已通过资料，不存在时返回 nil
+func (s *materialService) findDuplicate(ctx context.Context, hash string) *model.Material {
+	if hash == "" {
+		return nil
+	}
+
+	duplicates, err := s.materialRepo.FindApprovedByContentHashes(ctx, []string{hash})
+	if err != nil {
+		fmt.Printf("查询重复资料失败: hash=%s, err=%v\n", hash, err)
+		return nil
+	}
+	return duplicates[hash]
+}
+
+// attachDuplicates 为列表中的资料标记内容相同的已通过资料
+func (s *materialService) attachDuplicates(ctx context.Context, responses []*model.MaterialResponse) {
+	hashes := make([]string, 0, len(responses))
+	for _, response := range responses {
+		if response.ContentHash != "" {
+			hashes = append(hashes, response.ContentHash)
+		}
+	}
+	if len(hashes) == 0 {
+		return
+	}
+
+	duplicates, err := s.materialRepo.FindApprovedByContentHashes(ctx, hashes)
+	if err != nil {
+		fmt.Printf("查询重复资料失败: %v\n", err)
+		return
+	}
+
+	for _, response := range responses {
+		if original, ok := duplicates[response.ContentHash]; ok && original.ID != response.ID {
+			response.DuplicateOf = &model.DuplicateMaterialInfo{
+				ID:    original.ID,
+				Title: original.Title,
+			}
+		}
+	}
+}
+
+// isDuplicateAutoRejectEnabled 是否自动拒绝与已通过资料内容相同的上传
+func (s *materialService) isDuplicateAutoRejectEnabled() bool {
+	if s.configRepo == nil {
+		return false
+	}
+
+	config, err := s.configRepo.GetSystemConfig(duplicateAutoRejectKey)
+	if err != nil {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(strings.TrimSpace(config.ConfigValue))
+	if err != nil {
+		return false
+	}
+	return enabled
+}
+
+// duplicateRejectionReason 生成重复资料的拒绝原因，附带原资料链接
+func duplicateRejectionReason(original *model.Material) string {
+	return fmt.Sprintf("与已有资料《%s》内容完全相同，请直接查看原资料：/materials/%d", original.Title, original.ID)
+}
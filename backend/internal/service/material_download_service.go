@@ -0,0 +1,356 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/document"
+	"github.com/study-upc/backend/internal/repository"
+)
+
+var (
+	// ErrDownloadTokenInvalid 下载令牌不存在或已过期
+	ErrDownloadTokenInvalid = errors.New("下载链接无效或已过期")
+	// ErrDownloadTokenUsed 下载令牌已被使用
+	ErrDownloadTokenUsed = errors.New("下载链接已被使用")
+	// ErrDownloadTokenIPMismatch 下载令牌绑定的 IP 与当前请求不一致
+	ErrDownloadTokenIPMismatch = errors.New("下载链接仅限申请时的网络环境使用")
+)
+
+const (
+	// downloadModeKey 下载方式配置：presigned 允许客户端自行选择，stream 强制所有下载经后端代理
+	downloadModeKey = "download_mode"
+	// downloadWatermarkKey 为 true 时代理下载的 PDF 一律添加水印
+	downloadWatermarkKey = "download_watermark_enabled"
+	// downloadBindIPKey 为 true 时下载令牌绑定申请时的客户端 IP
+	downloadBindIPKey = "download_token_bind_ip"
+
+	downloadTokenPrefix = "download:token:"
+	downloadTokenTTL    = 5 * time.Minute
+	// downloadRedirectExpiry 兑换令牌后生成的预签名 URL 的有效期，只需覆盖浏览器跟随重定向发起请求
+	downloadRedirectExpiry = 2 * time.Minute
+	// watermarkMaxFileSize 添加水印需要将文件完整读入内存，超过该大小的文件不加水印
+	watermarkMaxFileSize = 50 * 1024 * 1024
+)
+
+// DownloadStream 代理下载的文件
+type DownloadStream struct {
+	Content  io.ReadSeekCloser
+	FileName string
+	MimeType string
+	Size     int64
+	ModTime  time.Time
+	ETag     string // 添加水印后内容因人而异，不返回 ETag
+	// Watermarked 内容已添加水印，不支持 Range 请求：每次请求都需要读取并处理整个文件，只允许完整下载一次
+	Watermarked bool
+}
+
+// downloadToken 下载令牌中保存的信息
+// 令牌本身是随机字符串，不包含任何可推断的信息；申请链接时不计入下载次数，兑换时才记录下载
+type downloadToken struct {
+	Mode       string    `json:"mode"`
+	MaterialID uint      `json:"material_id"`
+	UserID     uint      `json:"user_id"`
+	VersionID  *uint     `json:"version_id,omitempty"`
+	FileKey    string    `json:"file_key"`
+	FileName   string    `json:"file_name"`
+	MimeType   string    `json:"mime_type"`
+	Privileged bool      `json:"privileged,omitempty"` // 管理员或上传者，兑换时不要求资料已审核通过
+	ClientIP   string    `json:"client_ip,omitempty"`  // 开启 IP 绑定时为申请时的客户端 IP
+	Watermark  bool      `json:"watermark,omitempty"`
+	IssuedAt   time.Time `json:"issued_at"`
+}
+
+// GetDownloadLink 按请求和系统配置的下载方式获取资料当前版本的下载链接
+func (s *materialService) GetDownloadLink(ctx context.Context, materialID, userID uint, clientIP string, req *model.DownloadLinkRequest) (*model.DownloadLinkResponse, error) {
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+	return s.issueDownloadLink(ctx, material, nil, userID, false, clientIP, req)
+}
+
+// issueDownloadLink 检查下载权限和下载次数并签发下载令牌，当前版本和历史版本的下载都经过这里
+// version 为 nil 时下载资料当前版本；privileged 为管理员或上传者，可以下载未公开的资料和未通过的版本。
+// 下载方式和水印按系统配置和请求确定，系统配置为 stream 时忽略客户端的选择，避免通过预签名 URL 绕过代理下载和水印
+func (s *materialService) issueDownloadLink(ctx context.Context, material *model.Material, version *model.MaterialVersion, userID uint, privileged bool, clientIP string, req *model.DownloadLinkRequest) (*model.DownloadLinkResponse, error) {
+	// 普通用户只能下载已公开资料的已审核通过版本
+	if !privileged && (!material.Status.IsPublic() || (version != nil && version.Status != model.StatusApproved)) {
+		return nil, ErrAccessDenied
+	}
+	// 提前检查以便及时提示，下载次数在兑换令牌时才记录
+	if err := s.checkDownloadLimits(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	token := &downloadToken{
+		MaterialID: material.ID,
+		UserID:     userID,
+		VersionID:  material.CurrentVersionID,
+		FileKey:    material.FileKey,
+		FileName:   material.FileName,
+		MimeType:   material.MimeType,
+		Privileged: privileged,
+	}
+	if version != nil {
+		token.VersionID = &version.ID
+		token.FileKey = version.FileKey
+		token.FileName = version.FileName
+		token.MimeType = version.MimeType
+	}
+
+	token.Mode = s.getDownloadMode()
+	if token.Mode != model.DownloadModeStream && req.Mode != "" {
+		token.Mode = req.Mode
+	}
+	token.Watermark = token.Mode == model.DownloadModeStream && isPDFMimeType(token.MimeType) &&
+		(req.Watermark || s.isConfigEnabled(downloadWatermarkKey))
+	return s.issueDownloadToken(ctx, token, clientIP)
+}
+
+// issueDownloadToken 生成下载令牌并保存到 Redis
+func (s *materialService) issueDownloadToken(ctx context.Context, token *downloadToken, clientIP string) (*model.DownloadLinkResponse, error) {
+	token.IssuedAt = time.Now()
+	if s.isConfigEnabled(downloadBindIPKey) {
+		token.ClientIP = clientIP
+	}
+	value, err := json.Marshal(token)
+	if err != nil {
+		return nil, fmt.Errorf("生成下载令牌失败: %w", err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("生成下载令牌失败: %w", err)
+	}
+	tokenID := hex.EncodeToString(buf)
+	if err := s.redisClient.Set(ctx, downloadTokenPrefix+tokenID, value, downloadTokenTTL).Err(); err != nil {
+		return nil, fmt.Errorf("保存下载令牌失败: %w", err)
+	}
+
+	return &model.DownloadLinkResponse{
+		Mode:      token.Mode,
+		Token:     tokenID,
+		ExpiresIn: int(downloadTokenTTL.Seconds()),
+		Watermark: token.Watermark,
+	}, nil
+}
+
+// loadDownloadToken 读取指定下载方式的令牌并校验绑定的 IP
+func (s *materialService) loadDownloadToken(ctx context.Context, tokenID, mode, clientIP string) (*downloadToken, error) {
+	value, err := s.redisClient.Get(ctx, downloadTokenPrefix+tokenID).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrDownloadTokenInvalid
+		}
+		return nil, fmt.Errorf("读取下载令牌失败: %w", err)
+	}
+	var token downloadToken
+	if err := json.Unmarshal(value, &token); err != nil || token.Mode != mode {
+		return nil, ErrDownloadTokenInvalid
+	}
+	if token.ClientIP != "" && token.ClientIP != clientIP {
+		return nil, ErrDownloadTokenIPMismatch
+	}
+	return &token, nil
+}
+
+// checkDownloadToken 兑换令牌时再次检查资料状态和下载次数
+func (s *materialService) checkDownloadToken(ctx context.Context, token *downloadToken) error {
+	material, err := s.materialRepo.FindByID(ctx, token.MaterialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return ErrMaterialNotFound
+		}
+		return fmt.Errorf("获取资料失败: %w", err)
+	}
+	if !token.Privileged && !material.Status.IsPublic() {
+		return ErrAccessDenied
+	}
+	// 申请后可能又申请了其他链接，兑换时才真正检查并计入下载次数
+	return s.checkDownloadLimits(ctx, token.UserID)
+}
+
+// RedeemDownloadToken 兑换预签名下载令牌，返回短时有效的预签名 URL
+// 令牌使用 GETDEL 原子地读取并删除，只能兑换一次
+func (s *materialService) RedeemDownloadToken(ctx context.Context, tokenID, clientIP string) (string, error) {
+	token, err := s.loadDownloadToken(ctx, tokenID, model.DownloadModePresigned, clientIP)
+	if err != nil {
+		return "", err
+	}
+	if err := s.redisClient.GetDel(ctx, downloadTokenPrefix+tokenID).Err(); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrDownloadTokenUsed
+		}
+		return "", fmt.Errorf("读取下载令牌失败: %w", err)
+	}
+
+	if err := s.checkDownloadToken(ctx, token); err != nil {
+		return "", err
+	}
+	downloadURL, err := s.ossService.GenerateDownloadSignatureWithExpiry(ctx, token.FileKey, downloadRedirectExpiry)
+	if err != nil {
+		return "", fmt.Errorf("生成下载签名失败: %w", err)
+	}
+
+	s.recordDownload(ctx, token.UserID, token.MaterialID, token.VersionID)
+	return downloadURL, nil
+}
+
+// OpenDownloadStream 使用一次性令牌打开代理下载的文件
+// 令牌首次使用时绑定客户端 IP 并记录下载；有效期内同一 IP 的 Range 请求（断点续传、
+// 浏览器分段加载）可以继续使用，不重复计数，其余重复使用均被拒绝。
+// 添加水印的下载每次都要读取并处理整个文件，不允许续传，令牌只能使用一次
+func (s *materialService) OpenDownloadStream(ctx context.Context, tokenID, clientIP string, resume bool) (*DownloadStream, error) {
+	token, err := s.loadDownloadToken(ctx, tokenID, model.DownloadModeStream, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	usedKey := downloadTokenPrefix + tokenID + ":ip"
+	firstUse, err := s.redisClient.SetNX(ctx, usedKey, clientIP, downloadTokenTTL).Result()
+	if err != nil {
+		return nil, fmt.Errorf("读取下载令牌失败: %w", err)
+	}
+	if !firstUse {
+		boundIP, err := s.redisClient.Get(ctx, usedKey).Result()
+		if err != nil || !resume || boundIP != clientIP {
+			return nil, ErrDownloadTokenUsed
+		}
+	}
+	recorded := false
+	defer func() {
+		// 首次使用但未能成功打开文件时释放令牌，允许用户重试
+		if firstUse && !recorded {
+			s.redisClient.Del(ctx, usedKey)
+		}
+	}()
+
+	if firstUse {
+		if err := s.checkDownloadToken(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := s.ossService.StatFile(ctx, token.FileKey)
+	if err != nil {
+		return nil, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+	watermark := token.Watermark && info.Size <= watermarkMaxFileSize
+	if watermark && !firstUse {
+		return nil, ErrDownloadTokenUsed
+	}
+	reader, err := s.ossService.OpenFile(ctx, token.FileKey)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	stream := &DownloadStream{
+		FileName: token.FileName,
+		MimeType: token.MimeType,
+		Size:     info.Size,
+		ModTime:  info.LastModified,
+		ETag:     info.ETag,
+	}
+	if watermark {
+		stream.Content, stream.Size, err = s.watermarkDownload(ctx, reader, token)
+		stream.ETag = ""
+		stream.Watermarked = true
+	} else if seeker, ok := reader.(io.ReadSeekCloser); ok {
+		stream.Content = seeker
+	} else {
+		stream.Content, stream.Size, err = readAllSeeker(reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if firstUse {
+		s.recordDownload(ctx, token.UserID, token.MaterialID, token.VersionID)
+		recorded = true
+	}
+	return stream, nil
+}
+
+// watermarkDownload 为 PDF 添加用户名、用户 ID 和签发日期水印，失败时返回原文件
+func (s *materialService) watermarkDownload(ctx context.Context, reader io.ReadCloser, token *downloadToken) (io.ReadSeekCloser, int64, error) {
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, 0, fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	username := ""
+	if user, err := s.userRepo.FindByID(ctx, token.UserID); err == nil {
+		username = user.Username
+	}
+	text := fmt.Sprintf("%s (ID %d) %s", username, token.UserID, token.IssuedAt.Format("2006-01-02"))
+	stamped, err := document.WatermarkPDF(data, text)
+	if err != nil {
+		fmt.Printf("添加水印失败，返回原文件: material_id=%d, %v\n", token.MaterialID, err)
+		stamped = data
+	}
+	return nopSeekCloser{bytes.NewReader(stamped)}, int64(len(stamped)), nil
+}
+
+// getDownloadMode 获取系统配置的下载方式，默认为预签名 URL
+func (s *materialService) getDownloadMode() string {
+	if s.configRepo == nil {
+		return model.DownloadModePresigned
+	}
+	config, err := s.configRepo.GetSystemConfig(downloadModeKey)
+	if err != nil || strings.TrimSpace(config.ConfigValue) != model.DownloadModeStream {
+		return model.DownloadModePresigned
+	}
+	return model.DownloadModeStream
+}
+
+// isConfigEnabled 布尔类型的系统配置是否开启，未配置时视为关闭
+func (s *materialService) isConfigEnabled(key string) bool {
+	if s.configRepo == nil {
+		return false
+	}
+	config, err := s.configRepo.GetSystemConfig(key)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(config.ConfigValue), "true")
+}
+
+// isPDFMimeType 是否为 PDF 文件
+func isPDFMimeType(mimeType string) bool {
+	return strings.EqualFold(mimeType, "application/pdf")
+}
+
+// readAllSeeker 将不支持随机读取的文件读入内存
+func readAllSeeker(reader io.ReadCloser) (io.ReadSeekCloser, int64, error) {
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, 0, fmt.Errorf("读取文件失败: %w", err)
+	}
+	return nopSeekCloser{bytes.NewReader(data)}, int64(len(data)), nil
+}
+
+// nopSeekCloser 为内存中的内容提供空的 Close
+type nopSeekCloser struct {
+	*bytes.Reader
+}
+
+// Close 实现 io.Closer
+func (nopSeekCloser) Close() error {
+	return nil
+}
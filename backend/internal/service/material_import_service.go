@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/importer"
+	"github.com/study-upc/backend/internal/pkg/logger"
+	"github.com/study-upc/backend/internal/pkg/oss"
+	"github.com/study-upc/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrMaterialImportNotFound 批量导入任务不存在
+	ErrMaterialImportNotFound = errors.New("批量导入任务不存在")
+	// ErrInvalidImportArchive 导入文件不是 ZIP 压缩包
+	ErrInvalidImportArchive = errors.New("批量导入文件必须是 ZIP 压缩包")
+)
+
+const (
+	materialImportPollInterval = 10 * time.Second
+	// materialImportStaleTimeout 执行中的任务超过该时间未完成，视为进程异常退出遗留的任务继续执行
+	materialImportStaleTimeout = 30 * time.Minute
+)
+
+// MaterialImportService 资料批量导入服务接口
+type MaterialImportService interface {
+	// CreateImport 使用已上传的 ZIP 压缩包创建导入任务，由后台逐行创建待审核资料
+	CreateImport(ctx context.Context, userID uint, req *model.CreateMaterialImportRequest) (*model.MaterialImport, error)
+	// GetImport 获取导入任务及每行的导入结果
+	GetImport(ctx context.Context, importID, userID uint, userRole string) (*model.MaterialImport, error)
+	// ListImports 获取用户的导入任务列表
+	ListImports(ctx context.Context, userID uint, req *model.MaterialImportListRequest) ([]*model.MaterialImport, int64, error)
+	// ProcessPending 执行一个待执行的导入任务，返回是否执行了任务
+	ProcessPending(ctx context.Context) (bool, error)
+	// Start 启动后台导入处理
+	Start(ctx context.Context)
+}
+
+// materialImportService 资料批量导入服务实现
+type materialImportService struct {
+	importRepo      repository.MaterialImportRepository
+	categoryRepo    *repository.MaterialCategoryRepository
+	materialService MaterialService
+	ossService      oss.OSSService
+}
+
+// NewMaterialImportService 创建资料批量导入服务实例
+func NewMaterialImportService(
+	importRepo repository.MaterialImportRepository,
+	categoryRepo *repository.MaterialCategoryRepository,
+	materialService MaterialService,
+	ossService oss.OSSService,
+) MaterialImportService {
+	return &materialImportService{
+		importRepo:      importRepo,
+		categoryRepo:    categoryRepo,
+		materialService: materialService,
+		ossService:      ossService,
+	}
+}
+
+// CreateImport 创建导入任务
+func (s *materialImportService) CreateImport(ctx context.Context, userID uint, req *model.CreateMaterialImportRequest) (*model.MaterialImport, error) {
+	if strings.ToLower(path.Ext(req.FileName)) != ".zip" {
+		return nil, ErrInvalidImportArchive
+	}
+
+	// 压缩包必须位于当前用户的上传目录下: materials/{userID}/{uuid}_{fileName}
+	prefix := fmt.Sprintf("materials/%d/", userID)
+	if !strings.HasPrefix(req.FileKey, "materials/") || strings.Contains(req.FileKey, "..") {
+		return nil, ErrInvalidFileKey
+	}
+	if !strings.HasPrefix(req.FileKey, prefix) || len(req.FileKey) == len(prefix) {
+		return nil, ErrFileKeyForbidden
+	}
+
+	info, err := s.ossService.StatFile(ctx, req.FileKey)
+	if err != nil {
+		if errors.Is(err, oss.ErrFileNotFound) {
+			return nil, ErrUploadedFileNotFound
+		}
+		return nil, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+	if info.Size > s.ossService.GetMaxFileSize() {
+		return nil, oss.ErrFileTooLarge
+	}
+
+	materialImport := &model.MaterialImport{
+		UploaderID: userID,
+		FileKey:    req.FileKey,
+		FileName:   req.FileName,
+		Status:     model.MaterialImportPending,
+	}
+	if err := s.importRepo.Create(ctx, materialImport); err != nil {
+		return nil, fmt.Errorf("创建导入任务失败: %w", err)
+	}
+	return materialImport, nil
+}
+
+// GetImport 获取导入任务，只有导入者和管理员可以查看
+func (s *materialImportService) GetImport(ctx context.Context, importID, userID uint, userRole string) (*model.MaterialImport, error) {
+	materialImport, err := s.importRepo.FindByID(ctx, importID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialImportNotFound) {
+			return nil, ErrMaterialImportNotFound
+		}
+		return nil, fmt.Errorf("获取导入任务失败: %w", err)
+	}
+	if materialImport.UploaderID != userID && userRole != "admin" {
+		return nil, ErrAccessDenied
+	}
+	return materialImport, nil
+}
+
+// ListImports 获取用户的导入任务列表
+func (s *materialImportService) ListImports(ctx context.Context, userID uint, req *model.MaterialImportListRequest) ([]*model.MaterialImport, int64, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	imports, total, err := s.importRepo.ListByUploader(ctx, userID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取导入任务失败: %w", err)
+	}
+	return imports, total, nil
+}
+
+// ProcessPending 执行一个待执行的导入任务
+func (s *materialImportService) ProcessPending(ctx context.Context) (bool, error) {
+	if _, err := s.importRepo.ResetStale(ctx, time.Now().Add(-materialImportStaleTimeout)); err != nil {
+		logger.Warn("重置超时导入任务失败", zap.Error(err))
+	}
+
+	materialImport, err := s.importRepo.ClaimPending(ctx)
+	if err != nil {
+		return false, fmt.Errorf("领取导入任务失败: %w", err)
+	}
+	if materialImport == nil {
+		return false, nil
+	}
+
+	s.runImport(ctx, materialImport)
+	return true, nil
+}
+
+// Start 启动后台导入处理，有任务时连续处理，空闲时按固定间隔轮询
+func (s *materialImportService) Start(ctx context.Context) {
+	go func() {
+		for {
+			processed, err := s.ProcessPending(ctx)
+			if err != nil {
+				logger.Warn("处理资料批量导入失败", zap.Error(err))
+			}
+			if processed && err == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(materialImportPollInterval):
+			}
+		}
+	}()
+}
+
+// runImport 读取压缩包和清单，逐行创建资料并记录结果
+// 任务中断后重新执行时跳过已有结果的行，不会重复创建资料
+func (s *materialImportService) runImport(ctx context.Context, materialImport *model.MaterialImport) {
+	done := make(map[int]bool)
+	if existing, err := s.importRepo.FindByID(ctx, materialImport.ID); err == nil {
+		for _, item := range existing.Items {
+			done[item.RowNumber] = true
+		}
+	}
+
+	file, size, err := s.downloadArchive(ctx, materialImport.FileKey)
+	if err != nil {
+		s.finishImport(ctx, materialImport, err)
+		return
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	archive, err := importer.OpenArchive(file, size)
+	if err != nil {
+		s.finishImport(ctx, materialImport, err)
+		return
+	}
+
+	materialImport.TotalRows = len(archive.Entries)
+	if err := s.importRepo.Update(ctx, materialImport); err != nil {
+		logger.Warn("更新导入任务失败", zap.Uint("import_id", materialImport.ID), zap.Error(err))
+	}
+
+	for _, entry := range archive.Entries {
+		if done[entry.Row] {
+			continue
+		}
+
+		item := &model.MaterialImportItem{
+			ImportID:  materialImport.ID,
+			RowNumber: entry.Row,
+			FilePath:  truncateRunes(entry.File, 500),
+			Title:     truncateRunes(entry.Title, 200),
+		}
+		materialID, err := s.importEntry(ctx, materialImport.UploaderID, archive, entry)
+		if err != nil {
+			item.Status = model.MaterialImportItemFailed
+			item.Error = err.Error()
+			materialImport.FailedRows++
+		} else {
+			item.Status = model.MaterialImportItemSucceeded
+			item.MaterialID = &materialID
+			materialImport.SucceededRows++
+		}
+
+		if err := s.importRepo.CreateItem(ctx, item); err != nil {
+			logger.Warn("记录导入结果失败", zap.Uint("import_id", materialImport.ID), zap.Int("row", entry.Row), zap.Error(err))
+		}
+		if err := s.importRepo.Update(ctx, materialImport); err != nil {
+			logger.Warn("更新导入任务失败", zap.Uint("import_id", materialImport.ID), zap.Error(err))
+		}
+	}
+
+	s.finishImport(ctx, materialImport, nil)
+}
+
+// finishImport 标记导入任务结束，err 不为空时表示整个任务失败
+func (s *materialImportService) finishImport(ctx context.Context, materialImport *model.MaterialImport, err error) {
+	now := time.Now()
+	materialImport.FinishedAt = &now
+	if err != nil {
+		materialImport.Status = model.MaterialImportFailed
+		materialImport.LastError = err.Error()
+		logger.Warn("资料批量导入失败", zap.Uint("import_id", materialImport.ID), zap.Error(err))
+	} else {
+		materialImport.Status = model.MaterialImportCompleted
+		materialImport.LastError = ""
+	}
+
+	if err := s.importRepo.Update(ctx, materialImport); err != nil {
+		logger.Warn("更新导入任务失败", zap.Uint("import_id", materialImport.ID), zap.Error(err))
+	}
+}
+
+// downloadArchive 将压缩包下载到临时文件，ZIP 需要随机读取，不能直接使用 OSS 的流
+func (s *materialImportService) downloadArchive(ctx context.Context, fileKey string) (*os.File, int64, error) {
+	reader, err := s.ossService.OpenFile(ctx, fileKey)
+	if err != nil {
+		if errors.Is(err, oss.ErrFileNotFound) {
+			return nil, 0, ErrUploadedFileNotFound
+		}
+		return nil, 0, fmt.Errorf("读取压缩包失败: %w", err)
+	}
+	defer reader.Close()
+
+	file, err := os.CreateTemp("", "material-import-*.zip")
+	if err != nil {
+		return nil, 0, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+
+	maxSize := s.ossService.GetMaxFileSize()
+	size, err := io.Copy(file, io.LimitReader(reader, maxSize+1))
+	if err == nil && size > maxSize {
+		err = oss.ErrFileTooLarge
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, 0, fmt.Errorf("读取压缩包失败: %w", err)
+	}
+	return file, size, nil
+}
+
+// importEntry 校验清单中的一行，将文件写入 OSS 并创建待审核资料，返回资料ID
+func (s *materialImportService) importEntry(ctx context.Context, userID uint, archive *importer.Archive, entry importer.ManifestEntry) (uint, error) {
+	if err := s.validateEntry(&entry); err != nil {
+		return 0, err
+	}
+
+	file, err := archive.File(entry.File)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", err, entry.File)
+	}
+	fileName := path.Base(strings.ReplaceAll(file.Name, "\\", "/"))
+	fileSize := int64(file.UncompressedSize64)
+	mimeType := oss.MimeTypeByExtension(fileName)
+	if err := s.ossService.ValidateFile(fileName, fileSize, mimeType); err != nil {
+		return 0, fmt.Errorf("文件验证失败: %w", err)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return 0, fmt.Errorf("读取压缩包中的文件失败: %w", err)
+	}
+	fileKey, err := s.ossService.PutUserFile(ctx, userID, fileName, reader, fileSize, mimeType)
+	reader.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	material, err := s.materialService.CreateMaterial(ctx, userID, &model.CreateMaterialRequest{
+		Title:       entry.Title,
+		Description: entry.Description,
+		Category:    model.MaterialCategoryType(entry.Category),
+		CourseName:  entry.CourseName,
+		FileName:    fileName,
+		FileSize:    fileSize,
+		MimeType:    mimeType,
+		FileKey:     fileKey,
+	})
+	if err != nil {
+		if delErr := s.ossService.DeleteFile(ctx, fileKey); delErr != nil {
+			logger.Warn("删除导入失败的文件失败", zap.String("file_key", fileKey), zap.Error(delErr))
+		}
+		return 0, err
+	}
+	return material.ID, nil
+}
+
+// validateEntry 校验清单行的资料信息，规则与创建资料请求一致
+func (s *materialImportService) validateEntry(entry *importer.ManifestEntry) error {
+	if n := utf8.RuneCountInString(entry.Title); n < 2 || n > 200 {
+		return errors.New("标题长度必须在 2 到 200 个字符之间")
+	}
+	if entry.CourseName == "" {
+		return errors.New("课程名称不能为空")
+	}
+	if utf8.RuneCountInString(entry.CourseName) > 100 {
+		return errors.New("课程名称不能超过 100 个字符")
+	}
+	if utf8.RuneCountInString(entry.Description) > 2000 {
+		return errors.New("描述不能超过 2000 个字符")
+	}
+	if entry.Category == "" {
+		return errors.New("资料类型不能为空")
+	}
+
+	category, err := s.categoryRepo.GetByCode(entry.Category)
+	if err != nil || !category.IsActive {
+		return fmt.Errorf("无效的资料类型: %s", entry.Category)
+	}
+	return nil
+}
+
+// truncateRunes 截断字符串到最多 maxRunes 个字符
+func truncateRunes(s string, maxRunes int) string {
+	if utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	return string([]rune(s)[:maxRunes])
+}
@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/document"
+	"github.com/study-upc/backend/internal/pkg/logger"
+	"github.com/study-upc/backend/internal/pkg/oss"
+	"github.com/study-upc/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrProcessingJobNotFound 处理任务不存在
+	ErrProcessingJobNotFound = errors.New("处理任务不存在")
+	// ErrProcessingJobNotRetryable 处理任务不可重试
+	ErrProcessingJobNotRetryable = errors.New("只能重试已失败的任务")
+)
+
+const (
+	// previewKeyPrefix 预览文件的存储前缀，不在 materials/ 下，避免被孤立上传文件清理删除
+	previewKeyPrefix = "previews/"
+	// previewMaxFileSize 生成预览和提取全文时读取的最大文件大小，超过时不处理
+	previewMaxFileSize = 50 * 1024 * 1024
+	// contentTextMaxRunes 提取全文的最大字符数，超出部分不计入搜索
+	contentTextMaxRunes = 20000
+
+	processingMaxAttempts  = 3
+	processingPollInterval = 10 * time.Second
+	processingBatchSize    = 5
+	// processingRetryBaseDelay 失败重试的基础延迟，之后每次翻倍
+	processingRetryBaseDelay = 30 * time.Second
+	processingRetryMaxDelay  = 30 * time.Minute
+	// processingStaleTimeout 执行中的任务超过该时间未完成，视为进程异常退出遗留的任务重新执行
+	processingStaleTimeout = 15 * time.Minute
+)
+
+// MaterialProcessingService 资料后台处理服务接口（生成首页缩略图和文本摘要、提取全文）
+type MaterialProcessingService interface {
+	// Enqueue 为资料的当前文件创建预览和全文提取任务，已有未完成的同类任务时不重复创建
+	Enqueue(ctx context.Context, material *model.Material) error
+	// RegeneratePreview 重新生成资料预览
+	RegeneratePreview(ctx context.Context, materialID uint) (*model.ProcessingJob, error)
+	// ListJobs 获取处理任务列表
+	ListJobs(ctx context.Context, req *model.ProcessingJobListRequest) ([]*model.ProcessingJob, int64, error)
+	// RetryJob 重试已失败的任务
+	RetryJob(ctx context.Context, jobID uint) (*model.ProcessingJob, error)
+	// ProcessPending 执行一批到期的任务，返回执行的任务数
+	ProcessPending(ctx context.Context) (int, error)
+	// Start 启动后台任务处理
+	Start(ctx context.Context)
+}
+
+// materialProcessingService 资料后台处理服务实现
+type materialProcessingService struct {
+	jobRepo      repository.ProcessingJobRepository
+	materialRepo repository.MaterialRepository
+	ossService   oss.OSSService
+}
+
+// NewMaterialProcessingService 创建资料后台处理服务实例
+func NewMaterialProcessingService(
+	jobRepo repository.ProcessingJobRepository,
+	materialRepo repository.MaterialRepository,
+	ossService oss.OSSService,
+) MaterialProcessingService {
+	return &materialProcessingService{
+		jobRepo:      jobRepo,
+		materialRepo: materialRepo,
+		ossService:   ossService,
+	}
+}
+
+// previewThumbnailKey 根据资料文件的存储键生成缩略图的存储键，每个版本的文件对应独立的缩略图
+// 归档只移动资料文件，缩略图仍位于归档前的存储键下
+func previewThumbnailKey(fileKey string) string {
+	return previewKeyPrefix + strings.TrimPrefix(fileKey, materialArchivePrefix) + "/thumbnail.png"
+}
+
+// Enqueue 为资料的当前文件创建预览和全文提取任务
+func (s *materialProcessingService) Enqueue(ctx context.Context, material *model.Material) error {
+	if _, err := s.enqueue(ctx, material, model.ProcessingJobPreview); err != nil {
+		return err
+	}
+	_, err := s.enqueue(ctx, material, model.ProcessingJobExtractText)
+	return err
+}
+
+// enqueue 创建指定类型的处理任务，并清除资料上该任务的旧结果
+func (s *materialProcessingService) enqueue(ctx context.Context, material *model.Material, jobType model.ProcessingJobType) (*model.ProcessingJob, error) {
+	pending, err := s.jobRepo.FindPending(ctx, material.ID, jobType)
+	if err != nil && !errors.Is(err, repository.ErrProcessingJobNotFound) {
+		return nil, fmt.Errorf("查询处理任务失败: %w", err)
+	}
+	if pending != nil && pending.FileKey == material.FileKey {
+		return pending, nil
+	}
+
+	job := &model.ProcessingJob{
+		MaterialID:  material.ID,
+		FileKey:     material.FileKey,
+		JobType:     jobType,
+		Status:      model.ProcessingJobPending,
+		MaxAttempts: processingMaxAttempts,
+		RunAfter:    time.Now(),
+	}
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("创建处理任务失败: %w", err)
+	}
+
+	switch jobType {
+	case model.ProcessingJobPreview:
+		material.ThumbnailKey = ""
+		material.PreviewExcerpt = ""
+		material.PreviewStatus = model.PreviewStatusPending
+		if _, err := s.materialRepo.UpdatePreview(ctx, material.ID, material.FileKey, &repository.MaterialPreview{
+			Status: model.PreviewStatusPending,
+		}); err != nil {
+			fmt.Printf("重置资料预览状态失败: material_id=%d, err=%v\n", material.ID, err)
+		}
+	case model.ProcessingJobExtractText:
+		// 旧版本文件的全文不再代表资料内容，新版本提取完成前不参与全文匹配
+		material.ContentText = ""
+		if _, err := s.materialRepo.UpdateContentText(ctx, material.ID, material.FileKey, ""); err != nil {
+			fmt.Printf("清除资料全文失败: material_id=%d, err=%v\n", material.ID, err)
+		}
+	}
+	return job, nil
+}
+
+// RegeneratePreview 重新生成资料预览
+func (s *materialProcessingService) RegeneratePreview(ctx context.Context, materialID uint) (*model.ProcessingJob, error) {
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+	return s.enqueue(ctx, material, model.ProcessingJobPreview)
+}
+
+// ListJobs 获取处理任务列表
+func (s *materialProcessingService) ListJobs(ctx context.Context, req *model.ProcessingJobListRequest) ([]*model.ProcessingJob, int64, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	jobs, total, err := s.jobRepo.List(ctx, page, pageSize, &repository.ProcessingJobListOptions{
+		Status:     req.Status,
+		JobType:    req.JobType,
+		MaterialID: req.MaterialID,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取处理任务失败: %w", err)
+	}
+	return jobs, total, nil
+}
+
+// RetryJob 重试已失败的任务，重新计算执行次数
+func (s *materialProcessingService) RetryJob(ctx context.Context, jobID uint) (*model.ProcessingJob, error) {
+	job, err := s.jobRepo.FindByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, repository.ErrProcessingJobNotFound) {
+			return nil, ErrProcessingJobNotFound
+		}
+		return nil, fmt.Errorf("获取处理任务失败: %w", err)
+	}
+	if job.Status != model.ProcessingJobFailed {
+		return nil, ErrProcessingJobNotRetryable
+	}
+
+	job.Status = model.ProcessingJobPending
+	job.Attempts = 0
+	job.LastError = ""
+	job.RunAfter = time.Now()
+	job.FinishedAt = nil
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("更新处理任务失败: %w", err)
+	}
+
+	if job.JobType == model.ProcessingJobPreview {
+		if _, err := s.materialRepo.UpdatePreview(ctx, job.MaterialID, job.FileKey, &repository.MaterialPreview{
+			Status: model.PreviewStatusPending,
+		}); err != nil {
+			fmt.Printf("重置资料预览状态失败: material_id=%d, err=%v\n", job.MaterialID, err)
+		}
+	}
+	return job, nil
+}
+
+// ProcessPending 执行一批到期的任务
+func (s *materialProcessingService) ProcessPending(ctx context.Context) (int, error) {
+	if _, err := s.jobRepo.ResetStale(ctx, time.Now().Add(-processingStaleTimeout)); err != nil {
+		logger.Warn("重置超时处理任务失败", zap.Error(err))
+	}
+
+	jobs, err := s.jobRepo.ClaimDue(ctx, processingBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("领取处理任务失败: %w", err)
+	}
+
+	for _, job := range jobs {
+		s.runJob(ctx, job)
+	}
+	return len(jobs), nil
+}
+
+// Start 启动后台任务处理，有任务时连续处理，空闲时按固定间隔轮询
+func (s *materialProcessingService) Start(ctx context.Context) {
+	go func() {
+		for {
+			processed, err := s.ProcessPending(ctx)
+			if err != nil {
+				logger.Warn("处理资料后台任务失败", zap.Error(err))
+			}
+			if processed > 0 && err == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(processingPollInterval):
+			}
+		}
+	}()
+}
+
+// runJob 执行任务并记录结果，失败时按指数退避安排重试
+// 解析文件时发生 panic 的任务直接标记为失败，同一文件重试仍会 panic
+func (s *materialProcessingService) runJob(ctx context.Context, job *model.ProcessingJob) {
+	panicked, err := s.executeJob(ctx, job)
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		job.Status = model.ProcessingJobSucceeded
+		job.LastError = ""
+		job.FinishedAt = &now
+	case panicked || job.Attempts >= job.MaxAttempts:
+		job.Status = model.ProcessingJobFailed
+		job.LastError = err.Error()
+		job.FinishedAt = &now
+		if job.JobType == model.ProcessingJobPreview {
+			if _, updateErr := s.materialRepo.UpdatePreview(ctx, job.MaterialID, job.FileKey, &repository.MaterialPreview{
+				Status: model.PreviewStatusFailed,
+			}); updateErr != nil {
+				logger.Warn("更新资料预览状态失败", zap.Uint("material_id", job.MaterialID), zap.Error(updateErr))
+			}
+		}
+		logger.Warn("资料处理任务失败", zap.Uint("job_id", job.ID), zap.String("job_type", string(job.JobType)),
+			zap.Uint("material_id", job.MaterialID), zap.Error(err))
+	default:
+		job.Status = model.ProcessingJobPending
+		job.LastError = err.Error()
+		job.RunAfter = now.Add(processingRetryDelay(job.Attempts))
+	}
+
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		logger.Warn("更新处理任务失败", zap.Uint("job_id", job.ID), zap.Error(err))
+	}
+}
+
+// executeJob 执行任务，恢复处理过程中的 panic（如构造的恶意文件触发解析器 panic），避免后台协程退出导致进程崩溃
+func (s *materialProcessingService) executeJob(ctx context.Context, job *model.ProcessingJob) (panicked bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("处理任务时发生 panic: %v", r)
+			panicked = true
+			logger.Error("资料处理任务 panic", zap.Uint("job_id", job.ID), zap.Uint("material_id", job.MaterialID),
+				zap.Any("panic", r), zap.Stack("stack"))
+		}
+	}()
+
+	switch job.JobType {
+	case model.ProcessingJobPreview:
+		return false, s.generatePreview(ctx, job)
+	case model.ProcessingJobExtractText:
+		return false, s.extractContent(ctx, job)
+	default:
+		return false, fmt.Errorf("未知的任务类型: %s", job.JobType)
+	}
+}
+
+// processingRetryDelay 第 attempts 次失败后的重试延迟
+func processingRetryDelay(attempts int) time.Duration {
+	delay := processingRetryBaseDelay
+	for i := 1; i < attempts && delay < processingRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > processingRetryMaxDelay {
+		delay = processingRetryMaxDelay
+	}
+	return delay
+}
+
+// loadJobMaterial 获取任务对应的资料，资料已删除或已更换版本时返回 nil
+func (s *materialProcessingService) loadJobMaterial(ctx context.Context, job *model.ProcessingJob) (*model.Material, error) {
+	material, err := s.materialRepo.FindByID(ctx, job.MaterialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			// 资料已删除，无需处理
+			return nil, nil
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+	if material.FileKey != job.FileKey {
+		// 资料已更换版本，由新版本的任务处理
+		return nil, nil
+	}
+	return material, nil
+}
+
+// readJobFile 从 OSS 读取任务对应的文件
+func (s *materialProcessingService) readJobFile(ctx context.Context, job *model.ProcessingJob) ([]byte, error) {
+	reader, err := s.ossService.OpenFile(ctx, job.FileKey)
+	if err != nil {
+		return nil, fmt.Errorf("读取资料文件失败: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, previewMaxFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("读取资料文件失败: %w", err)
+	}
+	return data, nil
+}
+
+// generatePreview 读取资料文件，生成缩略图上传到 OSS 并保存文本摘要
+func (s *materialProcessingService) generatePreview(ctx context.Context, job *model.ProcessingJob) error {
+	material, err := s.loadJobMaterial(ctx, job)
+	if err != nil || material == nil {
+		return err
+	}
+
+	if !document.SupportsPreview(material.MimeType) || material.FileSize > previewMaxFileSize {
+		return s.savePreview(ctx, job, &repository.MaterialPreview{Status: model.PreviewStatusUnsupported})
+	}
+
+	data, err := s.readJobFile(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	preview, err := document.GeneratePreview(material.MimeType, data)
+	if err != nil {
+		if errors.Is(err, document.ErrUnsupportedType) {
+			return s.savePreview(ctx, job, &repository.MaterialPreview{Status: model.PreviewStatusUnsupported})
+		}
+		return fmt.Errorf("生成预览失败: %w", err)
+	}
+
+	thumbnailKey := previewThumbnailKey(job.FileKey)
+	if err := s.ossService.UploadFile(ctx, thumbnailKey, preview.Thumbnail, document.ThumbnailContentType); err != nil {
+		return fmt.Errorf("上传缩略图失败: %w", err)
+	}
+
+	return s.savePreview(ctx, job, &repository.MaterialPreview{
+		ThumbnailKey: thumbnailKey,
+		Excerpt:      preview.Excerpt,
+		Status:       model.PreviewStatusReady,
+	})
+}
+
+// savePreview 保存预览结果
+func (s *materialProcessingService) savePreview(ctx context.Context, job *model.ProcessingJob, preview *repository.MaterialPreview) error {
+	if _, err := s.materialRepo.UpdatePreview(ctx, job.MaterialID, job.FileKey, preview); err != nil {
+		return fmt.Errorf("保存资料预览失败: %w", err)
+	}
+	return nil
+}
+
+// extractContent 读取资料文件，提取全文保存到资料上，保存时由 RefreshSearchVector 以最低权重计入搜索向量
+// 不支持的类型或过大的文件保存空全文，只按标题、描述和课程名称搜索
+func (s *materialProcessingService) extractContent(ctx context.Context, job *model.ProcessingJob) error {
+	material, err := s.loadJobMaterial(ctx, job)
+	if err != nil || material == nil {
+		return err
+	}
+
+	if !document.SupportsTextExtraction(material.MimeType) || material.FileSize > previewMaxFileSize {
+		return s.saveContentText(ctx, job, "")
+	}
+
+	data, err := s.readJobFile(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	text, err := document.ExtractText(material.MimeType, data, contentTextMaxRunes)
+	if err != nil {
+		if errors.Is(err, document.ErrUnsupportedType) {
+			return s.saveContentText(ctx, job, "")
+		}
+		return fmt.Errorf("提取全文失败: %w", err)
+	}
+	return s.saveContentText(ctx, job, text)
+}
+
+// saveContentText 保存提取的全文，并重新计算资料的搜索向量
+func (s *materialProcessingService) saveContentText(ctx context.Context, job *model.ProcessingJob, text string) error {
+	if _, err := s.materialRepo.UpdateContentText(ctx, job.MaterialID, job.FileKey, text); err != nil {
+		return fmt.Errorf("保存资料全文失败: %w", err)
+	}
+	return nil
+}
+
+// enqueuePreview 为资料创建预览和全文提取任务，失败时不影响主流程
+func (s *materialService) enqueuePreview(ctx context.Context, material *model.Material) {
+	if s.processingService == nil {
+		return
+	}
+	if err := s.processingService.Enqueue(ctx, material); err != nil {
+		fmt.Printf("创建资料处理任务失败: material_id=%d, err=%v\n", material.ID, err)
+	}
+}
+
+// attachThumbnailURL 为资料响应生成缩略图的预签名地址
+func (s *materialService) attachThumbnailURL(ctx context.Context, material *model.Material, response *model.MaterialResponse) {
+	if material.ThumbnailKey == "" || material.PreviewStatus != model.PreviewStatusReady {
+		return
+	}
+	url, err := s.ossService.GenerateDownloadSignature(ctx, material.ThumbnailKey)
+	if err != nil {
+		fmt.Printf("生成缩略图地址失败: material_id=%d, err=%v\n", material.ID, err)
+		return
+	}
+	response.ThumbnailURL = url
+}
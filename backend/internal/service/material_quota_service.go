@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/study-upc/backend/internal/model"
+)
+
+var (
+	// ErrStorageQuotaExceeded 超过存储配额
+	ErrStorageQuotaExceeded = errors.New("上传后将超过存储配额")
+)
+
+const (
+	// storageQuotaDefaultKey 未单独配置的角色使用的存储配额（MB）
+	storageQuotaDefaultKey = "storage_quota_default_mb"
+	// storageQuotaRoleKeyFormat 按角色配置的存储配额（MB），如 storage_quota_committee_mb
+	storageQuotaRoleKeyFormat = "storage_quota_%s_mb"
+	defaultStorageQuotaMB     = 2048
+
+	storageQuotaSourceUser    = "user"
+	storageQuotaSourceRole    = "role"
+	storageQuotaSourceDefault = "default"
+)
+
+// GetUploadQuota 获取存储配额
+func (s *materialService) GetUploadQuota(ctx context.Context, userID uint) (*model.UploadQuotaResponse, error) {
+	limitMB, source, err := s.getStorageQuota(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.materialRepo.SumFileSizeByUploader(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("统计存储空间失败: %w", err)
+	}
+
+	limit := limitMB * 1024 * 1024
+	unlimited := limit <= 0
+	remaining := limit - used
+	if unlimited {
+		remaining = -1
+	} else if remaining < 0 {
+		remaining = 0
+	}
+
+	return &model.UploadQuotaResponse{
+		Limit:     limit,
+		Used:      used,
+		Remaining: remaining,
+		Unlimited: unlimited,
+		Source:    source,
+	}, nil
+}
+
+// CheckStorageQuota 检查用户再上传 fileSize 字节后是否超过存储配额
+func (s *materialService) CheckStorageQuota(ctx context.Context, userID uint, fileSize int64) error {
+	quota, err := s.GetUploadQuota(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if quota.Unlimited || quota.Used+fileSize <= quota.Limit {
+		return nil
+	}
+	return fmt.Errorf("%w: 已使用 %s，配额 %s，本次上传 %s", ErrStorageQuotaExceeded,
+		formatStorageSize(quota.Used), formatStorageSize(quota.Limit), formatStorageSize(fileSize))
+}
+
+// getStorageQuota 获取用户的存储配额（MB），0 表示不限制
+// 优先使用管理员为用户单独设置的配额，其次是按角色的配置，最后是默认配置
+func (s *materialService) getStorageQuota(ctx context.Context, userID uint) (int64, string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return 0, "", fmt.Errorf("获取用户信息失败: %w", err)
+	}
+	if user.StorageQuotaMB != nil && *user.StorageQuotaMB >= 0 {
+		return *user.StorageQuotaMB, storageQuotaSourceUser, nil
+	}
+
+	if quota, ok := s.getStorageQuotaConfig(fmt.Sprintf(storageQuotaRoleKeyFormat, user.Role)); ok {
+		return quota, storageQuotaSourceRole, nil
+	}
+	if quota, ok := s.getStorageQuotaConfig(storageQuotaDefaultKey); ok {
+		return quota, storageQuotaSourceDefault, nil
+	}
+	return defaultStorageQuotaMB, storageQuotaSourceDefault, nil
+}
+
+// getStorageQuotaConfig 读取存储配额配置，未配置或格式错误时返回 false
+func (s *materialService) getStorageQuotaConfig(key string) (int64, bool) {
+	if s.configRepo == nil {
+		return 0, false
+	}
+
+	config, err := s.configRepo.GetSystemConfig(key)
+	if err != nil {
+		return 0, false
+	}
+
+	quota, err := strconv.ParseInt(strings.TrimSpace(config.ConfigValue), 10, 64)
+	if err != nil || quota < 0 {
+		return 0, false
+	}
+	return quota, true
+}
+
+// formatStorageSize 将字节数格式化为便于阅读的大小
+func formatStorageSize(size int64) string {
+	switch {
+	case size >= 1024*1024*1024:
+		return fmt.Sprintf("%.2f GB", float64(size)/(1024*1024*1024))
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.2f MB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.2f KB", float64(size)/1024)
+	}
+	return fmt.Sprintf("%d B", size)
+}
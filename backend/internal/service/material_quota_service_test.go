@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/oss"
+	"github.com/study-upc/backend/internal/repository"
+)
+
+// quotaMaterialRepo 只实现配额检查用到的方法，其余方法调用时 panic
+type quotaMaterialRepo struct {
+	repository.MaterialRepository
+	material *model.Material
+	used     int64
+}
+
+func (r *quotaMaterialRepo) FindByID(ctx context.Context, id uint) (*model.Material, error) {
+	if r.material == nil || r.material.ID != id {
+		return nil, repository.ErrMaterialNotFound
+	}
+	return r.material, nil
+}
+
+func (r *quotaMaterialRepo) SumFileSizeByUploader(ctx context.Context, uploaderID uint) (int64, error) {
+	return r.used, nil
+}
+
+// quotaUserRepo 返回固定用户
+type quotaUserRepo struct {
+	repository.UserRepository
+	user *model.User
+}
+
+func (r *quotaUserRepo) FindByID(ctx context.Context, id uint) (*model.User, error) {
+	return r.user, nil
+}
+
+// quotaOSSService 文件校验总是通过，其余方法调用时 panic
+type quotaOSSService struct {
+	oss.OSSService
+}
+
+func (s *quotaOSSService) ValidateFile(fileName string, fileSize int64, mimeType string) error {
+	return nil
+}
+
+func TestUploadVersionStorageQuota(t *testing.T) {
+	quotaMB := int64(1)
+	svc := &materialService{
+		materialRepo: &quotaMaterialRepo{
+			material: &model.Material{ID: 1, UploaderID: 7, Status: model.StatusApproved},
+			used:     900 * 1024,
+		},
+		userRepo:   &quotaUserRepo{user: &model.User{ID: 7, Role: "committee", StorageQuotaMB: &quotaMB}},
+		ossService: &quotaOSSService{},
+	}
+
+	// 已使用 900KB，配额 1MB，再上传 200KB 的新版本超过配额
+	_, err := svc.UploadVersion(context.Background(), 1, 7, "committee", &model.UploadMaterialVersionRequest{
+		FileName: "v2.pdf",
+		FileSize: 200 * 1024,
+		MimeType: "application/pdf",
+		FileKey:  "materials/7/v2.pdf",
+	})
+	if !errors.Is(err, ErrStorageQuotaExceeded) {
+		t.Errorf("UploadVersion() 错误 = %v, want %v", err, ErrStorageQuotaExceeded)
+	}
+}
+
+func TestGetUploadQuota(t *testing.T) {
+	quotaMB := int64(1)
+	svc := &materialService{
+		materialRepo: &quotaMaterialRepo{used: 900 * 1024},
+		userRepo:     &quotaUserRepo{user: &model.User{ID: 7, Role: "committee", StorageQuotaMB: &quotaMB}},
+	}
+
+	quota, err := svc.GetUploadQuota(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetUploadQuota() 失败: %v", err)
+	}
+	if quota.Used != 900*1024 || quota.Remaining != 124*1024 || quota.Source != storageQuotaSourceUser {
+		t.Errorf("GetUploadQuota() = %+v, want used=%d remaining=%d source=%s", quota, 900*1024, 124*1024, storageQuotaSourceUser)
+	}
+}
+
+// quotaSessionRepo 没有进行中的上传会话，其余方法调用时 panic
+type quotaSessionRepo struct {
+	repository.UploadSessionRepository
+}
+
+func (r *quotaSessionRepo) CountActiveByUser(ctx context.Context, userID uint) (int64, error) {
+	return 0, nil
+}
+
+func TestCreateUploadSessionStorageQuota(t *testing.T) {
+	quotaMB := int64(1)
+	materialService := &materialService{
+		materialRepo: &quotaMaterialRepo{used: 900 * 1024},
+		userRepo:     &quotaUserRepo{user: &model.User{ID: 7, Role: "committee", StorageQuotaMB: &quotaMB}},
+	}
+	// ossService 为 nil，超过配额时不应发起分片上传
+	svc := NewUploadSessionService(&quotaSessionRepo{}, materialService, nil)
+
+	_, err := svc.CreateSession(context.Background(), 7, &model.CreateUploadSessionRequest{
+		FileName: "notes.pdf",
+		FileSize: 200 * 1024,
+		MimeType: "application/pdf",
+	})
+	if !errors.Is(err, ErrStorageQuotaExceeded) {
+		t.Errorf("CreateSession() 错误 = %v, want %v", err, ErrStorageQuotaExceeded)
+	}
+}
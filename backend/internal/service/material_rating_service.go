@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/repository"
+)
+
+var (
+	// ErrRatingNotFound 评分不存在
+	ErrRatingNotFound = errors.New("尚未对该资料评分")
+	// ErrCannotRateOwnMaterial 不能给自己上传的资料评分
+	ErrCannotRateOwnMaterial = errors.New("不能给自己上传的资料评分")
+)
+
+// MaterialRatingService 资料评分服务接口
+type MaterialRatingService interface {
+	// GetRating 获取资料的评分统计及当前用户的评分
+	GetRating(ctx context.Context, userID, materialID uint) (*model.MaterialRatingResponse, error)
+	// RateMaterial 评分，重复评分会覆盖之前的分数
+	RateMaterial(ctx context.Context, userID, materialID uint, req *model.RateMaterialRequest) (*model.MaterialRatingResponse, error)
+	// DeleteRating 撤销评分
+	DeleteRating(ctx context.Context, userID, materialID uint) (*model.MaterialRatingResponse, error)
+}
+
+// materialRatingService 资料评分服务实现
+type materialRatingService struct {
+	ratingRepo   repository.MaterialRatingRepository
+	materialRepo repository.MaterialRepository
+}
+
+// NewMaterialRatingService 创建资料评分服务实例
+func NewMaterialRatingService(
+	ratingRepo repository.MaterialRatingRepository,
+	materialRepo repository.MaterialRepository,
+) MaterialRatingService {
+	return &materialRatingService{
+		ratingRepo:   ratingRepo,
+		materialRepo: materialRepo,
+	}
+}
+
+// GetRating 获取资料的评分统计及当前用户的评分
+func (s *materialRatingService) GetRating(ctx context.Context, userID, materialID uint) (*model.MaterialRatingResponse, error) {
+	material, err := s.findMaterial(ctx, materialID)
+	if err != nil {
+		return nil, err
+	}
+	if !material.Status.IsPublic() && material.UploaderID != userID {
+		return nil, ErrAccessDenied
+	}
+	return s.buildResponse(ctx, userID, material)
+}
+
+// RateMaterial 评分
+func (s *materialRatingService) RateMaterial(ctx context.Context, userID, materialID uint, req *model.RateMaterialRequest) (*model.MaterialRatingResponse, error) {
+	material, err := s.findMaterial(ctx, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只能给已审核通过的资料评分，已归档的资料不再接受新评分
+	if material.Status != model.StatusApproved {
+		return nil, ErrAccessDenied
+	}
+	if material.UploaderID == userID {
+		return nil, ErrCannotRateOwnMaterial
+	}
+
+	rating := &model.MaterialRating{
+		MaterialID: materialID,
+		UserID:     userID,
+		Score:      req.Score,
+	}
+	if err := s.ratingRepo.Upsert(ctx, rating); err != nil {
+		return nil, fmt.Errorf("评分失败: %w", err)
+	}
+	return s.reload(ctx, userID, materialID)
+}
+
+// DeleteRating 撤销评分
+func (s *materialRatingService) DeleteRating(ctx context.Context, userID, materialID uint) (*model.MaterialRatingResponse, error) {
+	if _, err := s.findMaterial(ctx, materialID); err != nil {
+		return nil, err
+	}
+
+	if err := s.ratingRepo.Delete(ctx, userID, materialID); err != nil {
+		if errors.Is(err, repository.ErrRatingNotFound) {
+			return nil, ErrRatingNotFound
+		}
+		return nil, fmt.Errorf("撤销评分失败: %w", err)
+	}
+	return s.reload(ctx, userID, materialID)
+}
+
+// findMaterial 获取资料
+func (s *materialRatingService) findMaterial(ctx context.Context, materialID uint) (*model.Material, error) {
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+	return material, nil
+}
+
+// reload 重新读取资料以获得刷新后的评分统计
+func (s *materialRatingService) reload(ctx context.Context, userID, materialID uint) (*model.MaterialRatingResponse, error) {
+	material, err := s.findMaterial(ctx, materialID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildResponse(ctx, userID, material)
+}
+
+// buildResponse 构造评分响应
+func (s *materialRatingService) buildResponse(ctx context.Context, userID uint, material *model.Material) (*model.MaterialRatingResponse, error) {
+	response := &model.MaterialRatingResponse{
+		MaterialID:    material.ID,
+		RatingAverage: material.RatingAverage,
+		RatingCount:   material.RatingCount,
+	}
+
+	rating, err := s.ratingRepo.FindByUserAndMaterial(ctx, userID, material.ID)
+	if err != nil && !errors.Is(err, repository.ErrRatingNotFound) {
+		return nil, fmt.Errorf("获取评分失败: %w", err)
+	}
+	if rating != nil {
+		response.MyRating = &rating.Score
+	}
+	return response, nil
+}
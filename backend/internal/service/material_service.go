@@ -11,6 +11,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/study-upc/backend/internal/model"
 	"github.com/study-upc/backend/internal/pkg/oss"
+	"github.com/study-upc/backend/internal/pkg/segmenter"
 	"github.com/study-upc/backend/internal/repository"
 	"gorm.io/gorm"
 )
@@ -28,6 +29,8 @@ var (
 	ErrInvalidMaterialStatus = errors.New("无效的资料状态")
 	// ErrDownloadLimitExceeded 超过每日下载上限
 	ErrDownloadLimitExceeded = errors.New("已达到每日下载上限")
+	// ErrInvalidCourseTerm 开课学期格式错误
+	ErrInvalidCourseTerm = errors.New("开课学期格式错误，应为 2024-2025-1 的形式")
 )
 
 const (
@@ -51,14 +54,40 @@ type MaterialService interface {
 	ReviewMaterial(ctx context.Context, materialID, reviewerID uint, req *model.ReviewMaterialRequest) error
 	// GetUploadSignature 获取上传签名
 	GetUploadSignature(ctx context.Context, userID uint, req *model.UploadSignatureRequest) (*model.UploadSignatureResponse, error)
-	// GetDownloadURL 获取下载链接
-	GetDownloadURL(ctx context.Context, materialID, userID uint) (string, error)
+	// GetDownloadLink 按下载方式获取一次性下载令牌（兑换预签名 URL 或代理下载）
+	GetDownloadLink(ctx context.Context, materialID, userID uint, clientIP string, req *model.DownloadLinkRequest) (*model.DownloadLinkResponse, error)
+	// RedeemDownloadToken 兑换预签名下载令牌，返回短时有效的预签名 URL
+	RedeemDownloadToken(ctx context.Context, token, clientIP string) (string, error)
+	// OpenDownloadStream 使用代理下载令牌打开文件
+	OpenDownloadStream(ctx context.Context, token, clientIP string, resume bool) (*DownloadStream, error)
 	// GetDownloadQuota 获取下载配额
 	GetDownloadQuota(ctx context.Context, userID uint) (*model.DownloadQuotaResponse, error)
+	// GetUploadQuota 获取存储配额
+	GetUploadQuota(ctx context.Context, userID uint) (*model.UploadQuotaResponse, error)
+	// CheckStorageQuota 检查用户再上传 fileSize 字节后是否超过存储配额
+	CheckStorageQuota(ctx context.Context, userID uint, fileSize int64) error
 	// SearchMaterials 搜索资料
 	SearchMaterials(ctx context.Context, keyword string, page, pageSize int) (*model.MaterialListResponse, error)
 	// DeleteUploadedFile 删除已上传但未创建记录的文件
 	DeleteUploadedFile(ctx context.Context, userID uint, fileKey string) error
+	// FinalizeUpload 确认上传完成并校验 OSS 中的文件
+	FinalizeUpload(ctx context.Context, userID uint, req *model.FinalizeUploadRequest) (*model.FinalizeUploadResponse, error)
+	// UploadVersion 上传资料新版本
+	UploadVersion(ctx context.Context, materialID, userID uint, userRole string, req *model.UploadMaterialVersionRequest) (*model.MaterialVersionResponse, error)
+	// ListVersions 获取资料版本历史
+	ListVersions(ctx context.Context, materialID, userID uint, userRole string) ([]*model.MaterialVersionResponse, error)
+	// GetVersionDownloadURL 获取指定版本的下载链接
+	GetVersionDownloadURL(ctx context.Context, materialID, versionID, userID uint, userRole, clientIP string, req *model.DownloadLinkRequest) (*model.DownloadLinkResponse, error)
+	// ReviewVersion 审核资料版本
+	ReviewVersion(ctx context.Context, materialID, versionID, reviewerID uint, req *model.ReviewMaterialVersionRequest) error
+	// RollbackVersion 回滚到指定版本
+	RollbackVersion(ctx context.Context, materialID, versionID uint) (*model.MaterialResponse, error)
+	// ListPendingVersions 获取待审核的版本列表
+	ListPendingVersions(ctx context.Context, page, pageSize int) ([]*model.MaterialVersionResponse, int64, error)
+	// SetProcessingService 设置资料后台处理服务
+	SetProcessingService(processingService MaterialProcessingService)
+	// SetSearchSuggestService 设置搜索联想服务
+	SetSearchSuggestService(suggestService SearchSuggestService)
 }
 
 // materialService 资料服务实现
@@ -66,9 +95,16 @@ type materialService struct {
 	materialRepo      repository.MaterialRepository
 	favoriteRepo      repository.FavoriteRepository
 	downloadRepo      repository.DownloadRecordRepository
+	versionRepo       repository.MaterialVersionRepository
 	categoryRepo      *repository.MaterialCategoryRepository
 	configRepo        repository.SystemConfigRepository
+	userRepo          repository.UserRepository
+	boostRepo         repository.DownloadQuotaBoostRepository
+	courseRepo        repository.CourseRepository
+	tagRepo           repository.TagRepository
 	ossService        oss.OSSService
+	processingService MaterialProcessingService
+	suggestService    SearchSuggestService
 	redisClient       *redis.Client
 	cacheTTL          time.Duration
 }
@@ -78,8 +114,13 @@ func NewMaterialService(
 	materialRepo repository.MaterialRepository,
 	favoriteRepo repository.FavoriteRepository,
 	downloadRepo repository.DownloadRecordRepository,
+	versionRepo repository.MaterialVersionRepository,
 	categoryRepo *repository.MaterialCategoryRepository,
 	configRepo repository.SystemConfigRepository,
+	userRepo repository.UserRepository,
+	boostRepo repository.DownloadQuotaBoostRepository,
+	courseRepo repository.CourseRepository,
+	tagRepo repository.TagRepository,
 	ossService oss.OSSService,
 	redisClient *redis.Client,
 ) MaterialService {
@@ -87,14 +128,29 @@ func NewMaterialService(
 		materialRepo: materialRepo,
 		favoriteRepo: favoriteRepo,
 		downloadRepo: downloadRepo,
+		versionRepo:  versionRepo,
 		categoryRepo: categoryRepo,
 		configRepo:   configRepo,
+		userRepo:     userRepo,
+		boostRepo:    boostRepo,
+		courseRepo:   courseRepo,
+		tagRepo:      tagRepo,
 		ossService:   ossService,
 		redisClient:  redisClient,
 		cacheTTL:     10 * time.Minute, // 默认缓存 10 分钟
 	}
 }
 
+// SetProcessingService 设置资料后台处理服务
+func (s *materialService) SetProcessingService(processingService MaterialProcessingService) {
+	s.processingService = processingService
+}
+
+// SetSearchSuggestService 设置搜索联想服务
+func (s *materialService) SetSearchSuggestService(suggestService SearchSuggestService) {
+	s.suggestService = suggestService
+}
+
 // CreateMaterial 创建资料
 func (s *materialService) CreateMaterial(ctx context.Context, userID uint, req *model.CreateMaterialRequest) (*model.MaterialResponse, error) {
 	// 验证文件
@@ -108,30 +164,92 @@ func (s *materialService) CreateMaterial(ctx context.Context, userID uint, req *
 		return nil, fmt.Errorf("无效的资料类型: %s", req.Category)
 	}
 
+	if req.CourseTerm != "" && !model.IsValidCourseTerm(req.CourseTerm) {
+		return nil, ErrInvalidCourseTerm
+	}
+
+	// 关联课程：指定课程或按课程名称、别名解析
+	course, err := resolveCourse(ctx, s.courseRepo, req.CourseID, req.CourseName)
+	if err != nil {
+		return nil, err
+	}
+	courseName := req.CourseName
+	if courseName == "" && course != nil {
+		courseName = course.Name
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查存储配额
+	if err := s.CheckStorageQuota(ctx, userID, req.FileSize); err != nil {
+		return nil, err
+	}
+
+	// 校验文件确实已上传且归属于当前用户
+	info, err := s.verifyUploadedFile(ctx, userID, req.FileKey, req.FileSize, req.MimeType, req.Checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	// 根据文件头检测真实类型
+	inspection, err := s.inspectFileType(ctx, req.FileKey, req.MimeType)
+	if err != nil {
+		return nil, err
+	}
+
 	// 创建资料记录
 	material := &model.Material{
 		Title:       req.Title,
 		Description: req.Description,
 		Category:    req.Category,
-		CourseName:  req.CourseName,
+		CourseName:  courseName,
+		CourseID:    courseIDOf(course),
+		CourseTerm:  req.CourseTerm,
 		UploaderID:  userID,
 		Status:      model.StatusPending, // 默认待审核
 		FileName:    req.FileName,
 		FileSize:    req.FileSize,
 		FileKey:     req.FileKey,
 		MimeType:    req.MimeType,
+		ContentHash: s.contentHash(ctx, info),
+		DetectedMimeType: inspection.DetectedMimeType,
+		MimeMismatch: inspection.Mismatch,
 		DownloadCount: 0,
 		FavoriteCount: 0,
 		ViewCount:    0,
 	}
 
-	// 生成搜索向量（全文搜索）
-	material.SearchVector = s.generateSearchVector(req.Title, req.Description, req.CourseName)
+	// 与已通过资料内容完全相同时，按系统配置自动拒绝并附上原资料链接
+	if original := s.findDuplicate(ctx, material.ContentHash); original != nil && s.isDuplicateAutoRejectEnabled() {
+		now := time.Now()
+		material.Status = model.StatusRejected
+		material.ReviewedAt = &now
+		material.RejectionReason = duplicateRejectionReason(original)
+	}
 
+	// 搜索向量由数据访问层在写入后根据分词结果计算
 	if err := s.materialRepo.Create(ctx, material); err != nil {
 		return nil, fmt.Errorf("创建资料失败: %w", err)
 	}
 
+	// 创建初始版本
+	if err := s.createInitialVersion(ctx, material); err != nil {
+		return nil, fmt.Errorf("创建资料版本失败: %w", err)
+	}
+
+	// 设置标签
+	if len(tags) > 0 {
+		if material.Tags, err = s.tagRepo.SetMaterialTags(ctx, material.ID, tags); err != nil {
+			return nil, fmt.Errorf("设置资料标签失败: %w", err)
+		}
+	}
+
+	// 异步生成缩略图和文本摘要
+	s.enqueuePreview(ctx, material)
+
 	return material.ToMaterialResponse(), nil
 }
 
@@ -143,6 +261,28 @@ func (s *materialService) UpdateMaterial(ctx context.Context, materialID, userID
 		return nil, fmt.Errorf("无效的资料类型: %s", req.Category)
 	}
 
+	if req.CourseTerm != "" && !model.IsValidCourseTerm(req.CourseTerm) {
+		return nil, ErrInvalidCourseTerm
+	}
+
+	// 关联课程：指定课程或按课程名称、别名解析
+	course, err := resolveCourse(ctx, s.courseRepo, req.CourseID, req.CourseName)
+	if err != nil {
+		return nil, err
+	}
+	courseName := req.CourseName
+	if courseName == "" && course != nil {
+		courseName = course.Name
+	}
+
+	// 未传标签时保留原有标签
+	var tags []string
+	if req.Tags != nil {
+		if tags, err = normalizeTags(req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
 	// 获取资料
 	material, err := s.materialRepo.FindByID(ctx, materialID)
 	if err != nil {
@@ -161,7 +301,7 @@ func (s *materialService) UpdateMaterial(ctx context.Context, materialID, userID
 	}
 
 	// 非管理员只能修改待审核或已拒绝的资料
-	if !isAdmin && material.Status == model.StatusApproved {
+	if !isAdmin && material.Status.IsPublic() {
 		return nil, ErrMaterialAlreadyApproved
 	}
 
@@ -169,20 +309,31 @@ func (s *materialService) UpdateMaterial(ctx context.Context, materialID, userID
 	material.Title = req.Title
 	material.Description = req.Description
 	material.Category = req.Category
-	material.CourseName = req.CourseName
+	material.CourseName = courseName
+	material.CourseID = courseIDOf(course)
+	material.CourseTerm = req.CourseTerm
 
 	// 管理员修改不改变状态,学委修改重新提交审核
 	if !isAdmin {
 		material.Status = model.StatusPending
 	}
 
-	// 更新搜索向量
-	material.SearchVector = s.generateSearchVector(req.Title, req.Description, req.CourseName)
-
 	if err := s.materialRepo.Update(ctx, material); err != nil {
 		return nil, fmt.Errorf("更新资料失败: %w", err)
 	}
 
+	// 更新标签
+	if req.Tags != nil {
+		if material.Tags, err = s.tagRepo.SetMaterialTags(ctx, material.ID, tags); err != nil {
+			return nil, fmt.Errorf("更新资料标签失败: %w", err)
+		}
+	} else if tagMap, err := s.tagRepo.ListByMaterials(ctx, []uint{material.ID}); err == nil {
+		material.Tags = tagMap[material.ID]
+	}
+
+	// 同步当前版本的元数据快照
+	s.syncCurrentVersion(ctx, material)
+
 	// 清除缓存
 	s.clearMaterialCache(ctx, materialID)
 
@@ -208,8 +359,8 @@ func (s *materialService) GetMaterial(ctx context.Context, materialID, currentUs
 		return nil, fmt.Errorf("获取资料失败: %w", err)
 	}
 
-	// 权限检查：只有已通过审核的资料对所有用户可见
-	if material.Status != model.StatusApproved {
+	// 权限检查：只有已通过审核（含已归档）的资料对所有用户可见
+	if !material.Status.IsPublic() {
 		// 只有上传者和管理员可以查看未审核的资料
 		if material.UploaderID != currentUserID {
 			return nil, ErrAccessDenied
@@ -223,6 +374,7 @@ func (s *materialService) GetMaterial(ctx context.Context, materialID, currentUs
 	}
 
 	response := material.ToMaterialResponse()
+	s.attachThumbnailURL(ctx, material, response)
 
 	// 检查当前用户是否已收藏
 	if currentUserID > 0 {
@@ -238,11 +390,25 @@ func (s *materialService) ListMaterials(ctx context.Context, req *model.Material
 	// 构建查询选项
 	opts := &repository.MaterialListOptions{
 		CourseName: req.CourseName,
+		CourseID:   req.CourseID,
 		Keyword:    req.Keyword,
 		SortBy:     req.SortBy,
 		SortOrder:  req.SortOrder,
 	}
 
+	for _, tag := range req.Tags {
+		if tag = model.NormalizeTagName(tag); tag != "" {
+			opts.Tags = append(opts.Tags, tag)
+		}
+	}
+
+	// 课程名称能解析到课程（含别名，如“高数”）时按课程筛选
+	if opts.CourseID == nil && req.CourseName != "" {
+		if course, err := resolveCourse(ctx, s.courseRepo, nil, req.CourseName); err == nil && course != nil {
+			opts.CourseID = &course.ID
+		}
+	}
+
 	if req.Category != "" {
 		opts.Category = &req.Category
 	}
@@ -276,6 +442,7 @@ func (s *materialService) ListMaterials(ctx context.Context, req *model.Material
 	responses := make([]*model.MaterialResponse, 0, len(materials))
 	for _, material := range materials {
 		response := material.ToMaterialResponse()
+		s.attachThumbnailURL(ctx, material, response)
 		// 检查是否已收藏
 		if currentUserID > 0 {
 			favorited, _ := s.favoriteRepo.Exists(ctx, currentUserID, material.ID)
@@ -284,6 +451,11 @@ func (s *materialService) ListMaterials(ctx context.Context, req *model.Material
 		responses = append(responses, response)
 	}
 
+	// 管理员查看待审核列表时，标记与已通过资料内容重复的上传
+	if currentUserRole == "admin" && req.Status == model.StatusPending {
+		s.attachDuplicates(ctx, responses)
+	}
+
 	totalPages := int(total) / req.PageSize
 	if int(total)%req.PageSize > 0 {
 		totalPages++
@@ -309,10 +481,15 @@ func (s *materialService) DeleteMaterial(ctx context.Context, materialID uint) e
 		return fmt.Errorf("获取资料失败: %w", err)
 	}
 
-	// 删除 OSS 文件
-	if err := s.ossService.DeleteFile(ctx, material.FileKey); err != nil {
-		// 记录错误但继续删除数据库记录
-		fmt.Printf("删除 OSS 文件失败: %v\n", err)
+	// 删除 OSS 文件（包括全部历史版本及其预览缩略图）
+	for _, fileKey := range s.collectMaterialFileKeys(ctx, material) {
+		if err := s.ossService.DeleteFile(ctx, fileKey); err != nil {
+			// 记录错误但继续删除数据库记录
+			fmt.Printf("删除 OSS 文件失败: %v\n", err)
+		}
+		if err := s.ossService.DeleteFile(ctx, previewThumbnailKey(fileKey)); err != nil {
+			fmt.Printf("删除预览缩略图失败: %v\n", err)
+		}
 	}
 
 	// 删除资料记录
@@ -320,6 +497,16 @@ func (s *materialService) DeleteMaterial(ctx context.Context, materialID uint) e
 		return fmt.Errorf("删除资料失败: %w", err)
 	}
 
+	// 移出搜索联想
+	if s.suggestService != nil {
+		s.suggestService.RemoveMaterial(ctx, material)
+	}
+
+	// 删除版本记录
+	if err := s.versionRepo.DeleteByMaterial(ctx, materialID); err != nil {
+		fmt.Printf("删除资料版本失败: %v\n", err)
+	}
+
 	// 清除缓存
 	s.clearMaterialCache(ctx, materialID)
 
@@ -357,6 +544,11 @@ func (s *materialService) ReviewMaterial(ctx context.Context, materialID, review
 		return fmt.Errorf("更新审核状态失败: %w", err)
 	}
 
+	// 审核通过的资料加入搜索联想
+	if req.Status == model.StatusApproved && s.suggestService != nil {
+		s.suggestService.AddMaterial(ctx, material)
+	}
+
 	// 清除缓存
 	s.clearMaterialCache(ctx, materialID)
 
@@ -365,6 +557,11 @@ func (s *materialService) ReviewMaterial(ctx context.Context, materialID, review
 
 // GetUploadSignature 获取上传签名
 func (s *materialService) GetUploadSignature(ctx context.Context, userID uint, req *model.UploadSignatureRequest) (*model.UploadSignatureResponse, error) {
+	// 检查存储配额
+	if err := s.CheckStorageQuota(ctx, userID, req.FileSize); err != nil {
+		return nil, err
+	}
+
 	result, err := s.ossService.GenerateUploadSignature(ctx, userID, req.FileName, req.FileSize, req.MimeType)
 	if err != nil {
 		return nil, fmt.Errorf("生成上传签名失败: %w", err)
@@ -379,45 +576,13 @@ func (s *materialService) GetUploadSignature(ctx context.Context, userID uint, r
 	}, nil
 }
 
-// GetDownloadURL 获取下载链接
-func (s *materialService) GetDownloadURL(ctx context.Context, materialID, userID uint) (string, error) {
-	// 获取资料
-	material, err := s.materialRepo.FindByID(ctx, materialID)
-	if err != nil {
-		if errors.Is(err, repository.ErrMaterialNotFound) {
-			return "", ErrMaterialNotFound
-		}
-		return "", fmt.Errorf("获取资料失败: %w", err)
-	}
-
-	// 检查状态：只能下载已审核通过的资料
-	if material.Status != model.StatusApproved {
-		return "", ErrAccessDenied
-	}
-
-	dailyLimit := s.getDailyDownloadLimit(ctx)
-	if dailyLimit > 0 {
-		now := time.Now()
-		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-		downloaded, err := s.downloadRepo.CountByUserSince(ctx, userID, startOfDay)
-		if err != nil {
-			return "", fmt.Errorf("统计下载次数失败: %w", err)
-		}
-		if downloaded >= int64(dailyLimit) {
-			return "", ErrDownloadLimitExceeded
-		}
-	}
-
-	// 生成下载签名
-	downloadURL, err := s.ossService.GenerateDownloadSignature(ctx, material.FileKey)
-	if err != nil {
-		return "", fmt.Errorf("生成下载签名失败: %w", err)
-	}
-
+// recordDownload 记录下载并累加资料下载次数（下载统计归属于资料本身，而非具体版本）
+func (s *materialService) recordDownload(ctx context.Context, userID, materialID uint, versionID *uint) {
 	// 创建下载记录
 	record := &model.DownloadRecord{
 		UserID:     userID,
 		MaterialID: materialID,
+		VersionID:  versionID,
 	}
 	if err := s.downloadRepo.Create(ctx, record); err != nil {
 		// 记录错误但不影响下载
@@ -432,37 +597,9 @@ func (s *materialService) GetDownloadURL(ctx context.Context, materialID, userID
 
 	// 清除缓存
 	s.clearMaterialCache(ctx, materialID)
-
-	return downloadURL, nil
-}
-
-// GetDownloadQuota 获取下载配额
-func (s *materialService) GetDownloadQuota(ctx context.Context, userID uint) (*model.DownloadQuotaResponse, error) {
-	limit := s.getDailyDownloadLimit(ctx)
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-
-	used, err := s.downloadRepo.CountByUserSince(ctx, userID, startOfDay)
-	if err != nil {
-		return nil, fmt.Errorf("统计下载次数失败: %w", err)
-	}
-
-	unlimited := limit <= 0
-	remaining := int64(limit) - used
-	if unlimited {
-		remaining = -1
-	} else if remaining < 0 {
-		remaining = 0
-	}
-
-	return &model.DownloadQuotaResponse{
-		Limit:     limit,
-		Used:      used,
-		Remaining: remaining,
-		Unlimited: unlimited,
-	}, nil
 }
 
+// getDailyDownloadLimit 获取默认的每日下载次数限制，配置不存在时自动创建
 func (s *materialService) getDailyDownloadLimit(ctx context.Context) int {
 	if s.configRepo == nil {
 		return defaultDailyLimit
@@ -509,10 +646,22 @@ func (s *materialService) SearchMaterials(ctx context.Context, keyword string, p
 		return nil, fmt.Errorf("搜索资料失败: %w", err)
 	}
 
+	// 标注命中字段
+	ids := make([]uint, 0, len(materials))
+	for _, material := range materials {
+		ids = append(ids, material.ID)
+	}
+	matchedFields, err := s.materialRepo.FindMatchedFields(ctx, ids, segmenter.Default().PrefixQuery(keyword), []string{keyword})
+	if err != nil {
+		fmt.Printf("获取命中字段失败: %v\n", err)
+	}
+
 	// 转换为响应格式
 	responses := make([]*model.MaterialResponse, 0, len(materials))
 	for _, material := range materials {
 		response := material.ToMaterialResponse()
+		s.attachThumbnailURL(ctx, material, response)
+		response.MatchedFields = matchedFields[material.ID]
 		responses = append(responses, response)
 	}
 
@@ -535,22 +684,6 @@ func (s *materialService) SearchMaterials(ctx context.Context, keyword string, p
 	return result, nil
 }
 
-// generateSearchVector 生成搜索向量
-func (s *materialService) generateSearchVector(title, description, courseName string) string {
-	// 将标题、描述、课程名称组合成搜索向量
-	parts := make([]string, 0, 3)
-	if title != "" {
-		parts = append(parts, title)
-	}
-	if description != "" {
-		parts = append(parts, description)
-	}
-	if courseName != "" {
-		parts = append(parts, courseName)
-	}
-	return strings.Join(parts, " ")
-}
-
 // getMaterialCacheKey 获取资料缓存键
 func (s *materialService) getMaterialCacheKey(materialID uint) string {
 	return fmt.Sprintf("material:%d", materialID)
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/oss"
+)
+
+var (
+	// ErrInvalidFileKey 无效的文件存储键
+	ErrInvalidFileKey = errors.New("无效的文件路径")
+	// ErrFileKeyForbidden 文件不属于当前用户
+	ErrFileKeyForbidden = errors.New("无权使用该文件")
+	// ErrFileKeyInUse 文件已被使用
+	ErrFileKeyInUse = errors.New("该文件已被其他资料使用")
+	// ErrUploadedFileNotFound 文件未上传
+	ErrUploadedFileNotFound = errors.New("文件尚未上传到存储服务")
+	// ErrUploadedFileMismatch 文件信息不一致
+	ErrUploadedFileMismatch = errors.New("上传的文件与声明的信息不一致")
+	// ErrFileTypeMismatch 文件实际类型与声明不符
+	ErrFileTypeMismatch = errors.New("文件实际类型与声明的类型不符")
+)
+
+const (
+	mimeSniffActionKey = "mime_sniff_action"
+	// mimeSniffActionBlock 类型不符时拒绝上传
+	mimeSniffActionBlock = "block"
+	// mimeSniffActionFlag 类型不符时仅标记，交由审核人员判断
+	mimeSniffActionFlag = "flag"
+)
+
+// fileTypeInspection 文件类型检测结果
+type fileTypeInspection struct {
+	DetectedMimeType string
+	Mismatch         bool
+}
+
+// FinalizeUpload 确认上传完成
+// 校验文件确实存在于 OSS 中、归属于当前用户且未被其他资料使用，并返回 OSS 中记录的实际文件信息与内容哈希
+func (s *materialService) FinalizeUpload(ctx context.Context, userID uint, req *model.FinalizeUploadRequest) (*model.FinalizeUploadResponse, error) {
+	info, err := s.verifyUploadedFile(ctx, userID, req.FileKey, req.FileSize, req.MimeType, req.Checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	inspection, err := s.inspectFileType(ctx, req.FileKey, req.MimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.FinalizeUploadResponse{
+		FileKey:          info.Key,
+		FileSize:         info.Size,
+		MimeType:         normalizeMimeType(info.ContentType),
+		DetectedMimeType: inspection.DetectedMimeType,
+		MimeMismatch:     inspection.Mismatch,
+		ETag:             info.ETag,
+		ContentHash:      s.contentHash(ctx, info),
+	}
+
+	// 提前告知上传者已存在内容相同的资料
+	if original := s.findDuplicate(ctx, response.ContentHash); original != nil {
+		response.DuplicateOf = &model.DuplicateMaterialInfo{
+			ID:    original.ID,
+			Title: original.Title,
+		}
+	}
+
+	return response, nil
+}
+
+// verifyUploadedFile 校验客户端声明的文件信息与 OSS 中的实际对象一致
+func (s *materialService) verifyUploadedFile(ctx context.Context, userID uint, fileKey string, fileSize int64, mimeType, checksum string) (*oss.ObjectInfo, error) {
+	// 文件必须位于当前用户的上传目录下: materials/{userID}/{uuid}_{fileName}
+	prefix := fmt.Sprintf("materials/%d/", userID)
+	if !strings.HasPrefix(fileKey, "materials/") || strings.Contains(fileKey, "..") {
+		return nil, ErrInvalidFileKey
+	}
+	if !strings.HasPrefix(fileKey, prefix) || len(fileKey) == len(prefix) {
+		return nil, ErrFileKeyForbidden
+	}
+
+	// 同一个文件只能被一个资料（或版本）使用
+	used, err := s.materialRepo.ExistsByFileKey(ctx, fileKey)
+	if err != nil {
+		return nil, fmt.Errorf("检查文件使用情况失败: %w", err)
+	}
+	if !used {
+		used, err = s.versionRepo.ExistsByFileKey(ctx, fileKey)
+		if err != nil {
+			return nil, fmt.Errorf("检查文件使用情况失败: %w", err)
+		}
+	}
+	if used {
+		return nil, ErrFileKeyInUse
+	}
+
+	info, err := s.ossService.StatFile(ctx, fileKey)
+	if err != nil {
+		if errors.Is(err, oss.ErrFileNotFound) {
+			return nil, ErrUploadedFileNotFound
+		}
+		return nil, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+
+	if info.Size != fileSize {
+		return nil, fmt.Errorf("%w: 文件大小应为 %d 字节，实际为 %d 字节", ErrUploadedFileMismatch, fileSize, info.Size)
+	}
+
+	// 未设置 Content-Type 上传的对象会被记录为 application/octet-stream，此时无法比较
+	actualType := normalizeMimeType(info.ContentType)
+	if actualType != "" && actualType != "application/octet-stream" && actualType != normalizeMimeType(mimeType) {
+		return nil, fmt.Errorf("%w: 文件类型应为 %s，实际为 %s", ErrUploadedFileMismatch, mimeType, actualType)
+	}
+
+	// 普通上传的 ETag 即文件 MD5；分片上传的 ETag 形如 "{md5}-{parts}"，无法直接比较
+	if checksum != "" {
+		etag := strings.ToLower(strings.Trim(info.ETag, `"`))
+		if !strings.Contains(etag, "-") && etag != strings.ToLower(checksum) {
+			return nil, fmt.Errorf("%w: 文件校验值不匹配", ErrUploadedFileMismatch)
+		}
+	}
+
+	return info, nil
+}
+
+// inspectFileType 读取文件头检测真实类型
+// 客户端声明的 MIME 类型不可信，类型不符时按系统配置 mime_sniff_action 拒绝或仅标记；可执行文件始终拒绝
+func (s *materialService) inspectFileType(ctx context.Context, fileKey, declaredMimeType string) (*fileTypeInspection, error) {
+	detected, err := s.ossService.SniffFile(ctx, fileKey)
+	if err != nil {
+		if errors.Is(err, oss.ErrFileNotFound) {
+			return nil, ErrUploadedFileNotFound
+		}
+		return nil, fmt.Errorf("检测文件类型失败: %w", err)
+	}
+
+	inspection := &fileTypeInspection{
+		DetectedMimeType: detected,
+		Mismatch:         !oss.IsCompatibleMimeType(declaredMimeType, detected),
+	}
+	if !inspection.Mismatch {
+		return inspection, nil
+	}
+
+	if detected == oss.MimeTypeExecutable || s.getMimeSniffAction() == mimeSniffActionBlock {
+		return nil, fmt.Errorf("%w: 声明为 %s，实际为 %s", ErrFileTypeMismatch, declaredMimeType, detected)
+	}
+	return inspection, nil
+}
+
+// getMimeSniffAction 获取类型不符时的处理方式，默认拒绝
+func (s *materialService) getMimeSniffAction() string {
+	if s.configRepo == nil {
+		return mimeSniffActionBlock
+	}
+
+	config, err := s.configRepo.GetSystemConfig(mimeSniffActionKey)
+	if err != nil {
+		return mimeSniffActionBlock
+	}
+
+	if strings.ToLower(strings.TrimSpace(config.ConfigValue)) == mimeSniffActionFlag {
+		return mimeSniffActionFlag
+	}
+	return mimeSniffActionBlock
+}
+
+// normalizeMimeType 去除 MIME 类型中的参数并转为小写
+func normalizeMimeType(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(mimeType))
+	}
+	return mediaType
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/repository"
+)
+
+var (
+	// ErrMaterialVersionNotFound 资料版本不存在
+	ErrMaterialVersionNotFound = errors.New("资料版本不存在")
+	// ErrVersionAlreadyReviewed 版本已审核
+	ErrVersionAlreadyReviewed = errors.New("该版本已审核")
+	// ErrVersionNotApproved 版本未审核通过
+	ErrVersionNotApproved = errors.New("只能回滚到已审核通过的版本")
+	// ErrVersionIsCurrent 版本已是当前版本
+	ErrVersionIsCurrent = errors.New("该版本已是当前版本")
+	// ErrVersionFileKeyExists 版本文件已被使用
+	ErrVersionFileKeyExists = errors.New("该文件已被其他资料或版本使用")
+	// ErrMaterialArchived 资料已归档
+	ErrMaterialArchived = errors.New("资料已归档，请先恢复后再上传新版本")
+)
+
+// UploadVersion 上传资料新版本
+// 学委上传的新版本需要审核通过后才会替换当前版本，管理员上传的版本直接生效
+func (s *materialService) UploadVersion(ctx context.Context, materialID, userID uint, userRole string, req *model.UploadMaterialVersionRequest) (*model.MaterialVersionResponse, error) {
+	// 验证文件
+	if err := s.ossService.ValidateFile(req.FileName, req.FileSize, req.MimeType); err != nil {
+		return nil, fmt.Errorf("文件验证失败: %w", err)
+	}
+
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+
+	// 检查权限：只有上传者和管理员可以上传新版本
+	isAdmin := userRole == "admin"
+	if !isAdmin && material.UploaderID != userID {
+		return nil, ErrAccessDenied
+	}
+
+	// 已归档资料的文件位于归档存储中，新版本需在恢复后上传
+	if material.Status == model.StatusArchived {
+		return nil, ErrMaterialArchived
+	}
+
+	// 历史版本的文件仍占用存储，新版本同样计入上传者的配额
+	if err := s.CheckStorageQuota(ctx, userID, req.FileSize); err != nil {
+		return nil, err
+	}
+
+	// 校验文件确实已上传且归属于当前用户
+	info, err := s.verifyUploadedFile(ctx, userID, req.FileKey, req.FileSize, req.MimeType, req.Checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	// 根据文件头检测真实类型
+	inspection, err := s.inspectFileType(ctx, req.FileKey, req.MimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	// 未填写的元数据沿用当前版本
+	version := model.NewMaterialVersionSnapshot(material, 0)
+	version.FileName = req.FileName
+	version.FileSize = req.FileSize
+	version.FileKey = req.FileKey
+	version.MimeType = req.MimeType
+	version.ContentHash = s.contentHash(ctx, info)
+	version.DetectedMimeType = inspection.DetectedMimeType
+	version.MimeMismatch = inspection.Mismatch
+	version.ChangeNote = req.ChangeNote
+	version.UploaderID = userID
+	if req.Title != "" {
+		version.Title = req.Title
+	}
+	if req.Description != nil {
+		version.Description = *req.Description
+	}
+	if req.Category != "" {
+		// 验证资料类型(动态验证)
+		if _, err := s.categoryRepo.GetByCode(string(req.Category)); err != nil {
+			return nil, fmt.Errorf("无效的资料类型: %s", req.Category)
+		}
+		version.Category = req.Category
+	}
+	if req.CourseName != "" {
+		version.CourseName = req.CourseName
+	}
+
+	if isAdmin {
+		version.Status = model.StatusApproved
+		version.ReviewerID = &userID
+	} else {
+		version.Status = model.StatusPending
+	}
+
+	if err := s.versionRepo.Create(ctx, version); err != nil {
+		if errors.Is(err, repository.ErrMaterialAlreadyExists) {
+			return nil, ErrVersionFileKeyExists
+		}
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("创建资料版本失败: %w", err)
+	}
+
+	// 管理员上传的版本直接切换为当前版本
+	if version.Status == model.StatusApproved {
+		if err := s.applyVersion(ctx, material, version); err != nil {
+			return nil, err
+		}
+	}
+
+	return version.ToMaterialVersionResponse(material.CurrentVersionID), nil
+}
+
+// ListVersions 获取资料版本历史
+// 普通用户只能看到已审核通过的版本，上传者和管理员可以看到全部版本
+func (s *materialService) ListVersions(ctx context.Context, materialID, userID uint, userRole string) ([]*model.MaterialVersionResponse, error) {
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+
+	privileged := userRole == "admin" || material.UploaderID == userID
+	if !privileged && !material.Status.IsPublic() {
+		return nil, ErrAccessDenied
+	}
+
+	var statuses []model.MaterialStatus
+	if !privileged {
+		statuses = []model.MaterialStatus{model.StatusApproved}
+	}
+
+	versions, err := s.versionRepo.ListByMaterial(ctx, materialID, statuses)
+	if err != nil {
+		return nil, fmt.Errorf("获取资料版本失败: %w", err)
+	}
+
+	responses := make([]*model.MaterialVersionResponse, 0, len(versions))
+	for _, version := range versions {
+		responses = append(responses, version.ToMaterialVersionResponse(material.CurrentVersionID))
+	}
+	return responses, nil
+}
+
+// GetVersionDownloadURL 获取指定版本的下载链接
+// 与当前版本的下载经过同一处权限、下载次数、下载方式和水印检查；兑换时记录下载，下载记录和下载次数归属于资料本身，同时记录具体下载的版本
+func (s *materialService) GetVersionDownloadURL(ctx context.Context, materialID, versionID, userID uint, userRole, clientIP string, req *model.DownloadLinkRequest) (*model.DownloadLinkResponse, error) {
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+
+	version, err := s.versionRepo.FindByMaterialAndID(ctx, materialID, versionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialVersionNotFound) {
+			return nil, ErrMaterialVersionNotFound
+		}
+		return nil, fmt.Errorf("获取资料版本失败: %w", err)
+	}
+
+	privileged := userRole == "admin" || material.UploaderID == userID
+	return s.issueDownloadLink(ctx, material, version, userID, privileged, clientIP, req)
+}
+
+// ReviewVersion 审核资料版本
+// 审核通过的版本会成为资料的当前版本
+func (s *materialService) ReviewVersion(ctx context.Context, materialID, versionID, reviewerID uint, req *model.ReviewMaterialVersionRequest) error {
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return ErrMaterialNotFound
+		}
+		return fmt.Errorf("获取资料失败: %w", err)
+	}
+
+	version, err := s.versionRepo.FindByMaterialAndID(ctx, materialID, versionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialVersionNotFound) {
+			return ErrMaterialVersionNotFound
+		}
+		return fmt.Errorf("获取资料版本失败: %w", err)
+	}
+
+	// 检查状态
+	if version.Status != model.StatusPending {
+		return ErrVersionAlreadyReviewed
+	}
+
+	// 如果是拒绝状态，必须填写拒绝原因
+	var rejectionReason string
+	if req.Status == model.StatusRejected {
+		if req.RejectionReason == "" {
+			return errors.New("拒绝时必须填写拒绝原因")
+		}
+		rejectionReason = req.RejectionReason
+	}
+
+	if err := s.versionRepo.UpdateReviewStatus(ctx, versionID, req.Status, &reviewerID, rejectionReason); err != nil {
+		return fmt.Errorf("更新版本审核状态失败: %w", err)
+	}
+
+	if req.Status == model.StatusApproved {
+		version.Status = model.StatusApproved
+		if err := s.applyVersion(ctx, material, version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RollbackVersion 回滚到指定版本
+func (s *materialService) RollbackVersion(ctx context.Context, materialID, versionID uint) (*model.MaterialResponse, error) {
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+
+	version, err := s.versionRepo.FindByMaterialAndID(ctx, materialID, versionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialVersionNotFound) {
+			return nil, ErrMaterialVersionNotFound
+		}
+		return nil, fmt.Errorf("获取资料版本失败: %w", err)
+	}
+
+	if version.Status != model.StatusApproved {
+		return nil, ErrVersionNotApproved
+	}
+	if material.CurrentVersionID != nil && *material.CurrentVersionID == version.ID {
+		return nil, ErrVersionIsCurrent
+	}
+
+	if err := s.applyVersion(ctx, material, version); err != nil {
+		return nil, err
+	}
+
+	return material.ToMaterialResponse(), nil
+}
+
+// ListPendingVersions 获取待审核的版本列表
+func (s *materialService) ListPendingVersions(ctx context.Context, page, pageSize int) ([]*model.MaterialVersionResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	versions, total, err := s.versionRepo.ListPending(ctx, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取待审核版本失败: %w", err)
+	}
+
+	responses := make([]*model.MaterialVersionResponse, 0, len(versions))
+	for _, version := range versions {
+		responses = append(responses, version.ToMaterialVersionResponse(nil))
+	}
+	return responses, total, nil
+}
+
+// applyVersion 将版本切换为资料的当前版本
+func (s *materialService) applyVersion(ctx context.Context, material *model.Material, version *model.MaterialVersion) error {
+	previousCourseName := material.CourseName
+	material.ApplyVersion(version)
+	// 版本修改了课程名称时重新解析关联课程
+	if material.CourseName != previousCourseName {
+		course, err := resolveCourse(ctx, s.courseRepo, nil, material.CourseName)
+		if err != nil {
+			return err
+		}
+		material.CourseID = courseIDOf(course)
+	}
+
+	if err := s.materialRepo.Update(ctx, material); err != nil {
+		return fmt.Errorf("切换资料版本失败: %w", err)
+	}
+
+	// 为新的当前文件重新生成预览
+	s.enqueuePreview(ctx, material)
+
+	// 清除缓存
+	s.clearMaterialCache(ctx, material.ID)
+
+	return nil
+}
+
+// createInitialVersion 为新建资料创建第 1 个版本
+func (s *materialService) createInitialVersion(ctx context.Context, material *model.Material) error {
+	version := model.NewMaterialVersionSnapshot(material, 1)
+	if err := s.versionRepo.Create(ctx, version); err != nil {
+		return err
+	}
+
+	versionID := version.ID
+	material.CurrentVersionID = &versionID
+	material.CurrentVersion = version.VersionNumber
+	return s.materialRepo.Update(ctx, material)
+}
+
+// syncCurrentVersion 将资料元数据的修改同步到当前版本快照
+func (s *materialService) syncCurrentVersion(ctx context.Context, material *model.Material) {
+	if material.CurrentVersionID == nil {
+		return
+	}
+
+	version, err := s.versionRepo.FindByID(ctx, *material.CurrentVersionID)
+	if err != nil {
+		fmt.Printf("获取当前版本失败: %v\n", err)
+		return
+	}
+
+	version.Title = material.Title
+	version.Description = material.Description
+	version.Category = material.Category
+	version.CourseName = material.CourseName
+	version.Status = material.Status
+	if err := s.versionRepo.Update(ctx, version); err != nil {
+		fmt.Printf("同步版本快照失败: %v\n", err)
+	}
+}
+
+// collectMaterialFileKeys 收集资料及其全部版本的文件存储键
+func (s *materialService) collectMaterialFileKeys(ctx context.Context, material *model.Material) []string {
+	fileKeys := []string{material.FileKey}
+	seen := map[string]bool{material.FileKey: true}
+
+	versions, err := s.versionRepo.ListByMaterial(ctx, material.ID, nil)
+	if err != nil {
+		fmt.Printf("获取资料版本失败: %v\n", err)
+		return fileKeys
+	}
+
+	for _, version := range versions {
+		if !seen[version.FileKey] {
+			seen[version.FileKey] = true
+			fileKeys = append(fileKeys, version.FileKey)
+		}
+	}
+	return fileKeys
+}
@@ -3,18 +3,32 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/study-upc/backend/internal/model"
 	"github.com/study-upc/backend/internal/repository"
 	"gorm.io/gorm"
 )
 
+// 相关资料打分权重，满分为 1
+const (
+	relatedTagWeight        = 0.15 // 每个共同标签
+	relatedMaxTagScore      = 0.45 // 共同标签得分上限
+	relatedCourseWeight     = 0.3  // 同一课程
+	relatedCategoryWeight   = 0.15 // 同一分类
+	relatedPopularityWeight = 0.1  // 热度
+	relatedCandidateFactor  = 5    // 候选资料数为返回数量的倍数
+	maxRelatedCandidates    = 200
+)
+
 // recommendationService 推荐服务实现
 type recommendationService struct {
 	db             *gorm.DB
 	materialRepo   repository.MaterialRepository
 	downloadRepo   repository.DownloadRecordRepository
 	favoriteRepo   repository.FavoriteRepository
+	courseRepo     repository.CourseRepository
 }
 
 // NewRecommendationService 创建推荐服务实例
@@ -23,12 +37,14 @@ func NewRecommendationService(
 	materialRepo repository.MaterialRepository,
 	downloadRepo repository.DownloadRecordRepository,
 	favoriteRepo repository.FavoriteRepository,
+	courseRepo repository.CourseRepository,
 ) RecommendationService {
 	return &recommendationService{
 		db:           db,
 		materialRepo: materialRepo,
 		downloadRepo: downloadRepo,
 		favoriteRepo: favoriteRepo,
+		courseRepo:   courseRepo,
 	}
 }
 
@@ -87,10 +103,11 @@ func (s *recommendationService) GetRecommendations(ctx context.Context, userID u
 // GetHotMaterials 获取热门资料
 func (s *recommendationService) GetHotMaterials(ctx context.Context, limit int) ([]*model.Material, error) {
 	var materials []*model.Material
-	// 综合下载量、收藏量和浏览量计算热度
+	// 综合下载量、收藏量、浏览量和评分计算热度
+	// 评分以 3 分为中性，按评分人数（最多计 50 人）放大，避免个别评分左右排名
 	err := s.db.WithContext(ctx).
 		Where("status = ?", model.StatusApproved).
-		Order("(download_count * 3 + favorite_count * 5 + view_count) DESC, created_at DESC").
+		Order("(download_count * 3 + favorite_count * 5 + view_count + (rating_average - 3) * LEAST(rating_count, 50) * 5) DESC, created_at DESC").
 		Limit(limit).
 		Find(&materials).Error
 
@@ -116,14 +133,17 @@ func (s *recommendationService) GetPersonalizedRecommendations(ctx context.Conte
 	}
 
 	// 3. 分析用户偏好
+	// 课程按关联的课程统计，同一课程的不同别名（如“高数”和“高等数学”）计为同一门
 	categoryCount := make(map[string]int)
 	courseCount := make(map[string]int)
+	courseSample := make(map[string]*model.Material)
 
 	for _, d := range downloads {
 		if d.Material != nil {
 			categoryCount[string(d.Material.Category)]++
-			if d.Material.CourseName != "" {
-				courseCount[d.Material.CourseName]++
+			if key := courseKey(d.Material); key != "" {
+				courseCount[key]++
+				courseSample[key] = d.Material
 			}
 		}
 	}
@@ -131,15 +151,16 @@ func (s *recommendationService) GetPersonalizedRecommendations(ctx context.Conte
 	for _, f := range favorites {
 		if f.Material != nil {
 			categoryCount[string(f.Material.Category)]++
-			if f.Material.CourseName != "" {
-				courseCount[f.Material.CourseName]++
+			if key := courseKey(f.Material); key != "" {
+				courseCount[key]++
+				courseSample[key] = f.Material
 			}
 		}
 	}
 
 	// 4. 找出最热门的分类和课程
 	var topCategory string
-	var topCourse string
+	var topCourse *model.Material
 	maxCount := 0
 
 	for cat, count := range categoryCount {
@@ -150,10 +171,10 @@ func (s *recommendationService) GetPersonalizedRecommendations(ctx context.Conte
 	}
 
 	maxCount = 0
-	for course, count := range courseCount {
+	for key, count := range courseCount {
 		if count > maxCount {
 			maxCount = count
-			topCourse = course
+			topCourse = courseSample[key]
 		}
 	}
 
@@ -168,8 +189,14 @@ func (s *recommendationService) GetPersonalizedRecommendations(ctx context.Conte
 		query = query.Where("category = ?", topCategory)
 	}
 	// 如果有偏好的课程，也作为筛选条件
-	if topCourse != "" {
-		query = query.Where("course_name = ?", topCourse)
+	var topCourseName string
+	if topCourse != nil {
+		if topCourse.CourseID != nil {
+			query = query.Where("course_id = ?", *topCourse.CourseID)
+		} else {
+			query = query.Where("course_name = ?", topCourse.CourseName)
+		}
+		topCourseName = s.courseDisplayName(ctx, topCourse)
 	}
 
 	var materials []*model.Material
@@ -184,8 +211,8 @@ func (s *recommendationService) GetPersonalizedRecommendations(ctx context.Conte
 		if string(m.Category) == topCategory {
 			reason = fmt.Sprintf("基于您喜欢的 %s 类资料推荐", m.Category)
 		}
-		if m.CourseName == topCourse {
-			reason = fmt.Sprintf("基于 %s 课程相关资料推荐", m.CourseName)
+		if topCourse != nil && sameCourse(m, topCourse) {
+			reason = fmt.Sprintf("基于 %s 课程相关资料推荐", topCourseName)
 		}
 
 		results = append(results, &model.RecommendationResult{
@@ -199,44 +226,95 @@ func (s *recommendationService) GetPersonalizedRecommendations(ctx context.Conte
 }
 
 // GetRelatedMaterials 获取相关资料
+// 候选资料为同分类、同课程或有共同标签的已通过资料，按共同标签数、课程、分类和热度综合打分
 func (s *recommendationService) GetRelatedMaterials(ctx context.Context, materialID uint, limit int) ([]*model.RecommendationResult, error) {
 	// 1. 获取原资料
 	var material model.Material
-	if err := s.db.WithContext(ctx).First(&material, materialID).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("Tags").First(&material, materialID).Error; err != nil {
 		return nil, fmt.Errorf("获取资料失败: %w", err)
 	}
 
-	// 2. 查找相关资料（相同分类或相同课程）
+	// 2. 查找候选资料（相同分类、相同课程或有共同标签）
+	courseCond, courseArg := "course_name = ?", interface{}(material.CourseName)
+	if material.CourseID != nil {
+		courseCond, courseArg = "course_id = ?", *material.CourseID
+	}
+	cond := "category = ? OR " + courseCond
+	args := []interface{}{material.Category, courseArg}
+	tagIDs := make([]uint, 0, len(material.Tags))
+	for _, tag := range material.Tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	if len(tagIDs) > 0 {
+		cond += " OR id IN (SELECT material_id FROM material_tags WHERE tag_id IN ?)"
+		args = append(args, tagIDs)
+	}
+
+	candidateLimit := limit * relatedCandidateFactor
+	if candidateLimit > maxRelatedCandidates {
+		candidateLimit = maxRelatedCandidates
+	}
+
 	var materials []*model.Material
 	err := s.db.WithContext(ctx).
 		Where("status = ? AND id != ?", model.StatusApproved, materialID).
-		Where("(category = ? OR course_name = ?)", material.Category, material.CourseName).
+		Where("("+cond+")", args...).
 		Order("download_count DESC, favorite_count DESC").
-		Limit(limit).
+		Limit(candidateLimit).
+		Preload("Tags").
 		Find(&materials).Error
 
 	if err != nil {
 		return nil, fmt.Errorf("获取相关资料失败: %w", err)
 	}
 
-	// 3. 构建推荐结果
+	// 3. 打分并构建推荐结果
+	courseName := s.courseDisplayName(ctx, &material)
 	results := make([]*model.RecommendationResult, 0, len(materials))
 	for _, m := range materials {
+		sharedTags := sharedTagNames(&material, m)
+		score := float64(len(sharedTags)) * relatedTagWeight
+		if score > relatedMaxTagScore {
+			score = relatedMaxTagScore
+		}
+
 		reason := "相关资料推荐"
 		if m.Category == material.Category {
+			score += relatedCategoryWeight
 			reason = fmt.Sprintf("同为 %s 类资料", m.Category)
 		}
-		if m.CourseName == material.CourseName && m.CourseName != "" {
-			reason = fmt.Sprintf("%s 课程相关资料", m.CourseName)
+		if len(sharedTags) == 1 {
+			reason = fmt.Sprintf("同样带有标签 %s", sharedTags[0])
+		}
+		if sameCourse(m, &material) {
+			score += relatedCourseWeight
+			reason = fmt.Sprintf("%s 课程相关资料", courseName)
 		}
+		if len(sharedTags) > 1 {
+			reason = fmt.Sprintf("同样带有标签 %s", strings.Join(sharedTags, "、"))
+		}
+
+		// 热度只用于同分数时区分先后
+		popularity := float64(m.DownloadCount+m.FavoriteCount*2) / 100.0
+		if popularity > 1 {
+			popularity = 1
+		}
+		score += popularity * relatedPopularityWeight
 
 		results = append(results, &model.RecommendationResult{
 			Material: m,
 			Reason:   reason,
-			Score:    0.7,
+			Score:    score,
 		})
 	}
 
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
 	return results, nil
 }
 
@@ -282,10 +360,11 @@ func (s *recommendationService) getRecommendedByDownloadHistory(ctx context.Cont
 			AND (
 				m.category IN (SELECT DISTINCT category FROM materials WHERE id IN (SELECT material_id FROM download_records WHERE user_id = ?))
 				OR m.course_name IN (SELECT DISTINCT course_name FROM materials WHERE id IN (SELECT material_id FROM download_records WHERE user_id = ?) AND course_name != '')
+				OR m.course_id IN (SELECT DISTINCT course_id FROM materials WHERE id IN (SELECT material_id FROM download_records WHERE user_id = ?) AND course_id IS NOT NULL)
 			)
 			ORDER BY m.download_count DESC, m.favorite_count DESC
 			LIMIT ?
-		`, userID, userID, userID, limit).
+		`, userID, userID, userID, userID, limit).
 		Scan(&materials).Error
 
 	if err != nil {
@@ -304,3 +383,45 @@ func (s *recommendationService) getRecommendedByDownloadHistory(ctx context.Cont
 
 	return results, nil
 }
+
+// courseKey 资料所属课程的统计键：已关联课程时按课程ID，否则按课程名称
+func courseKey(m *model.Material) string {
+	if m.CourseID != nil {
+		return fmt.Sprintf("id:%d", *m.CourseID)
+	}
+	if m.CourseName != "" {
+		return "name:" + m.CourseName
+	}
+	return ""
+}
+
+// sameCourse 两份资料是否属于同一课程
+func sameCourse(a, b *model.Material) bool {
+	key := courseKey(a)
+	return key != "" && key == courseKey(b)
+}
+
+// courseDisplayName 资料所属课程的展示名称，已关联课程时使用课程规范名称
+func (s *recommendationService) courseDisplayName(ctx context.Context, m *model.Material) string {
+	if m.CourseID != nil {
+		if course, err := s.courseRepo.FindByID(ctx, *m.CourseID); err == nil {
+			return course.Name
+		}
+	}
+	return m.CourseName
+}
+
+// sharedTagNames 两份资料的共同标签
+func sharedTagNames(a, b *model.Material) []string {
+	names := make(map[uint]bool, len(a.Tags))
+	for _, tag := range a.Tags {
+		names[tag.ID] = true
+	}
+	shared := make([]string, 0)
+	for _, tag := range b.Tags {
+		if names[tag.ID] {
+			shared = append(shared, tag.Name)
+		}
+	}
+	return shared
+}
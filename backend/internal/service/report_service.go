@@ -14,31 +14,44 @@ import (
 type ReportService interface {
 	// CreateReport 创建举报
 	CreateReport(ctx context.Context, userID, materialID uint, req *model.ReportRequest) error
+	// CreateCommentReport 举报评论
+	CreateCommentReport(ctx context.Context, userID, commentID uint, req *model.ReportRequest) error
 	// HandleReport 处理举报
 	HandleReport(ctx context.Context, reportID, handlerID uint, req *model.HandleReportRequest) error
 	// ListReports 获取举报列表
 	ListReports(ctx context.Context, page, pageSize int, status *model.ReportStatus) ([]*model.ReportResponse, int64, error)
 	// GetReport 获取举报详情
 	GetReport(ctx context.Context, reportID uint) (*model.ReportResponse, error)
+	// SetSearchSuggestService 设置搜索联想服务
+	SetSearchSuggestService(suggestSvc SearchSuggestService)
 }
 
 // reportService 举报服务实现
 type reportService struct {
 	reportRepo   repository.ReportRepository
 	materialRepo repository.MaterialRepository
+	commentRepo  repository.MaterialCommentRepository
+	suggestSvc   SearchSuggestService
 }
 
 // NewReportService 创建举报服务实例
 func NewReportService(
 	reportRepo repository.ReportRepository,
 	materialRepo repository.MaterialRepository,
+	commentRepo repository.MaterialCommentRepository,
 ) ReportService {
 	return &reportService{
 		reportRepo:   reportRepo,
 		materialRepo: materialRepo,
+		commentRepo:  commentRepo,
 	}
 }
 
+// SetSearchSuggestService 设置搜索联想服务
+func (s *reportService) SetSearchSuggestService(suggestSvc SearchSuggestService) {
+	s.suggestSvc = suggestSvc
+}
+
 // CreateReport 创建举报
 func (s *reportService) CreateReport(ctx context.Context, userID, materialID uint, req *model.ReportRequest) error {
 	// 检查资料是否存在
@@ -81,6 +94,49 @@ func (s *reportService) CreateReport(ctx context.Context, userID, materialID uin
 	return nil
 }
 
+// CreateCommentReport 举报评论
+func (s *reportService) CreateCommentReport(ctx context.Context, userID, commentID uint, req *model.ReportRequest) error {
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCommentNotFound) {
+			return ErrCommentNotFound
+		}
+		return fmt.Errorf("获取评论失败: %w", err)
+	}
+
+	// 只能举报正常显示的评论，不能举报自己的评论
+	if comment.Status != model.CommentVisible {
+		return ErrCommentNotEditable
+	}
+	if comment.UserID == userID {
+		return ErrAccessDenied
+	}
+
+	// 检查是否已有待处理的举报
+	_, err = s.reportRepo.FindPendingByUserAndComment(ctx, userID, commentID)
+	if err == nil {
+		return errors.New("已有待处理的举报，请勿重复举报")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("检查举报状态失败: %w", err)
+	}
+
+	report := &model.Report{
+		UserID:      userID,
+		MaterialID:  comment.MaterialID,
+		CommentID:   &comment.ID,
+		Reason:      req.Reason,
+		Description: req.Description,
+		Status:      model.ReportStatusPending,
+	}
+
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return fmt.Errorf("创建举报失败: %w", err)
+	}
+
+	return nil
+}
+
 // HandleReport 处理举报
 func (s *reportService) HandleReport(ctx context.Context, reportID, handlerID uint, req *model.HandleReportRequest) error {
 	// 获取举报
@@ -106,11 +162,19 @@ func (s *reportService) HandleReport(ctx context.Context, reportID, handlerID ui
 		return fmt.Errorf("处理举报失败: %w", err)
 	}
 
-	// 如果举报通过，删除资料
-	if req.Status == model.ReportStatusApproved {
+	// 如果举报通过，隐藏被举报的评论或删除资料
+	if req.Status == model.ReportStatusApproved && report.IsCommentReport() {
+		if err := hideComment(ctx, s.commentRepo, *report.CommentID, handlerID, req.HandleNote); err != nil {
+			// 记录错误但继续处理
+			fmt.Printf("隐藏被举报评论失败: %v\n", err)
+		}
+	} else if req.Status == model.ReportStatusApproved {
+		material, _ := s.materialRepo.FindByID(ctx, report.MaterialID)
 		if err := s.materialRepo.Delete(ctx, report.MaterialID); err != nil {
 			// 记录错误但继续处理
 			fmt.Printf("删除被举报资料失败: %v\n", err)
+		} else if material != nil && s.suggestSvc != nil {
+			s.suggestSvc.RemoveMaterial(ctx, material)
 		}
 	}
 
@@ -161,6 +225,15 @@ func (s *reportService) convertToResponse(report *model.Report) *model.ReportRes
 		CreatedAt:   report.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 
+	response.TargetType = "material"
+	if report.IsCommentReport() {
+		response.TargetType = "comment"
+		response.CommentID = report.CommentID
+	}
+	if report.Comment != nil {
+		response.Comment = report.Comment.ToCommentResponse(true)
+	}
+
 	// 转换举报人信息
 	if report.User != nil {
 		userInfo := report.User.ToUserInfo()
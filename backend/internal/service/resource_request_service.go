@@ -0,0 +1,461 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/logger"
+	"github.com/study-upc/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrResourceRequestNotFound 求资料不存在
+	ErrResourceRequestNotFound = errors.New("求资料不存在")
+	// ErrInvalidResourceRequest 求资料参数错误
+	ErrInvalidResourceRequest = errors.New("求资料参数错误")
+	// ErrResourceRequestNotActive 求资料已完成或已关闭
+	ErrResourceRequestNotActive = errors.New("求资料已完成或已关闭")
+	// ErrResourceRequestClaimed 求资料已被认领
+	ErrResourceRequestClaimed = errors.New("求资料已被其他学委认领")
+	// ErrResourceRequestNotClaimed 求资料未被认领
+	ErrResourceRequestNotClaimed = errors.New("求资料未被认领")
+	// ErrAlreadyVoted 已投票
+	ErrAlreadyVoted = errors.New("已为该求资料投票")
+	// ErrVoteNotFound 未投票
+	ErrVoteNotFound = errors.New("尚未为该求资料投票")
+	// ErrCannotVoteOwnRequest 不能为自己的求资料投票
+	ErrCannotVoteOwnRequest = errors.New("不能为自己发布的求资料投票")
+)
+
+// ResourceRequestService 求资料服务接口
+type ResourceRequestService interface {
+	// ListRequests 分页获取求资料列表
+	ListRequests(ctx context.Context, userID uint, req *model.ResourceRequestListRequest) ([]*model.ResourceRequestResponse, int64, error)
+	// GetRequest 获取求资料详情
+	GetRequest(ctx context.Context, userID, requestID uint) (*model.ResourceRequestResponse, error)
+	// CreateRequest 发布求资料
+	CreateRequest(ctx context.Context, userID uint, req *model.ResourceRequestRequest) (*model.ResourceRequestResponse, error)
+	// UpdateRequest 编辑求资料，仅发布者可在认领前编辑
+	UpdateRequest(ctx context.Context, userID, requestID uint, req *model.ResourceRequestRequest) (*model.ResourceRequestResponse, error)
+	// CloseRequest 关闭求资料，发布者或管理员可关闭
+	CloseRequest(ctx context.Context, userID, requestID uint, isAdmin bool, req *model.CloseResourceRequestRequest) error
+
+	// Vote 为求资料投票
+	Vote(ctx context.Context, userID, requestID uint) (*model.ResourceRequestResponse, error)
+	// Unvote 取消投票
+	Unvote(ctx context.Context, userID, requestID uint) (*model.ResourceRequestResponse, error)
+
+	// ClaimRequest 学委认领求资料
+	ClaimRequest(ctx context.Context, userID, requestID uint) (*model.ResourceRequestResponse, error)
+	// ReleaseRequest 取消认领，认领人或管理员可取消
+	ReleaseRequest(ctx context.Context, userID, requestID uint, isAdmin bool) (*model.ResourceRequestResponse, error)
+	// FulfillRequest 关联已通过审核的资料完成求资料，并通知发布者和投票用户
+	FulfillRequest(ctx context.Context, userID, requestID uint, isAdmin bool, req *model.FulfillResourceRequestRequest) (*model.ResourceRequestResponse, error)
+
+	// SetNotificationService 设置通知服务
+	SetNotificationService(notificationSvc NotificationService)
+}
+
+// resourceRequestService 求资料服务实现
+type resourceRequestService struct {
+	requestRepo     repository.ResourceRequestRepository
+	materialRepo    repository.MaterialRepository
+	categoryRepo    *repository.MaterialCategoryRepository
+	courseRepo      repository.CourseRepository
+	notificationSvc NotificationService
+}
+
+// NewResourceRequestService 创建求资料服务实例
+func NewResourceRequestService(
+	requestRepo repository.ResourceRequestRepository,
+	materialRepo repository.MaterialRepository,
+	categoryRepo *repository.MaterialCategoryRepository,
+	courseRepo repository.CourseRepository,
+) ResourceRequestService {
+	return &resourceRequestService{
+		requestRepo:  requestRepo,
+		materialRepo: materialRepo,
+		categoryRepo: categoryRepo,
+		courseRepo:   courseRepo,
+	}
+}
+
+// SetNotificationService 设置通知服务
+func (s *resourceRequestService) SetNotificationService(notificationSvc NotificationService) {
+	s.notificationSvc = notificationSvc
+}
+
+// ListRequests 分页获取求资料列表
+func (s *resourceRequestService) ListRequests(ctx context.Context, userID uint, req *model.ResourceRequestListRequest) ([]*model.ResourceRequestResponse, int64, error) {
+	opts := &repository.ResourceRequestListOptions{
+		CourseID:   req.CourseID,
+		CourseName: req.CourseName,
+		Keyword:    strings.TrimSpace(req.Keyword),
+		SortBy:     req.SortBy,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+	}
+	if req.Status != "" {
+		opts.Statuses = []model.ResourceRequestStatus{req.Status}
+	}
+	if req.Category != "" {
+		opts.Category = &req.Category
+	}
+
+	// 课程名称能解析到课程（含别名）时按课程筛选
+	if opts.CourseID == nil && req.CourseName != "" {
+		if course, err := resolveCourse(ctx, s.courseRepo, nil, req.CourseName); err == nil && course != nil {
+			opts.CourseID = &course.ID
+		}
+	}
+	if req.Mine {
+		opts.UserID = &userID
+	}
+	if req.ClaimedByMe {
+		opts.ClaimedBy = &userID
+	}
+
+	requests, total, err := s.requestRepo.List(ctx, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取求资料列表失败: %w", err)
+	}
+
+	responses, err := s.buildResponses(ctx, userID, requests)
+	if err != nil {
+		return nil, 0, err
+	}
+	return responses, total, nil
+}
+
+// GetRequest 获取求资料详情
+func (s *resourceRequestService) GetRequest(ctx context.Context, userID, requestID uint) (*model.ResourceRequestResponse, error) {
+	request, err := s.findRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildResponse(ctx, userID, request)
+}
+
+// CreateRequest 发布求资料
+func (s *resourceRequestService) CreateRequest(ctx context.Context, userID uint, req *model.ResourceRequestRequest) (*model.ResourceRequestResponse, error) {
+	request := &model.ResourceRequest{
+		UserID: userID,
+		Status: model.ResourceRequestOpen,
+	}
+	if err := s.applyRequest(ctx, request, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.requestRepo.Create(ctx, request); err != nil {
+		return nil, fmt.Errorf("发布求资料失败: %w", err)
+	}
+	return s.reload(ctx, userID, request.ID)
+}
+
+// UpdateRequest 编辑求资料
+func (s *resourceRequestService) UpdateRequest(ctx context.Context, userID, requestID uint, req *model.ResourceRequestRequest) (*model.ResourceRequestResponse, error) {
+	request, err := s.findRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.UserID != userID {
+		return nil, ErrAccessDenied
+	}
+	// 认领后学委已按原描述寻找资料，不再允许修改
+	if request.Status != model.ResourceRequestOpen {
+		return nil, fmt.Errorf("%w: 仅等待认领的求资料可以编辑", ErrInvalidResourceRequest)
+	}
+
+	if err := s.applyRequest(ctx, request, req); err != nil {
+		return nil, err
+	}
+	if err := s.requestRepo.Update(ctx, request); err != nil {
+		return nil, fmt.Errorf("更新求资料失败: %w", err)
+	}
+	return s.reload(ctx, userID, requestID)
+}
+
+// CloseRequest 关闭求资料
+func (s *resourceRequestService) CloseRequest(ctx context.Context, userID, requestID uint, isAdmin bool, req *model.CloseResourceRequestRequest) error {
+	request, err := s.findRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if request.UserID != userID && !isAdmin {
+		return ErrAccessDenied
+	}
+	if !request.Status.IsActive() {
+		return ErrResourceRequestNotActive
+	}
+
+	if err := s.requestRepo.Close(ctx, requestID, strings.TrimSpace(req.Reason)); err != nil {
+		return s.mapStatusError(err, "关闭求资料失败")
+	}
+
+	// 管理员关闭他人的求资料时通知发布者
+	if request.UserID != userID {
+		content := fmt.Sprintf("你发布的求资料《%s》已被管理员关闭", request.Title)
+		if reason := strings.TrimSpace(req.Reason); reason != "" {
+			content += "，原因：" + reason
+		}
+		s.notify(ctx, request.UserID, "求资料已关闭", content, requestID)
+	}
+	return nil
+}
+
+// Vote 为求资料投票
+func (s *resourceRequestService) Vote(ctx context.Context, userID, requestID uint) (*model.ResourceRequestResponse, error) {
+	request, err := s.findRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.UserID == userID {
+		return nil, ErrCannotVoteOwnRequest
+	}
+	if !request.Status.IsActive() {
+		return nil, ErrResourceRequestNotActive
+	}
+
+	if err := s.requestRepo.AddVote(ctx, requestID, userID); err != nil {
+		if errors.Is(err, repository.ErrAlreadyVoted) {
+			return nil, ErrAlreadyVoted
+		}
+		return nil, fmt.Errorf("投票失败: %w", err)
+	}
+	return s.reload(ctx, userID, requestID)
+}
+
+// Unvote 取消投票
+func (s *resourceRequestService) Unvote(ctx context.Context, userID, requestID uint) (*model.ResourceRequestResponse, error) {
+	if _, err := s.findRequest(ctx, requestID); err != nil {
+		return nil, err
+	}
+
+	if err := s.requestRepo.RemoveVote(ctx, requestID, userID); err != nil {
+		if errors.Is(err, repository.ErrVoteNotFound) {
+			return nil, ErrVoteNotFound
+		}
+		return nil, fmt.Errorf("取消投票失败: %w", err)
+	}
+	return s.reload(ctx, userID, requestID)
+}
+
+// ClaimRequest 学委认领求资料
+func (s *resourceRequestService) ClaimRequest(ctx context.Context, userID, requestID uint) (*model.ResourceRequestResponse, error) {
+	request, err := s.findRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	switch request.Status {
+	case model.ResourceRequestOpen:
+	case model.ResourceRequestClaimed:
+		return nil, ErrResourceRequestClaimed
+	default:
+		return nil, ErrResourceRequestNotActive
+	}
+
+	if err := s.requestRepo.Claim(ctx, requestID, userID); err != nil {
+		if errors.Is(err, repository.ErrResourceRequestStatusChanged) {
+			// 并发认领时另一位学委先完成了认领
+			return nil, ErrResourceRequestClaimed
+		}
+		return nil, fmt.Errorf("认领求资料失败: %w", err)
+	}
+
+	if request.UserID != userID {
+		s.notify(ctx, request.UserID, "求资料已被认领",
+			fmt.Sprintf("你发布的求资料《%s》已被学委认领，资料上传后会通知你", request.Title), requestID)
+	}
+	return s.reload(ctx, userID, requestID)
+}
+
+// ReleaseRequest 取消认领
+func (s *resourceRequestService) ReleaseRequest(ctx context.Context, userID, requestID uint, isAdmin bool) (*model.ResourceRequestResponse, error) {
+	request, err := s.findRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != model.ResourceRequestClaimed {
+		return nil, ErrResourceRequestNotClaimed
+	}
+	if (request.ClaimedBy == nil || *request.ClaimedBy != userID) && !isAdmin {
+		return nil, ErrAccessDenied
+	}
+
+	if err := s.requestRepo.Release(ctx, requestID); err != nil {
+		if errors.Is(err, repository.ErrResourceRequestStatusChanged) {
+			return nil, ErrResourceRequestNotClaimed
+		}
+		return nil, fmt.Errorf("取消认领失败: %w", err)
+	}
+	return s.reload(ctx, userID, requestID)
+}
+
+// FulfillRequest 关联资料完成求资料
+func (s *resourceRequestService) FulfillRequest(ctx context.Context, userID, requestID uint, isAdmin bool, req *model.FulfillResourceRequestRequest) (*model.ResourceRequestResponse, error) {
+	request, err := s.findRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if !request.Status.IsActive() {
+		return nil, ErrResourceRequestNotActive
+	}
+	// 已认领的求资料只能由认领人完成，避免学委之间重复劳动
+	if request.Status == model.ResourceRequestClaimed && (request.ClaimedBy == nil || *request.ClaimedBy != userID) && !isAdmin {
+		return nil, ErrResourceRequestClaimed
+	}
+
+	material, err := s.materialRepo.FindByID(ctx, req.MaterialID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMaterialNotFound) {
+			return nil, ErrMaterialNotFound
+		}
+		return nil, fmt.Errorf("获取资料失败: %w", err)
+	}
+	// 待审核的资料需审核通过后再关联，保证通知中的链接可访问
+	if material.Status != model.StatusApproved {
+		return nil, fmt.Errorf("%w: 只能关联已审核通过的资料", ErrInvalidResourceRequest)
+	}
+
+	if err := s.requestRepo.Fulfill(ctx, requestID, material.ID); err != nil {
+		return nil, s.mapStatusError(err, "完成求资料失败")
+	}
+
+	s.notifyFulfilled(ctx, userID, request, material)
+	return s.reload(ctx, userID, requestID)
+}
+
+// notifyFulfilled 通知发布者和投票用户求资料已完成
+func (s *resourceRequestService) notifyFulfilled(ctx context.Context, fulfillerID uint, request *model.ResourceRequest, material *model.Material) {
+	if s.notificationSvc == nil {
+		return
+	}
+
+	if request.UserID != fulfillerID {
+		s.notify(ctx, request.UserID, "求资料已完成",
+			fmt.Sprintf("你发布的求资料《%s》已有资料：%s", request.Title, material.Title), request.ID)
+	}
+
+	voterIDs, err := s.requestRepo.ListVoterIDs(ctx, request.ID)
+	if err != nil {
+		logger.Warn("获取求资料投票用户失败", zap.Uint("request_id", request.ID), zap.Error(err))
+		return
+	}
+	for _, voterID := range voterIDs {
+		if voterID == request.UserID || voterID == fulfillerID {
+			continue
+		}
+		s.notify(ctx, voterID, "你关注的求资料已完成",
+			fmt.Sprintf("你投票的求资料《%s》已有资料：%s", request.Title, material.Title), request.ID)
+	}
+}
+
+// notify 发送求资料相关通知
+func (s *resourceRequestService) notify(ctx context.Context, userID uint, title, content string, requestID uint) {
+	if s.notificationSvc == nil {
+		return
+	}
+	notification := &model.Notification{
+		UserID:  userID,
+		Type:    model.NotifyRequest,
+		Title:   title,
+		Content: content,
+		Status:  model.NotifyUnread,
+		Link:    fmt.Sprintf("/resource-requests/%d", requestID),
+	}
+	if err := s.notificationSvc.CreateNotification(ctx, notification); err != nil {
+		logger.Warn("发送求资料通知失败", zap.Uint("user_id", userID), zap.Error(err))
+	}
+}
+
+// applyRequest 校验并写入求资料的标题、描述、类型和课程
+func (s *resourceRequestService) applyRequest(ctx context.Context, request *model.ResourceRequest, req *model.ResourceRequestRequest) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return fmt.Errorf("%w: 标题不能为空", ErrInvalidResourceRequest)
+	}
+
+	// 验证资料类型(动态验证)
+	if _, err := s.categoryRepo.GetByCode(string(req.Category)); err != nil {
+		return fmt.Errorf("%w: 无效的资料类型: %s", ErrInvalidResourceRequest, req.Category)
+	}
+
+	// 关联课程：指定课程或按课程名称、别名解析
+	course, err := resolveCourse(ctx, s.courseRepo, req.CourseID, req.CourseName)
+	if err != nil {
+		return err
+	}
+	courseName := strings.TrimSpace(req.CourseName)
+	if courseName == "" && course != nil {
+		courseName = course.Name
+	}
+
+	request.Title = title
+	request.Description = strings.TrimSpace(req.Description)
+	request.Category = req.Category
+	request.CourseName = courseName
+	request.CourseID = courseIDOf(course)
+	return nil
+}
+
+// findRequest 获取求资料
+func (s *resourceRequestService) findRequest(ctx context.Context, requestID uint) (*model.ResourceRequest, error) {
+	request, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, repository.ErrResourceRequestNotFound) {
+			return nil, ErrResourceRequestNotFound
+		}
+		return nil, fmt.Errorf("获取求资料失败: %w", err)
+	}
+	return request, nil
+}
+
+// reload 重新加载求资料并构建响应
+func (s *resourceRequestService) reload(ctx context.Context, userID, requestID uint) (*model.ResourceRequestResponse, error) {
+	request, err := s.findRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildResponse(ctx, userID, request)
+}
+
+// buildResponse 构建单条求资料响应
+func (s *resourceRequestService) buildResponse(ctx context.Context, userID uint, request *model.ResourceRequest) (*model.ResourceRequestResponse, error) {
+	responses, err := s.buildResponses(ctx, userID, []*model.ResourceRequest{request})
+	if err != nil {
+		return nil, err
+	}
+	return responses[0], nil
+}
+
+// buildResponses 构建求资料响应并标注当前用户是否已投票
+func (s *resourceRequestService) buildResponses(ctx context.Context, userID uint, requests []*model.ResourceRequest) ([]*model.ResourceRequestResponse, error) {
+	ids := make([]uint, 0, len(requests))
+	for _, request := range requests {
+		ids = append(ids, request.ID)
+	}
+	voted, err := s.requestRepo.ListVotedIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("获取投票状态失败: %w", err)
+	}
+
+	responses := make([]*model.ResourceRequestResponse, 0, len(requests))
+	for _, request := range requests {
+		response := request.ToResourceRequestResponse()
+		response.Voted = voted[request.ID]
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// mapStatusError 将并发状态变更转换为业务错误
+func (s *resourceRequestService) mapStatusError(err error, action string) error {
+	if errors.Is(err, repository.ErrResourceRequestStatusChanged) {
+		return ErrResourceRequestNotActive
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
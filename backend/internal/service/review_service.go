@@ -26,6 +26,8 @@ type ReviewService interface {
 	GetReviewerStatistics(ctx context.Context, reviewerID uint) (*model.ReviewerStatistics, error)
 	// SetNotificationService 设置通知服务
 	SetNotificationService(notificationSvc NotificationService)
+	// SetSearchSuggestService 设置搜索联想服务
+	SetSearchSuggestService(suggestSvc SearchSuggestService)
 }
 
 // reviewService 审核服务实现
@@ -35,8 +37,10 @@ type reviewService struct {
 	reportRepo        repository.ReportRepository
 	reviewRepo        repository.ReviewRepository
 	userRepo          repository.UserRepository
+	commentRepo       repository.MaterialCommentRepository
 	notificationSvc   NotificationService
 	notificationSvcSet bool // 标记通知服务是否已设置
+	suggestSvc        SearchSuggestService
 }
 
 // NewReviewService 创建审核服务实例（通知服务可选）
@@ -46,6 +50,7 @@ func NewReviewService(
 	reportRepo repository.ReportRepository,
 	reviewRepo repository.ReviewRepository,
 	userRepo repository.UserRepository,
+	commentRepo repository.MaterialCommentRepository,
 ) ReviewService {
 	return &reviewService{
 		materialRepo:  materialRepo,
@@ -53,6 +58,7 @@ func NewReviewService(
 		reportRepo:    reportRepo,
 		reviewRepo:    reviewRepo,
 		userRepo:      userRepo,
+		commentRepo:   commentRepo,
 	}
 }
 
@@ -62,6 +68,11 @@ func (s *reviewService) SetNotificationService(notificationSvc NotificationServi
 	s.notificationSvcSet = true
 }
 
+// SetSearchSuggestService 设置搜索联想服务
+func (s *reviewService) SetSearchSuggestService(suggestSvc SearchSuggestService) {
+	s.suggestSvc = suggestSvc
+}
+
 // ReviewMaterial 审核资料
 func (s *reviewService) ReviewMaterial(ctx context.Context, materialID, reviewerID uint, approved bool, comment string) error {
 	// 获取资料信息
@@ -95,6 +106,11 @@ func (s *reviewService) ReviewMaterial(ctx context.Context, materialID, reviewer
 		return fmt.Errorf("更新资料状态失败: %w", err)
 	}
 
+	// 审核通过的资料加入搜索联想
+	if approved && s.suggestSvc != nil {
+		s.suggestSvc.AddMaterial(ctx, material)
+	}
+
 	// 创建审核记录
 	reviewRecord := &model.ReviewRecord{
 		ReviewerID:   reviewerID,
@@ -144,13 +160,24 @@ func (s *reviewService) HandleReport(ctx context.Context, reportID, handlerID ui
 		return fmt.Errorf("更新举报状态失败: %w", err)
 	}
 
-	// 如果举报通过，需要处理被举报的资料（例如删除或标记）
-	if approved {
+	// 评论举报通过时隐藏评论，资料举报通过时处理被举报的资料
+	if approved && report.IsCommentReport() {
+		if err := hideComment(ctx, s.commentRepo, *report.CommentID, handlerID, note); err != nil && !errors.Is(err, repository.ErrCommentNotFound) {
+			return fmt.Errorf("隐藏被举报评论失败: %w", err)
+		}
+	} else if approved {
 		// 这里可以根据业务需求处理被举报的资料
 		// 例如：软删除资料
+		material, err := s.materialRepo.FindByID(ctx, report.MaterialID)
+		if err != nil && !errors.Is(err, repository.ErrMaterialNotFound) {
+			return fmt.Errorf("获取被举报资料失败: %w", err)
+		}
 		if err := s.materialRepo.Delete(ctx, report.MaterialID); err != nil {
 			return fmt.Errorf("处理被举报资料失败: %w", err)
 		}
+		if material != nil && s.suggestSvc != nil {
+			s.suggestSvc.RemoveMaterial(ctx, material)
+		}
 	}
 
 	// 创建审核记录
@@ -163,13 +190,21 @@ func (s *reviewService) HandleReport(ctx context.Context, reportID, handlerID ui
 		OriginalData: fmt.Sprintf(`{"material_id": %d, "reason": "%s", "description": "%s"}`,
 			report.MaterialID, report.Reason, report.Description),
 	}
+	if report.IsCommentReport() {
+		reviewRecord.OriginalData = fmt.Sprintf(`{"material_id": %d, "comment_id": %d, "reason": "%s", "description": "%s"}`,
+			report.MaterialID, *report.CommentID, report.Reason, report.Description)
+	}
 	if err := s.reviewRepo.CreateReviewRecord(ctx, reviewRecord); err != nil {
 		return fmt.Errorf("创建审核记录失败: %w", err)
 	}
 
 	// 发送通知给举报人
+	target := "资料"
+	if report.IsCommentReport() {
+		target = "评论"
+	}
 	s.sendReviewNotification(ctx, report.UserID, "举报处理结果",
-		fmt.Sprintf("您对资料的举报已%s", getReviewStatusText(approved)), model.NotifyReport, "")
+		fmt.Sprintf("您对%s的举报已%s", target, getReviewStatusText(approved)), model.NotifyReport, "")
 
 	return nil
 }
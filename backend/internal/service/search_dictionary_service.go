@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/logger"
+	"github.com/study-upc/backend/internal/pkg/segmenter"
+	"github.com/study-upc/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	// dictionaryReloadInterval 重新加载分词词典的间隔，课程名称和别名的变更在此间隔内生效
+	dictionaryReloadInterval = 10 * time.Minute
+	// reindexBatchSize 重建搜索向量时每批处理的资料数
+	reindexBatchSize = 200
+)
+
+var (
+	// ErrDictionaryWordNotFound 分词词条不存在
+	ErrDictionaryWordNotFound = errors.New("分词词条不存在")
+	// ErrDictionaryWordExists 分词词条已存在
+	ErrDictionaryWordExists = errors.New("分词词条已存在")
+	// ErrInvalidDictionaryWord 分词词条格式错误
+	ErrInvalidDictionaryWord = errors.New("分词词条不能为空、不能包含空白且不超过 20 个字")
+)
+
+// SearchDictionaryService 搜索分词词典服务接口
+// 词典由内置词典、管理员维护的词条以及课程名称和别名组成。词典变化后分词结果随之变化，
+// 已有资料的搜索向量需要重建，否则查询与索引的切分不一致
+type SearchDictionaryService interface {
+	// ListWords 分页获取自定义词条
+	ListWords(ctx context.Context, req *model.SearchDictionaryListRequest) ([]*model.SearchDictionaryWord, int64, error)
+	// AddWord 添加词条，并在后台重建搜索向量
+	AddWord(ctx context.Context, adminID uint, req *model.SearchDictionaryWordRequest) (*model.SearchDictionaryWord, error)
+	// DeleteWord 删除词条，并在后台重建搜索向量
+	DeleteWord(ctx context.Context, id uint) error
+	// Segment 预览文本的分词结果
+	Segment(text string) *model.SegmentPreviewResponse
+	// Reindex 在后台重建全部资料的搜索向量，已有重建任务在执行时返回 false（执行完后会再重建一次）
+	Reindex() bool
+	// Start 加载词典，补建缺失的搜索向量，并定期重新加载词典
+	Start(ctx context.Context)
+}
+
+// searchDictionaryService 搜索分词词典服务实现
+type searchDictionaryService struct {
+	dictionaryRepo repository.SearchDictionaryRepository
+	courseRepo     repository.CourseRepository
+	materialRepo   repository.MaterialRepository
+
+	mu          sync.Mutex
+	fingerprint string // 当前词典内容的摘要，用于判断重新加载后词典是否变化
+	reindexing  bool   // 是否有重建任务在执行
+	pending     bool   // 重建期间是否又收到了重建请求
+}
+
+// NewSearchDictionaryService 创建搜索分词词典服务实例
+func NewSearchDictionaryService(
+	dictionaryRepo repository.SearchDictionaryRepository,
+	courseRepo repository.CourseRepository,
+	materialRepo repository.MaterialRepository,
+) SearchDictionaryService {
+	return &searchDictionaryService{
+		dictionaryRepo: dictionaryRepo,
+		courseRepo:     courseRepo,
+		materialRepo:   materialRepo,
+	}
+}
+
+// ListWords 分页获取自定义词条
+func (s *searchDictionaryService) ListWords(ctx context.Context, req *model.SearchDictionaryListRequest) ([]*model.SearchDictionaryWord, int64, error) {
+	words, total, err := s.dictionaryRepo.List(ctx, req.Page, req.PageSize, strings.ToLower(strings.TrimSpace(req.Keyword)))
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取分词词条失败: %w", err)
+	}
+	return words, total, nil
+}
+
+// AddWord 添加词条
+func (s *searchDictionaryService) AddWord(ctx context.Context, adminID uint, req *model.SearchDictionaryWordRequest) (*model.SearchDictionaryWord, error) {
+	text := segmenter.NormalizeWord(req.Word)
+	if text == "" {
+		return nil, ErrInvalidDictionaryWord
+	}
+	frequency := req.Frequency
+	if frequency <= 0 {
+		frequency = segmenter.DefaultFrequency
+	}
+
+	word := &model.SearchDictionaryWord{
+		Word:      text,
+		Frequency: frequency,
+		CreatedBy: adminID,
+	}
+	if err := s.dictionaryRepo.Create(ctx, word); err != nil {
+		if errors.Is(err, repository.ErrDictionaryWordExists) {
+			return nil, ErrDictionaryWordExists
+		}
+		return nil, fmt.Errorf("添加分词词条失败: %w", err)
+	}
+
+	s.reloadAndReindex(ctx)
+	return word, nil
+}
+
+// DeleteWord 删除词条
+func (s *searchDictionaryService) DeleteWord(ctx context.Context, id uint) error {
+	if err := s.dictionaryRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrDictionaryWordNotFound) {
+			return ErrDictionaryWordNotFound
+		}
+		return fmt.Errorf("删除分词词条失败: %w", err)
+	}
+
+	s.reloadAndReindex(ctx)
+	return nil
+}
+
+// Segment 预览文本的分词结果
+func (s *searchDictionaryService) Segment(text string) *model.SegmentPreviewResponse {
+	seg := segmenter.Default()
+	return &model.SegmentPreviewResponse{
+		Text:   text,
+		Tokens: seg.Cut(text),
+		Index:  seg.CutForSearch(text),
+	}
+}
+
+// Reindex 在后台重建全部资料的搜索向量
+func (s *searchDictionaryService) Reindex() bool {
+	s.mu.Lock()
+	if s.reindexing {
+		s.pending = true
+		s.mu.Unlock()
+		return false
+	}
+	s.reindexing = true
+	s.mu.Unlock()
+
+	go s.runReindex(false)
+	return true
+}
+
+// Start 加载词典并定期重新加载
+func (s *searchDictionaryService) Start(ctx context.Context) {
+	if _, err := s.loadDictionary(ctx); err != nil {
+		logger.Warn("加载搜索分词词典失败", zap.Error(err))
+	}
+
+	// 补建缺失的搜索向量（如刚升级到应用侧计算搜索向量时）
+	s.mu.Lock()
+	s.reindexing = true
+	s.mu.Unlock()
+	go s.runReindex(true)
+
+	go func() {
+		ticker := time.NewTicker(dictionaryReloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.reloadAndReindex(ctx)
+			}
+		}
+	}()
+}
+
+// reloadAndReindex 重新加载词典，词典有变化时重建搜索向量
+func (s *searchDictionaryService) reloadAndReindex(ctx context.Context) {
+	changed, err := s.loadDictionary(ctx)
+	if err != nil {
+		logger.Warn("加载搜索分词词典失败", zap.Error(err))
+		return
+	}
+	if changed {
+		s.Reindex()
+	}
+}
+
+// loadDictionary 从数据库加载自定义词条和课程名称、别名，返回词典是否发生变化
+func (s *searchDictionaryService) loadDictionary(ctx context.Context) (bool, error) {
+	words := make(map[string]int)
+
+	terms, err := s.courseRepo.ListTerms(ctx)
+	if err != nil {
+		return false, fmt.Errorf("获取课程名称失败: %w", err)
+	}
+	for _, term := range terms {
+		// 课程名称中可能含有空格（如“C 语言”），无法作为单个词条
+		if word := segmenter.NormalizeWord(term); word != "" {
+			words[word] = segmenter.DefaultFrequency
+		}
+	}
+
+	entries, err := s.dictionaryRepo.ListAll(ctx)
+	if err != nil {
+		return false, fmt.Errorf("获取分词词条失败: %w", err)
+	}
+	for _, entry := range entries {
+		words[entry.Word] = entry.Frequency
+	}
+
+	fingerprint := dictionaryFingerprint(words)
+	s.mu.Lock()
+	changed := fingerprint != s.fingerprint
+	s.fingerprint = fingerprint
+	s.mu.Unlock()
+
+	if changed {
+		segmenter.Default().SetCustomWords(words)
+	}
+	return changed, nil
+}
+
+// runReindex 分批重建搜索向量，重建期间收到新请求时结束后再全量重建一次
+func (s *searchDictionaryService) runReindex(missingOnly bool) {
+	for {
+		ctx := context.Background()
+		start := time.Now()
+		count, failed := 0, 0
+		var afterID uint
+		for {
+			ids, err := s.materialRepo.ListIDsForReindex(ctx, afterID, reindexBatchSize, missingOnly)
+			if err != nil {
+				logger.Warn("获取待重建搜索向量的资料失败", zap.Error(err))
+				break
+			}
+			if len(ids) == 0 {
+				break
+			}
+			for _, id := range ids {
+				if err := s.materialRepo.RefreshSearchVector(ctx, id); err != nil {
+					failed++
+					logger.Warn("重建搜索向量失败", zap.Uint("material_id", id), zap.Error(err))
+					continue
+				}
+				count++
+			}
+			afterID = ids[len(ids)-1]
+		}
+		if count > 0 || failed > 0 {
+			logger.Info("重建搜索向量完成",
+				zap.Bool("missing_only", missingOnly),
+				zap.Int("count", count),
+				zap.Int("failed", failed),
+				zap.Duration("duration", time.Since(start)))
+		}
+
+		s.mu.Lock()
+		if !s.pending {
+			s.reindexing = false
+			s.mu.Unlock()
+			return
+		}
+		s.pending = false
+		s.mu.Unlock()
+		missingOnly = false
+	}
+}
+
+// dictionaryFingerprint 计算词典内容的摘要
+func dictionaryFingerprint(words map[string]int) string {
+	keys := make([]string, 0, len(words))
+	for word := range words {
+		keys = append(keys, word)
+	}
+	sort.Strings(keys)
+
+	h := sha1.New()
+	for _, word := range keys {
+		h.Write([]byte(word))
+		h.Write([]byte{':'})
+		h.Write([]byte(strconv.Itoa(words[word])))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
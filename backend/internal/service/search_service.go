@@ -2,15 +2,47 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/highlight"
+	"github.com/study-upc/backend/internal/pkg/logger"
+	"github.com/study-upc/backend/internal/pkg/searchquery"
+	"github.com/study-upc/backend/internal/pkg/segmenter"
+	"github.com/study-upc/backend/internal/pkg/spellcheck"
 	"github.com/study-upc/backend/internal/repository"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+const (
+	// searchRequestLimit 搜索结果中附带的求资料数量
+	searchRequestLimit = 5
+
+	// spellCheckReloadInterval 重新构建拼写纠错词库的间隔，新上架资料的标题在此间隔内进入词库
+	spellCheckReloadInterval = 10 * time.Minute
+	// spellCheckTitleLimit 构建词库时读取的资料标题数量上限（按下载次数取前若干个）
+	spellCheckTitleLimit = 20000
+	// spellCheckKeywordLimit 构建词库时读取的热门搜索词数量上限
+	spellCheckKeywordLimit = 2000
+	// spellCheckCourseWeight 课程名称和别名在词库中的权重，相似度接近时优先建议课程
+	spellCheckCourseWeight = 1000
+	// sparseResultThreshold 结果少于该数量时给出拼写建议
+	sparseResultThreshold = 3
+	// didYouMeanLimit 拼写建议的最大数量
+	didYouMeanLimit = 3
+	// fuzzyExpandLimit 模糊匹配时每个检索词最多扩展的近似词数量
+	fuzzyExpandLimit = 3
+)
+
+// ErrInvalidSearchQuery 搜索语法无效错误
+var ErrInvalidSearchQuery = errors.New("搜索语法无效")
+
 // SearchService 搜索服务接口
 type SearchService interface {
 	// Search 搜索资料
@@ -23,6 +55,8 @@ type SearchService interface {
 	ClearSearchHistory(ctx context.Context, userID uint) error
 	// GetHotKeywords 获取热门搜索词
 	GetHotKeywords(ctx context.Context, limit int) ([]*model.HotKeyword, error)
+	// Start 构建拼写纠错词库并定期重新构建
+	Start(ctx context.Context)
 }
 
 // RecommendationService 推荐服务接口
@@ -44,6 +78,10 @@ type searchService struct {
 	searchHistoryRepo repository.SearchHistoryRepository
 	hotKeywordRepo    repository.HotKeywordRepository
 	downloadRepo      repository.DownloadRecordRepository
+	courseRepo        repository.CourseRepository
+	requestRepo       repository.ResourceRequestRepository
+	highlighter       *highlight.Highlighter
+	spellChecker      *spellcheck.Checker
 }
 
 // NewSearchService 创建搜索服务实例
@@ -53,6 +91,9 @@ func NewSearchService(
 	searchHistoryRepo repository.SearchHistoryRepository,
 	hotKeywordRepo repository.HotKeywordRepository,
 	downloadRepo repository.DownloadRecordRepository,
+	courseRepo repository.CourseRepository,
+	requestRepo repository.ResourceRequestRepository,
+	highlighter *highlight.Highlighter,
 ) SearchService {
 	return &searchService{
 		db:                db,
@@ -60,6 +101,10 @@ func NewSearchService(
 		searchHistoryRepo: searchHistoryRepo,
 		hotKeywordRepo:    hotKeywordRepo,
 		downloadRepo:      downloadRepo,
+		courseRepo:        courseRepo,
+		requestRepo:       requestRepo,
+		highlighter:       highlighter,
+		spellChecker:      spellcheck.New(),
 	}
 }
 
@@ -69,26 +114,33 @@ func (s *searchService) Search(ctx context.Context, userID uint, req *model.Sear
 	query := s.db.WithContext(ctx).
 		Where("status = ?", model.StatusApproved) // 只搜索已审核通过的资料
 
+	// 解析搜索语法（短语、排除、OR、字段前缀），用户输入全部以参数传递
+	parsed, err := searchquery.Parse(req.Keyword)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSearchQuery, err)
+	}
+	// 模糊匹配时，词库中不存在的检索词同时匹配拼写相近的词
+	opts := s.queryOptions(ctx)
+	var expanded []string
+	if req.Fuzzy {
+		opts.Expand = func(term string) []string {
+			words := s.fuzzyWords(term)
+			expanded = append(expanded, words...)
+			return words
+		}
+	}
+	keywordQuery := searchquery.Compile(parsed, opts)
+	rankQuery := ""
+	if keywordQuery != nil {
+		rankQuery = keywordQuery.RankQuery
+	}
+
+	// 课程筛选按课程别名解析（如“高数”匹配关联到“高等数学”的资料）
+	courseCond, courseArgs := s.courseCondition(ctx, req)
+
 	// 关键词全文搜索
-	if req.Keyword != "" {
-		// 使用 PostgreSQL 的全文搜索
-		query = query.Where(`
-			search_vector @@ to_tsquery('simple', ?)
-			OR title ILIKE ?
-			OR description ILIKE ?
-			OR course_name ILIKE ?
-		`,
-			fmt.Sprintf("%s:*", req.Keyword), // to_tsquery 格式
-			fmt.Sprintf("%%%s%%", req.Keyword),
-			fmt.Sprintf("%%%s%%", req.Keyword),
-			fmt.Sprintf("%%%s%%", req.Keyword),
-		)
-
-		// 添加相关度排序
-		query = query.Order(fmt.Sprintf(
-			"ts_rank(search_vector, to_tsquery('simple', '%s:*')) DESC",
-			req.Keyword,
-		))
+	if keywordQuery != nil {
+		query = query.Where(keywordQuery.SQL, keywordQuery.Args...)
 	}
 
 	// 分类筛选
@@ -96,14 +148,15 @@ func (s *searchService) Search(ctx context.Context, userID uint, req *model.Sear
 		query = query.Where("category = ?", *req.Category)
 	}
 
-	// 课程名称筛选
-	if req.CourseName != "" {
-		query = query.Where("course_name ILIKE ?", fmt.Sprintf("%%%s%%", req.CourseName))
+	// 课程筛选
+	if courseCond != "" {
+		query = query.Where(courseCond, courseArgs...)
 	}
 
-	// 标签筛选 (使用数组包含查询)
-	if len(req.Tags) > 0 {
-		query = query.Where("tags && ?", "{"+strings.Join(req.Tags, ",")+"}")
+	// 标签筛选（命中任一标签即可）
+	tagCond, tagArgs := tagCondition(req.Tags)
+	if tagCond != "" {
+		query = query.Where(tagCond, tagArgs...)
 	}
 
 	// 时间范围筛选
@@ -123,69 +176,7 @@ func (s *searchService) Search(ctx context.Context, userID uint, req *model.Sear
 	}
 
 	// 排序
-	switch req.SortBy {
-	case "created_at":
-		order := "created_at"
-		if req.SortOrder == "asc" {
-			order += " ASC"
-		} else {
-			order += " DESC"
-		}
-		if req.Keyword != "" {
-			// 如果有搜索关键词，相关度优先
-			query = query.Order(fmt.Sprintf(
-				"ts_rank(search_vector, to_tsquery('simple', '%s:*')) DESC, %s",
-				req.Keyword, order,
-			))
-		} else {
-			query = query.Order(order)
-		}
-	case "download_count":
-		order := "download_count"
-		if req.SortOrder == "asc" {
-			order += " ASC"
-		} else {
-			order += " DESC"
-		}
-		query = query.Order(order)
-	case "favorite_count":
-		order := "favorite_count"
-		if req.SortOrder == "asc" {
-			order += " ASC"
-		} else {
-			order += " DESC"
-		}
-		query = query.Order(order)
-	case "view_count":
-		order := "view_count"
-		if req.SortOrder == "asc" {
-			order += " ASC"
-		} else {
-			order += " DESC"
-		}
-		query = query.Order(order)
-	case "relevance":
-		// 相关度排序（仅在有关键词时有效）
-		if req.Keyword != "" {
-			query = query.Order(fmt.Sprintf(
-				"ts_rank(search_vector, to_tsquery('simple', '%s:*')) DESC",
-				req.Keyword,
-			))
-		} else {
-			// 没有关键词时默认按创建时间排序
-			query = query.Order("created_at DESC")
-		}
-	default:
-		// 默认排序
-		if req.Keyword != "" {
-			query = query.Order(fmt.Sprintf(
-				"ts_rank(search_vector, to_tsquery('simple', '%s:*')) DESC, created_at DESC",
-				req.Keyword,
-			))
-		} else {
-			query = query.Order("created_at DESC")
-		}
-	}
+	query = query.Order(searchOrder(req, rankQuery))
 
 	// 分页
 	offset := (req.Page - 1) * req.PageSize
@@ -193,7 +184,7 @@ func (s *searchService) Search(ctx context.Context, userID uint, req *model.Sear
 
 	// 执行查询
 	var materials []*model.Material
-	if err := query.Find(&materials).Error; err != nil {
+	if err := query.Preload("Tags").Find(&materials).Error; err != nil {
 		return nil, fmt.Errorf("搜索失败: %w", err)
 	}
 
@@ -202,27 +193,17 @@ func (s *searchService) Search(ctx context.Context, userID uint, req *model.Sear
 	countQuery := s.db.WithContext(ctx).Model(&model.Material{}).Where("status = ?", model.StatusApproved)
 
 	// 重复应用筛选条件（用于计数）
-	if req.Keyword != "" {
-		countQuery = countQuery.Where(`
-			search_vector @@ to_tsquery('simple', ?)
-			OR title ILIKE ?
-			OR description ILIKE ?
-			OR course_name ILIKE ?
-		`,
-			fmt.Sprintf("%s:*", req.Keyword),
-			fmt.Sprintf("%%%s%%", req.Keyword),
-			fmt.Sprintf("%%%s%%", req.Keyword),
-			fmt.Sprintf("%%%s%%", req.Keyword),
-		)
+	if keywordQuery != nil {
+		countQuery = countQuery.Where(keywordQuery.SQL, keywordQuery.Args...)
 	}
 	if req.Category != nil {
 		countQuery = countQuery.Where("category = ?", *req.Category)
 	}
-	if req.CourseName != "" {
-		countQuery = countQuery.Where("course_name ILIKE ?", fmt.Sprintf("%%%s%%", req.CourseName))
+	if courseCond != "" {
+		countQuery = countQuery.Where(courseCond, courseArgs...)
 	}
-	if len(req.Tags) > 0 {
-		countQuery = countQuery.Where("tags && ?", "{"+strings.Join(req.Tags, ",")+"}")
+	if tagCond != "" {
+		countQuery = countQuery.Where(tagCond, tagArgs...)
 	}
 	if req.StartDate != "" {
 		startDate, err := time.Parse("2006-01-02", req.StartDate)
@@ -242,13 +223,61 @@ func (s *searchService) Search(ctx context.Context, userID uint, req *model.Sear
 		return nil, fmt.Errorf("获取总数失败: %w", err)
 	}
 
+	// 标注命中字段，让用户知道结果是因标题还是文件正文被搜到
+	matchedFields := make(map[uint][]string)
+	hits := make(map[uint]*repository.MaterialSearchHit)
+	var keywords []string
+	if keywordQuery != nil && len(materials) > 0 {
+		ids := make([]uint, 0, len(materials))
+		for _, material := range materials {
+			ids = append(ids, material.ID)
+		}
+		terms := make([]string, 0, len(keywordQuery.Terms)+len(expanded))
+		terms = append(terms, keywordQuery.Terms...)
+		terms = append(terms, expanded...)
+		if matched, err := s.materialRepo.FindMatchedFields(ctx, ids, rankQuery, terms); err == nil {
+			matchedFields = matched
+		} else {
+			fmt.Printf("获取命中字段失败: %v\n", err)
+		}
+
+		// 相关度和全文片段，片段比摘要长一些，留出以命中位置为中心截取的余地
+		keywords = highlightKeywords(terms)
+		if found, err := s.materialRepo.FindSearchHits(ctx, ids, rankQuery, keywords, s.highlighter.SnippetLength()*2); err == nil {
+			hits = found
+		} else {
+			fmt.Printf("获取搜索相关度失败: %v\n", err)
+		}
+	}
+
 	// 构建搜索结果
 	results := make([]*model.SearchResult, 0, len(materials))
 	for _, material := range materials {
-		results = append(results, &model.SearchResult{
-			Material:  material,
-			Relevance: 0.8, // 简化的相关度计算
-		})
+		result := &model.SearchResult{
+			Material:      material,
+			MatchedFields: matchedFields[material.ID],
+		}
+		if keywordQuery != nil {
+			hit := hits[material.ID]
+			if hit != nil {
+				result.Relevance = hit.Rank
+			}
+			result.HighlightedTitle, _ = s.highlighter.Highlight(material.Title, keywords)
+			result.Highlighted = s.snippet(material, hit, keywords)
+		}
+		results = append(results, result)
+	}
+
+	// 第一页同时返回匹配的求资料，找不到资料的同学可以直接投票
+	var requests []*model.ResourceRequestResponse
+	if keywordQuery != nil && len(keywordQuery.Terms) > 0 && req.Page == 1 {
+		requests = s.searchRequests(ctx, keywordQuery.Terms)
+	}
+
+	// 结果为空或很少时给出拼写建议
+	var didYouMean []string
+	if keywordQuery != nil && req.Page == 1 && total < sparseResultThreshold {
+		didYouMean = s.didYouMean(req.Keyword, keywordQuery.Terms)
 	}
 
 	// 计算总页数
@@ -267,13 +296,295 @@ func (s *searchService) Search(ctx context.Context, userID uint, req *model.Sear
 		_ = s.hotKeywordRepo.IncrementKeywordCount(context.Background(), req.Keyword)
 	}()
 
-	return &model.SearchResponse{
+	response := &model.SearchResponse{
 		Results:    results,
 		Total:      total,
 		Page:       req.Page,
 		PageSize:   req.PageSize,
 		TotalPages: totalPages,
-	}, nil
+		DidYouMean: didYouMean,
+		Requests:   requests,
+	}
+	if keywordQuery != nil {
+		response.HighlightPreTag = s.highlighter.PreTag()
+		response.HighlightPostTag = s.highlighter.PostTag()
+	}
+	return response, nil
+}
+
+// didYouMean 为词库中不存在的检索词给出拼写建议，返回纠正后的完整搜索词
+// 只有一个检索词拼写有误时列出多个候选，多个检索词有误时各自替换为最相近的词，合成一条建议
+func (s *searchService) didYouMean(keyword string, terms []string) []string {
+	var unknown []string
+	for _, term := range terms {
+		if !s.spellChecker.Contains(term) {
+			unknown = append(unknown, term)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	if len(unknown) == 1 {
+		suggestions := s.spellChecker.Suggest(unknown[0], didYouMeanLimit)
+		corrections := make([]string, 0, len(suggestions))
+		for _, suggestion := range suggestions {
+			corrections = append(corrections, strings.Replace(keyword, unknown[0], suggestion.Word, 1))
+		}
+		return corrections
+	}
+
+	corrected := keyword
+	for _, term := range unknown {
+		if suggestions := s.spellChecker.Suggest(term, 1); len(suggestions) > 0 {
+			corrected = strings.Replace(corrected, term, suggestions[0].Word, 1)
+		}
+	}
+	if corrected == keyword {
+		return nil
+	}
+	return []string{corrected}
+}
+
+// fuzzyWords 返回模糊匹配时检索词的近似词，词库中已有的检索词视为拼写正确，不做扩展
+func (s *searchService) fuzzyWords(term string) []string {
+	if s.spellChecker.Contains(term) {
+		return nil
+	}
+	suggestions := s.spellChecker.Suggest(term, fuzzyExpandLimit)
+	words := make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		words = append(words, suggestion.Word)
+	}
+	return words
+}
+
+// Start 构建拼写纠错词库并定期重新构建
+func (s *searchService) Start(ctx context.Context) {
+	if err := s.loadSpellCheckWords(ctx); err != nil {
+		logger.Warn("构建拼写纠错词库失败", zap.Error(err))
+	}
+
+	go func() {
+		ticker := time.NewTicker(spellCheckReloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.loadSpellCheckWords(ctx); err != nil {
+					logger.Warn("构建拼写纠错词库失败", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// loadSpellCheckWords 由资料标题及其分词、课程名称和别名、搜到过资料的热门搜索词构建拼写纠错词库
+// 没有结果的搜索词多为拼写错误，不能进入词库
+func (s *searchService) loadSpellCheckWords(ctx context.Context) error {
+	words := make(map[string]int)
+
+	titles, err := s.materialRepo.ListApprovedTitles(ctx, spellCheckTitleLimit)
+	if err != nil {
+		return fmt.Errorf("获取资料标题失败: %w", err)
+	}
+	for _, title := range titles {
+		words[title]++
+		for _, token := range segmenter.Default().Cut(title) {
+			if utf8.RuneCountInString(token) >= spellcheck.MinWordLength {
+				words[token]++
+			}
+		}
+	}
+
+	terms, err := s.courseRepo.ListTerms(ctx)
+	if err != nil {
+		return fmt.Errorf("获取课程名称失败: %w", err)
+	}
+	for _, term := range terms {
+		words[term] += spellCheckCourseWeight
+	}
+
+	keywords, err := s.hotKeywordRepo.GetEffectiveHotKeywords(ctx, spellCheckKeywordLimit)
+	if err != nil {
+		return fmt.Errorf("获取热门搜索词失败: %w", err)
+	}
+	for _, keyword := range keywords {
+		words[keyword.Keyword] += keyword.SearchCount
+	}
+
+	s.spellChecker.Load(words)
+	return nil
+}
+
+// snippet 生成搜索结果摘要：优先取描述中的命中片段，其次取文件正文中的命中片段，都未命中时取描述开头
+func (s *searchService) snippet(material *model.Material, hit *repository.MaterialSearchHit, keywords []string) string {
+	description, matched := s.highlighter.Snippet(material.Description, keywords)
+	if matched {
+		return description
+	}
+	if hit != nil && hit.ContentExcerpt != "" {
+		if content, matched := s.highlighter.Snippet(hit.ContentExcerpt, keywords); matched {
+			return content
+		}
+	}
+	return description
+}
+
+// highlightKeywords 返回需要高亮的关键词：检索词原文及其分词结果，忽略单个字母或数字以免高亮过多
+func highlightKeywords(terms []string) []string {
+	seen := make(map[string]bool)
+	keywords := make([]string, 0, len(terms)*2)
+	add := func(word string) {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || seen[word] {
+			return
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+
+	for _, term := range terms {
+		add(term)
+		for _, token := range segmenter.Default().Cut(term) {
+			if len(token) == 1 {
+				continue
+			}
+			add(token)
+		}
+	}
+	return keywords
+}
+
+// searchRequests 搜索与检索词匹配的待完成求资料，失败时不影响资料搜索结果
+func (s *searchService) searchRequests(ctx context.Context, terms []string) []*model.ResourceRequestResponse {
+	// 只有一个检索词且是课程名称或别名时，同时匹配关联到该课程的求资料
+	var courseID *uint
+	if len(terms) == 1 {
+		if course, err := resolveCourse(ctx, s.courseRepo, nil, terms[0]); err == nil && course != nil {
+			courseID = &course.ID
+		}
+	}
+
+	requests, err := s.requestRepo.SearchActive(ctx, terms, courseID, searchRequestLimit)
+	if err != nil {
+		fmt.Printf("搜索求资料失败: %v\n", err)
+		return nil
+	}
+
+	responses := make([]*model.ResourceRequestResponse, 0, len(requests))
+	for _, request := range requests {
+		responses = append(responses, request.ToResourceRequestResponse())
+	}
+	return responses
+}
+
+// queryOptions 搜索语法的编译选项：检索词按中文分词，课程按名称或别名解析，分类支持代码和名称
+func (s *searchService) queryOptions(ctx context.Context) searchquery.Options {
+	return searchquery.Options{
+		Segment: segmenter.Default().Cut,
+		TermFilter: func(term string) (string, []interface{}) {
+			return s.termCondition(ctx, term)
+		},
+		FieldFilter: func(field, value string) (string, []interface{}) {
+			switch field {
+			case searchquery.FieldCourse:
+				return s.courseNameCondition(ctx, value)
+			case searchquery.FieldCategory:
+				return "category IN (SELECT code FROM material_categories WHERE deleted_at IS NULL AND (code = ? OR name = ?))", []interface{}{value, value}
+			case searchquery.FieldTag:
+				return tagCondition([]string{value})
+			}
+			return "", nil
+		},
+	}
+}
+
+// termCondition 检索词与标签完全一致时匹配带有该标签的资料，是课程名称或别名时匹配关联到该课程的资料
+func (s *searchService) termCondition(ctx context.Context, term string) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if tag := model.NormalizeTagName(term); tag != "" {
+		conds = append(conds, "id IN (SELECT material_tags.material_id FROM material_tags JOIN tags ON tags.id = material_tags.tag_id WHERE tags.name = ?)")
+		args = append(args, tag)
+	}
+	if course, err := resolveCourse(ctx, s.courseRepo, nil, term); err == nil && course != nil {
+		conds = append(conds, "course_id = ?")
+		args = append(args, course.ID)
+	}
+	return strings.Join(conds, " OR "), args
+}
+
+// searchOrder 构建排序条件，有检索词时相关度优先；tsquery 以参数传递，整体作为一个排序表达式
+func searchOrder(req *model.SearchRequest, rankQuery string) clause.OrderBy {
+	direction := "DESC"
+	if req.SortOrder == "asc" {
+		direction = "ASC"
+	}
+
+	var columns []string
+	var vars []interface{}
+	if rankQuery != "" {
+		columns = append(columns, "ts_rank(search_vector, to_tsquery('simple', ?)) DESC")
+		vars = append(vars, rankQuery)
+	}
+
+	switch req.SortBy {
+	case "created_at", "download_count", "favorite_count", "view_count":
+		columns = append(columns, req.SortBy+" "+direction)
+	case "rating":
+		// 平均分相同时评分人数多的优先
+		columns = append(columns, "rating_average "+direction+", rating_count DESC")
+	case "relevance":
+		// 相关度排序仅在有检索词时有效，没有检索词时按创建时间排序
+		if rankQuery == "" {
+			columns = append(columns, "created_at DESC")
+		}
+	default:
+		columns = append(columns, "created_at DESC")
+	}
+
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                strings.Join(columns, ", "),
+		Vars:               vars,
+		WithoutParentheses: true,
+	}}
+}
+
+// tagCondition 构建标签筛选条件，资料带有任一标签即命中
+func tagCondition(tags []string) (string, []interface{}) {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = model.NormalizeTagName(tag); tag != "" {
+			names = append(names, tag)
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	return "id IN (SELECT material_tags.material_id FROM material_tags JOIN tags ON tags.id = material_tags.tag_id WHERE tags.name IN ?)", []interface{}{names}
+}
+
+// courseCondition 构建课程筛选条件，课程名称能解析到课程时按课程ID筛选，否则按名称模糊匹配
+func (s *searchService) courseCondition(ctx context.Context, req *model.SearchRequest) (string, []interface{}) {
+	if req.CourseID != nil {
+		return "course_id = ?", []interface{}{*req.CourseID}
+	}
+	if req.CourseName == "" {
+		return "", nil
+	}
+	return s.courseNameCondition(ctx, req.CourseName)
+}
+
+// courseNameCondition 按课程名称或别名构建筛选条件
+func (s *searchService) courseNameCondition(ctx context.Context, name string) (string, []interface{}) {
+	pattern := "%" + searchquery.EscapeLike(name) + "%"
+	if course, err := resolveCourse(ctx, s.courseRepo, nil, name); err == nil && course != nil {
+		return "(course_id = ? OR course_name ILIKE ?)", []interface{}{course.ID, pattern}
+	}
+	return "course_name ILIKE ?", []interface{}{pattern}
 }
 
 // RecordSearchHistory 记录搜索历史
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/logger"
+	"github.com/study-upc/backend/internal/pkg/searchquery"
+	"github.com/study-upc/backend/internal/pkg/segmenter"
+	"github.com/study-upc/backend/internal/pkg/suggest"
+	"github.com/study-upc/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	// suggestKeyPrefix 联想索引的 Redis 键前缀
+	suggestKeyPrefix = "search:suggest:"
+	// suggestRebuildLockKey 全量重建联想索引的锁，多实例部署时同一周期内只由一个实例重建
+	suggestRebuildLockKey = "search:suggest-rebuild-lock"
+	// suggestRebuildInterval 全量重建联想索引的间隔，课程、标签和热门搜索词的变化在此间隔内生效
+	suggestRebuildInterval = 30 * time.Minute
+	// suggestIndexTTL 联想索引键的过期时间，重建失败时旧索引仍可使用一段时间
+	suggestIndexTTL = 3 * suggestRebuildInterval
+
+	// suggestDefaultLimit 默认返回的联想词数量
+	suggestDefaultLimit = 8
+	// suggestMaxLimit 最多返回的联想词数量
+	suggestMaxLimit = 20
+	// suggestHistoryLimit 返回的最近搜索数量
+	suggestHistoryLimit = 5
+
+	// suggestTitleLimit 重建时读取的资料标题数量上限（按下载次数取前若干个）
+	suggestTitleLimit = 20000
+	// suggestTagLimit 重建时读取的标签数量上限
+	suggestTagLimit = 2000
+	// suggestKeywordLimit 重建时读取的热门搜索词数量上限
+	suggestKeywordLimit = 2000
+	// suggestCourseWeight 课程名称和别名的权重
+	suggestCourseWeight = 20
+)
+
+// SearchSuggestService 搜索联想服务接口
+// 联想词来自资料标题、课程名称和别名、标签以及搜到过资料的热门搜索词，存放在 Redis 前缀索引中。
+// 资料审核通过或删除时增量更新标题和标签，其余来源和权重随定期全量重建更新
+type SearchSuggestService interface {
+	// Suggest 获取以 query 开头的联想词和当前用户匹配的最近搜索
+	Suggest(ctx context.Context, userID uint, query string, limit int) (*model.SearchSuggestResponse, error)
+	// AddMaterial 资料审核通过后将标题和标签加入联想索引
+	AddMaterial(ctx context.Context, material *model.Material)
+	// RemoveMaterial 资料删除后将标题和标签移出联想索引，material 为删除前的资料
+	RemoveMaterial(ctx context.Context, material *model.Material)
+	// Start 构建联想索引并定期全量重建
+	Start(ctx context.Context)
+}
+
+// searchSuggestService 搜索联想服务实现
+type searchSuggestService struct {
+	materialRepo      repository.MaterialRepository
+	courseRepo        repository.CourseRepository
+	tagRepo           repository.TagRepository
+	hotKeywordRepo    repository.HotKeywordRepository
+	searchHistoryRepo repository.SearchHistoryRepository
+	redisClient       *redis.Client
+	index             *suggest.Index
+}
+
+// NewSearchSuggestService 创建搜索联想服务实例
+func NewSearchSuggestService(
+	materialRepo repository.MaterialRepository,
+	courseRepo repository.CourseRepository,
+	tagRepo repository.TagRepository,
+	hotKeywordRepo repository.HotKeywordRepository,
+	searchHistoryRepo repository.SearchHistoryRepository,
+	redisClient *redis.Client,
+) SearchSuggestService {
+	return &searchSuggestService{
+		materialRepo:      materialRepo,
+		courseRepo:        courseRepo,
+		tagRepo:           tagRepo,
+		hotKeywordRepo:    hotKeywordRepo,
+		searchHistoryRepo: searchHistoryRepo,
+		redisClient:       redisClient,
+		index:             suggest.NewIndex(redisClient, suggestKeyPrefix, suggestIndexTTL, segmenter.Default().Cut),
+	}
+}
+
+// Suggest 获取联想词和最近搜索，联想索引不可用时只返回最近搜索，不影响输入
+func (s *searchSuggestService) Suggest(ctx context.Context, userID uint, query string, limit int) (*model.SearchSuggestResponse, error) {
+	if limit <= 0 || limit > suggestMaxLimit {
+		limit = suggestDefaultLimit
+	}
+	query = strings.TrimSpace(query)
+
+	result := &model.SearchSuggestResponse{
+		Suggestions: make([]*model.SearchSuggestion, 0, limit),
+		Histories:   make([]string, 0, suggestHistoryLimit),
+	}
+	if utf8.RuneCountInString(query) > searchquery.MaxQueryLength {
+		return result, nil
+	}
+
+	// 当前用户以输入开头的最近搜索
+	if userID != 0 {
+		histories, err := s.searchHistoryRepo.GetRecentKeywordsByPrefix(ctx, userID, query, suggestHistoryLimit)
+		if err != nil {
+			return nil, fmt.Errorf("获取最近搜索失败: %w", err)
+		}
+		result.Histories = append(result.Histories, histories...)
+	}
+	if query == "" {
+		return result, nil
+	}
+
+	// 已在最近搜索中出现的联想词不再重复返回
+	seen := make(map[string]bool, len(result.Histories))
+	for _, history := range result.Histories {
+		seen[suggest.Normalize(history)] = true
+	}
+
+	suggestions, err := s.index.Lookup(ctx, query, limit+len(result.Histories))
+	if err != nil {
+		logger.Warn("查询搜索联想失败", zap.Error(err))
+		return result, nil
+	}
+	for _, suggestion := range suggestions {
+		if seen[suggest.Normalize(suggestion.Text)] {
+			continue
+		}
+		result.Suggestions = append(result.Suggestions, &model.SearchSuggestion{
+			Text: suggestion.Text,
+			Type: suggestion.Kind,
+		})
+		if len(result.Suggestions) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// AddMaterial 将资料标题和标签加入联想索引
+func (s *searchSuggestService) AddMaterial(ctx context.Context, material *model.Material) {
+	if err := s.index.Add(ctx, s.materialEntries(ctx, material)...); err != nil {
+		logger.Warn("更新搜索联想索引失败", zap.Uint("material_id", material.ID), zap.Error(err))
+	}
+}
+
+// RemoveMaterial 将资料标题和标签移出联想索引，只有已通过的资料在索引中
+func (s *searchSuggestService) RemoveMaterial(ctx context.Context, material *model.Material) {
+	if material.Status != model.StatusApproved {
+		return
+	}
+	if err := s.index.Remove(ctx, s.materialEntries(ctx, material)...); err != nil {
+		logger.Warn("更新搜索联想索引失败", zap.Uint("material_id", material.ID), zap.Error(err))
+	}
+}
+
+// materialEntries 资料对应的联想词条：标题和每个标签各计一份权重，与全量重建时的计数方式一致
+func (s *searchSuggestService) materialEntries(ctx context.Context, material *model.Material) []suggest.Entry {
+	entries := []suggest.Entry{{Kind: suggest.KindTitle, Text: material.Title, Weight: 1}}
+	tags, err := s.tagRepo.ListByMaterials(ctx, []uint{material.ID})
+	if err != nil {
+		logger.Warn("获取资料标签失败", zap.Uint("material_id", material.ID), zap.Error(err))
+		return entries
+	}
+	for _, tag := range tags[material.ID] {
+		entries = append(entries, suggest.Entry{Kind: suggest.KindTag, Text: tag.Name, Weight: 1})
+	}
+	return entries
+}
+
+// Start 构建联想索引并定期全量重建
+func (s *searchSuggestService) Start(ctx context.Context) {
+	go func() {
+		s.rebuild(ctx)
+
+		ticker := time.NewTicker(suggestRebuildInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.rebuild(ctx)
+			}
+		}
+	}()
+}
+
+// rebuild 全量重建联想索引，其他实例在本周期内已重建时跳过
+func (s *searchSuggestService) rebuild(ctx context.Context) {
+	acquired, err := s.redisClient.SetNX(ctx, suggestRebuildLockKey, "1", suggestRebuildInterval/2).Result()
+	if err != nil {
+		logger.Warn("获取搜索联想重建锁失败", zap.Error(err))
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	entries, err := s.loadEntries(ctx)
+	if err == nil {
+		err = s.index.Rebuild(ctx, entries)
+	}
+	if err != nil {
+		// 释放锁，让下一个周期（或其他实例）重试
+		s.redisClient.Del(ctx, suggestRebuildLockKey)
+		logger.Warn("重建搜索联想索引失败", zap.Error(err))
+	}
+}
+
+// loadEntries 从数据库读取全部联想词条
+func (s *searchSuggestService) loadEntries(ctx context.Context) ([]suggest.Entry, error) {
+	var entries []suggest.Entry
+
+	titles, err := s.materialRepo.ListApprovedTitles(ctx, suggestTitleLimit)
+	if err != nil {
+		return nil, fmt.Errorf("获取资料标题失败: %w", err)
+	}
+	for _, title := range titles {
+		entries = append(entries, suggest.Entry{Kind: suggest.KindTitle, Text: title, Weight: 1})
+	}
+
+	terms, err := s.courseRepo.ListTerms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取课程名称失败: %w", err)
+	}
+	for _, term := range terms {
+		entries = append(entries, suggest.Entry{Kind: suggest.KindCourse, Text: term, Weight: suggestCourseWeight})
+	}
+
+	tags, err := s.tagRepo.ListPopular(ctx, suggestTagLimit)
+	if err != nil {
+		return nil, fmt.Errorf("获取标签失败: %w", err)
+	}
+	for _, tag := range tags {
+		entries = append(entries, suggest.Entry{Kind: suggest.KindTag, Text: tag.Name, Weight: float64(tag.MaterialCount)})
+	}
+
+	// 没有结果的搜索词多为拼写错误，不作为联想词
+	keywords, err := s.hotKeywordRepo.GetEffectiveHotKeywords(ctx, suggestKeywordLimit)
+	if err != nil {
+		return nil, fmt.Errorf("获取热门搜索词失败: %w", err)
+	}
+	for _, keyword := range keywords {
+		entries = append(entries, suggest.Entry{Kind: suggest.KindKeyword, Text: keyword.Keyword, Weight: float64(keyword.SearchCount)})
+	}
+
+	return entries, nil
+}
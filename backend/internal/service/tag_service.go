@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/repository"
+)
+
+var (
+	// ErrTagNotFound 标签不存在
+	ErrTagNotFound = errors.New("标签不存在")
+	// ErrInvalidTag 标签格式错误
+	ErrInvalidTag = errors.New("标签格式错误")
+	// ErrTagAlreadyExists 标签已存在
+	ErrTagAlreadyExists = errors.New("标签已存在，请使用合并")
+)
+
+const (
+	defaultPopularTagLimit = 20
+	maxPopularTagLimit     = 100
+)
+
+// TagService 标签服务接口
+type TagService interface {
+	// ListPopularTags 获取热门标签（按使用该标签的已通过资料数）
+	ListPopularTags(ctx context.Context, limit int) ([]*model.TagResponse, error)
+	// ListTags 分页获取全部标签（管理员）
+	ListTags(ctx context.Context, req *model.TagListRequest) ([]*model.TagResponse, int64, error)
+	// RenameTag 重命名标签
+	RenameTag(ctx context.Context, id uint, req *model.RenameTagRequest) error
+	// MergeTags 将源标签合并到目标标签，源标签的资料改为使用目标标签
+	MergeTags(ctx context.Context, req *model.MergeTagsRequest) error
+	// DeleteTag 删除标签
+	DeleteTag(ctx context.Context, id uint) error
+}
+
+// tagService 标签服务实现
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+// NewTagService 创建标签服务实例
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{
+		tagRepo: tagRepo,
+	}
+}
+
+// ListPopularTags 获取热门标签
+func (s *tagService) ListPopularTags(ctx context.Context, limit int) ([]*model.TagResponse, error) {
+	if limit <= 0 {
+		limit = defaultPopularTagLimit
+	}
+	if limit > maxPopularTagLimit {
+		limit = maxPopularTagLimit
+	}
+
+	tags, err := s.tagRepo.ListPopular(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("获取热门标签失败: %w", err)
+	}
+	return toTagResponses(tags), nil
+}
+
+// ListTags 分页获取全部标签
+func (s *tagService) ListTags(ctx context.Context, req *model.TagListRequest) ([]*model.TagResponse, int64, error) {
+	tags, total, err := s.tagRepo.List(ctx, req.Page, req.PageSize, model.NormalizeTagName(req.Keyword))
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取标签列表失败: %w", err)
+	}
+	return toTagResponses(tags), total, nil
+}
+
+// RenameTag 重命名标签
+func (s *tagService) RenameTag(ctx context.Context, id uint, req *model.RenameTagRequest) error {
+	name := model.NormalizeTagName(req.Name)
+	if !model.IsValidTagName(name) {
+		return fmt.Errorf("%w: 标签不能为空且不超过 %d 个字符", ErrInvalidTag, model.MaxTagLength)
+	}
+
+	if err := s.tagRepo.Rename(ctx, id, name); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTagNotFound):
+			return ErrTagNotFound
+		case errors.Is(err, repository.ErrTagAlreadyExists):
+			return ErrTagAlreadyExists
+		}
+		return fmt.Errorf("重命名标签失败: %w", err)
+	}
+	return nil
+}
+
+// MergeTags 合并标签
+func (s *tagService) MergeTags(ctx context.Context, req *model.MergeTagsRequest) error {
+	if _, err := s.tagRepo.FindByID(ctx, req.TargetID); err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			return ErrTagNotFound
+		}
+		return fmt.Errorf("获取标签失败: %w", err)
+	}
+
+	sourceIDs := make([]uint, 0, len(req.SourceIDs))
+	for _, id := range req.SourceIDs {
+		if id != req.TargetID {
+			sourceIDs = append(sourceIDs, id)
+		}
+	}
+	if len(sourceIDs) == 0 {
+		return fmt.Errorf("%w: 源标签不能只包含目标标签", ErrInvalidTag)
+	}
+
+	if err := s.tagRepo.Merge(ctx, sourceIDs, req.TargetID); err != nil {
+		return fmt.Errorf("合并标签失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteTag 删除标签
+func (s *tagService) DeleteTag(ctx context.Context, id uint) error {
+	if err := s.tagRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			return ErrTagNotFound
+		}
+		return fmt.Errorf("删除标签失败: %w", err)
+	}
+	return nil
+}
+
+// toTagResponses 转换为标签响应
+func toTagResponses(tags []*repository.TagWithCount) []*model.TagResponse {
+	responses := make([]*model.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		responses = append(responses, &model.TagResponse{
+			ID:            tag.ID,
+			Name:          tag.Name,
+			MaterialCount: tag.MaterialCount,
+		})
+	}
+	return responses
+}
+
+// normalizeTags 规范化并去重资料的标签
+func normalizeTags(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = model.NormalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if !model.IsValidTagName(name) {
+			return nil, fmt.Errorf("%w: 标签「%s」超过 %d 个字符", ErrInvalidTag, name, model.MaxTagLength)
+		}
+		seen[name] = true
+		tags = append(tags, name)
+	}
+	if len(tags) > model.MaxTagsPerMaterial {
+		return nil, fmt.Errorf("%w: 每份资料最多 %d 个标签", ErrInvalidTag, model.MaxTagsPerMaterial)
+	}
+	return tags, nil
+}
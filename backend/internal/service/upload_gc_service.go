@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/logger"
+	"github.com/study-upc/backend/internal/pkg/oss"
+	"github.com/study-upc/backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUploadGCRunning 清理任务正在执行
+	ErrUploadGCRunning = errors.New("清理任务正在执行，请稍后再试")
+)
+
+const (
+	uploadGCEnabledKey       = "upload_gc_enabled"
+	uploadGCGraceHoursKey    = "upload_gc_grace_hours"
+	uploadGCIntervalHoursKey = "upload_gc_interval_hours"
+	uploadGCDryRunKey        = "upload_gc_dry_run"
+
+	defaultUploadGCGraceHours    = 24
+	defaultUploadGCIntervalHours = 6
+
+	// uploadGCPrefix 用户上传文件的存储前缀
+	uploadGCPrefix = "materials/"
+	// uploadGCBatchSize 每批查询引用关系的文件数
+	uploadGCBatchSize = 500
+	// uploadGCMaxReportOrphans 报告中返回的孤立文件明细上限
+	uploadGCMaxReportOrphans = 200
+)
+
+// UploadGCService 孤立上传文件清理服务接口
+type UploadGCService interface {
+	// Run 执行一次清理，graceHours 为空时使用系统配置
+	Run(ctx context.Context, dryRun bool, graceHours *int, triggeredBy *uint) (*model.UploadGCReport, error)
+	// ListRuns 获取清理记录
+	ListRuns(ctx context.Context, page, pageSize int) ([]*model.UploadGCRun, int64, error)
+	// Start 启动定时清理任务
+	Start(ctx context.Context)
+}
+
+// uploadGCService 孤立上传文件清理服务实现
+type uploadGCService struct {
+	gcRepo     repository.UploadGCRepository
+	configRepo repository.SystemConfigRepository
+	ossService oss.OSSService
+	mu         sync.Mutex
+}
+
+// NewUploadGCService 创建孤立上传文件清理服务实例
+func NewUploadGCService(
+	gcRepo repository.UploadGCRepository,
+	configRepo repository.SystemConfigRepository,
+	ossService oss.OSSService,
+) UploadGCService {
+	return &uploadGCService{
+		gcRepo:     gcRepo,
+		configRepo: configRepo,
+		ossService: ossService,
+	}
+}
+
+// Run 执行一次清理
+// 列出 materials/ 下的全部文件，删除超过宽限期且未被任何资料或资料版本引用的文件
+func (s *uploadGCService) Run(ctx context.Context, dryRun bool, graceHours *int, triggeredBy *uint) (*model.UploadGCReport, error) {
+	if !s.mu.TryLock() {
+		return nil, ErrUploadGCRunning
+	}
+	defer s.mu.Unlock()
+
+	grace := s.getIntConfig(uploadGCGraceHoursKey, defaultUploadGCGraceHours, "孤立上传文件的宽限期（小时）")
+	if graceHours != nil {
+		grace = *graceHours
+	}
+
+	run := &model.UploadGCRun{
+		DryRun:      dryRun,
+		GraceHours:  grace,
+		TriggeredBy: triggeredBy,
+	}
+	if err := s.gcRepo.CreateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("创建清理记录失败: %w", err)
+	}
+
+	report := &model.UploadGCReport{Run: run, Orphans: make([]*model.UploadGCOrphan, 0)}
+	runErr := s.collect(ctx, run, report, time.Now().Add(-time.Duration(grace)*time.Hour))
+	if runErr != nil {
+		run.Error = runErr.Error()
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	if err := s.gcRepo.UpdateRun(ctx, run); err != nil {
+		logger.Warn("更新清理记录失败", zap.Uint("run_id", run.ID), zap.Error(err))
+	}
+
+	if runErr != nil {
+		return report, runErr
+	}
+	return report, nil
+}
+
+// collect 扫描并（非演练模式下）删除孤立文件
+func (s *uploadGCService) collect(ctx context.Context, run *model.UploadGCRun, report *model.UploadGCReport, cutoff time.Time) error {
+	objects, err := s.ossService.ListFiles(ctx, uploadGCPrefix)
+	if err != nil {
+		return fmt.Errorf("列出 OSS 文件失败: %w", err)
+	}
+	run.ScannedCount = len(objects)
+
+	// 只处理超过宽限期的文件，避免误删刚上传、尚未创建资料的文件
+	candidates := make([]oss.ObjectInfo, 0)
+	for _, obj := range objects {
+		if obj.LastModified.Before(cutoff) {
+			candidates = append(candidates, obj)
+		}
+	}
+
+	for start := 0; start < len(candidates); start += uploadGCBatchSize {
+		end := start + uploadGCBatchSize
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+		batch := candidates[start:end]
+
+		keys := make([]string, 0, len(batch))
+		for _, obj := range batch {
+			keys = append(keys, obj.Key)
+		}
+		referenced, err := s.gcRepo.FindReferencedFileKeys(ctx, keys)
+		if err != nil {
+			return fmt.Errorf("查询文件引用失败: %w", err)
+		}
+
+		for _, obj := range batch {
+			if referenced[obj.Key] {
+				continue
+			}
+
+			run.OrphanCount++
+			run.OrphanBytes += obj.Size
+
+			orphan := &model.UploadGCOrphan{
+				FileKey:      obj.Key,
+				Size:         obj.Size,
+				LastModified: obj.LastModified,
+			}
+			if !run.DryRun {
+				if err := s.ossService.DeleteFile(ctx, obj.Key); err != nil {
+					run.FailedCount++
+					logger.Warn("删除孤立文件失败", zap.String("file_key", obj.Key), zap.Error(err))
+				} else {
+					orphan.Deleted = true
+					run.DeletedCount++
+					run.ReclaimedBytes += obj.Size
+				}
+			}
+
+			if len(report.Orphans) < uploadGCMaxReportOrphans {
+				report.Orphans = append(report.Orphans, orphan)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListRuns 获取清理记录
+func (s *uploadGCService) ListRuns(ctx context.Context, page, pageSize int) ([]*model.UploadGCRun, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	runs, total, err := s.gcRepo.ListRuns(ctx, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取清理记录失败: %w", err)
+	}
+	return runs, total, nil
+}
+
+// Start 启动定时清理任务，每次执行前重新读取系统配置
+func (s *uploadGCService) Start(ctx context.Context) {
+	go func() {
+		for {
+			interval := s.getIntConfig(uploadGCIntervalHoursKey, defaultUploadGCIntervalHours, "孤立上传文件清理间隔（小时）")
+			if interval <= 0 {
+				interval = defaultUploadGCIntervalHours
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Duration(interval) * time.Hour):
+			}
+
+			if !s.getBoolConfig(uploadGCEnabledKey, true) {
+				continue
+			}
+
+			dryRun := s.getBoolConfig(uploadGCDryRunKey, false)
+			report, err := s.Run(ctx, dryRun, nil, nil)
+			if err != nil {
+				logger.Warn("孤立上传文件清理失败", zap.Error(err))
+				continue
+			}
+			logger.Info("孤立上传文件清理完成",
+				zap.Bool("dry_run", dryRun),
+				zap.Int("scanned", report.Run.ScannedCount),
+				zap.Int("orphans", report.Run.OrphanCount),
+				zap.Int("deleted", report.Run.DeletedCount),
+				zap.Int64("reclaimed_bytes", report.Run.ReclaimedBytes))
+		}
+	}()
+}
+
+// getIntConfig 读取整数类型的系统配置，不存在时写入默认值
+func (s *uploadGCService) getIntConfig(key string, defaultValue int, description string) int {
+	config, err := s.configRepo.GetSystemConfig(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = s.configRepo.CreateSystemConfig(&model.SystemConfig{
+				ConfigKey:   key,
+				ConfigValue: strconv.Itoa(defaultValue),
+				Description: description,
+				Category:    "upload",
+			})
+		}
+		return defaultValue
+	}
+
+	value, err := strconv.Atoi(strings.TrimSpace(config.ConfigValue))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getBoolConfig 读取布尔类型的系统配置
+func (s *uploadGCService) getBoolConfig(key string, defaultValue bool) bool {
+	config, err := s.configRepo.GetSystemConfig(key)
+	if err != nil {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(strings.TrimSpace(config.ConfigValue))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/study-upc/backend/internal/model"
+	"github.com/study-upc/backend/internal/pkg/logger"
+	"github.com/study-upc/backend/internal/pkg/oss"
+	"github.com/study-upc/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrUploadSessionNotFound 上传会话不存在
+	ErrUploadSessionNotFound = errors.New("上传会话不存在")
+	// ErrUploadSessionClosed 上传会话已结束
+	ErrUploadSessionClosed = errors.New("上传会话已结束")
+	// ErrUploadSessionExpired 上传会话已过期
+	ErrUploadSessionExpired = errors.New("上传会话已过期")
+	// ErrUploadIncomplete 分片未上传完整
+	ErrUploadIncomplete = errors.New("仍有分片未上传完成")
+	// ErrTooManyUploadSessions 进行中的上传会话过多
+	ErrTooManyUploadSessions = errors.New("进行中的上传任务过多，请先完成或取消已有任务")
+)
+
+const (
+	// uploadSessionTTL 上传会话有效期，超过后自动取消
+	uploadSessionTTL = 24 * time.Hour
+	// uploadSessionSweepInterval 过期会话检查间隔
+	uploadSessionSweepInterval = 30 * time.Minute
+	// maxActiveUploadSessions 每个用户同时进行的上传会话上限
+	maxActiveUploadSessions = 5
+)
+
+// UploadSessionService 分片上传会话服务接口
+type UploadSessionService interface {
+	// CreateSession 创建分片上传会话
+	CreateSession(ctx context.Context, userID uint, req *model.CreateUploadSessionRequest) (*model.UploadSessionResponse, error)
+	// GetSession 获取上传会话及已上传的分片（用于断点续传）
+	GetSession(ctx context.Context, userID, sessionID uint) (*model.UploadSessionResponse, error)
+	// PresignParts 获取分片上传地址
+	PresignParts(ctx context.Context, userID, sessionID uint, req *model.PresignUploadPartsRequest) (*model.PresignUploadPartsResponse, error)
+	// CompleteSession 合并分片完成上传
+	CompleteSession(ctx context.Context, userID, sessionID uint) (*model.UploadSessionResponse, error)
+	// AbortSession 取消上传
+	AbortSession(ctx context.Context, userID, sessionID uint) error
+	// AbortExpiredSessions 自动取消已过期的上传会话
+	AbortExpiredSessions(ctx context.Context) (int, error)
+	// Start 启动过期会话的定时清理
+	Start(ctx context.Context)
+}
+
+// uploadSessionService 分片上传会话服务实现
+type uploadSessionService struct {
+	sessionRepo     repository.UploadSessionRepository
+	materialService MaterialService
+	ossService      oss.OSSService
+}
+
+// NewUploadSessionService 创建分片上传会话服务实例
+func NewUploadSessionService(sessionRepo repository.UploadSessionRepository, materialService MaterialService, ossService oss.OSSService) UploadSessionService {
+	return &uploadSessionService{
+		sessionRepo:     sessionRepo,
+		materialService: materialService,
+		ossService:      ossService,
+	}
+}
+
+// CreateSession 创建分片上传会话
+func (s *uploadSessionService) CreateSession(ctx context.Context, userID uint, req *model.CreateUploadSessionRequest) (*model.UploadSessionResponse, error) {
+	active, err := s.sessionRepo.CountActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("统计上传会话失败: %w", err)
+	}
+	if active >= maxActiveUploadSessions {
+		return nil, ErrTooManyUploadSessions
+	}
+
+	// 与直传签名相同，在上传前检查存储配额，避免超出配额的文件先写入存储
+	if err := s.materialService.CheckStorageQuota(ctx, userID, req.FileSize); err != nil {
+		return nil, err
+	}
+
+	result, err := s.ossService.InitiateMultipartUpload(ctx, userID, req.FileName, req.FileSize, req.MimeType, req.PartSize)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &model.UploadSession{
+		UserID:      userID,
+		FileKey:     result.FileKey,
+		FileName:    req.FileName,
+		FileSize:    req.FileSize,
+		MimeType:    req.MimeType,
+		OSSUploadID: result.UploadID,
+		PartSize:    result.PartSize,
+		PartCount:   result.PartCount,
+		Status:      model.UploadSessionUploading,
+		ExpiresAt:   time.Now().Add(uploadSessionTTL),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		// 会话记录创建失败时取消 OSS 分片上传，避免残留
+		_ = s.ossService.AbortMultipartUpload(ctx, result.FileKey, result.UploadID)
+		return nil, fmt.Errorf("创建上传会话失败: %w", err)
+	}
+
+	return session.ToUploadSessionResponse(), nil
+}
+
+// GetSession 获取上传会话及已上传的分片
+func (s *uploadSessionService) GetSession(ctx context.Context, userID, sessionID uint) (*model.UploadSessionResponse, error) {
+	session, err := s.findSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := session.ToUploadSessionResponse()
+	if session.Status != model.UploadSessionUploading {
+		return response, nil
+	}
+
+	parts, err := s.ossService.ListUploadedParts(ctx, session.FileKey, session.OSSUploadID)
+	if err != nil {
+		return nil, fmt.Errorf("获取已上传分片失败: %w", err)
+	}
+	for _, part := range parts {
+		response.UploadedParts = append(response.UploadedParts, &model.UploadedPartInfo{
+			PartNumber: part.PartNumber,
+			Size:       part.Size,
+			ETag:       part.ETag,
+		})
+	}
+	return response, nil
+}
+
+// PresignParts 获取分片上传地址
+func (s *uploadSessionService) PresignParts(ctx context.Context, userID, sessionID uint, req *model.PresignUploadPartsRequest) (*model.PresignUploadPartsResponse, error) {
+	session, err := s.findActiveSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, partNumber := range req.PartNumbers {
+		if partNumber < 1 || partNumber > session.PartCount {
+			return nil, oss.ErrInvalidPartNumber
+		}
+	}
+
+	urls, err := s.ossService.GeneratePartUploadURLs(ctx, session.FileKey, session.OSSUploadID, req.PartNumbers)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]*model.UploadPartURL, 0, len(req.PartNumbers))
+	for _, partNumber := range req.PartNumbers {
+		parts = append(parts, &model.UploadPartURL{
+			PartNumber: partNumber,
+			UploadURL:  urls[partNumber],
+		})
+	}
+
+	return &model.PresignUploadPartsResponse{
+		Parts:     parts,
+		ExpiresIn: int64(s.ossService.UploadExpiresIn().Seconds()),
+	}, nil
+}
+
+// CompleteSession 合并分片完成上传
+// 以 OSS 中实际存在的分片为准进行校验和合并，客户端无需回传 ETag
+func (s *uploadSessionService) CompleteSession(ctx context.Context, userID, sessionID uint) (*model.UploadSessionResponse, error) {
+	session, err := s.findActiveSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := s.ossService.ListUploadedParts(ctx, session.FileKey, session.OSSUploadID)
+	if err != nil {
+		return nil, fmt.Errorf("获取已上传分片失败: %w", err)
+	}
+	if err := validateUploadedParts(session, parts); err != nil {
+		return nil, err
+	}
+
+	if err := s.ossService.CompleteMultipartUpload(ctx, session.FileKey, session.OSSUploadID, parts); err != nil {
+		return nil, fmt.Errorf("合并分片失败: %w", err)
+	}
+
+	if err := s.sessionRepo.UpdateStatus(ctx, session.ID, model.UploadSessionCompleted); err != nil {
+		return nil, fmt.Errorf("更新上传会话失败: %w", err)
+	}
+	session.Status = model.UploadSessionCompleted
+
+	return session.ToUploadSessionResponse(), nil
+}
+
+// AbortSession 取消上传
+func (s *uploadSessionService) AbortSession(ctx context.Context, userID, sessionID uint) error {
+	session, err := s.findSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.Status != model.UploadSessionUploading {
+		return ErrUploadSessionClosed
+	}
+
+	if err := s.ossService.AbortMultipartUpload(ctx, session.FileKey, session.OSSUploadID); err != nil {
+		return fmt.Errorf("取消分片上传失败: %w", err)
+	}
+
+	if err := s.sessionRepo.UpdateStatus(ctx, session.ID, model.UploadSessionAborted); err != nil {
+		return fmt.Errorf("更新上传会话失败: %w", err)
+	}
+	return nil
+}
+
+// AbortExpiredSessions 自动取消已过期的上传会话
+func (s *uploadSessionService) AbortExpiredSessions(ctx context.Context) (int, error) {
+	sessions, err := s.sessionRepo.ListExpired(ctx, time.Now(), 100)
+	if err != nil {
+		return 0, fmt.Errorf("获取过期上传会话失败: %w", err)
+	}
+
+	aborted := 0
+	for _, session := range sessions {
+		if err := s.ossService.AbortMultipartUpload(ctx, session.FileKey, session.OSSUploadID); err != nil {
+			// OSS 侧可能已自动清理，记录后仍标记为过期
+			logger.Warn("取消过期分片上传失败", zap.Uint("session_id", session.ID), zap.Error(err))
+		}
+		if err := s.sessionRepo.UpdateStatus(ctx, session.ID, model.UploadSessionExpired); err != nil {
+			logger.Warn("更新过期上传会话失败", zap.Uint("session_id", session.ID), zap.Error(err))
+			continue
+		}
+		aborted++
+	}
+	return aborted, nil
+}
+
+// Start 启动过期会话的定时清理
+func (s *uploadSessionService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(uploadSessionSweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				aborted, err := s.AbortExpiredSessions(ctx)
+				if err != nil {
+					logger.Warn("清理过期上传会话失败", zap.Error(err))
+					continue
+				}
+				if aborted > 0 {
+					logger.Info("已取消过期上传会话", zap.Int("count", aborted))
+				}
+			}
+		}
+	}()
+}
+
+// findSession 查找用户的上传会话
+func (s *uploadSessionService) findSession(ctx context.Context, userID, sessionID uint) (*model.UploadSession, error) {
+	session, err := s.sessionRepo.FindByIDAndUser(ctx, sessionID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUploadSessionNotFound) {
+			return nil, ErrUploadSessionNotFound
+		}
+		return nil, fmt.Errorf("获取上传会话失败: %w", err)
+	}
+	return session, nil
+}
+
+// findActiveSession 查找仍可继续上传的会话
+func (s *uploadSessionService) findActiveSession(ctx context.Context, userID, sessionID uint) (*model.UploadSession, error) {
+	session, err := s.findSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != model.UploadSessionUploading {
+		return nil, ErrUploadSessionClosed
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return nil, ErrUploadSessionExpired
+	}
+	return session, nil
+}
+
+// validateUploadedParts 校验分片是否完整且大小与会话一致
+func validateUploadedParts(session *model.UploadSession, parts []oss.UploadedPart) error {
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	if len(parts) != session.PartCount {
+		return fmt.Errorf("%w: 已上传 %d/%d 个分片", ErrUploadIncomplete, len(parts), session.PartCount)
+	}
+
+	var total int64
+	for i, part := range parts {
+		if part.PartNumber != i+1 {
+			return fmt.Errorf("%w: 缺少第 %d 个分片", ErrUploadIncomplete, i+1)
+		}
+		if i < len(parts)-1 && part.Size != session.PartSize {
+			return fmt.Errorf("%w: 第 %d 个分片大小不正确", ErrUploadedFileMismatch, part.PartNumber)
+		}
+		total += part.Size
+	}
+
+	if total != session.FileSize {
+		return fmt.Errorf("%w: 文件大小应为 %d 字节，实际为 %d 字节", ErrUploadedFileMismatch, session.FileSize, total)
+	}
+	return nil
+}
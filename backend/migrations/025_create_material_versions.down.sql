@@ -0,0 +1,12 @@
+-- Rollback material versioning
+
+DROP INDEX IF EXISTS idx_download_records_version_id;
+ALTER TABLE download_records DROP COLUMN IF EXISTS version_id;
+
+DROP INDEX IF EXISTS idx_materials_current_version_id;
+ALTER TABLE materials
+    DROP COLUMN IF EXISTS current_version_id,
+    DROP COLUMN IF EXISTS current_version;
+
+DROP TRIGGER IF EXISTS update_material_versions_updated_at ON material_versions;
+DROP TABLE IF EXISTS material_versions;
@@ -0,0 +1,68 @@
+-- Material versioning: each version owns its file, metadata snapshot and review status
+
+CREATE TABLE IF NOT EXISTS material_versions (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    material_id BIGINT NOT NULL REFERENCES materials(id),
+    version_number INT NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    category VARCHAR(50) NOT NULL,
+    course_name VARCHAR(100),
+    file_name VARCHAR(255) NOT NULL,
+    file_size BIGINT NOT NULL,
+    file_key VARCHAR(500) NOT NULL UNIQUE,
+    mime_type VARCHAR(100) NOT NULL,
+    change_note VARCHAR(500),
+    uploader_id BIGINT NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewer_id BIGINT REFERENCES users(id),
+    reviewed_at TIMESTAMP,
+    rejection_reason TEXT,
+    UNIQUE (material_id, version_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_material_versions_material_id ON material_versions(material_id);
+CREATE INDEX IF NOT EXISTS idx_material_versions_status ON material_versions(status);
+CREATE INDEX IF NOT EXISTS idx_material_versions_uploader_id ON material_versions(uploader_id);
+CREATE INDEX IF NOT EXISTS idx_material_versions_deleted_at ON material_versions(deleted_at);
+
+ALTER TABLE materials
+    ADD COLUMN IF NOT EXISTS current_version_id BIGINT,
+    ADD COLUMN IF NOT EXISTS current_version INT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_materials_current_version_id ON materials(current_version_id);
+
+ALTER TABLE download_records ADD COLUMN IF NOT EXISTS version_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_download_records_version_id ON download_records(version_id);
+
+-- Backfill version 1 for existing materials
+INSERT INTO material_versions (
+    created_at, updated_at, material_id, version_number, title, description, category, course_name,
+    file_name, file_size, file_key, mime_type, uploader_id, status, reviewer_id, reviewed_at, rejection_reason
+)
+SELECT
+    m.created_at, m.updated_at, m.id, 1, m.title, m.description, m.category, m.course_name,
+    m.file_name, m.file_size, m.file_key, COALESCE(m.mime_type, ''), m.uploader_id, m.status::text,
+    m.reviewer_id, m.reviewed_at, m.rejection_reason
+FROM materials m
+WHERE m.file_key IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM material_versions v WHERE v.material_id = m.id);
+
+UPDATE materials m
+SET current_version_id = v.id, current_version = v.version_number
+FROM material_versions v
+WHERE v.material_id = m.id AND v.version_number = 1 AND m.current_version_id IS NULL;
+
+CREATE TRIGGER update_material_versions_updated_at
+    BEFORE UPDATE ON material_versions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE material_versions IS '资料版本表';
+COMMENT ON COLUMN material_versions.version_number IS '版本号';
+COMMENT ON COLUMN material_versions.change_note IS '版本说明';
+COMMENT ON COLUMN materials.current_version_id IS '当前生效版本ID';
+COMMENT ON COLUMN download_records.version_id IS '下载的资料版本ID';
@@ -0,0 +1,9 @@
+DELETE FROM system_configs WHERE config_key IN (
+    'upload_gc_enabled',
+    'upload_gc_grace_hours',
+    'upload_gc_interval_hours',
+    'upload_gc_dry_run'
+);
+
+DROP TRIGGER IF EXISTS update_upload_gc_runs_updated_at ON upload_gc_runs;
+DROP TABLE IF EXISTS upload_gc_runs;
@@ -0,0 +1,38 @@
+-- Orphaned upload garbage collector: run history and configuration
+
+CREATE TABLE IF NOT EXISTS upload_gc_runs (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    grace_hours INT NOT NULL,
+    triggered_by BIGINT REFERENCES users(id),
+    scanned_count INT NOT NULL DEFAULT 0,
+    orphan_count INT NOT NULL DEFAULT 0,
+    orphan_bytes BIGINT NOT NULL DEFAULT 0,
+    deleted_count INT NOT NULL DEFAULT 0,
+    reclaimed_bytes BIGINT NOT NULL DEFAULT 0,
+    failed_count INT NOT NULL DEFAULT 0,
+    error TEXT,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_gc_runs_created_at ON upload_gc_runs(created_at);
+CREATE INDEX IF NOT EXISTS idx_upload_gc_runs_triggered_by ON upload_gc_runs(triggered_by);
+
+CREATE TRIGGER update_upload_gc_runs_updated_at
+    BEFORE UPDATE ON upload_gc_runs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO system_configs (config_key, config_value, description, category) VALUES
+('upload_gc_enabled', 'true', '是否启用孤立上传文件自动清理', 'upload'),
+('upload_gc_grace_hours', '24', '孤立上传文件的宽限期（小时）', 'upload'),
+('upload_gc_interval_hours', '6', '孤立上传文件清理间隔（小时）', 'upload'),
+('upload_gc_dry_run', 'false', '定时清理是否只统计不删除', 'upload')
+ON CONFLICT (config_key) DO NOTHING;
+
+COMMENT ON TABLE upload_gc_runs IS '孤立上传文件清理记录表';
+COMMENT ON COLUMN upload_gc_runs.dry_run IS '是否为演练模式';
+COMMENT ON COLUMN upload_gc_runs.grace_hours IS '宽限期（小时）';
+COMMENT ON COLUMN upload_gc_runs.reclaimed_bytes IS '已回收空间（字节）';
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_upload_sessions_updated_at ON upload_sessions;
+DROP TABLE IF EXISTS upload_sessions;
@@ -0,0 +1,31 @@
+-- Multipart upload sessions for resumable uploads
+
+CREATE TABLE IF NOT EXISTS upload_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    file_key VARCHAR(500) NOT NULL UNIQUE,
+    file_name VARCHAR(255) NOT NULL,
+    file_size BIGINT NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    oss_upload_id VARCHAR(255) NOT NULL,
+    part_size BIGINT NOT NULL,
+    part_count INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'uploading',
+    expires_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_user_id ON upload_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_status ON upload_sessions(status);
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_expires_at ON upload_sessions(expires_at);
+
+CREATE TRIGGER update_upload_sessions_updated_at
+    BEFORE UPDATE ON upload_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE upload_sessions IS '分片上传会话表';
+COMMENT ON COLUMN upload_sessions.oss_upload_id IS 'OSS 分片上传ID';
+COMMENT ON COLUMN upload_sessions.status IS '会话状态: uploading/completed/aborted/expired';
@@ -0,0 +1,7 @@
+DELETE FROM system_configs WHERE config_key = 'duplicate_auto_reject';
+
+DROP INDEX IF EXISTS idx_material_versions_content_hash;
+ALTER TABLE material_versions DROP COLUMN IF EXISTS content_hash;
+
+DROP INDEX IF EXISTS idx_materials_content_hash;
+ALTER TABLE materials DROP COLUMN IF EXISTS content_hash;
@@ -0,0 +1,14 @@
+-- Content-hash deduplication: SHA-256 of the uploaded object, computed during upload finalization
+
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_materials_content_hash ON materials(content_hash);
+
+ALTER TABLE material_versions ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_material_versions_content_hash ON material_versions(content_hash);
+
+INSERT INTO system_configs (config_key, config_value, description, category) VALUES
+('duplicate_auto_reject', 'false', '上传与已通过资料内容完全相同的文件时是否自动拒绝', 'upload')
+ON CONFLICT (config_key) DO NOTHING;
+
+COMMENT ON COLUMN materials.content_hash IS '文件内容 SHA-256';
+COMMENT ON COLUMN material_versions.content_hash IS '文件内容 SHA-256';
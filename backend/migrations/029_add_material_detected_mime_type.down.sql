@@ -0,0 +1,10 @@
+DELETE FROM system_configs WHERE config_key = 'mime_sniff_action';
+
+ALTER TABLE material_versions
+    DROP COLUMN IF EXISTS mime_mismatch,
+    DROP COLUMN IF EXISTS detected_mime_type;
+
+DROP INDEX IF EXISTS idx_materials_mime_mismatch;
+ALTER TABLE materials
+    DROP COLUMN IF EXISTS mime_mismatch,
+    DROP COLUMN IF EXISTS detected_mime_type;
@@ -0,0 +1,19 @@
+-- Magic-byte content sniffing: store the type detected from the object's leading bytes
+
+ALTER TABLE materials
+    ADD COLUMN IF NOT EXISTS detected_mime_type VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS mime_mismatch BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_materials_mime_mismatch ON materials(mime_mismatch);
+
+ALTER TABLE material_versions
+    ADD COLUMN IF NOT EXISTS detected_mime_type VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS mime_mismatch BOOLEAN NOT NULL DEFAULT FALSE;
+
+INSERT INTO system_configs (config_key, config_value, description, category) VALUES
+('mime_sniff_action', 'block', '文件实际类型与声明类型不符时的处理方式：block 拒绝上传，flag 仅标记供审核参考', 'upload')
+ON CONFLICT (config_key) DO NOTHING;
+
+COMMENT ON COLUMN materials.detected_mime_type IS '根据文件头检测到的真实类型';
+COMMENT ON COLUMN materials.mime_mismatch IS '检测类型与声明类型是否不一致';
+COMMENT ON COLUMN material_versions.detected_mime_type IS '根据文件头检测到的真实类型';
+COMMENT ON COLUMN material_versions.mime_mismatch IS '检测类型与声明类型是否不一致';
@@ -0,0 +1,7 @@
+ALTER TABLE materials
+    DROP COLUMN IF EXISTS preview_status,
+    DROP COLUMN IF EXISTS preview_excerpt,
+    DROP COLUMN IF EXISTS thumbnail_key;
+
+DROP TRIGGER IF EXISTS update_processing_jobs_updated_at ON processing_jobs;
+DROP TABLE IF EXISTS processing_jobs;
@@ -0,0 +1,43 @@
+-- Asynchronous processing jobs: first-page thumbnail and text excerpt generation
+
+CREATE TABLE IF NOT EXISTS processing_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    material_id BIGINT NOT NULL REFERENCES materials(id) ON DELETE CASCADE,
+    file_key VARCHAR(500) NOT NULL,
+    job_type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 3,
+    last_error TEXT,
+    run_after TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_processing_jobs_material_id ON processing_jobs(material_id);
+CREATE INDEX IF NOT EXISTS idx_processing_jobs_status ON processing_jobs(status);
+CREATE INDEX IF NOT EXISTS idx_processing_jobs_run_after ON processing_jobs(run_after);
+
+CREATE TRIGGER update_processing_jobs_updated_at
+    BEFORE UPDATE ON processing_jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE materials
+    ADD COLUMN IF NOT EXISTS thumbnail_key VARCHAR(500),
+    ADD COLUMN IF NOT EXISTS preview_excerpt TEXT,
+    ADD COLUMN IF NOT EXISTS preview_status VARCHAR(20) NOT NULL DEFAULT 'pending';
+
+-- 为已有资料补充预览任务
+INSERT INTO processing_jobs (material_id, file_key, job_type)
+SELECT id, file_key, 'preview' FROM materials WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE processing_jobs IS '资料后台处理任务表';
+COMMENT ON COLUMN processing_jobs.file_key IS '处理的文件，资料更换版本后旧任务的结果会被丢弃';
+COMMENT ON COLUMN processing_jobs.status IS '任务状态: pending/running/succeeded/failed';
+COMMENT ON COLUMN processing_jobs.run_after IS '最早执行时间，用于失败后延迟重试';
+COMMENT ON COLUMN materials.thumbnail_key IS '首页缩略图 OSS 存储键';
+COMMENT ON COLUMN materials.preview_excerpt IS '文本摘要';
+COMMENT ON COLUMN materials.preview_status IS '预览生成状态: pending/ready/failed/unsupported';
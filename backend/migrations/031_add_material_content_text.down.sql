@@ -0,0 +1,18 @@
+DELETE FROM processing_jobs WHERE job_type = 'extract_text';
+
+CREATE OR REPLACE FUNCTION materials_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', COALESCE(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.description, '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.course_name, '')), 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE materials DROP COLUMN IF EXISTS content_text;
+
+UPDATE materials SET search_vector =
+    setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('simple', COALESCE(course_name, '')), 'C');
@@ -0,0 +1,21 @@
+-- Full-text extraction: store extracted document text and fold it into the search vector
+
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS content_text TEXT;
+
+-- 全文以最低权重 D 计入搜索向量，排序时标题、描述、课程名称优先
+CREATE OR REPLACE FUNCTION materials_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', COALESCE(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.description, '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.course_name, '')), 'C') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.content_text, '')), 'D');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- 为已有资料补充全文提取任务
+INSERT INTO processing_jobs (material_id, file_key, job_type)
+SELECT id, file_key, 'extract_text' FROM materials WHERE deleted_at IS NULL;
+
+COMMENT ON COLUMN materials.content_text IS '从文件中提取的全文（有长度上限），用于全文搜索';
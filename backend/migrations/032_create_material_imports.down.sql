@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS material_import_items;
+DROP TRIGGER IF EXISTS update_material_imports_updated_at ON material_imports;
+DROP TABLE IF EXISTS material_imports;
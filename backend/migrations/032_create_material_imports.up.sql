@@ -0,0 +1,46 @@
+-- Bulk material import: a ZIP archive with a manifest, tracked per row
+
+CREATE TABLE IF NOT EXISTS material_imports (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    uploader_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_key VARCHAR(500) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    total_rows INT NOT NULL DEFAULT 0,
+    succeeded_rows INT NOT NULL DEFAULT 0,
+    failed_rows INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_material_imports_uploader_id ON material_imports(uploader_id);
+CREATE INDEX IF NOT EXISTS idx_material_imports_status ON material_imports(status);
+
+CREATE TRIGGER update_material_imports_updated_at
+    BEFORE UPDATE ON material_imports
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS material_import_items (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    import_id BIGINT NOT NULL REFERENCES material_imports(id) ON DELETE CASCADE,
+    row_number INT NOT NULL,
+    file_path VARCHAR(500),
+    title VARCHAR(200),
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    material_id BIGINT REFERENCES materials(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_material_import_items_import_id ON material_import_items(import_id);
+
+COMMENT ON TABLE material_imports IS '资料批量导入任务表';
+COMMENT ON COLUMN material_imports.file_key IS '包含资料文件和清单的 ZIP 压缩包存储键';
+COMMENT ON COLUMN material_imports.status IS '任务状态: pending/running/completed/failed';
+COMMENT ON TABLE material_import_items IS '资料批量导入条目结果表';
+COMMENT ON COLUMN material_import_items.row_number IS '清单中的行号（从 1 开始，不含表头）';
+COMMENT ON COLUMN material_import_items.status IS '导入结果: succeeded/failed';
@@ -0,0 +1,3 @@
+DELETE FROM system_configs WHERE config_key IN ('storage_quota_default_mb', 'storage_quota_committee_mb', 'storage_quota_admin_mb');
+
+ALTER TABLE users DROP COLUMN IF EXISTS storage_quota_mb;
@@ -0,0 +1,11 @@
+-- Per-user and per-role storage quotas
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS storage_quota_mb BIGINT;
+
+INSERT INTO system_configs (config_key, config_value, description, category) VALUES
+('storage_quota_default_mb', '2048', '未单独配置的角色的存储配额（MB），0 表示不限制', 'upload'),
+('storage_quota_committee_mb', '2048', '学委的存储配额（MB），0 表示不限制', 'upload'),
+('storage_quota_admin_mb', '0', '管理员的存储配额（MB），0 表示不限制', 'upload')
+ON CONFLICT (config_key) DO NOTHING;
+
+COMMENT ON COLUMN users.storage_quota_mb IS '管理员为该用户单独设置的存储配额（MB），为空时按角色配置，0 表示不限制';
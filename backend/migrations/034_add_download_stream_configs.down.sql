@@ -0,0 +1 @@
+DELETE FROM system_configs WHERE config_key IN ('download_mode', 'download_watermark_enabled');
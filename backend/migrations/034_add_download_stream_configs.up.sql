@@ -0,0 +1,6 @@
+-- Proxied (streamed) downloads and PDF watermarking
+
+INSERT INTO system_configs (config_key, config_value, description, category) VALUES
+('download_mode', 'presigned', '下载方式：presigned 允许客户端选择预签名 URL 或代理下载，stream 强制所有下载经后端代理', 'download'),
+('download_watermark_enabled', 'false', '代理下载的 PDF 是否一律添加用户水印（用户名、用户 ID 和日期）', 'download')
+ON CONFLICT (config_key) DO NOTHING;
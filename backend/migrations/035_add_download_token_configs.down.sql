@@ -0,0 +1 @@
+DELETE FROM system_configs WHERE config_key = 'download_token_bind_ip';
@@ -0,0 +1,5 @@
+-- Single-use download tokens
+
+INSERT INTO system_configs (config_key, config_value, description, category) VALUES
+('download_token_bind_ip', 'false', '下载链接是否绑定申请时的客户端 IP，开启后其他网络环境无法使用该链接', 'download')
+ON CONFLICT (config_key) DO NOTHING;
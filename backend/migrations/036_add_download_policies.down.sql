@@ -0,0 +1,7 @@
+DELETE FROM system_configs WHERE config_key IN ('download_weekly_limit', 'download_burst_limit', 'download_daily_limit_committee', 'download_daily_limit_admin', 'download_burst_limit_admin');
+
+DROP TABLE IF EXISTS download_quota_boosts;
+
+ALTER TABLE users DROP COLUMN IF EXISTS download_burst_limit;
+ALTER TABLE users DROP COLUMN IF EXISTS download_weekly_limit;
+ALTER TABLE users DROP COLUMN IF EXISTS download_daily_limit;
@@ -0,0 +1,40 @@
+-- Role- and user-aware download limits with daily, weekly and burst windows
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS download_daily_limit INTEGER;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS download_weekly_limit INTEGER;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS download_burst_limit INTEGER;
+
+COMMENT ON COLUMN users.download_daily_limit IS '管理员为该用户单独设置的每日下载次数，为空时按角色配置，0 表示不限制';
+COMMENT ON COLUMN users.download_weekly_limit IS '管理员为该用户单独设置的每周下载次数，为空时按角色配置，0 表示不限制';
+COMMENT ON COLUMN users.download_burst_limit IS '管理员为该用户单独设置的每分钟下载次数，为空时按角色配置，0 表示不限制';
+
+CREATE TABLE IF NOT EXISTS download_quota_boosts (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20),
+    extra_daily INTEGER NOT NULL DEFAULT 0,
+    extra_weekly INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    reason VARCHAR(255),
+    created_by BIGINT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_download_quota_boosts_user_id ON download_quota_boosts(user_id);
+CREATE INDEX IF NOT EXISTS idx_download_quota_boosts_ends_at ON download_quota_boosts(ends_at);
+
+CREATE TRIGGER update_download_quota_boosts_updated_at BEFORE UPDATE ON download_quota_boosts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE download_quota_boosts IS '临时下载额度提升（如考试周），指定用户时只对该用户生效，否则对指定角色或全体用户生效';
+
+-- 每日限制的默认值沿用 download_daily_limit
+INSERT INTO system_configs (config_key, config_value, description, category) VALUES
+('download_weekly_limit', '0', '未单独配置的角色每周最大下载次数，0 表示不限制', 'download'),
+('download_burst_limit', '10', '未单独配置的角色每分钟最大下载次数，0 表示不限制', 'download'),
+('download_daily_limit_committee', '50', '学委每日最大下载次数，0 表示不限制', 'download'),
+('download_daily_limit_admin', '0', '管理员每日最大下载次数，0 表示不限制', 'download'),
+('download_burst_limit_admin', '0', '管理员每分钟最大下载次数，0 表示不限制', 'download')
+ON CONFLICT (config_key) DO NOTHING;
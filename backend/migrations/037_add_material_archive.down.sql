@@ -0,0 +1,17 @@
+DELETE FROM system_configs WHERE config_key IN ('material_archive_enabled', 'material_archive_interval_hours', 'material_archive_restore_grace_days');
+
+DROP INDEX IF EXISTS idx_download_records_material_created_at;
+DROP TABLE IF EXISTS material_archive_rules;
+
+-- 枚举值无法直接删除，已归档的资料恢复为已通过
+UPDATE materials SET status = 'approved' WHERE status = 'archived';
+
+DROP INDEX IF EXISTS idx_materials_archive_scheduled_at;
+DROP INDEX IF EXISTS idx_materials_archive_rule_id;
+DROP INDEX IF EXISTS idx_materials_course_term;
+
+ALTER TABLE materials DROP COLUMN IF EXISTS archive_restored_at;
+ALTER TABLE materials DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE materials DROP COLUMN IF EXISTS archive_scheduled_at;
+ALTER TABLE materials DROP COLUMN IF EXISTS archive_rule_id;
+ALTER TABLE materials DROP COLUMN IF EXISTS course_term;
@@ -0,0 +1,50 @@
+-- Material archival lifecycle: archived status, archive rules and course terms
+
+ALTER TYPE material_status ADD VALUE IF NOT EXISTS 'archived';
+
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS course_term VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS archive_rule_id BIGINT;
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS archive_scheduled_at TIMESTAMP;
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS archive_restored_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_materials_course_term ON materials(course_term);
+CREATE INDEX IF NOT EXISTS idx_materials_archive_rule_id ON materials(archive_rule_id);
+CREATE INDEX IF NOT EXISTS idx_materials_archive_scheduled_at ON materials(archive_scheduled_at) WHERE archive_scheduled_at IS NOT NULL;
+
+COMMENT ON COLUMN materials.course_term IS '开课学期，如 2024-2025-1';
+COMMENT ON COLUMN materials.archive_rule_id IS '命中的归档规则ID';
+COMMENT ON COLUMN materials.archive_scheduled_at IS '计划归档时间（已提前通知上传者）';
+COMMENT ON COLUMN materials.archived_at IS '归档时间';
+COMMENT ON COLUMN materials.archive_restored_at IS '最近一次从归档恢复的时间';
+
+CREATE TABLE IF NOT EXISTS material_archive_rules (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    category VARCHAR(50),
+    age_months INTEGER NOT NULL DEFAULT 0,
+    inactive_months INTEGER NOT NULL DEFAULT 0,
+    before_term VARCHAR(20),
+    notice_days INTEGER NOT NULL DEFAULT 7,
+    transition_storage BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by BIGINT NOT NULL REFERENCES users(id),
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_material_archive_rules_updated_at BEFORE UPDATE ON material_archive_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE material_archive_rules IS '资料归档规则：按上传时间、下载活跃度或开课学期归档已通过的资料';
+
+-- 按活跃度归档时需要按资料统计近期下载
+CREATE INDEX IF NOT EXISTS idx_download_records_material_created_at ON download_records(material_id, created_at);
+
+INSERT INTO system_configs (config_key, config_value, description, category) VALUES
+('material_archive_enabled', 'true', '是否启用资料定时归档任务', 'material'),
+('material_archive_interval_hours', '24', '资料归档任务执行间隔（小时）', 'material'),
+('material_archive_restore_grace_days', '180', '从归档恢复的资料在多少天内不会再次被自动归档', 'material')
+ON CONFLICT (config_key) DO NOTHING;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_materials_course_id;
+ALTER TABLE materials DROP COLUMN IF EXISTS course_id;
+
+DROP TABLE IF EXISTS course_aliases;
+DROP TRIGGER IF EXISTS update_courses_updated_at ON courses;
+DROP TABLE IF EXISTS courses;
@@ -0,0 +1,87 @@
+-- Course catalog: canonical courses with aliases, materials linked by course_id
+
+CREATE TABLE IF NOT EXISTS courses (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL,
+    department VARCHAR(100) NOT NULL DEFAULT '',
+    semester VARCHAR(20) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_courses_name ON courses(lower(name));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_courses_code ON courses(lower(code)) WHERE code <> '';
+CREATE INDEX IF NOT EXISTS idx_courses_department ON courses(department);
+
+CREATE TRIGGER update_courses_updated_at BEFORE UPDATE ON courses FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE courses IS '课程目录';
+COMMENT ON COLUMN courses.code IS '课程代码';
+COMMENT ON COLUMN courses.name IS '规范名称';
+COMMENT ON COLUMN courses.department IS '开课院系';
+COMMENT ON COLUMN courses.semester IS '通常开课的学期';
+
+CREATE TABLE IF NOT EXISTS course_aliases (
+    id BIGSERIAL PRIMARY KEY,
+    course_id BIGINT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    alias VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_course_aliases_alias ON course_aliases(lower(alias));
+CREATE INDEX IF NOT EXISTS idx_course_aliases_course_id ON course_aliases(course_id);
+
+COMMENT ON TABLE course_aliases IS '课程别名，资料的课程名称按名称或别名关联到课程';
+
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS course_id BIGINT REFERENCES courses(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_materials_course_id ON materials(course_id);
+
+COMMENT ON COLUMN materials.course_id IS '关联的课程ID，按课程名称或别名解析';
+
+-- 常见公共课程及其别名
+INSERT INTO courses (name, department, semester) VALUES
+('高等数学', '理学院', '大一'),
+('线性代数', '理学院', '大一下'),
+('概率论与数理统计', '理学院', '大二上'),
+('大学物理', '理学院', '大一下'),
+('大学英语', '外国语学院', '大一'),
+('C语言程序设计', '计算机科学与技术学院', '大一上'),
+('数据结构', '计算机科学与技术学院', '大二上'),
+('离散数学', '计算机科学与技术学院', '大一下')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO course_aliases (course_id, alias)
+SELECT c.id, a.alias
+FROM (VALUES
+    ('高等数学', '高数'),
+    ('高等数学', '高等数学A'),
+    ('高等数学', '高等数学B'),
+    ('高等数学', '微积分'),
+    ('线性代数', '线代'),
+    ('概率论与数理统计', '概率论'),
+    ('概率论与数理统计', '概率统计'),
+    ('概率论与数理统计', '概统'),
+    ('大学物理', '大物'),
+    ('大学英语', '大英'),
+    ('C语言程序设计', 'C语言'),
+    ('C语言程序设计', 'C程序设计'),
+    ('数据结构', '数据结构与算法'),
+    ('离散数学', '离散')
+) AS a(course_name, alias)
+JOIN courses c ON c.name = a.course_name
+ON CONFLICT DO NOTHING;
+
+-- 按课程名称或别名关联已有资料（忽略大小写和多余空白）
+UPDATE materials m
+SET course_id = c.id
+FROM courses c
+WHERE m.course_id IS NULL
+  AND lower(regexp_replace(trim(m.course_name), '\s+', ' ', 'g')) = lower(c.name);
+
+UPDATE materials m
+SET course_id = a.course_id
+FROM course_aliases a
+WHERE m.course_id IS NULL
+  AND lower(regexp_replace(trim(m.course_name), '\s+', ' ', 'g')) = lower(a.alias);
@@ -0,0 +1,15 @@
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS tags VARCHAR(500)[];
+
+UPDATE materials m
+SET tags = sub.names
+FROM (
+    SELECT mt.material_id, array_agg(t.name ORDER BY mt.created_at) AS names
+    FROM material_tags mt
+    JOIN tags t ON t.id = mt.tag_id
+    GROUP BY mt.material_id
+) AS sub
+WHERE m.id = sub.material_id;
+
+DROP TABLE IF EXISTS material_tags;
+DROP TRIGGER IF EXISTS update_tags_updated_at ON tags;
+DROP TABLE IF EXISTS tags;
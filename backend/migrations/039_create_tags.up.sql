@@ -0,0 +1,55 @@
+-- Tagging: tags table and material-tag join, replacing the unused materials.tags array column
+
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags(name);
+
+CREATE TRIGGER update_tags_updated_at BEFORE UPDATE ON tags FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE tags IS '标签';
+COMMENT ON COLUMN tags.name IS '标签名（去掉开头的 #、合并空白并转小写）';
+
+CREATE TABLE IF NOT EXISTS material_tags (
+    material_id BIGINT NOT NULL REFERENCES materials(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (material_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_material_tags_tag_id ON material_tags(tag_id);
+
+COMMENT ON TABLE material_tags IS '资料与标签的关联';
+
+-- 迁移旧的 materials.tags 数组列
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'materials' AND column_name = 'tags'
+    ) THEN
+        CREATE TEMP TABLE legacy_material_tags ON COMMIT DROP AS
+        SELECT DISTINCT m.id AS material_id,
+               lower(regexp_replace(trim(ltrim(trim(t.tag), '#＃')), '\s+', ' ', 'g')) AS name
+        FROM materials m, unnest(m.tags) AS t(tag)
+        WHERE m.tags IS NOT NULL;
+
+        DELETE FROM legacy_material_tags WHERE name = '' OR char_length(name) > 20;
+
+        INSERT INTO tags (name)
+        SELECT DISTINCT name FROM legacy_material_tags
+        ON CONFLICT DO NOTHING;
+
+        INSERT INTO material_tags (material_id, tag_id)
+        SELECT l.material_id, t.id
+        FROM legacy_material_tags l
+        JOIN tags t ON t.name = l.name
+        ON CONFLICT DO NOTHING;
+
+        ALTER TABLE materials DROP COLUMN tags;
+    END IF;
+END $$;
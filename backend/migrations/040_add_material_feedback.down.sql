@@ -0,0 +1,15 @@
+-- notification_type 枚举值无法删除，'comment' 保留
+
+DROP INDEX IF EXISTS idx_reports_comment_id;
+ALTER TABLE reports DROP COLUMN IF EXISTS comment_id;
+
+DROP INDEX IF EXISTS idx_materials_rating_average;
+ALTER TABLE materials DROP COLUMN IF EXISTS comment_count;
+ALTER TABLE materials DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE materials DROP COLUMN IF EXISTS rating_average;
+
+DROP TRIGGER IF EXISTS update_material_comments_updated_at ON material_comments;
+DROP TABLE IF EXISTS material_comments;
+
+DROP TRIGGER IF EXISTS update_material_ratings_updated_at ON material_ratings;
+DROP TABLE IF EXISTS material_ratings;
@@ -0,0 +1,68 @@
+-- Material feedback: 1-5 ratings, two-level threaded comments with moderation, comment reports
+
+CREATE TABLE IF NOT EXISTS material_ratings (
+    id BIGSERIAL PRIMARY KEY,
+    material_id BIGINT NOT NULL REFERENCES materials(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    score SMALLINT NOT NULL CHECK (score BETWEEN 1 AND 5),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_material_ratings_material_user ON material_ratings(material_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_material_ratings_user_id ON material_ratings(user_id);
+
+CREATE TRIGGER update_material_ratings_updated_at BEFORE UPDATE ON material_ratings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE material_ratings IS '资料评分，每个用户对每份资料一条';
+COMMENT ON COLUMN material_ratings.score IS '评分 1-5';
+
+CREATE TABLE IF NOT EXISTS material_comments (
+    id BIGSERIAL PRIMARY KEY,
+    material_id BIGINT NOT NULL REFERENCES materials(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    root_id BIGINT REFERENCES material_comments(id) ON DELETE CASCADE,
+    parent_id BIGINT REFERENCES material_comments(id) ON DELETE SET NULL,
+    reply_to_user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    content TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'visible',
+    reply_count INTEGER NOT NULL DEFAULT 0,
+    edited_at TIMESTAMP,
+    hidden_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    hidden_at TIMESTAMP,
+    hidden_reason VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_material_comments_status CHECK (status IN ('visible', 'hidden', 'deleted'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_material_comments_material_root ON material_comments(material_id, created_at DESC) WHERE root_id IS NULL AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_material_comments_root_id ON material_comments(root_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_material_comments_parent_id ON material_comments(parent_id);
+CREATE INDEX IF NOT EXISTS idx_material_comments_user_id ON material_comments(user_id);
+CREATE INDEX IF NOT EXISTS idx_material_comments_status ON material_comments(status);
+CREATE INDEX IF NOT EXISTS idx_material_comments_deleted_at ON material_comments(deleted_at);
+
+CREATE TRIGGER update_material_comments_updated_at BEFORE UPDATE ON material_comments FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE material_comments IS '资料评论，回复统一挂在顶层评论下';
+COMMENT ON COLUMN material_comments.root_id IS '所在的顶层评论，为空表示顶层评论';
+COMMENT ON COLUMN material_comments.parent_id IS '被回复的评论';
+COMMENT ON COLUMN material_comments.status IS 'visible 正常，hidden 被管理员隐藏，deleted 作者已删除但仍有回复';
+COMMENT ON COLUMN material_comments.reply_count IS '顶层评论下正常显示的回复数';
+
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS rating_average NUMERIC(3,2) NOT NULL DEFAULT 0;
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE materials ADD COLUMN IF NOT EXISTS comment_count INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_materials_rating_average ON materials(rating_average);
+
+COMMENT ON COLUMN materials.rating_average IS '平均评分，无评分时为 0';
+COMMENT ON COLUMN materials.comment_count IS '正常显示的评论数（含回复）';
+
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS comment_id BIGINT REFERENCES material_comments(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_reports_comment_id ON reports(comment_id);
+
+COMMENT ON COLUMN reports.comment_id IS '被举报的评论，为空时举报的是资料本身';
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'comment';
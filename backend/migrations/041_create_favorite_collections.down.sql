@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS update_favorite_collection_items_updated_at ON favorite_collection_items;
+DROP TABLE IF EXISTS favorite_collection_items;
+
+DROP TRIGGER IF EXISTS update_favorite_collections_updated_at ON favorite_collections;
+DROP TABLE IF EXISTS favorite_collections;
@@ -0,0 +1,43 @@
+-- Favorite collections: named, ordered lists of favorited materials with notes and optional public sharing
+
+CREATE TABLE IF NOT EXISTS favorite_collections (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(30) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    share_token VARCHAR(64),
+    item_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorite_collections_user_id ON favorite_collections(user_id, position);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_favorite_collections_share_token ON favorite_collections(share_token) WHERE share_token IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_favorite_collections_deleted_at ON favorite_collections(deleted_at);
+
+CREATE TRIGGER update_favorite_collections_updated_at BEFORE UPDATE ON favorite_collections FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE favorite_collections IS '收藏夹，全部收藏（favorites）即默认收藏夹';
+COMMENT ON COLUMN favorite_collections.position IS '在用户收藏夹列表中的位置';
+COMMENT ON COLUMN favorite_collections.share_token IS '公开分享令牌，为空表示未分享';
+
+CREATE TABLE IF NOT EXISTS favorite_collection_items (
+    id BIGSERIAL PRIMARY KEY,
+    collection_id BIGINT NOT NULL REFERENCES favorite_collections(id) ON DELETE CASCADE,
+    material_id BIGINT NOT NULL REFERENCES materials(id) ON DELETE CASCADE,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_collection_material ON favorite_collection_items(collection_id, material_id);
+CREATE INDEX IF NOT EXISTS idx_favorite_collection_items_position ON favorite_collection_items(collection_id, position);
+CREATE INDEX IF NOT EXISTS idx_favorite_collection_items_material_id ON favorite_collection_items(material_id);
+
+CREATE TRIGGER update_favorite_collection_items_updated_at BEFORE UPDATE ON favorite_collection_items FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE favorite_collection_items IS '收藏夹中的资料，加入收藏夹的资料同时在 favorites 中';
+COMMENT ON COLUMN favorite_collection_items.note IS '用户备注';
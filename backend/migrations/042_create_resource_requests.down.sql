@@ -0,0 +1,6 @@
+-- notification_type 枚举值无法删除，'request' 保留
+
+DROP TABLE IF EXISTS resource_request_votes;
+
+DROP TRIGGER IF EXISTS update_resource_requests_updated_at ON resource_requests;
+DROP TABLE IF EXISTS resource_requests;
@@ -0,0 +1,52 @@
+-- Resource request board: students request materials, others upvote, committee members claim and fulfill them
+
+CREATE TABLE IF NOT EXISTS resource_requests (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    category VARCHAR(50) NOT NULL,
+    course_name VARCHAR(100) NOT NULL,
+    course_id BIGINT REFERENCES courses(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    vote_count INTEGER NOT NULL DEFAULT 0,
+    claimed_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    claimed_at TIMESTAMP,
+    fulfilled_material_id BIGINT REFERENCES materials(id) ON DELETE SET NULL,
+    fulfilled_at TIMESTAMP,
+    close_reason VARCHAR(200) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_resource_requests_status CHECK (status IN ('open', 'claimed', 'fulfilled', 'closed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_resource_requests_user_id ON resource_requests(user_id);
+CREATE INDEX IF NOT EXISTS idx_resource_requests_status_votes ON resource_requests(status, vote_count DESC);
+CREATE INDEX IF NOT EXISTS idx_resource_requests_category ON resource_requests(category);
+CREATE INDEX IF NOT EXISTS idx_resource_requests_course_id ON resource_requests(course_id);
+CREATE INDEX IF NOT EXISTS idx_resource_requests_claimed_by ON resource_requests(claimed_by);
+CREATE INDEX IF NOT EXISTS idx_resource_requests_fulfilled_material_id ON resource_requests(fulfilled_material_id);
+CREATE INDEX IF NOT EXISTS idx_resource_requests_deleted_at ON resource_requests(deleted_at);
+
+CREATE TRIGGER update_resource_requests_updated_at BEFORE UPDATE ON resource_requests FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE resource_requests IS '求资料';
+COMMENT ON COLUMN resource_requests.status IS '状态: open-等待认领, claimed-已认领, fulfilled-已完成, closed-已关闭';
+COMMENT ON COLUMN resource_requests.vote_count IS '投票数';
+COMMENT ON COLUMN resource_requests.claimed_by IS '认领的学委';
+COMMENT ON COLUMN resource_requests.fulfilled_material_id IS '完成时关联的资料';
+
+CREATE TABLE IF NOT EXISTS resource_request_votes (
+    id BIGSERIAL PRIMARY KEY,
+    request_id BIGINT NOT NULL REFERENCES resource_requests(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_resource_request_vote ON resource_request_votes(request_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_resource_request_votes_user_id ON resource_request_votes(user_id);
+
+COMMENT ON TABLE resource_request_votes IS '求资料投票，每个用户对每条求资料只能投一票';
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'request';
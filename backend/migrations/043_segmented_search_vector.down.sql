@@ -0,0 +1,25 @@
+CREATE OR REPLACE FUNCTION materials_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', COALESCE(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.description, '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.course_name, '')), 'C') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.content_text, '')), 'D');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS materials_search_vector_trigger ON materials;
+CREATE TRIGGER materials_search_vector_trigger
+    BEFORE INSERT OR UPDATE ON materials
+    FOR EACH ROW
+    EXECUTE FUNCTION materials_search_vector_update();
+
+UPDATE materials SET search_vector =
+    setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('simple', COALESCE(course_name, '')), 'C') ||
+    setweight(to_tsvector('simple', COALESCE(content_text, '')), 'D');
+
+DROP TRIGGER IF EXISTS update_search_dictionary_words_updated_at ON search_dictionary_words;
+DROP TABLE IF EXISTS search_dictionary_words;
@@ -0,0 +1,24 @@
+-- Chinese-aware search: search_vector is now computed by the application from segmented text
+
+CREATE TABLE IF NOT EXISTS search_dictionary_words (
+    id BIGSERIAL PRIMARY KEY,
+    word VARCHAR(50) NOT NULL,
+    frequency INTEGER NOT NULL DEFAULT 1000,
+    created_by BIGINT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_search_dictionary_words_word ON search_dictionary_words(word);
+
+CREATE TRIGGER update_search_dictionary_words_updated_at BEFORE UPDATE ON search_dictionary_words FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE search_dictionary_words IS '搜索分词自定义词条，课程名称和别名会自动加入分词词典';
+COMMENT ON COLUMN search_dictionary_words.frequency IS '词频，越大越倾向于整体切分';
+
+-- 中文需先分词再写入搜索向量，数据库触发器无法完成，改由应用计算
+DROP TRIGGER IF EXISTS materials_search_vector_trigger ON materials;
+DROP FUNCTION IF EXISTS materials_search_vector_update();
+
+-- 清空旧的搜索向量，应用启动时会按分词结果补建
+UPDATE materials SET search_vector = NULL;